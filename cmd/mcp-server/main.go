@@ -1,10 +1,14 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"flag"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 
@@ -12,22 +16,28 @@ import (
 
 	"github.com/gagliardetto/solana-go/rpc"
 
+	"github.com/vultisig/mcp/internal/abiregistry"
 	"github.com/vultisig/mcp/internal/blockchair"
 	"github.com/vultisig/mcp/internal/coingecko"
 	"github.com/vultisig/mcp/internal/config"
 	"github.com/vultisig/mcp/internal/defillama"
+	"github.com/vultisig/mcp/internal/electrum"
 	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/feerate"
 	"github.com/vultisig/mcp/internal/fourbyte"
 	gaiaclient "github.com/vultisig/mcp/internal/gaia"
 	"github.com/vultisig/mcp/internal/jupiter"
 	mcplog "github.com/vultisig/mcp/internal/logging"
 	"github.com/vultisig/mcp/internal/mayachain"
+	"github.com/vultisig/mcp/internal/mempool"
 	pumpfunclient "github.com/vultisig/mcp/internal/pumpfun"
 	"github.com/vultisig/mcp/internal/skills"
 	solanaclient "github.com/vultisig/mcp/internal/solana"
 	"github.com/vultisig/mcp/internal/thorchain"
+	"github.com/vultisig/mcp/internal/tokenmeta"
 	"github.com/vultisig/mcp/internal/tools"
 	tronclient "github.com/vultisig/mcp/internal/tron"
+	"github.com/vultisig/mcp/internal/utxobackend"
 	"github.com/vultisig/mcp/internal/vault"
 	"github.com/vultisig/mcp/internal/verifier"
 	xrpclient "github.com/vultisig/mcp/internal/xrp"
@@ -44,12 +54,55 @@ func main() {
 		logger.Fatalf("failed to load config: %v", err)
 	}
 
-	evmPool := evmclient.NewPool(cfg.EVM.ToURLMap())
+	tokenRegistry, err := tokenmeta.NewRegistry(cfg.TokenCachePath)
+	if err != nil {
+		logger.Fatalf("failed to load token cache: %v", err)
+	}
+	if cfg.TokenCachePath != "" {
+		logger.Printf("token cache: %s (%d tokens)", cfg.TokenCachePath, tokenRegistry.Len())
+	}
+
+	evmURLs := cfg.EVM.ToURLMap()
+	if cfg.EVMChainsFile != "" {
+		specs, err := evmclient.LoadChainSpecs(cfg.EVMChainsFile)
+		if err != nil {
+			logger.Fatalf("failed to load EVM chains: %v", err)
+		}
+		for _, spec := range specs {
+			url, err := selectChainRPC(spec)
+			if errors.Is(err, evmclient.ErrChainIDMismatch) {
+				logger.Fatalf("EVM chain %s: %v", spec.Name, err)
+			}
+			if err != nil {
+				logger.Printf("[WARN] %v; using %s", err, spec.RPCURLs[0])
+				url = spec.RPCURLs[0]
+			}
+			if err := evmclient.RegisterChain(spec); err != nil {
+				logger.Fatalf("register EVM chain %s: %v", spec.Name, err)
+			}
+			evmURLs[spec.Name] = url
+			logger.Printf("evm chain %s (chain ID %d): %s", spec.Name, spec.ChainID, url)
+		}
+	}
+
+	evmPool := evmclient.NewPool(evmURLs)
+	evmPool.SetTokenRegistry(tokenRegistry)
 	defer evmPool.Close()
 
 	store := vault.NewStore()
 	cgClient := coingecko.NewClient()
 	bcClient := blockchair.NewClient(cfg.BlockchairURL)
+	electrumBackends := make(map[string]utxobackend.Backend)
+	for chain, url := range cfg.Electrum.ToURLMap() {
+		ec, err := electrum.NewClient(url)
+		if err != nil {
+			logger.Fatalf("electrum %s: %v", chain, err)
+		}
+		defer ec.Close()
+		electrumBackends[chain] = ec
+		logger.Printf("electrum %s: %s", chain, url)
+	}
+	utxoBackend := utxobackend.NewRouter(bcClient, electrumBackends)
 	dlClient := defillama.NewClient(cfg.DefillamaURL)
 
 	hooks := mcplog.NewHooks(logger)
@@ -64,6 +117,7 @@ func main() {
 
 	solanaRPC := rpc.New(cfg.SolanaRPCURL)
 	solClient := solanaclient.NewClient(solanaRPC)
+	solClient.SetTokenRegistry(tokenRegistry)
 	logger.Printf("solana RPC: %s", cfg.SolanaRPCURL)
 
 	jupClient := jupiter.NewClient(cfg.JupiterAPIURL, solanaRPC)
@@ -73,6 +127,7 @@ func main() {
 	logger.Printf("xrp RPC: %s", cfg.XrpRpcURL)
 
 	tronClient := tronclient.NewClient(cfg.TronRPCURL)
+	tronClient.SetTokenRegistry(tokenRegistry)
 	logger.Printf("tron RPC: %s", cfg.TronRPCURL)
 
 	gaiaClient := gaiaclient.NewClient(cfg.GaiaRPCURL)
@@ -83,18 +138,42 @@ func main() {
 
 	swapSvc := swap.NewService()
 	tcClient := thorchain.NewClient(cfg.ThorchainURL)
+	mempoolSources := make(map[string]feerate.Source)
+	for chain, url := range map[string]string{
+		"Bitcoin":  cfg.MempoolBitcoinURL,
+		"Litecoin": cfg.MempoolLitecoinURL,
+	} {
+		if url != "" {
+			mempoolSources[chain] = mempool.NewClient(url)
+			logger.Printf("mempool fees %s: %s", chain, url)
+		}
+	}
+	feeSource := feerate.NewRouter(feerate.NewThorchainSource(tcClient), mempoolSources)
 	mcClient := mayachain.NewClient(cfg.MayachainURL)
 	logger.Printf("mayachain: %s", cfg.MayachainURL)
 
 	fbClient := fourbyte.NewClient()
 
+	var explorer *abiregistry.Explorer
+	if cfg.EtherscanAPIKey != "" || cfg.EtherscanAPIURL != "" {
+		explorer = abiregistry.NewExplorer(cfg.EtherscanAPIURL, cfg.EtherscanAPIKey)
+		logger.Printf("ABI explorer: %s", cmp.Or(cfg.EtherscanAPIURL, abiregistry.DefaultExplorerURL))
+	}
+	abiRegistry, err := abiregistry.NewRegistry(cfg.ABICachePath, explorer)
+	if err != nil {
+		logger.Fatalf("failed to load ABI cache: %v", err)
+	}
+	if cfg.ABICachePath != "" {
+		logger.Printf("ABI cache: %s (%d contracts)", cfg.ABICachePath, abiRegistry.Len())
+	}
+
 	var vcClient *verifier.Client
 	if cfg.VerifierURL != "" {
 		vcClient = verifier.NewClient(cfg.VerifierURL, cfg.VerifierAPIKey)
 		logger.Printf("verifier: %s", cfg.VerifierURL)
 	}
 
-	if err := tools.RegisterAll(s, store, evmPool, cgClient, bcClient, swapSvc, tcClient, mcClient, solClient, jupClient, xrpClient, tronClient, gaiaClient, pfClient, fbClient, vcClient, dlClient); err != nil {
+	if err := tools.RegisterAll(s, store, evmPool, cgClient, utxoBackend, feeSource, swapSvc, tcClient, mcClient, solClient, jupClient, xrpClient, tronClient, gaiaClient, pfClient, fbClient, abiRegistry, vcClient, dlClient); err != nil {
 		logger.Printf("[WARN] some tools not registered: %v", err)
 	}
 	skills.RegisterMCPResources(s)
@@ -123,3 +202,11 @@ func main() {
 		}
 	}
 }
+
+// selectChainRPC picks the RPC of a declared EVM chain, giving each
+// endpoint a bounded time to answer.
+func selectChainRPC(spec evmclient.ChainSpec) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return evmclient.SelectRPC(ctx, spec)
+}
@@ -0,0 +1,98 @@
+package abiregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultExplorerURL is Etherscan's multichain (V2) API, which serves every
+// chain it indexes from one endpoint selected by chainid.
+const DefaultExplorerURL = "https://api.etherscan.io/v2/api"
+
+// ErrNotVerified is returned for contracts without verified source.
+var ErrNotVerified = errors.New("contract source not verified")
+
+// Explorer fetches verified contract ABIs from an Etherscan-compatible API
+// (Etherscan, its per-chain clones, Blockscout).
+type Explorer struct {
+	http    *http.Client
+	baseURL string
+	apiKey  string
+}
+
+// NewExplorer returns an explorer client for baseURL; an empty baseURL
+// uses DefaultExplorerURL. apiKey may be empty for APIs that do not need
+// one.
+func NewExplorer(baseURL, apiKey string) *Explorer {
+	if baseURL == "" {
+		baseURL = DefaultExplorerURL
+	}
+	return &Explorer{
+		http:    &http.Client{Timeout: 30 * time.Second},
+		baseURL: baseURL,
+		apiKey:  apiKey,
+	}
+}
+
+type explorerResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+type sourceCode struct {
+	ABI          string `json:"ABI"`
+	ContractName string `json:"ContractName"`
+}
+
+// ContractABI returns the name and JSON ABI of a verified contract.
+func (e *Explorer) ContractABI(ctx context.Context, chainID uint64, address common.Address) (string, json.RawMessage, error) {
+	q := url.Values{
+		"chainid": {strconv.FormatUint(chainID, 10)},
+		"module":  {"contract"},
+		"action":  {"getsourcecode"},
+		"address": {address.Hex()},
+	}
+	if e.apiKey != "" {
+		q.Set("apikey", e.apiKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("explorer request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("explorer: request returned %d", resp.StatusCode)
+	}
+
+	var er explorerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&er); err != nil {
+		return "", nil, fmt.Errorf("decode explorer response: %w", err)
+	}
+	var sources []sourceCode
+	if err := json.Unmarshal(er.Result, &sources); err != nil {
+		// Errors such as rate limits and bad API keys come back as a
+		// string result.
+		var msg string
+		if json.Unmarshal(er.Result, &msg) == nil && msg != "" {
+			return "", nil, fmt.Errorf("explorer: %s", msg)
+		}
+		return "", nil, fmt.Errorf("explorer: %s", er.Message)
+	}
+	if len(sources) == 0 || sources[0].ABI == "" || sources[0].ABI[0] != '[' {
+		return "", nil, ErrNotVerified
+	}
+	return sources[0].ContractName, json.RawMessage(sources[0].ABI), nil
+}
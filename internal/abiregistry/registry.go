@@ -0,0 +1,370 @@
+// Package abiregistry caches contract ABIs per chain: verified ABIs fetched
+// from an Etherscan-compatible explorer and ABIs imported by the user.
+// Unlike 4-byte signature lookups, these identify a selector unambiguously
+// and carry parameter names.
+package abiregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ABI sources.
+const (
+	SourceExplorer = "explorer"
+	SourceImport   = "import"
+)
+
+const (
+	// missRetry is how long a contract the explorer has no ABI for is not
+	// asked about again.
+	missRetry = time.Hour
+	// proxyTTL is how long a proxy's implementation, or a contract not
+	// being a proxy, is remembered before it is checked again for upgrades.
+	proxyTTL = 10 * time.Minute
+)
+
+// ErrNotFound is returned when neither the cache nor the explorer has an
+// ABI for a contract.
+var ErrNotFound = errors.New("no ABI found")
+
+// Entry is a cached contract ABI.
+type Entry struct {
+	Name   string          `json:"name,omitempty"`
+	ABI    json.RawMessage `json:"abi"`
+	Source string          `json:"source"`
+}
+
+// ProxyResolver finds the implementation behind a proxy contract; it
+// returns the zero address for contracts that are not proxies.
+// evm.Client implements it.
+type ProxyResolver interface {
+	ProxyImplementation(ctx context.Context, proxy common.Address) (common.Address, string, error)
+}
+
+// Contract is the ABI of a contract. For proxies, ABI holds the
+// implementation's functions plus the proxy's own.
+type Contract struct {
+	Address            common.Address
+	Name               string
+	Source             string
+	ABI                abi.ABI
+	ProxyKind          string
+	Implementation     common.Address
+	ImplementationName string
+}
+
+// IsProxy reports whether the contract forwards to an implementation.
+func (c *Contract) IsProxy() bool {
+	return c.Implementation != (common.Address{})
+}
+
+// Registry holds contract ABIs for every chain, keyed by chain ID and
+// address. Entries are kept for the life of the process and, if the
+// registry has a file, written to it so they survive restarts. It is safe
+// for concurrent use.
+type Registry struct {
+	path     string
+	explorer *Explorer
+
+	mu      sync.RWMutex
+	entries map[string]Entry
+	parsed  map[string]abi.ABI
+	misses  map[string]time.Time
+	proxies map[string]proxyTarget
+	writeMu sync.Mutex
+}
+
+type proxyTarget struct {
+	implementation common.Address
+	kind           string
+	checked        time.Time
+}
+
+// NewMemoryRegistry returns a registry that is not persisted. A nil
+// explorer only serves imported ABIs.
+func NewMemoryRegistry(explorer *Explorer) *Registry {
+	return &Registry{
+		explorer: explorer,
+		entries:  make(map[string]Entry),
+		parsed:   make(map[string]abi.ABI),
+		misses:   make(map[string]time.Time),
+		proxies:  make(map[string]proxyTarget),
+	}
+}
+
+// NewRegistry returns a registry persisted to path, loading any entries
+// already there. An empty path keeps entries in memory only.
+func NewRegistry(path string, explorer *Explorer) (*Registry, error) {
+	r := NewMemoryRegistry(explorer)
+	if path == "" {
+		return r, nil
+	}
+	r.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read ABI cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.entries); err != nil {
+		return nil, fmt.Errorf("parse ABI cache %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Len returns the number of cached ABIs across all chains.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.entries)
+}
+
+func key(chainID uint64, address common.Address) string {
+	return fmt.Sprintf("%d/%s", chainID, strings.ToLower(address.Hex()))
+}
+
+// Import stores a user-supplied ABI for a contract, replacing any cached
+// one, and returns it parsed.
+func (r *Registry) Import(chainID uint64, address common.Address, name string, abiJSON []byte) (abi.ABI, error) {
+	parsed, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("invalid ABI: %w", err)
+	}
+	compact, err := compactJSON(abiJSON)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	r.put(key(chainID, address), Entry{Name: name, ABI: compact, Source: SourceImport}, parsed)
+	return parsed, nil
+}
+
+// Get returns the ABI of the contract at address, fetching it from the
+// explorer when it is not cached. It does not follow proxies.
+func (r *Registry) Get(ctx context.Context, chainID uint64, address common.Address) (Entry, abi.ABI, error) {
+	k := key(chainID, address)
+	r.mu.RLock()
+	entry, ok := r.entries[k]
+	parsed, isParsed := r.parsed[k]
+	missed, isMiss := r.misses[k]
+	r.mu.RUnlock()
+
+	if ok {
+		if !isParsed {
+			var err error
+			if parsed, err = abi.JSON(strings.NewReader(string(entry.ABI))); err != nil {
+				return Entry{}, abi.ABI{}, fmt.Errorf("cached ABI for %s: %w", address.Hex(), err)
+			}
+			r.mu.Lock()
+			r.parsed[k] = parsed
+			r.mu.Unlock()
+		}
+		return entry, parsed, nil
+	}
+	if r.explorer == nil || (isMiss && time.Since(missed) < missRetry) {
+		return Entry{}, abi.ABI{}, ErrNotFound
+	}
+
+	name, abiJSON, err := r.explorer.ContractABI(ctx, chainID, address)
+	if errors.Is(err, ErrNotVerified) {
+		r.mu.Lock()
+		r.misses[k] = time.Now()
+		r.mu.Unlock()
+		return Entry{}, abi.ABI{}, ErrNotFound
+	}
+	if err != nil {
+		return Entry{}, abi.ABI{}, err
+	}
+	parsed, err = abi.JSON(strings.NewReader(string(abiJSON)))
+	if err != nil {
+		return Entry{}, abi.ABI{}, fmt.Errorf("explorer ABI for %s: %w", address.Hex(), err)
+	}
+	compact, err := compactJSON(abiJSON)
+	if err != nil {
+		return Entry{}, abi.ABI{}, err
+	}
+	entry = Entry{Name: name, ABI: compact, Source: SourceExplorer}
+	r.put(k, entry, parsed)
+	return entry, parsed, nil
+}
+
+// Contract returns the ABI of the contract at address. With a resolver,
+// proxies are followed to their implementation, which is re-checked after
+// proxyTTL since proxies can be upgraded. It returns ErrNotFound when
+// neither the contract nor its implementation has an ABI.
+func (r *Registry) Contract(ctx context.Context, chainID uint64, address common.Address, resolver ProxyResolver) (*Contract, error) {
+	c := &Contract{Address: address}
+	entry, parsed, err := r.Get(ctx, chainID, address)
+	found := err == nil
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if found {
+		c.Name, c.Source, c.ABI = entry.Name, entry.Source, parsed
+	}
+
+	// Without an explorer or any cached ABI there is nothing an
+	// implementation could resolve to.
+	if resolver != nil && (r.explorer != nil || r.Len() > 0) {
+		impl, kind, err := r.proxyImplementation(ctx, chainID, address, resolver)
+		if err != nil && !found {
+			return nil, fmt.Errorf("detect proxy: %w", err)
+		}
+		if err == nil && impl != (common.Address{}) {
+			c.ProxyKind, c.Implementation = kind, impl
+			implEntry, implABI, err := r.Get(ctx, chainID, impl)
+			switch {
+			case err == nil:
+				c.ImplementationName = implEntry.Name
+				if c.Source == "" {
+					c.Source = implEntry.Source
+				}
+				if found {
+					c.ABI = mergeABI(implABI, c.ABI)
+				} else {
+					c.ABI = implABI
+				}
+				found = true
+			case !errors.Is(err, ErrNotFound) && !found:
+				return nil, err
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w for %s", ErrNotFound, address.Hex())
+	}
+	return c, nil
+}
+
+func (r *Registry) proxyImplementation(ctx context.Context, chainID uint64, address common.Address, resolver ProxyResolver) (common.Address, string, error) {
+	k := key(chainID, address)
+	r.mu.RLock()
+	target, ok := r.proxies[k]
+	r.mu.RUnlock()
+	if ok && time.Since(target.checked) < proxyTTL {
+		return target.implementation, target.kind, nil
+	}
+
+	impl, kind, err := resolver.ProxyImplementation(ctx, address)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	r.mu.Lock()
+	r.proxies[k] = proxyTarget{implementation: impl, kind: kind, checked: time.Now()}
+	r.mu.Unlock()
+	return impl, kind, nil
+}
+
+// mergeABI returns impl with the methods, events and errors of proxy that
+// impl does not declare.
+func mergeABI(impl, proxy abi.ABI) abi.ABI {
+	merged := impl
+	merged.Methods = make(map[string]abi.Method, len(impl.Methods)+len(proxy.Methods))
+	ids := make(map[string]bool, len(impl.Methods))
+	for name, m := range impl.Methods {
+		merged.Methods[name] = m
+		ids[string(m.ID)] = true
+	}
+	for name, m := range proxy.Methods {
+		if ids[string(m.ID)] {
+			continue
+		}
+		if _, taken := merged.Methods[name]; taken {
+			name = m.Sig
+		}
+		merged.Methods[name] = m
+	}
+
+	merged.Events = make(map[string]abi.Event, len(impl.Events)+len(proxy.Events))
+	for name, e := range impl.Events {
+		merged.Events[name] = e
+	}
+	for name, e := range proxy.Events {
+		if _, err := impl.EventByID(e.ID); err == nil {
+			continue
+		}
+		if _, taken := merged.Events[name]; taken {
+			name = e.Sig
+		}
+		merged.Events[name] = e
+	}
+
+	merged.Errors = make(map[string]abi.Error, len(impl.Errors)+len(proxy.Errors))
+	for name, e := range impl.Errors {
+		merged.Errors[name] = e
+	}
+	for name, e := range proxy.Errors {
+		if _, taken := merged.Errors[name]; !taken {
+			merged.Errors[name] = e
+		}
+	}
+	return merged
+}
+
+func compactJSON(data []byte) (json.RawMessage, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("invalid ABI JSON: %w", err)
+	}
+	return json.Marshal(v)
+}
+
+// put caches an entry. A failure to persist it is logged; it stays cached
+// in memory regardless.
+func (r *Registry) put(k string, entry Entry, parsed abi.ABI) {
+	r.mu.Lock()
+	r.entries[k] = entry
+	r.parsed[k] = parsed
+	delete(r.misses, k)
+	r.mu.Unlock()
+	if r.path == "" {
+		return
+	}
+	if err := r.save(); err != nil {
+		log.Printf("[abiregistry] %v", err)
+	}
+}
+
+// save writes all entries to the registry's file, replacing it atomically.
+func (r *Registry) save() error {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	r.mu.RLock()
+	data, err := json.MarshalIndent(r.entries, "", "  ")
+	r.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("marshal ABI cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write ABI cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write ABI cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write ABI cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("write ABI cache: %w", err)
+	}
+	return nil
+}
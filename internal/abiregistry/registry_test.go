@@ -0,0 +1,162 @@
+package abiregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	tokenABI = `[{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}]`
+	proxyABI = `[{"type":"function","name":"upgradeTo","stateMutability":"nonpayable","inputs":[{"name":"newImplementation","type":"address"}],"outputs":[]},
+		{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"dst","type":"address"},{"name":"wad","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}]`
+)
+
+var (
+	proxyAddr = common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	implAddr  = common.HexToAddress("0x43506849d7c04f9138d1a2050bbf3a0c054402dd")
+	otherAddr = common.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+)
+
+// explorerServer stands in for an Etherscan-compatible API serving abis by
+// lowercase address; other contracts are unverified. It counts requests.
+func explorerServer(t *testing.T, abis map[string]string) (*Explorer, *int) {
+	t.Helper()
+	var mu sync.Mutex
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		q := r.URL.Query()
+		if q.Get("module") != "contract" || q.Get("action") != "getsourcecode" || q.Get("chainid") != "1" || q.Get("apikey") != "key" {
+			http.Error(w, "bad query", http.StatusBadRequest)
+			return
+		}
+		source := map[string]string{"ABI": "Contract source code not verified", "ContractName": ""}
+		if a, ok := abis[strings.ToLower(q.Get("address"))]; ok {
+			source = map[string]string{"ABI": a, "ContractName": "FiatTokenV2_2"}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "1", "message": "OK", "result": []any{source}})
+	}))
+	t.Cleanup(srv.Close)
+	return NewExplorer(srv.URL, "key"), &requests
+}
+
+type fakeResolver map[common.Address]common.Address
+
+func (f fakeResolver) ProxyImplementation(_ context.Context, proxy common.Address) (common.Address, string, error) {
+	return f[proxy], "eip1967", nil
+}
+
+func TestRegistry_FetchAndPersist(t *testing.T) {
+	explorer, requests := explorerServer(t, map[string]string{strings.ToLower(otherAddr.Hex()): tokenABI})
+	path := filepath.Join(t.TempDir(), "abis.json")
+	r, err := NewRegistry(path, explorer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for range 2 {
+		entry, parsed, err := r.Get(ctx, 1, otherAddr)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if entry.Source != SourceExplorer || entry.Name != "FiatTokenV2_2" || parsed.Methods["transfer"].Sig != "transfer(address,uint256)" {
+			t.Errorf("entry %+v", entry)
+		}
+	}
+	for range 2 {
+		if _, _, err := r.Get(ctx, 1, implAddr); !errors.Is(err, ErrNotFound) {
+			t.Errorf("unverified: err = %v", err)
+		}
+	}
+	if *requests != 2 {
+		t.Errorf("explorer requests = %d, want 2 (hits and misses are cached)", *requests)
+	}
+
+	reloaded, err := NewRegistry(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Len() != 1 {
+		t.Fatalf("reloaded %d entries", reloaded.Len())
+	}
+	if _, parsed, err := reloaded.Get(ctx, 1, otherAddr); err != nil || len(parsed.Methods) != 1 {
+		t.Errorf("reloaded Get: %v", err)
+	}
+	if _, _, err := reloaded.Get(ctx, 10, otherAddr); !errors.Is(err, ErrNotFound) {
+		t.Errorf("other chain: err = %v", err)
+	}
+}
+
+func TestRegistry_Import(t *testing.T) {
+	r := NewMemoryRegistry(nil)
+	if _, err := r.Import(1, otherAddr, "", []byte(`[{"type":"function"`)); err == nil {
+		t.Error("expected error for invalid ABI")
+	}
+	if _, err := r.Import(1, otherAddr, "Token", []byte(tokenABI)); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	c, err := r.Contract(context.Background(), 1, otherAddr, nil)
+	if err != nil {
+		t.Fatalf("Contract: %v", err)
+	}
+	if c.Source != SourceImport || c.Name != "Token" || c.IsProxy() {
+		t.Errorf("contract %+v", c)
+	}
+}
+
+func TestRegistry_Proxy(t *testing.T) {
+	explorer, _ := explorerServer(t, map[string]string{
+		strings.ToLower(proxyAddr.Hex()): proxyABI,
+		strings.ToLower(implAddr.Hex()):  tokenABI,
+	})
+	r := NewMemoryRegistry(explorer)
+	resolver := fakeResolver{proxyAddr: implAddr}
+
+	c, err := r.Contract(context.Background(), 1, proxyAddr, resolver)
+	if err != nil {
+		t.Fatalf("Contract: %v", err)
+	}
+	if c.Implementation != implAddr || c.ProxyKind != "eip1967" {
+		t.Errorf("proxy %s %q", c.Implementation.Hex(), c.ProxyKind)
+	}
+	transfer := c.ABI.Methods["transfer"]
+	if transfer.Inputs[0].Name != "to" {
+		t.Errorf("transfer inputs %v, want the implementation's names", transfer.Inputs)
+	}
+	if _, ok := c.ABI.Methods["upgradeTo"]; !ok {
+		t.Error("proxy's own methods were dropped")
+	}
+
+	// An unverified proxy still resolves to its implementation's ABI.
+	unverified := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	resolver[unverified] = implAddr
+	if c, err := r.Contract(context.Background(), 1, unverified, resolver); err != nil || len(c.ABI.Methods) != 1 {
+		t.Errorf("unverified proxy: %v", err)
+	}
+	if _, err := r.Contract(context.Background(), 1, otherAddr, resolver); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestExplorer_ErrorResult(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`))
+	}))
+	defer srv.Close()
+	_, _, err := NewExplorer(srv.URL, "").ContractABI(context.Background(), 1, otherAddr)
+	if err == nil || !strings.Contains(err.Error(), "rate limit") || errors.Is(err, ErrNotVerified) {
+		t.Errorf("err = %v", err)
+	}
+}
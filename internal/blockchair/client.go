@@ -6,7 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
+
+	"github.com/vultisig/mcp/internal/utxobackend"
 )
 
 const dashboardCacheTTL = 5 * time.Minute
@@ -38,63 +42,40 @@ var ChainNames = []string{
 	"Zcash",
 }
 
-// AddressInfo holds the address-level stats from the Blockchair dashboard.
-type AddressInfo struct {
-	Type               string  `json:"type"`
-	Balance            int64   `json:"balance"`
-	BalanceUSD         float64 `json:"balance_usd"`
-	Received           int64   `json:"received"`
-	Spent              int64   `json:"spent"`
-	OutputCount        int     `json:"output_count"`
-	UnspentOutputCount int     `json:"unspent_output_count"`
-	TransactionCount   int     `json:"transaction_count"`
-}
-
-// UTXO represents a single unspent transaction output.
-type UTXO struct {
-	BlockID         int64  `json:"block_id"`
-	TransactionHash string `json:"transaction_hash"`
-	Index           int    `json:"index"`
-	Value           int64  `json:"value"`
-}
-
-// AddressDashboard contains the full dashboard data for one address.
-type AddressDashboard struct {
-	Address      AddressInfo `json:"address"`
-	Transactions []string    `json:"transactions"`
-	UTXOs        []UTXO      `json:"utxo"`
-}
-
 // dashboardResponse is the raw JSON envelope from the Blockchair API.
 type dashboardResponse struct {
-	Data    map[string]AddressDashboard `json:"data"`
+	Data    map[string]utxobackend.AddressDashboard `json:"data"`
 	Context struct {
-		Code int `json:"code"`
+		Code  int   `json:"code"`
+		State int64 `json:"state"`
 	} `json:"context"`
 }
 
 // Client wraps the Vultisig Blockchair proxy with an in-memory TTL cache.
+// It is the default utxobackend.Backend.
 type Client struct {
 	http    *http.Client
 	baseURL string
 
-	cache      *ttlCache[*AddressDashboard]
+	cache      *ttlCache[*utxobackend.AddressDashboard]
 	rawTxCache *ttlCache[[]byte]
 }
 
+var _ utxobackend.Backend = (*Client)(nil)
+
 // NewClient creates a Blockchair API client.
 func NewClient(baseURL string) *Client {
 	return &Client{
 		http:       &http.Client{Timeout: 30 * time.Second},
 		baseURL:    baseURL,
-		cache:      newTTLCache[*AddressDashboard](dashboardCacheTTL),
+		cache:      newTTLCache[*utxobackend.AddressDashboard](dashboardCacheTTL),
 		rawTxCache: newTTLCache[[]byte](dashboardCacheTTL),
 	}
 }
 
 // GetAddressDashboard fetches the address dashboard for a UTXO chain.
 // Results are cached for 5 minutes keyed by chain:address.
-func (c *Client) GetAddressDashboard(ctx context.Context, chain, address string) (*AddressDashboard, error) {
+func (c *Client) GetAddressDashboard(ctx context.Context, chain, address string) (*utxobackend.AddressDashboard, error) {
 	info, ok := SupportedChains[chain]
 	if !ok {
 		return nil, fmt.Errorf("unsupported UTXO chain: %s", chain)
@@ -131,6 +112,7 @@ func (c *Client) GetAddressDashboard(ctx context.Context, chain, address string)
 		return nil, fmt.Errorf("blockchair: no data for address %s", address)
 	}
 
+	dashboard.Height = dr.Context.State
 	c.cache.set(cacheKey, &dashboard)
 	return &dashboard, nil
 }
@@ -201,15 +183,6 @@ func (a *chainFetcherAdapter) GetRawTransaction(txHash string) ([]byte, error) {
 	return a.client.GetRawTransaction(a.ctx, a.chain, txHash)
 }
 
-// TxDashboard holds the transaction-level dashboard data from Blockchair.
-type TxDashboard struct {
-	BlockID       int64  `json:"block_id"`
-	Fee           int64  `json:"fee"`
-	InputTotal    int64  `json:"input_total"`
-	OutputTotal   int64  `json:"output_total"`
-	Confirmations int    `json:"confirmations,omitempty"`
-}
-
 type txDashboardResponse struct {
 	Data    map[string]txDashboardData `json:"data"`
 	Context struct {
@@ -218,12 +191,12 @@ type txDashboardResponse struct {
 }
 
 type txDashboardData struct {
-	Transaction TxDashboard `json:"transaction"`
+	Transaction utxobackend.TxDashboard `json:"transaction"`
 }
 
 // GetTxDashboard fetches the transaction dashboard for a UTXO chain tx hash.
 // Returns nil, nil if the transaction is not found (404).
-func (c *Client) GetTxDashboard(ctx context.Context, chain, txHash string) (*TxDashboard, error) {
+func (c *Client) GetTxDashboard(ctx context.Context, chain, txHash string) (*utxobackend.TxDashboard, error) {
 	info, ok := SupportedChains[chain]
 	if !ok {
 		return nil, fmt.Errorf("unsupported UTXO chain: %s", chain)
@@ -262,6 +235,60 @@ func (c *Client) GetTxDashboard(ctx context.Context, chain, txHash string) (*TxD
 	return &data.Transaction, nil
 }
 
+// GetUTXOs returns the unspent outputs of address from its dashboard.
+func (c *Client) GetUTXOs(ctx context.Context, chain, address string) ([]utxobackend.UTXO, error) {
+	dashboard, err := c.GetAddressDashboard(ctx, chain, address)
+	if err != nil {
+		return nil, err
+	}
+	return dashboard.UTXOs, nil
+}
+
+type pushResponse struct {
+	Data struct {
+		TransactionHash string `json:"transaction_hash"`
+	} `json:"data"`
+	Context struct {
+		Code  int    `json:"code"`
+		Error string `json:"error"`
+	} `json:"context"`
+}
+
+// BroadcastTransaction submits a signed transaction through the push endpoint.
+func (c *Client) BroadcastTransaction(ctx context.Context, chain string, rawTx []byte) (string, error) {
+	info, ok := SupportedChains[chain]
+	if !ok {
+		return "", fmt.Errorf("unsupported UTXO chain: %s", chain)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/push/transaction", c.baseURL, info.Slug)
+	form := url.Values{"data": {hex.EncodeToString(rawTx)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var pr pushResponse
+	err = json.NewDecoder(resp.Body).Decode(&pr)
+	if err != nil {
+		return "", fmt.Errorf("blockchair: decode push response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if pr.Context.Error != "" {
+			return "", fmt.Errorf("blockchair: broadcast rejected: %s", pr.Context.Error)
+		}
+		return "", fmt.Errorf("blockchair: broadcast returned %d", resp.StatusCode)
+	}
+	return pr.Data.TransactionHash, nil
+}
+
 // FormatSatoshis converts a satoshi-like integer to a decimal string.
 func FormatSatoshis(amount int64, decimals int) string {
 	if decimals == 0 {
@@ -2,7 +2,10 @@ package blockchair
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -89,3 +92,29 @@ func TestChainFetcher(t *testing.T) {
 		t.Error("expected non-empty raw transaction bytes")
 	}
 }
+
+func TestBroadcastTransaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/bitcoin/push/transaction" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.FormValue("data") == "00" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"data":null,"context":{"code":400,"error":"Invalid transaction"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"transaction_hash":"` + r.FormValue("data") + `"},"context":{"code":200}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	txid, err := c.BroadcastTransaction(context.Background(), "Bitcoin", []byte{0xab, 0xcd})
+	if err != nil || txid != "abcd" {
+		t.Errorf("broadcast = %q, %v", txid, err)
+	}
+
+	_, err = c.BroadcastTransaction(context.Background(), "Bitcoin", []byte{0x00})
+	if err == nil || !strings.Contains(err.Error(), "Invalid transaction") {
+		t.Errorf("err = %v, want rejection", err)
+	}
+}
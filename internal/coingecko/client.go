@@ -44,9 +44,15 @@ type Client struct {
 
 // NewClient creates a CoinGecko API client that routes through the Vultisig proxy.
 func NewClient() *Client {
+	return NewClientWithBaseURL(defaultBaseURL)
+}
+
+// NewClientWithBaseURL creates a client for a CoinGecko-compatible API at
+// baseURL (e.g. a local mock in tests).
+func NewClientWithBaseURL(baseURL string) *Client {
 	return &Client{
 		http:        &http.Client{Timeout: 30 * time.Second},
-		baseURL:     defaultBaseURL,
+		baseURL:     baseURL,
 		searchCache: newTTLCache[[]SearchCoin](searchCacheTTL),
 		detailCache: newTTLCache[*CoinDetail](detailCacheTTL),
 		priceCache:  newTTLCache[PriceData](priceCacheTTL),
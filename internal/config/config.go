@@ -25,13 +25,30 @@ type EVMRPCConfig struct {
 	Zksync    RPCItem
 }
 
+// ElectrumConfig holds optional Electrum server URLs (electrs, Fulcrum,
+// ElectrumX) for the UTXO chains. Each field maps to ELECTRUM_{CHAIN}_URL
+// (e.g. ELECTRUM_BITCOIN_URL, ELECTRUM_BITCOIN_CASH_URL) in the form
+// tcp://host:port or ssl://host:port. Chains left unset use Blockchair.
+type ElectrumConfig struct {
+	Bitcoin     RPCItem
+	Litecoin    RPCItem
+	Dogecoin    RPCItem
+	BitcoinCash RPCItem `split_words:"true"`
+	Dash        RPCItem
+}
+
 type RPCItem struct {
 	URL string
 }
 
 type Config struct {
 	EVM           EVMRPCConfig
+	Electrum      ElectrumConfig
 	BlockchairURL string `envconfig:"BLOCKCHAIR_API_URL" default:"https://api.vultisig.com/blockchair"`
+	// Mempool fee APIs, opt-in (e.g. https://mempool.space); empty uses
+	// THORChain rates.
+	MempoolBitcoinURL  string `envconfig:"MEMPOOL_BITCOIN_URL" default:""`
+	MempoolLitecoinURL string `envconfig:"MEMPOOL_LITECOIN_URL" default:""`
 	ThorchainURL  string `envconfig:"THORCHAIN_URL" default:"https://thornode.ninerealms.com"`
 	MayachainURL  string `envconfig:"MAYACHAIN_URL" default:"https://mayanode.mayachain.info"`
 	SolanaRPCURL  string `envconfig:"SOLANA_RPC_URL" default:"https://api.mainnet-beta.solana.com"`
@@ -42,6 +59,19 @@ type Config struct {
 	TronRPCURL    string `envconfig:"TRON_RPC_URL" default:"https://api.trongrid.io"`
 	DefillamaURL  string `envconfig:"DEFILLAMA_URL" default:"https://api.llama.fi"`
 	GaiaRPCURL    string `envconfig:"GAIA_RPC_URL" default:"https://cosmos-rest.publicnode.com"`
+	// EVMChainsFile is a JSON array of evm.ChainSpec declaring extra EVM
+	// chains; empty uses the built-in chains only.
+	EVMChainsFile string `envconfig:"EVM_CHAINS_FILE" default:""`
+	// TokenCachePath persists token metadata across restarts; empty keeps
+	// it in memory only.
+	TokenCachePath string `envconfig:"TOKEN_CACHE_PATH" default:""`
+	// Contract ABIs are fetched from an Etherscan-compatible API when a key
+	// or URL is set; empty URL uses Etherscan's multichain API.
+	EtherscanAPIURL string `envconfig:"ETHERSCAN_API_URL" default:""`
+	EtherscanAPIKey string `envconfig:"ETHERSCAN_API_KEY" default:""`
+	// ABICachePath persists contract ABIs across restarts; empty keeps
+	// them in memory only.
+	ABICachePath string `envconfig:"ABI_CACHE_PATH" default:""`
 }
 
 // ToURLMap converts the EVM RPC config to a chain-name → URL map,
@@ -68,6 +98,24 @@ func (e EVMRPCConfig) ToURLMap() map[string]string {
 	return m
 }
 
+// ToURLMap converts the Electrum config to a chain-name → URL map holding
+// only the chains that have a server configured.
+func (e ElectrumConfig) ToURLMap() map[string]string {
+	m := make(map[string]string)
+	for chain, url := range map[string]string{
+		"Bitcoin":      e.Bitcoin.URL,
+		"Litecoin":     e.Litecoin.URL,
+		"Dogecoin":     e.Dogecoin.URL,
+		"Bitcoin-Cash": e.BitcoinCash.URL,
+		"Dash":         e.Dash.URL,
+	} {
+		if url != "" {
+			m[chain] = url
+		}
+	}
+	return m
+}
+
 func Load() (Config, error) {
 	var cfg Config
 	err := envconfig.Process("", &cfg)
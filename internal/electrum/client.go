@@ -0,0 +1,464 @@
+// Package electrum implements utxobackend.Backend over the Electrum
+// JSON-RPC protocol spoken by electrs, Fulcrum and ElectrumX.
+package electrum
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/vultisig/mcp/internal/utxo"
+	"github.com/vultisig/mcp/internal/utxobackend"
+)
+
+const (
+	defaultTimeout  = 30 * time.Second
+	clientName      = "vultisig-mcp"
+	protocolVersion = "1.4"
+)
+
+// RPCError is an error returned by the Electrum server.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("electrum: %s (code %d)", e.Message, e.Code)
+}
+
+// Client talks to one Electrum server over a persistent TCP or TLS
+// connection. Requests are serialized; a broken connection is redialled on
+// the next request.
+type Client struct {
+	addr   string
+	useTLS bool
+
+	mu     sync.Mutex
+	conn   net.Conn
+	rd     *bufio.Reader
+	nextID int
+}
+
+var _ utxobackend.Backend = (*Client)(nil)
+
+// NewClient creates a client for rawURL, given as tcp://host:port or
+// ssl://host:port (tls:// is accepted as an alias). The connection is opened
+// on the first request.
+func NewClient(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse electrum url: %w", err)
+	}
+	if u.Host == "" || u.Port() == "" {
+		return nil, fmt.Errorf("electrum url %q must include host and port", rawURL)
+	}
+
+	c := &Client{addr: u.Host}
+	switch u.Scheme {
+	case "tcp":
+	case "ssl", "tls":
+		c.useTLS = true
+	default:
+		return nil, fmt.Errorf("electrum url %q: unsupported scheme %q (expected tcp or ssl)", rawURL, u.Scheme)
+	}
+	return c, nil
+}
+
+// Close closes the connection, if one is open.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked()
+}
+
+func (c *Client) closeLocked() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	c.rd = nil
+	return err
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type response struct {
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// call sends one request and decodes its result into out. A request that
+// fails on a reused connection is retried once on a fresh one.
+func (c *Client) call(ctx context.Context, method string, params []any, out any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reused := c.conn != nil
+	result, err := c.roundTrip(ctx, method, params)
+	var rpcErr *RPCError
+	if err != nil && !errors.As(err, &rpcErr) && reused && ctx.Err() == nil {
+		result, err = c.roundTrip(ctx, method, params)
+	}
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	err = json.Unmarshal(result, out)
+	if err != nil {
+		return fmt.Errorf("electrum: decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+func (c *Client) roundTrip(ctx context.Context, method string, params []any) (json.RawMessage, error) {
+	if c.conn == nil {
+		err := c.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result, err := c.send(ctx, method, params)
+	var rpcErr *RPCError
+	if err != nil && !errors.As(err, &rpcErr) {
+		_ = c.closeLocked()
+	}
+	return result, err
+}
+
+// connect dials the server and negotiates the protocol version.
+func (c *Client) connect(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: defaultTimeout}
+	var conn net.Conn
+	var err error
+	if c.useTLS {
+		host, _, _ := net.SplitHostPort(c.addr)
+		td := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}
+		conn, err = td.DialContext(ctx, "tcp", c.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", c.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("electrum: dial %s: %w", c.addr, err)
+	}
+	c.conn = conn
+	c.rd = bufio.NewReader(conn)
+
+	_, err = c.send(ctx, "server.version", []any{clientName, protocolVersion})
+	if err != nil {
+		_ = c.closeLocked()
+		return fmt.Errorf("electrum: handshake: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) send(ctx context.Context, method string, params []any) (json.RawMessage, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	err := c.conn.SetDeadline(deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	c.nextID++
+	id := c.nextID
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(request{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.conn.Write(append(body, '\n'))
+	if err != nil {
+		return nil, fmt.Errorf("electrum: write %s: %w", method, err)
+	}
+
+	for {
+		line, err := c.rd.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("electrum: read %s: %w", method, err)
+		}
+		var resp response
+		err = json.Unmarshal(line, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("electrum: decode %s response: %w", method, err)
+		}
+		// Skip subscription notifications and stale replies.
+		if resp.ID == nil || *resp.ID != id {
+			continue
+		}
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		return resp.Result, nil
+	}
+}
+
+// scriptHash is the Electrum index key for an output script: its SHA-256
+// in reversed byte order.
+func scriptHash(script []byte) string {
+	h := sha256.Sum256(script)
+	slices.Reverse(h[:])
+	return hex.EncodeToString(h[:])
+}
+
+func addressScriptHash(chain, address string) (string, error) {
+	script, err := utxo.AddressScript(chain, address)
+	if err != nil {
+		return "", err
+	}
+	return scriptHash(script), nil
+}
+
+type unspentEntry struct {
+	TxHash string `json:"tx_hash"`
+	TxPos  int    `json:"tx_pos"`
+	Height int64  `json:"height"`
+	Value  int64  `json:"value"`
+}
+
+type historyEntry struct {
+	TxHash string `json:"tx_hash"`
+	Height int64  `json:"height"`
+}
+
+type balanceResult struct {
+	Confirmed   int64 `json:"confirmed"`
+	Unconfirmed int64 `json:"unconfirmed"`
+}
+
+type headerResult struct {
+	Height int64 `json:"height"`
+}
+
+// blockID converts an Electrum height, which is 0 or -1 in the mempool, to
+// the utxobackend convention of -1 for unconfirmed.
+func blockID(height int64) int64 {
+	if height <= 0 {
+		return -1
+	}
+	return height
+}
+
+// GetUTXOs lists the unspent outputs of address.
+func (c *Client) GetUTXOs(ctx context.Context, chain, address string) ([]utxobackend.UTXO, error) {
+	sh, err := addressScriptHash(chain, address)
+	if err != nil {
+		return nil, err
+	}
+	var unspent []unspentEntry
+	err = c.call(ctx, "blockchain.scripthash.listunspent", []any{sh}, &unspent)
+	if err != nil {
+		return nil, err
+	}
+
+	utxos := make([]utxobackend.UTXO, len(unspent))
+	for i, u := range unspent {
+		utxos[i] = utxobackend.UTXO{
+			BlockID:         blockID(u.Height),
+			TransactionHash: u.TxHash,
+			Index:           u.TxPos,
+			Value:           u.Value,
+		}
+	}
+	return utxos, nil
+}
+
+// GetAddressDashboard assembles a dashboard from the balance, history and
+// unspent outputs of address. Electrum servers carry no prices, so
+// BalanceUSD is always zero.
+func (c *Client) GetAddressDashboard(ctx context.Context, chain, address string) (*utxobackend.AddressDashboard, error) {
+	sh, err := addressScriptHash(chain, address)
+	if err != nil {
+		return nil, err
+	}
+
+	var balance balanceResult
+	err = c.call(ctx, "blockchain.scripthash.get_balance", []any{sh}, &balance)
+	if err != nil {
+		return nil, err
+	}
+	var history []historyEntry
+	err = c.call(ctx, "blockchain.scripthash.get_history", []any{sh}, &history)
+	if err != nil {
+		return nil, err
+	}
+	utxos, err := c.GetUTXOs(ctx, chain, address)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := c.tipHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// History is oldest first; dashboards list the newest first.
+	txs := make([]string, len(history))
+	for i, h := range history {
+		txs[len(history)-1-i] = h.TxHash
+	}
+
+	return &utxobackend.AddressDashboard{
+		Address: utxobackend.AddressInfo{
+			Balance:            balance.Confirmed + balance.Unconfirmed,
+			UnspentOutputCount: len(utxos),
+			TransactionCount:   len(history),
+		},
+		Transactions: txs,
+		UTXOs:        utxos,
+		Height:       tip,
+	}, nil
+}
+
+func (c *Client) tipHeight(ctx context.Context) (int64, error) {
+	var header headerResult
+	err := c.call(ctx, "blockchain.headers.subscribe", nil, &header)
+	if err != nil {
+		return 0, err
+	}
+	return header.Height, nil
+}
+
+// GetRawTransaction fetches the serialized transaction txHash.
+func (c *Client) GetRawTransaction(ctx context.Context, chain, txHash string) ([]byte, error) {
+	var rawHex string
+	err := c.call(ctx, "blockchain.transaction.get", []any{txHash}, &rawHex)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(rawHex)
+	if err != nil {
+		return nil, fmt.Errorf("electrum: decode raw tx hex: %w", err)
+	}
+	return raw, nil
+}
+
+// GetTxDashboard derives a transaction's fee from its previous outputs and
+// its height from the history of one of its output scripts. It returns nil,
+// nil if the server does not know the transaction.
+func (c *Client) GetTxDashboard(ctx context.Context, chain, txHash string) (*utxobackend.TxDashboard, error) {
+	if chain == "Zcash" {
+		return nil, errors.New("electrum: Zcash transactions are not supported")
+	}
+
+	tx, err := c.transaction(ctx, chain, txHash)
+	if err != nil {
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	dash := &utxobackend.TxDashboard{BlockID: -1}
+	for _, out := range tx.TxOut {
+		dash.OutputTotal += out.Value
+	}
+	if !isCoinbase(tx) {
+		for _, in := range tx.TxIn {
+			prev, err := c.transaction(ctx, chain, in.PreviousOutPoint.Hash.String())
+			if err != nil {
+				return nil, fmt.Errorf("fetch input %s: %w", in.PreviousOutPoint, err)
+			}
+			if int(in.PreviousOutPoint.Index) >= len(prev.TxOut) {
+				return nil, fmt.Errorf("input %s: previous transaction has no such output", in.PreviousOutPoint)
+			}
+			dash.InputTotal += prev.TxOut[in.PreviousOutPoint.Index].Value
+		}
+		dash.Fee = dash.InputTotal - dash.OutputTotal
+	}
+
+	height, err := c.txHeight(ctx, tx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if height > 0 {
+		tip, err := c.tipHeight(ctx)
+		if err != nil {
+			return nil, err
+		}
+		dash.BlockID = height
+		dash.Confirmations = int(max(tip-height+1, 1))
+	}
+	return dash, nil
+}
+
+func (c *Client) transaction(ctx context.Context, chain, txHash string) (*wire.MsgTx, error) {
+	raw, err := c.GetRawTransaction(ctx, chain, txHash)
+	if err != nil {
+		return nil, err
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	err = tx.Deserialize(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("electrum: decode transaction %s: %w", txHash, err)
+	}
+	return tx, nil
+}
+
+// txHeight looks tx up in the history of its first spendable output script.
+// It returns 0 while the transaction is unconfirmed.
+func (c *Client) txHeight(ctx context.Context, tx *wire.MsgTx, txHash string) (int64, error) {
+	for _, out := range tx.TxOut {
+		if txscript.IsNullData(out.PkScript) {
+			continue
+		}
+		var history []historyEntry
+		err := c.call(ctx, "blockchain.scripthash.get_history", []any{scriptHash(out.PkScript)}, &history)
+		if err != nil {
+			return 0, err
+		}
+		for _, h := range history {
+			if h.TxHash == txHash {
+				return max(h.Height, 0), nil
+			}
+		}
+		return 0, nil
+	}
+	return 0, nil
+}
+
+func isCoinbase(tx *wire.MsgTx) bool {
+	if len(tx.TxIn) != 1 {
+		return false
+	}
+	prev := tx.TxIn[0].PreviousOutPoint
+	return prev.Index == wire.MaxPrevOutIndex && prev.Hash == (chainhash.Hash{})
+}
+
+// BroadcastTransaction submits a signed transaction and returns its hash.
+func (c *Client) BroadcastTransaction(ctx context.Context, chain string, rawTx []byte) (string, error) {
+	var txid string
+	err := c.call(ctx, "blockchain.transaction.broadcast", []any{hex.EncodeToString(rawTx)}, &txid)
+	if err != nil {
+		return "", err
+	}
+	return txid, nil
+}
@@ -0,0 +1,344 @@
+package electrum
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/vultisig/mcp/internal/utxo"
+)
+
+const testAddress = "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+
+type handlerFunc func(method string, params []json.RawMessage) (any, *RPCError)
+
+// fakeServer is a line-delimited JSON-RPC server speaking enough of the
+// Electrum protocol for the client tests.
+type fakeServer struct {
+	t       *testing.T
+	ln      net.Listener
+	handler handlerFunc
+
+	mu      sync.Mutex
+	methods []string
+	// dropAfter closes each connection after this many replies; zero never.
+	dropAfter int
+}
+
+func newFakeServer(t *testing.T, handler handlerFunc) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeServer{t: t, ln: ln, handler: handler}
+	t.Cleanup(func() { _ = ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	sc := bufio.NewScanner(conn)
+	replies := 0
+	for sc.Scan() {
+		var req struct {
+			ID     int               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.methods = append(s.methods, req.Method)
+		dropAfter := s.dropAfter
+		s.mu.Unlock()
+
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if req.Method == "server.version" {
+			resp["result"] = []string{"fake 1.0", protocolVersion}
+		} else {
+			result, rpcErr := s.handler(req.Method, req.Params)
+			if rpcErr != nil {
+				resp["error"] = rpcErr
+			} else {
+				resp["result"] = result
+			}
+		}
+		// An unsolicited notification must be skipped by the client.
+		_, _ = conn.Write([]byte(`{"jsonrpc":"2.0","method":"blockchain.headers.subscribe","params":[{"height":1}]}` + "\n"))
+		data, _ := json.Marshal(resp)
+		if _, err := conn.Write(append(data, '\n')); err != nil {
+			return
+		}
+		replies++
+		if dropAfter > 0 && replies >= dropAfter {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.methods...)
+}
+
+func (s *fakeServer) client(t *testing.T) *Client {
+	t.Helper()
+	c, err := NewClient("tcp://" + s.ln.Addr().String())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func stringParam(t *testing.T, params []json.RawMessage, i int) string {
+	t.Helper()
+	var s string
+	if i >= len(params) || json.Unmarshal(params[i], &s) != nil {
+		t.Fatalf("param %d missing in %s", i, params)
+	}
+	return s
+}
+
+func TestNewClient(t *testing.T) {
+	for _, tc := range []struct {
+		url     string
+		wantTLS bool
+		wantErr bool
+	}{
+		{url: "tcp://electrum.example:50001"},
+		{url: "ssl://electrum.example:50002", wantTLS: true},
+		{url: "tls://electrum.example:50002", wantTLS: true},
+		{url: "http://electrum.example:50001", wantErr: true},
+		{url: "tcp://electrum.example", wantErr: true},
+	} {
+		c, err := NewClient(tc.url)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tc.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.url, err)
+			continue
+		}
+		if c.useTLS != tc.wantTLS {
+			t.Errorf("%s: useTLS = %v, want %v", tc.url, c.useTLS, tc.wantTLS)
+		}
+	}
+}
+
+func TestScriptHash(t *testing.T) {
+	// Example from the Electrum protocol documentation.
+	script, _ := hex.DecodeString("76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac")
+	want := "8b01df4e368ea28f8dc0423bcf7a4923e3a12d307c875e47a0cfbf90b5c39161"
+	if got := scriptHash(script); got != want {
+		t.Errorf("scriptHash = %s, want %s", got, want)
+	}
+}
+
+func TestGetAddressDashboard(t *testing.T) {
+	script, err := utxo.AddressScript("Bitcoin", testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHash := scriptHash(script)
+
+	srv := newFakeServer(t, func(method string, params []json.RawMessage) (any, *RPCError) {
+		if method != "blockchain.headers.subscribe" && stringParam(t, params, 0) != wantHash {
+			t.Errorf("%s called with wrong script hash", method)
+		}
+		switch method {
+		case "blockchain.scripthash.get_balance":
+			return map[string]int64{"confirmed": 70_000, "unconfirmed": 5_000}, nil
+		case "blockchain.scripthash.get_history":
+			return []map[string]any{
+				{"tx_hash": "aa", "height": 800_000},
+				{"tx_hash": "bb", "height": 800_050},
+				{"tx_hash": "cc", "height": 0},
+			}, nil
+		case "blockchain.scripthash.listunspent":
+			return []map[string]any{
+				{"tx_hash": "aa", "tx_pos": 1, "height": 800_000, "value": 70_000},
+				{"tx_hash": "cc", "tx_pos": 0, "height": 0, "value": 5_000},
+			}, nil
+		case "blockchain.headers.subscribe":
+			return map[string]any{"height": 800_100, "hex": ""}, nil
+		}
+		return nil, &RPCError{Code: -32601, Message: "unknown method " + method}
+	})
+	c := srv.client(t)
+
+	dash, err := c.GetAddressDashboard(context.Background(), "Bitcoin", testAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dash.Address.Balance != 75_000 || dash.Address.UnspentOutputCount != 2 || dash.Address.TransactionCount != 3 {
+		t.Errorf("address = %+v", dash.Address)
+	}
+	if dash.Height != 800_100 {
+		t.Errorf("height = %d, want 800100", dash.Height)
+	}
+	if len(dash.Transactions) != 3 || dash.Transactions[0] != "cc" || dash.Transactions[2] != "aa" {
+		t.Errorf("transactions = %v, want newest first", dash.Transactions)
+	}
+	if len(dash.UTXOs) != 2 || dash.UTXOs[0].BlockID != 800_000 || dash.UTXOs[1].BlockID != -1 {
+		t.Errorf("utxos = %+v", dash.UTXOs)
+	}
+	if dash.UTXOs[0].TransactionHash != "aa" || dash.UTXOs[0].Index != 1 || dash.UTXOs[0].Value != 70_000 {
+		t.Errorf("utxo[0] = %+v", dash.UTXOs[0])
+	}
+
+	calls := srv.calls()
+	if len(calls) == 0 || calls[0] != "server.version" {
+		t.Errorf("calls = %v, want server.version first", calls)
+	}
+}
+
+func TestGetAddressDashboardBadAddress(t *testing.T) {
+	c, _ := NewClient("tcp://127.0.0.1:1")
+	_, err := c.GetAddressDashboard(context.Background(), "Litecoin", testAddress)
+	if err == nil {
+		t.Error("expected error for a Bitcoin address on Litecoin")
+	}
+}
+
+func serializeTx(t *testing.T, tx *wire.MsgTx) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(buf.Bytes())
+}
+
+func TestGetTxDashboard(t *testing.T) {
+	outScript, _ := utxo.AddressScript("Bitcoin", testAddress)
+
+	prev := wire.NewMsgTx(2)
+	prev.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}, nil, nil))
+	prev.AddTxOut(wire.NewTxOut(30_000, outScript))
+	prev.AddTxOut(wire.NewTxOut(80_000, outScript))
+	prevHash := prev.TxHash()
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: prevHash, Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(0, []byte{0x6a, 0x01, 0x00}))
+	tx.AddTxOut(wire.NewTxOut(78_500, outScript))
+	txHash := tx.TxHash().String()
+
+	raws := map[string]string{
+		prevHash.String(): serializeTx(t, prev),
+		txHash:            serializeTx(t, tx),
+	}
+	height := int64(800_095)
+
+	srv := newFakeServer(t, func(method string, params []json.RawMessage) (any, *RPCError) {
+		switch method {
+		case "blockchain.transaction.get":
+			raw, ok := raws[stringParam(t, params, 0)]
+			if !ok {
+				return nil, &RPCError{Code: 2, Message: "missing transaction"}
+			}
+			return raw, nil
+		case "blockchain.scripthash.get_history":
+			if stringParam(t, params, 0) != scriptHash(outScript) {
+				t.Errorf("history requested for the wrong script")
+			}
+			return []map[string]any{{"tx_hash": txHash, "height": height}}, nil
+		case "blockchain.headers.subscribe":
+			return map[string]any{"height": 800_100}, nil
+		}
+		return nil, &RPCError{Code: -32601, Message: "unknown method " + method}
+	})
+	c := srv.client(t)
+
+	dash, err := c.GetTxDashboard(context.Background(), "Bitcoin", txHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dash.InputTotal != 80_000 || dash.OutputTotal != 78_500 || dash.Fee != 1_500 {
+		t.Errorf("totals = %+v", dash)
+	}
+	if dash.BlockID != 800_095 || dash.Confirmations != 6 {
+		t.Errorf("block %d confirmations %d, want 800095 and 6", dash.BlockID, dash.Confirmations)
+	}
+
+	height = 0
+	dash, err = c.GetTxDashboard(context.Background(), "Bitcoin", txHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dash.BlockID != -1 || dash.Confirmations != 0 {
+		t.Errorf("mempool tx: block %d confirmations %d", dash.BlockID, dash.Confirmations)
+	}
+
+	dash, err = c.GetTxDashboard(context.Background(), "Bitcoin", "ff")
+	if err != nil || dash != nil {
+		t.Errorf("unknown tx = %+v, %v; want nil, nil", dash, err)
+	}
+}
+
+func TestBroadcastTransaction(t *testing.T) {
+	srv := newFakeServer(t, func(method string, params []json.RawMessage) (any, *RPCError) {
+		if method != "blockchain.transaction.broadcast" {
+			return nil, &RPCError{Code: -32601, Message: "unknown method"}
+		}
+		if stringParam(t, params, 0) == "00" {
+			return nil, &RPCError{Code: 1, Message: "the transaction was rejected by network rules"}
+		}
+		return "abcd", nil
+	})
+	c := srv.client(t)
+
+	txid, err := c.BroadcastTransaction(context.Background(), "Bitcoin", []byte{0x02, 0x00})
+	if err != nil || txid != "abcd" {
+		t.Errorf("broadcast = %q, %v", txid, err)
+	}
+	_, err = c.BroadcastTransaction(context.Background(), "Bitcoin", []byte{0x00})
+	var rpcErr *RPCError
+	if err == nil || !errors.As(err, &rpcErr) || rpcErr.Code != 1 {
+		t.Errorf("err = %v, want rejection RPCError", err)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	srv := newFakeServer(t, func(method string, params []json.RawMessage) (any, *RPCError) {
+		return map[string]any{"height": 800_100}, nil
+	})
+	// Close after the handshake and one reply, so every second call finds
+	// a dead connection.
+	srv.dropAfter = 2
+	c := srv.client(t)
+
+	for i := range 3 {
+		h, err := c.tipHeight(context.Background())
+		if err != nil || h != 800_100 {
+			t.Fatalf("call %d: height %d, err %v", i, h, err)
+		}
+	}
+}
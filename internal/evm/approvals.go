@@ -0,0 +1,68 @@
+package evm
+
+import (
+	"math/big"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ApprovalTopic is the ERC-20 and ERC-721 Approval event. ERC-20 logs
+	// have three topics; ERC-721 logs index the token ID as a fourth.
+	ApprovalTopic = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+	// ApprovalForAllTopic is the ERC-721 and ERC-1155 operator approval event.
+	ApprovalForAllTopic = crypto.Keccak256Hash([]byte("ApprovalForAll(address,address,bool)"))
+)
+
+// UnlimitedAllowance is the threshold above which an allowance is treated as
+// unlimited. It is the uint96 maximum, which tokens such as UNI and COMP use
+// in place of the uint256 maximum.
+var UnlimitedAllowance = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 96), big.NewInt(1))
+
+// PackERC20Approve returns the calldata for approve(spender, amount).
+func PackERC20Approve(spender ethcommon.Address, amount *big.Int) []byte {
+	return erc20Codec.PackApprove(spender, amount)
+}
+
+// PackSetApprovalForAll returns the calldata for
+// setApprovalForAll(operator, approved).
+func PackSetApprovalForAll(operator ethcommon.Address, approved bool) []byte {
+	// setApprovalForAll(address,bool) selector = 0xa22cb465
+	data := make([]byte, 4+32+32)
+	copy(data, []byte{0xa2, 0x2c, 0xb4, 0x65})
+	copy(data[4+12:4+32], operator.Bytes())
+	if approved {
+		data[4+63] = 1
+	}
+	return data
+}
+
+// knownSpenders labels widely used approval targets. Most are deployed at
+// the same address on every chain.
+var knownSpenders = map[string]string{
+	"0x000000000022d473030f116ddee9f6b43ac78ba3": "Uniswap Permit2",
+	"0x7a250d5630b4cf539739df2c5dacb4c659f2488d": "Uniswap V2 Router",
+	"0xe592427a0aece92de3edee1f18e0157c05861564": "Uniswap V3 SwapRouter",
+	"0x68b3465833fb72a70ecdf485e0e4c7bd8665fc45": "Uniswap SwapRouter02",
+	"0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad": "Uniswap Universal Router",
+	"0x66a9893cc07d91d95644aedd05d03f95e1dba8af": "Uniswap V4 Universal Router",
+	"0x1111111254eeb25477b68fb85ed929f73a960582": "1inch Aggregation Router V5",
+	"0x111111125421ca6dc452d289314280a0f8842a65": "1inch Aggregation Router V6",
+	"0xdef1c0ded9bec7f1a1670819833240f027b25eff": "0x Exchange Proxy",
+	"0x1231deb6f5749ef6ce6943a275a1d3e7486f4eae": "LI.FI Diamond",
+	"0x10ed43c718714eb63d5aa57b78b54704e256024e": "PancakeSwap V2 Router",
+	"0x13f4ea83d0bd40e75c8222255bc855a974568dd4": "PancakeSwap Smart Router",
+	"0xd37bbe5744d730a1d98d8dc97c42f0ca46ad7146": "THORChain Router",
+	"0x87870bca3f3fd6335c3f4ce8392d69350b4fa4e2": "Aave V3 Pool",
+	"0x00000000000000adc04c56bf30ac9d3c0aaf14dc": "OpenSea Seaport 1.5",
+	"0x0000000000000068f116a894984e2db1123eb395": "OpenSea Seaport 1.6",
+	"0x1e0049783f008a0085193e00003d00cd54003c71": "OpenSea Conduit",
+}
+
+// SpenderLabel returns a human-readable name for a well-known spender, or ""
+// if the address is not known.
+func SpenderLabel(addr ethcommon.Address) string {
+	return knownSpenders[strings.ToLower(addr.Hex())]
+}
@@ -1,6 +1,9 @@
 package evm
 
-import "math/big"
+import (
+	"math/big"
+	"strings"
+)
 
 // EVMChains is the ordered list of supported EVM chain names.
 // These names match the values used in get_address and vultisig-go.
@@ -21,6 +24,13 @@ type chainConfig struct {
 	defaultRPCURL string
 	chainID       int64
 	ticker        string
+	explorerURL   string
+	// platform and coinGeckoID are only set for chains added with
+	// RegisterChain; built-in chains are mapped in the tools package.
+	platform    string
+	coinGeckoID string
+	// feeModel is one of the FeeModel constants; empty means FeeModelL1.
+	feeModel string
 }
 
 var chainDefaults = map[string]chainConfig{
@@ -28,51 +38,67 @@ var chainDefaults = map[string]chainConfig{
 		defaultRPCURL: "https://ethereum-rpc.publicnode.com",
 		chainID:       1,
 		ticker:        "ETH",
+		explorerURL:   "https://etherscan.io",
 	},
 	"BSC": {
 		defaultRPCURL: "https://bsc-rpc.publicnode.com",
 		chainID:       56,
 		ticker:        "BNB",
+		explorerURL:   "https://bscscan.com",
 	},
 	"Polygon": {
 		defaultRPCURL: "https://polygon-bor-rpc.publicnode.com",
 		chainID:       137,
 		ticker:        "POL",
+		explorerURL:   "https://polygonscan.com",
 	},
 	"Avalanche": {
 		defaultRPCURL: "https://avalanche-c-chain-rpc.publicnode.com",
 		chainID:       43114,
 		ticker:        "AVAX",
+		explorerURL:   "https://snowtrace.io",
 	},
 	"Arbitrum": {
 		defaultRPCURL: "https://arbitrum-one-rpc.publicnode.com",
 		chainID:       42161,
 		ticker:        "ETH",
+		explorerURL:   "https://arbiscan.io",
+		feeModel:      FeeModelArbitrum,
 	},
 	"Optimism": {
 		defaultRPCURL: "https://optimism-rpc.publicnode.com",
 		chainID:       10,
 		ticker:        "ETH",
+		explorerURL:   "https://optimistic.etherscan.io",
+		feeModel:      FeeModelOPStack,
 	},
 	"Base": {
 		defaultRPCURL: "https://base-rpc.publicnode.com",
 		chainID:       8453,
 		ticker:        "ETH",
+		explorerURL:   "https://basescan.org",
+		feeModel:      FeeModelOPStack,
 	},
 	"Blast": {
 		defaultRPCURL: "https://blast-rpc.publicnode.com",
 		chainID:       81457,
 		ticker:        "ETH",
+		explorerURL:   "https://blastscan.io",
+		feeModel:      FeeModelOPStack,
 	},
 	"Mantle": {
 		defaultRPCURL: "https://mantle-rpc.publicnode.com",
 		chainID:       5000,
 		ticker:        "MNT",
+		explorerURL:   "https://mantlescan.xyz",
+		feeModel:      FeeModelOPStack,
 	},
 	"Zksync": {
 		defaultRPCURL: "https://mainnet.era.zksync.io",
 		chainID:       324,
 		ticker:        "ETH",
+		explorerURL:   "https://era.zksync.network",
+		feeModel:      FeeModelZkSync,
 	},
 }
 
@@ -102,3 +128,23 @@ func NativeTicker(chainName string) string {
 	}
 	return cfg.ticker
 }
+
+// ExplorerTxURL returns the block explorer link for a transaction on a
+// chain, or "" if the chain has no explorer.
+func ExplorerTxURL(chainName, txHash string) string {
+	cfg, ok := chainDefaults[chainName]
+	if !ok || cfg.explorerURL == "" {
+		return ""
+	}
+	return strings.TrimRight(cfg.explorerURL, "/") + "/tx/" + txHash
+}
+
+// ChainNameByID returns the EVM chain name for a chain ID.
+func ChainNameByID(chainID *big.Int) (string, bool) {
+	for _, name := range EVMChains {
+		if chainID.Cmp(big.NewInt(chainDefaults[name].chainID)) == 0 {
+			return name, true
+		}
+	}
+	return "", false
+}
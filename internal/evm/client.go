@@ -11,29 +11,41 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/vultisig/recipes/sdk/evm/codegen/erc20"
+
+	"github.com/vultisig/mcp/internal/tokenmeta"
 )
 
 var erc20Codec = erc20.NewErc20()
 
+// PackERC20Transfer returns the calldata for transfer(to, amount).
+func PackERC20Transfer(to ethcommon.Address, amount *big.Int) []byte {
+	return erc20Codec.PackTransfer(to, amount)
+}
+
 // TokenBalance holds the result of a token balance query.
 type TokenBalance struct {
 	Balance  string
+	Raw      *big.Int
 	Symbol   string
 	Decimals uint8
 }
 
 // Client wraps an Ethereum JSON-RPC client.
 type Client struct {
-	eth    *ethclient.Client
-	rawRPC *rpc.Client
+	eth       *ethclient.Client
+	rawRPC    *rpc.Client
+	multicall multicallState
+	tokens    *tokenmeta.Chain
 }
 
+// NewClient dials rpcURL. Token metadata is cached in memory for this
+// client alone; Pool clients share the pool's registry instead.
 func NewClient(rpcURL string) (*Client, error) {
 	eth, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("dial evm rpc: %w", err)
 	}
-	return &Client{eth: eth, rawRPC: eth.Client()}, nil
+	return &Client{eth: eth, rawRPC: eth.Client(), tokens: tokenmeta.NewMemoryRegistry().Chain("")}, nil
 }
 
 func (c *Client) Close() {
@@ -61,55 +73,35 @@ func (c *Client) GetNativeBalance(ctx context.Context, addr string) (string, err
 }
 
 // GetTokenBalance returns the ERC-20 token balance, symbol, and decimals.
+// Uncached metadata is read in the same batch as the balance. Tokens
+// without decimals() are an error.
 func (c *Client) GetTokenBalance(ctx context.Context, tokenAddr, holderAddr string) (*TokenBalance, error) {
-	token := ethcommon.HexToAddress(tokenAddr)
 	holder := ethcommon.HexToAddress(holderAddr)
 
-	decimalsData, err := c.eth.CallContract(ctx, ethereum.CallMsg{
-		To:   &token,
-		Data: erc20Codec.PackDecimals(),
-	}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("call decimals(): %w", err)
-	}
-	decimals, err := erc20Codec.UnpackDecimals(decimalsData)
-	if err != nil {
-		return nil, fmt.Errorf("decode decimals: %w", err)
-	}
-
-	symbolData, err := c.eth.CallContract(ctx, ethereum.CallMsg{
-		To:   &token,
-		Data: erc20Codec.PackSymbol(),
-	}, nil)
+	out, meta, err := c.tokenReads(ctx, ethcommon.HexToAddress(tokenAddr),
+		erc20Read{"balanceOf", erc20Codec.PackBalanceOf(holder)},
+	)
 	if err != nil {
-		return nil, fmt.Errorf("call symbol(): %w", err)
+		return nil, err
 	}
-	symbol, err := DecodeABIString(symbolData)
-	if err != nil {
-		return nil, fmt.Errorf("decode symbol: %w", err)
+	if err := requireDecimals(tokenAddr, meta); err != nil {
+		return nil, err
 	}
-
-	balanceData, err := c.eth.CallContract(ctx, ethereum.CallMsg{
-		To:   &token,
-		Data: erc20Codec.PackBalanceOf(holder),
-	}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("call balanceOf(): %w", err)
-	}
-	balance, err := erc20Codec.UnpackBalanceOf(balanceData)
+	balance, err := erc20Codec.UnpackBalanceOf(out[0])
 	if err != nil {
 		return nil, fmt.Errorf("decode balanceOf: %w", err)
 	}
 
 	return &TokenBalance{
-		Balance:  FormatUnits(balance, int(decimals)),
-		Symbol:   symbol,
-		Decimals: decimals,
+		Balance:  FormatUnits(balance, int(meta.Decimals)),
+		Raw:      balance,
+		Symbol:   meta.Symbol,
+		Decimals: meta.Decimals,
 	}, nil
 }
 
-// GetAllowance returns the ERC-20 token allowance for a spender.
-func (c *Client) GetAllowance(ctx context.Context, tokenAddr, ownerAddr, spenderAddr string) (*big.Int, uint8, string, error) {
+// Allowance returns the raw ERC-20 allowance of spender over owner's tokens.
+func (c *Client) Allowance(ctx context.Context, tokenAddr, ownerAddr, spenderAddr string) (*big.Int, error) {
 	token := ethcommon.HexToAddress(tokenAddr)
 	owner := ethcommon.HexToAddress(ownerAddr)
 	spender := ethcommon.HexToAddress(spenderAddr)
@@ -119,38 +111,46 @@ func (c *Client) GetAllowance(ctx context.Context, tokenAddr, ownerAddr, spender
 		Data: erc20Codec.PackAllowance(owner, spender),
 	}, nil)
 	if err != nil {
-		return nil, 0, "", fmt.Errorf("call allowance(): %w", err)
+		return nil, fmt.Errorf("call allowance(): %w", err)
 	}
 	allowance, err := erc20Codec.UnpackAllowance(allowanceData)
 	if err != nil {
-		return nil, 0, "", fmt.Errorf("decode allowance: %w", err)
+		return nil, fmt.Errorf("decode allowance: %w", err)
 	}
+	return allowance, nil
+}
 
-	decimalsData, err := c.eth.CallContract(ctx, ethereum.CallMsg{
-		To:   &token,
-		Data: erc20Codec.PackDecimals(),
-	}, nil)
-	if err != nil {
-		return nil, 0, "", fmt.Errorf("call decimals(): %w", err)
-	}
-	decimals, err := erc20Codec.UnpackDecimals(decimalsData)
-	if err != nil {
-		return nil, 0, "", fmt.Errorf("decode decimals: %w", err)
-	}
+// GetAllowance returns the ERC-20 token allowance for a spender with the
+// token's decimals and symbol. Uncached metadata is read in the same batch
+// as the allowance.
+func (c *Client) GetAllowance(ctx context.Context, tokenAddr, ownerAddr, spenderAddr string) (*big.Int, uint8, string, error) {
+	owner := ethcommon.HexToAddress(ownerAddr)
+	spender := ethcommon.HexToAddress(spenderAddr)
 
-	symbolData, err := c.eth.CallContract(ctx, ethereum.CallMsg{
-		To:   &token,
-		Data: erc20Codec.PackSymbol(),
-	}, nil)
+	out, meta, err := c.tokenReads(ctx, ethcommon.HexToAddress(tokenAddr),
+		erc20Read{"allowance", erc20Codec.PackAllowance(owner, spender)},
+	)
 	if err != nil {
-		return nil, 0, "", fmt.Errorf("call symbol(): %w", err)
+		return nil, 0, "", err
 	}
-	symbol, err := DecodeABIString(symbolData)
+	if err := requireDecimals(tokenAddr, meta); err != nil {
+		return nil, 0, "", err
+	}
+	allowance, err := erc20Codec.UnpackAllowance(out[0])
 	if err != nil {
-		return nil, 0, "", fmt.Errorf("decode symbol: %w", err)
+		return nil, 0, "", fmt.Errorf("decode allowance: %w", err)
 	}
+	return allowance, meta.Decimals, meta.Symbol, nil
+}
 
-	return allowance, decimals, symbol, nil
+// PackERC20Symbol returns the calldata for symbol().
+func PackERC20Symbol() []byte {
+	return erc20Codec.PackSymbol()
+}
+
+// PackERC20Allowance returns the calldata for allowance(owner, spender).
+func PackERC20Allowance(owner, spender ethcommon.Address) []byte {
+	return erc20Codec.PackAllowance(owner, spender)
 }
 
 // ChainID returns the chain ID of the connected network.
@@ -173,6 +173,11 @@ func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
 	return c.eth.SuggestGasTipCap(ctx)
 }
 
+// SuggestGasPrice returns the suggested legacy gas price.
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.eth.SuggestGasPrice(ctx)
+}
+
 // LatestBaseFee returns the base fee from the latest block header.
 func (c *Client) LatestBaseFee(ctx context.Context) (*big.Int, error) {
 	header, err := c.eth.HeaderByNumber(ctx, nil)
@@ -196,18 +201,9 @@ func (c *Client) IsApprovedForAll(ctx context.Context, contractAddr, ownerAddr,
 	owner := ethcommon.HexToAddress(ownerAddr)
 	operator := ethcommon.HexToAddress(operatorAddr)
 
-	// isApprovedForAll(address,address) selector = 0xe985e9c5
-	data := make([]byte, 4+32+32)
-	data[0] = 0xe9
-	data[1] = 0x85
-	data[2] = 0xe9
-	data[3] = 0xc5
-	copy(data[4+12:4+32], owner.Bytes())
-	copy(data[4+32+12:4+64], operator.Bytes())
-
 	result, err := c.eth.CallContract(ctx, ethereum.CallMsg{
 		To:   &contract,
-		Data: data,
+		Data: PackIsApprovedForAll(owner, operator),
 	}, nil)
 	if err != nil {
 		return false, fmt.Errorf("call isApprovedForAll(): %w", err)
@@ -217,3 +213,14 @@ func (c *Client) IsApprovedForAll(ctx context.Context, contractAddr, ownerAddr,
 	}
 	return new(big.Int).SetBytes(result).Sign() != 0, nil
 }
+
+// PackIsApprovedForAll returns the calldata for
+// isApprovedForAll(owner, operator).
+func PackIsApprovedForAll(owner, operator ethcommon.Address) []byte {
+	// isApprovedForAll(address,address) selector = 0xe985e9c5
+	data := make([]byte, 4+32+32)
+	copy(data, []byte{0xe9, 0x85, 0xe9, 0xc5})
+	copy(data[4+12:4+32], owner.Bytes())
+	copy(data[4+32+12:4+64], operator.Bytes())
+	return data
+}
@@ -0,0 +1,172 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// ChainSpec declares an EVM chain at runtime, e.g. Linea, Gnosis or a
+// local devnet.
+type ChainSpec struct {
+	Name         string `json:"name"`
+	ChainID      int64  `json:"chain_id"`
+	NativeTicker string `json:"native_ticker"`
+	// RPCURLs are tried in order at startup; the first that reports
+	// ChainID is used.
+	RPCURLs []string `json:"rpc_urls"`
+	// CoinGeckoPlatform is the CoinGecko asset-platform ID for token
+	// prices, e.g. "linea".
+	CoinGeckoPlatform string `json:"coingecko_platform,omitempty"`
+	// CoinGeckoID is the CoinGecko coin ID of the native coin for USD
+	// values, e.g. "ethereum" or "xdai".
+	CoinGeckoID string `json:"coingecko_id,omitempty"`
+	ExplorerURL string `json:"explorer_url,omitempty"`
+	// FeeModel is "op_stack", "arbitrum" or "zksync" for rollups whose L1
+	// data fee should be quoted; plain gas pricing if omitted.
+	FeeModel string `json:"fee_model,omitempty"`
+}
+
+// ErrChainIDMismatch is returned by SelectRPC when an RPC serves a
+// different chain than the one declared.
+var ErrChainIDMismatch = errors.New("chain ID mismatch")
+
+// LoadChainSpecs reads a JSON array of chain specs from path and validates
+// them against the built-in chains and each other.
+func LoadChainSpecs(path string) ([]ChainSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read chains file: %w", err)
+	}
+	var specs []ChainSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("parse chains file %s: %w", path, err)
+	}
+
+	names := make(map[string]bool, len(specs))
+	ids := make(map[int64]string, len(specs))
+	for i, spec := range specs {
+		if err := spec.validate(); err != nil {
+			return nil, fmt.Errorf("chains[%d]: %w", i, err)
+		}
+		key := strings.ToLower(spec.Name)
+		if names[key] {
+			return nil, fmt.Errorf("chains[%d]: duplicate chain %q", i, spec.Name)
+		}
+		if other, ok := ids[spec.ChainID]; ok {
+			return nil, fmt.Errorf("chains[%d]: chain ID %d already used by %s", i, spec.ChainID, other)
+		}
+		names[key] = true
+		ids[spec.ChainID] = spec.Name
+	}
+	return specs, nil
+}
+
+func (s ChainSpec) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if s.ChainID <= 0 {
+		return fmt.Errorf("%s: chain_id must be positive", s.Name)
+	}
+	if s.NativeTicker == "" {
+		return fmt.Errorf("%s: native_ticker is required", s.Name)
+	}
+	if len(s.RPCURLs) == 0 {
+		return fmt.Errorf("%s: at least one rpc_urls entry is required", s.Name)
+	}
+	switch s.FeeModel {
+	case "", FeeModelL1, FeeModelOPStack, FeeModelArbitrum, FeeModelZkSync:
+	default:
+		return fmt.Errorf("%s: unknown fee_model %q", s.Name, s.FeeModel)
+	}
+	for _, name := range EVMChains {
+		if strings.EqualFold(name, s.Name) {
+			return fmt.Errorf("%s: chain already defined", s.Name)
+		}
+		if chainDefaults[name].chainID == s.ChainID {
+			return fmt.Errorf("%s: chain ID %d already used by %s", s.Name, s.ChainID, name)
+		}
+	}
+	return nil
+}
+
+// RegisterChain adds a chain to EVMChains and the chain lookups. It must
+// be called at startup, before any tool is registered or client created.
+func RegisterChain(spec ChainSpec) error {
+	if err := spec.validate(); err != nil {
+		return err
+	}
+	EVMChains = append(EVMChains, spec.Name)
+	chainDefaults[spec.Name] = chainConfig{
+		defaultRPCURL: spec.RPCURLs[0],
+		chainID:       spec.ChainID,
+		ticker:        spec.NativeTicker,
+		explorerURL:   spec.ExplorerURL,
+		platform:      spec.CoinGeckoPlatform,
+		coinGeckoID:   spec.CoinGeckoID,
+		feeModel:      spec.FeeModel,
+	}
+	return nil
+}
+
+// SelectRPC returns the first of spec's RPC URLs that answers with the
+// declared chain ID. An RPC serving another chain fails with
+// ErrChainIDMismatch; unreachable ones are skipped.
+func SelectRPC(ctx context.Context, spec ChainSpec) (string, error) {
+	want := big.NewInt(spec.ChainID)
+	var lastErr error
+	for _, url := range spec.RPCURLs {
+		client, err := NewClient(url)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		got, err := client.ChainID(ctx)
+		client.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("%s: get chain ID: %w", url, err)
+			continue
+		}
+		if got.Cmp(want) != 0 {
+			return "", fmt.Errorf("%s: %s reports chain ID %s, declared %d: %w", spec.Name, url, got, spec.ChainID, ErrChainIDMismatch)
+		}
+		return url, nil
+	}
+	return "", fmt.Errorf("%s: no RPC reachable: %w", spec.Name, lastErr)
+}
+
+// CoinGeckoPlatform returns the CoinGecko asset-platform ID declared for a
+// runtime chain.
+func CoinGeckoPlatform(chainName string) (string, bool) {
+	cfg, ok := chainDefaults[chainName]
+	if !ok || cfg.platform == "" {
+		return "", false
+	}
+	return cfg.platform, true
+}
+
+// ChainByCoinGeckoPlatform returns the runtime chain declared with a
+// CoinGecko asset-platform ID.
+func ChainByCoinGeckoPlatform(platform string) (string, bool) {
+	for _, name := range EVMChains {
+		if p := chainDefaults[name].platform; p != "" && p == platform {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// NativeCoinGeckoID returns the CoinGecko coin ID declared for a runtime
+// chain's native coin.
+func NativeCoinGeckoID(chainName string) (string, bool) {
+	cfg, ok := chainDefaults[chainName]
+	if !ok || cfg.coinGeckoID == "" {
+		return "", false
+	}
+	return cfg.coinGeckoID, true
+}
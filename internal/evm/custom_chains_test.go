@@ -0,0 +1,148 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var lineaSpec = ChainSpec{
+	Name:              "Linea",
+	ChainID:           59144,
+	NativeTicker:      "ETH",
+	RPCURLs:           []string{"https://rpc.linea.build"},
+	CoinGeckoPlatform: "linea",
+	CoinGeckoID:       "ethereum",
+	ExplorerURL:       "https://lineascan.build/",
+}
+
+// chainIDServer answers eth_chainId with chainID.
+func chainIDServer(t *testing.T, chainID int64) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x%x"}`, req.ID, chainID)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// registerTestChain registers spec and removes it when the test ends.
+func registerTestChain(t *testing.T, spec ChainSpec) {
+	t.Helper()
+	chains := EVMChains
+	if err := RegisterChain(spec); err != nil {
+		t.Fatalf("RegisterChain: %v", err)
+	}
+	t.Cleanup(func() {
+		EVMChains = chains
+		delete(chainDefaults, spec.Name)
+	})
+}
+
+func TestLoadChainSpecs(t *testing.T) {
+	write := func(specs []ChainSpec) string {
+		data, _ := json.Marshal(specs)
+		path := filepath.Join(t.TempDir(), "chains.json")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	specs, err := LoadChainSpecs(write([]ChainSpec{lineaSpec}))
+	if err != nil {
+		t.Fatalf("LoadChainSpecs: %v", err)
+	}
+	if len(specs) != 1 || specs[0].Name != "Linea" || specs[0].RPCURLs[0] != "https://rpc.linea.build" {
+		t.Errorf("specs %+v", specs)
+	}
+
+	dup := lineaSpec
+	dup.Name = "linea"
+	otherID := lineaSpec
+	otherID.Name = "Linea2"
+	for name, tc := range map[string][]ChainSpec{
+		"built-in name":      {{Name: "ethereum", ChainID: 9999, NativeTicker: "ETH", RPCURLs: []string{"http://x"}}},
+		"built-in chain ID":  {{Name: "Fork", ChainID: 1, NativeTicker: "ETH", RPCURLs: []string{"http://x"}}},
+		"missing rpc_urls":   {{Name: "Fork", ChainID: 9999, NativeTicker: "ETH"}},
+		"missing ticker":     {{Name: "Fork", ChainID: 9999, RPCURLs: []string{"http://x"}}},
+		"unknown fee model":  {{Name: "Fork", ChainID: 9999, NativeTicker: "ETH", RPCURLs: []string{"http://x"}, FeeModel: "scroll"}},
+		"duplicate name":     {lineaSpec, dup},
+		"duplicate chain ID": {lineaSpec, otherID},
+	} {
+		if _, err := LoadChainSpecs(write(tc)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestRegisterChain(t *testing.T) {
+	registerTestChain(t, lineaSpec)
+
+	if id, ok := ChainIDByName("Linea"); !ok || id.Int64() != 59144 {
+		t.Errorf("ChainIDByName = %v, %v", id, ok)
+	}
+	if name, ok := ChainNameByID(big.NewInt(59144)); !ok || name != "Linea" {
+		t.Errorf("ChainNameByID = %q, %v", name, ok)
+	}
+	if EVMChains[len(EVMChains)-1] != "Linea" {
+		t.Errorf("EVMChains = %v", EVMChains)
+	}
+	if p, ok := CoinGeckoPlatform("Linea"); !ok || p != "linea" {
+		t.Errorf("CoinGeckoPlatform = %q, %v", p, ok)
+	}
+	if c, ok := ChainByCoinGeckoPlatform("linea"); !ok || c != "Linea" {
+		t.Errorf("ChainByCoinGeckoPlatform = %q, %v", c, ok)
+	}
+	if got := ExplorerTxURL("Linea", "0xabc"); got != "https://lineascan.build/tx/0xabc" {
+		t.Errorf("ExplorerTxURL = %q", got)
+	}
+	if _, ok := CoinGeckoPlatform("Ethereum"); ok {
+		t.Error("built-in chain has a runtime platform")
+	}
+}
+
+func TestSelectRPC(t *testing.T) {
+	ctx := context.Background()
+	good := chainIDServer(t, 59144)
+	wrong := chainIDServer(t, 1)
+
+	spec := lineaSpec
+	spec.RPCURLs = []string{"http://127.0.0.1:1", good}
+	url, err := SelectRPC(ctx, spec)
+	if err != nil || url != good {
+		t.Errorf("SelectRPC = %q, %v; want %q", url, err, good)
+	}
+
+	spec.RPCURLs = []string{wrong, good}
+	if _, err := SelectRPC(ctx, spec); !errors.Is(err, ErrChainIDMismatch) {
+		t.Errorf("err = %v, want ErrChainIDMismatch", err)
+	}
+
+	spec.RPCURLs = []string{"http://127.0.0.1:1"}
+	if _, err := SelectRPC(ctx, spec); err == nil || errors.Is(err, ErrChainIDMismatch) {
+		t.Errorf("err = %v, want unreachable error", err)
+	}
+}
+
+func TestPool_ChainIDMismatch(t *testing.T) {
+	pool := NewPool(map[string]string{"BSC": chainIDServer(t, 1)})
+	defer pool.Close()
+	_, _, err := pool.Get(context.Background(), "BSC")
+	if err == nil || !strings.Contains(err.Error(), "chain ID") {
+		t.Fatalf("err = %v, want chain ID mismatch", err)
+	}
+}
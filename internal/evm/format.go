@@ -29,6 +29,25 @@ func FormatUnits(wei *big.Int, decimals int) string {
 	return whole.String() + "." + fracStr
 }
 
+// ParseUnits converts a decimal string such as "1.5" into base units with
+// the given number of decimals. Amounts with more fractional digits than
+// decimals are rejected rather than truncated.
+func ParseUnits(amount string, decimals int) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(amount, ".")
+	if whole == "" && frac == "" || strings.Trim(whole+frac, "0123456789") != "" {
+		return nil, fmt.Errorf("invalid amount: %q", amount)
+	}
+	if len(frac) > decimals {
+		return nil, fmt.Errorf("amount %q has more than %d decimal places", amount, decimals)
+	}
+
+	result, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %q", amount)
+	}
+	return result, nil
+}
+
 // DecodeABIString decodes an ABI-encoded string from contract return data.
 // Handles both standard ABI encoding (offset+length+data) and non-standard
 // encodings (e.g. bytes32 left-padded strings like MKR).
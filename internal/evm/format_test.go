@@ -61,6 +61,36 @@ func TestFormatWei(t *testing.T) {
 	}
 }
 
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals int
+		want     string
+	}{
+		{"1.5", 6, "1500000"},
+		{"100", 6, "100000000"},
+		{".25", 2, "25"},
+		{"0.000000000000000001", 18, "1"},
+		{"7", 0, "7"},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnits(tt.amount, tt.decimals)
+		if err != nil {
+			t.Errorf("ParseUnits(%q, %d): %v", tt.amount, tt.decimals, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseUnits(%q, %d) = %s, want %s", tt.amount, tt.decimals, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", ".", "-1", "1e6", "1.2.3", "0.1234567"} {
+		if _, err := ParseUnits(bad, 6); err == nil {
+			t.Errorf("ParseUnits(%q, 6) succeeded, want error", bad)
+		}
+	}
+}
+
 func TestDecodeABIString(t *testing.T) {
 	t.Run("standard ABI encoding", func(t *testing.T) {
 		data := make([]byte, 96)
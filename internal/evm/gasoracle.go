@@ -0,0 +1,149 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// MaxFeeHistoryBlocks caps the eth_feeHistory window; most RPCs reject
+// larger ones.
+const MaxFeeHistoryBlocks = 1024
+
+// Gas used by a plain native transfer and a typical ERC-20 transfer.
+const (
+	GasNativeTransfer = 21_000
+	GasERC20Transfer  = 65_000
+)
+
+// feeTierPercentiles are the eth_feeHistory reward percentiles sampled for
+// the slow, standard and fast tiers.
+var feeTierPercentiles = []float64{10, 50, 90}
+
+// feeTierHeadroom is the max fee's base fee allowance per tier, in percent
+// of the projected base fee. The base fee grows at most 12.5% per block, so
+// slow survives about two full blocks, standard three and fast six.
+var feeTierHeadroom = [3]int64{125, 150, 200}
+
+// FeeTier is an EIP-1559 fee suggestion.
+type FeeTier struct {
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+}
+
+// FeeOracle is a fee suggestion derived from recent blocks.
+type FeeOracle struct {
+	OldestBlock uint64
+	LatestBlock uint64
+	Blocks      int
+	// BaseFee is the latest block's base fee; NextBaseFee the projection
+	// for the next block.
+	BaseFee     *big.Int
+	NextBaseFee *big.Int
+	// GasUsedRatio is the mean block fullness over the window.
+	GasUsedRatio float64
+	// TipSamples is the number of non-empty blocks the tips come from. Zero
+	// means the node's eth_maxPriorityFeePerGas was used instead.
+	TipSamples int
+
+	Slow, Standard, Fast FeeTier
+}
+
+// FeeOracle suggests slow, standard and fast fees from the 10th, 50th and
+// 90th percentile priority fees paid over the last blocks blocks. Each
+// tier's tip is the median across non-empty blocks, and its max fee adds
+// headroom for base fee growth on top of the projected next base fee.
+func (c *Client) FeeOracle(ctx context.Context, blocks int) (*FeeOracle, error) {
+	if blocks < 1 || blocks > MaxFeeHistoryBlocks {
+		return nil, fmt.Errorf("blocks must be between 1 and %d", MaxFeeHistoryBlocks)
+	}
+	hist, err := c.eth.FeeHistory(ctx, uint64(blocks), nil, feeTierPercentiles)
+	if err != nil {
+		return nil, fmt.Errorf("fee history: %w", err)
+	}
+	n := len(hist.GasUsedRatio)
+	if n == 0 || len(hist.BaseFee) < n || hist.OldestBlock == nil {
+		return nil, fmt.Errorf("fee history: empty response")
+	}
+
+	o := &FeeOracle{
+		OldestBlock: hist.OldestBlock.Uint64(),
+		LatestBlock: hist.OldestBlock.Uint64() + uint64(n) - 1,
+		Blocks:      n,
+		BaseFee:     hist.BaseFee[n-1],
+	}
+	// eth_feeHistory returns one base fee past the window: the next block's.
+	if len(hist.BaseFee) > n {
+		o.NextBaseFee = hist.BaseFee[n]
+	} else {
+		o.NextBaseFee = NextBaseFee(o.BaseFee, hist.GasUsedRatio[n-1])
+	}
+	for _, r := range hist.GasUsedRatio {
+		o.GasUsedRatio += r / float64(n)
+	}
+
+	var tips [3]*big.Int
+	for i := range feeTierPercentiles {
+		var samples []*big.Int
+		for b, rewards := range hist.Reward {
+			if b < n && hist.GasUsedRatio[b] > 0 && i < len(rewards) && rewards[i] != nil {
+				samples = append(samples, rewards[i])
+			}
+		}
+		o.TipSamples = len(samples)
+		tips[i] = median(samples)
+	}
+	if o.TipSamples == 0 {
+		tip, err := c.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("suggest tip: %w", err)
+		}
+		tips = [3]*big.Int{tip, tip, tip}
+	}
+	// Percentiles of different blocks can cross; keep the tiers ordered.
+	for i := 1; i < len(tips); i++ {
+		if tips[i].Cmp(tips[i-1]) < 0 {
+			tips[i] = tips[i-1]
+		}
+	}
+
+	tiers := make([]FeeTier, len(tips))
+	for i, tip := range tips {
+		maxFee := new(big.Int).Mul(o.NextBaseFee, big.NewInt(feeTierHeadroom[i]))
+		maxFee.Div(maxFee, big.NewInt(100))
+		tiers[i] = FeeTier{MaxPriorityFeePerGas: tip, MaxFeePerGas: maxFee.Add(maxFee, tip)}
+	}
+	o.Slow, o.Standard, o.Fast = tiers[0], tiers[1], tiers[2]
+	return o, nil
+}
+
+// NextBaseFee projects the next block's base fee under EIP-1559: it moves
+// by up to 12.5% in proportion to how far gasUsedRatio is from the 50%
+// target.
+func NextBaseFee(baseFee *big.Int, gasUsedRatio float64) *big.Int {
+	// delta = baseFee * (ratio - 0.5) / 0.5 / 8, in parts per million.
+	ppm := int64((gasUsedRatio - 0.5) * 2 / 8 * 1_000_000)
+	delta := new(big.Int).Mul(baseFee, big.NewInt(ppm))
+	delta.Quo(delta, big.NewInt(1_000_000))
+	next := new(big.Int).Add(baseFee, delta)
+	if next.Sign() < 0 {
+		return new(big.Int)
+	}
+	return next
+}
+
+// median returns the median of values, or zero if there are none.
+func median(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return new(big.Int)
+	}
+	sorted := append([]*big.Int(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return new(big.Int).Set(sorted[mid])
+	}
+	sum := new(big.Int).Add(sorted[mid-1], sorted[mid])
+	return sum.Quo(sum, big.NewInt(2))
+}
@@ -0,0 +1,116 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// feeHistoryRPC serves eth_feeHistory with history and
+// eth_maxPriorityFeePerGas with 1 gwei.
+func feeHistoryRPC(t *testing.T, history map[string]any) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result any = history
+		if req.Method == "eth_maxPriorityFeePerGas" {
+			result = "0x3b9aca00"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestNextBaseFee(t *testing.T) {
+	base := big.NewInt(8_000_000_000)
+	for _, tc := range []struct {
+		ratio float64
+		want  int64
+	}{
+		{0.5, 8_000_000_000},
+		{1, 9_000_000_000},
+		{0, 7_000_000_000},
+		{0.75, 8_500_000_000},
+	} {
+		if got := NextBaseFee(base, tc.ratio); got.Int64() != tc.want {
+			t.Errorf("NextBaseFee(%v) = %s, want %d", tc.ratio, got, tc.want)
+		}
+	}
+}
+
+func TestFeeOracle(t *testing.T) {
+	c := feeHistoryRPC(t, map[string]any{
+		"oldestBlock":   "0x64",
+		"baseFeePerGas": []string{"0x3b9aca00", "0x3b9aca00", "0x77359400", "0x77359400"},
+		"gasUsedRatio":  []float64{0.5, 0, 0.9},
+		// Gwei tips at the 10th, 50th and 90th percentiles. The empty block's
+		// zeros are skipped, and the last block's crossed percentiles are
+		// reordered.
+		"reward": [][]string{
+			{"0x5f5e100", "0x3b9aca00", "0x77359400"},
+			{"0x0", "0x0", "0x0"},
+			{"0x1dcd6500", "0x77359400", "0x1dcd6500"},
+		},
+	})
+
+	o, err := c.FeeOracle(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("FeeOracle: %v", err)
+	}
+	if o.OldestBlock != 100 || o.LatestBlock != 102 || o.TipSamples != 2 {
+		t.Errorf("blocks %d-%d, %d samples", o.OldestBlock, o.LatestBlock, o.TipSamples)
+	}
+	gwei := func(g float64) *big.Int { return big.NewInt(int64(g * 1e9)) }
+	if o.NextBaseFee.Cmp(gwei(2)) != 0 {
+		t.Errorf("next base fee = %s, want the node's 2 gwei", o.NextBaseFee)
+	}
+	for name, tc := range map[string]struct {
+		tier     FeeTier
+		tip, max *big.Int
+	}{
+		// slow: median(0.1, 0.5); standard: median(1, 2); fast:
+		// median(2, 0.5) raised to standard.
+		"slow":     {o.Slow, gwei(0.3), gwei(2.8)},
+		"standard": {o.Standard, gwei(1.5), gwei(4.5)},
+		"fast":     {o.Fast, gwei(1.5), gwei(5.5)},
+	} {
+		if tc.tier.MaxPriorityFeePerGas.Cmp(tc.tip) != 0 || tc.tier.MaxFeePerGas.Cmp(tc.max) != 0 {
+			t.Errorf("%s = %s/%s, want %s/%s", name, tc.tier.MaxPriorityFeePerGas, tc.tier.MaxFeePerGas, tc.tip, tc.max)
+		}
+	}
+
+	if _, err := c.FeeOracle(context.Background(), 0); err == nil {
+		t.Error("zero blocks: expected error")
+	}
+}
+
+func TestFeeOracle_EmptyBlocks(t *testing.T) {
+	c := feeHistoryRPC(t, map[string]any{
+		"oldestBlock":   "0x1",
+		"baseFeePerGas": []string{"0x0", "0x0"},
+		"gasUsedRatio":  []float64{0},
+		"reward":        [][]string{{"0x0", "0x0", "0x0"}},
+	})
+	o, err := c.FeeOracle(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("FeeOracle: %v", err)
+	}
+	if o.TipSamples != 0 || o.Fast.MaxPriorityFeePerGas.Int64() != 1e9 || o.Fast.MaxFeePerGas.Int64() != 1e9 {
+		t.Errorf("fallback tip: %d samples, fast %s/%s", o.TipSamples, o.Fast.MaxPriorityFeePerGas, o.Fast.MaxFeePerGas)
+	}
+}
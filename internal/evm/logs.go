@@ -0,0 +1,83 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// minLogWindow is the smallest block range ScanLogs splits a query into
+// before giving up on an RPC that keeps rejecting it.
+const minLogWindow = 1_000
+
+// LogScan is the result of ScanLogs.
+type LogScan struct {
+	Logs []types.Log
+	// FromBlock is the lowest block covered. It is above the requested
+	// start when the query budget ran out first.
+	FromBlock uint64
+	ToBlock   uint64
+	Queries   int
+}
+
+// BlockNumber returns the latest block number.
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.eth.BlockNumber(ctx)
+}
+
+// FilterLogs runs a single eth_getLogs query.
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return c.eth.FilterLogs(ctx, q)
+}
+
+// ScanLogs runs q over [from, to] newest block first. Public RPCs cap the
+// block range or result size of eth_getLogs, so a rejected window is halved
+// and retried down to minLogWindow blocks. At most maxQueries requests are
+// made; the returned scan records how far back it got.
+func (c *Client) ScanLogs(ctx context.Context, q ethereum.FilterQuery, from, to uint64, maxQueries int) (*LogScan, error) {
+	return c.ScanLogsLimit(ctx, q, from, to, maxQueries, 0)
+}
+
+// ScanLogsLimit is ScanLogs that also stops once a window brings the total
+// to at least limit logs. A limit of 0 scans the whole range.
+func (c *Client) ScanLogsLimit(ctx context.Context, q ethereum.FilterQuery, from, to uint64, maxQueries, limit int) (*LogScan, error) {
+	scan := &LogScan{FromBlock: to + 1, ToBlock: to}
+	if from > to {
+		return scan, nil
+	}
+
+	window := to - from + 1
+	hi := to
+	for scan.Queries < maxQueries {
+		lo := from
+		if hi-from+1 > window {
+			lo = hi - window + 1
+		}
+
+		q.FromBlock = new(big.Int).SetUint64(lo)
+		q.ToBlock = new(big.Int).SetUint64(hi)
+		scan.Queries++
+		logs, err := c.eth.FilterLogs(ctx, q)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if window <= minLogWindow {
+				return nil, fmt.Errorf("get logs %d-%d: %w", lo, hi, err)
+			}
+			window /= 2
+			continue
+		}
+
+		scan.Logs = append(scan.Logs, logs...)
+		scan.FromBlock = lo
+		if lo == from || (limit > 0 && len(scan.Logs) >= limit) {
+			break
+		}
+		hi = lo - 1
+	}
+	return scan, nil
+}
@@ -0,0 +1,117 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// rangeLimitedRPC serves eth_getLogs, rejecting ranges wider than maxRange
+// and returning one log at each block in logBlocks.
+func rangeLimitedRPC(t *testing.T, maxRange uint64, logBlocks ...uint64) (*Client, *int) {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		calls++
+		var filter struct {
+			FromBlock string `json:"fromBlock"`
+			ToBlock   string `json:"toBlock"`
+		}
+		_ = json.Unmarshal(req.Params[0], &filter)
+		from, _ := strconv.ParseUint(filter.FromBlock[2:], 16, 64)
+		to, _ := strconv.ParseUint(filter.ToBlock[2:], 16, 64)
+
+		w.Header().Set("Content-Type", "application/json")
+		if to-from+1 > maxRange {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32005,"message":"block range too large"}}`, req.ID)
+			return
+		}
+		logs := []map[string]any{}
+		for _, b := range logBlocks {
+			if b >= from && b <= to {
+				logs = append(logs, map[string]any{
+					"address":         "0x0000000000000000000000000000000000000001",
+					"topics":          []string{},
+					"data":            "0x",
+					"blockNumber":     fmt.Sprintf("0x%x", b),
+					"transactionHash": fmt.Sprintf("0x%064x", b),
+				})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": logs})
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c, &calls
+}
+
+func TestScanLogs_SplitsRejectedRanges(t *testing.T) {
+	c, calls := rangeLimitedRPC(t, 5_000, 100, 9_000, 19_999)
+
+	scan, err := c.ScanLogs(context.Background(), ethereum.FilterQuery{}, 0, 19_999, 100)
+	if err != nil {
+		t.Fatalf("ScanLogs: %v", err)
+	}
+	if scan.FromBlock != 0 || scan.ToBlock != 19_999 {
+		t.Errorf("scanned %d-%d, want 0-19999", scan.FromBlock, scan.ToBlock)
+	}
+	if len(scan.Logs) != 3 || scan.Logs[0].BlockNumber != 19_999 {
+		t.Errorf("logs = %+v, want 3 newest first", scan.Logs)
+	}
+	// 20000 -> 10000 -> 5000 rejected twice, then four windows of 5000.
+	if scan.Queries != 6 || *calls != 6 {
+		t.Errorf("queries = %d (server saw %d), want 6", scan.Queries, *calls)
+	}
+}
+
+func TestScanLogs_Budget(t *testing.T) {
+	c, _ := rangeLimitedRPC(t, 5_000, 100, 19_999)
+
+	scan, err := c.ScanLogs(context.Background(), ethereum.FilterQuery{}, 0, 19_999, 3)
+	if err != nil {
+		t.Fatalf("ScanLogs: %v", err)
+	}
+	if scan.FromBlock != 15_000 || len(scan.Logs) != 1 {
+		t.Errorf("scan = from %d, %d logs; want from 15000 with the newest log only", scan.FromBlock, len(scan.Logs))
+	}
+}
+
+func TestScanLogs_GivesUpBelowMinWindow(t *testing.T) {
+	c, _ := rangeLimitedRPC(t, 100)
+
+	if _, err := c.ScanLogs(context.Background(), ethereum.FilterQuery{}, 0, 19_999, 100); err == nil {
+		t.Error("expected error when the RPC rejects every window")
+	}
+}
+
+func TestScanLogsLimit_StopsAtLimit(t *testing.T) {
+	c, calls := rangeLimitedRPC(t, 5_000, 100, 16_000, 19_999)
+
+	scan, err := c.ScanLogsLimit(context.Background(), ethereum.FilterQuery{}, 0, 19_999, 100, 2)
+	if err != nil {
+		t.Fatalf("ScanLogsLimit: %v", err)
+	}
+	if scan.FromBlock != 15_000 || len(scan.Logs) != 2 {
+		t.Errorf("scan = from %d, %d logs; want from 15000 with 2 logs", scan.FromBlock, len(scan.Logs))
+	}
+	// 20000 and 10000 rejected, then 15000-19999.
+	if *calls != 3 {
+		t.Errorf("server saw %d queries, want 3", *calls)
+	}
+}
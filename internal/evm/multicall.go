@@ -0,0 +1,174 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Multicall3Address is the Multicall3 deployment, at the same address on
+// nearly every EVM chain.
+var Multicall3Address = ethcommon.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// multicallChunk caps the calls per aggregate3 request so large batches
+// stay under RPC gas and response size limits.
+const multicallChunk = 200
+
+const multicallABIJSON = `[
+{"type":"function","name":"aggregate3","stateMutability":"payable","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],"outputs":[{"name":"returnData","type":"tuple[]","components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]}
+]`
+
+var multicallABI = mustParseABI(multicallABIJSON)
+
+// errNoMulticall reports that Multicall3 is not deployed on the chain.
+var errNoMulticall = errors.New("multicall3 not deployed")
+
+// Call is one read in a Multicall batch.
+type Call struct {
+	To   ethcommon.Address
+	Data []byte
+	// AllowFailure lets the call revert without failing the whole batch.
+	AllowFailure bool
+}
+
+// CallResult is the outcome of one Call. ReturnData is the revert data when
+// Success is false.
+type CallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+type multicallCall struct {
+	Target       ethcommon.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// multicallState remembers chains without Multicall3 so batches there go
+// straight to individual calls.
+type multicallState struct {
+	unavailable atomic.Bool
+}
+
+// Multicall runs calls through Multicall3 aggregate3 at block (nil for
+// latest), in as few eth_calls as possible. Calls that may fail must set
+// AllowFailure; any other failing call fails the batch. On chains without
+// Multicall3, or if an aggregate3 request fails, the calls are made one by
+// one with the same semantics.
+func (c *Client) Multicall(ctx context.Context, calls []Call, block *big.Int) ([]CallResult, error) {
+	results := make([]CallResult, 0, len(calls))
+	for start := 0; start < len(calls); start += multicallChunk {
+		chunk := calls[start:min(start+multicallChunk, len(calls))]
+
+		var batch []CallResult
+		var err error
+		if !c.multicall.unavailable.Load() {
+			batch, err = c.aggregate3(ctx, chunk, block)
+			if errors.Is(err, errNoMulticall) {
+				c.multicall.unavailable.Store(true)
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+		if batch == nil {
+			batch, err = c.callEach(ctx, chunk, block, start)
+			if err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+func (c *Client) aggregate3(ctx context.Context, calls []Call, block *big.Int) ([]CallResult, error) {
+	packed := make([]multicallCall, len(calls))
+	for i, call := range calls {
+		packed[i] = multicallCall{Target: call.To, AllowFailure: call.AllowFailure, CallData: call.Data}
+	}
+	data, err := multicallABI.Pack("aggregate3", packed)
+	if err != nil {
+		return nil, fmt.Errorf("pack aggregate3: %w", err)
+	}
+	out, err := c.eth.CallContract(ctx, ethereum.CallMsg{To: &Multicall3Address, Data: data}, block)
+	if err != nil {
+		return nil, fmt.Errorf("call aggregate3: %w", err)
+	}
+	if len(out) == 0 {
+		return nil, errNoMulticall
+	}
+	values, err := multicallABI.Unpack("aggregate3", out)
+	if err != nil || len(values) != 1 {
+		return nil, fmt.Errorf("decode aggregate3: %v", err)
+	}
+	decoded := *abi.ConvertType(values[0], new([]multicallResult)).(*[]multicallResult)
+	if len(decoded) != len(calls) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(decoded), len(calls))
+	}
+	results := make([]CallResult, len(decoded))
+	for i, r := range decoded {
+		results[i] = CallResult{Success: r.Success, ReturnData: r.ReturnData}
+	}
+	return results, nil
+}
+
+// callEach makes calls one eth_call at a time. Errors reported by the node
+// count as reverts; transport errors fail the batch. offset numbers the
+// calls in errors.
+func (c *Client) callEach(ctx context.Context, calls []Call, block *big.Int, offset int) ([]CallResult, error) {
+	results := make([]CallResult, len(calls))
+	for i, call := range calls {
+		to := call.To
+		out, err := c.eth.CallContract(ctx, ethereum.CallMsg{To: &to, Data: call.Data}, block)
+		if err == nil {
+			results[i] = CallResult{Success: true, ReturnData: out}
+			continue
+		}
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) || !call.AllowFailure {
+			return nil, fmt.Errorf("call %d to %s: %w", offset+i, to.Hex(), err)
+		}
+		results[i] = CallResult{ReturnData: revertData(err)}
+	}
+	return results, nil
+}
+
+// revertData returns the revert payload carried by an eth_call error, if
+// any.
+func revertData(err error) []byte {
+	data, _ := RevertData(err)
+	return data
+}
+
+// RevertData returns the revert payload of a failed eth_call or
+// eth_estimateGas. ok is false for errors that are not reverts, such as
+// transport failures; a revert without a reason has empty data.
+func RevertData(err error) (data []byte, ok bool) {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if s, isString := dataErr.ErrorData().(string); isString {
+			if b, decodeErr := hexutil.Decode(s); decodeErr == nil {
+				return b, true
+			}
+		}
+	}
+	if err != nil && strings.Contains(err.Error(), "execution reverted") {
+		return nil, true
+	}
+	return nil, false
+}
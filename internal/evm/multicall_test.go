@@ -0,0 +1,155 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// multicallRPC serves eth_call through call, which returns the return data
+// or false to revert. With deployed set, calls to Multicall3 run aggregate3
+// over call; otherwise Multicall3 has no code. It returns the number of
+// eth_call requests served.
+func multicallRPC(t *testing.T, deployed bool, call func(to ethcommon.Address, data []byte) ([]byte, bool)) (*Client, *int) {
+	t.Helper()
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Params []json.RawMessage
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		requests++
+		var msg struct {
+			To    ethcommon.Address `json:"to"`
+			Input hexutil.Bytes     `json:"input"`
+		}
+		_ = json.Unmarshal(req.Params[0], &msg)
+
+		w.Header().Set("Content-Type", "application/json")
+		revert := func() {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":3,"message":"execution reverted","data":"0x08c379a0"}}`, req.ID)
+		}
+		var out []byte
+		switch {
+		case msg.To == Multicall3Address && deployed:
+			args, err := multicallABI.Methods["aggregate3"].Inputs.Unpack(msg.Input[4:])
+			if err != nil {
+				t.Errorf("decode aggregate3: %v", err)
+			}
+			calls := *abi.ConvertType(args[0], new([]multicallCall)).(*[]multicallCall)
+			results := make([]multicallResult, len(calls))
+			for i, c := range calls {
+				ret, ok := call(c.Target, c.CallData)
+				if !ok && !c.AllowFailure {
+					revert()
+					return
+				}
+				results[i] = multicallResult{Success: ok, ReturnData: ret}
+			}
+			out, _ = multicallABI.Methods["aggregate3"].Outputs.Pack(results)
+		case msg.To == Multicall3Address:
+		default:
+			var ok bool
+			if out, ok = call(msg.To, msg.Input); !ok {
+				revert()
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": hexutil.Bytes(out)})
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c, &requests
+}
+
+var (
+	testToken  = ethcommon.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	testHolder = ethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+// testTokenCall answers decimals, symbol and balanceOf for a 6-decimal
+// "USDC" at testToken and reverts everything else.
+func testTokenCall(to ethcommon.Address, data []byte) ([]byte, bool) {
+	if to != testToken || len(data) < 4 {
+		return nil, false
+	}
+	switch hexutil.Encode(data[:4]) {
+	case "0x313ce567":
+		return ethcommon.LeftPadBytes([]byte{6}, 32), true
+	case "0x95d89b41":
+		out, _ := abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}.Pack("USDC")
+		return out, true
+	case "0x70a08231":
+		return ethcommon.LeftPadBytes(big.NewInt(1_500_000).Bytes(), 32), true
+	}
+	return nil, false
+}
+
+func TestMulticall(t *testing.T) {
+	for _, deployed := range []bool{true, false} {
+		c, requests := multicallRPC(t, deployed, testTokenCall)
+
+		tb, err := c.GetTokenBalance(context.Background(), testToken.Hex(), testHolder.Hex())
+		if err != nil {
+			t.Fatalf("deployed=%v: GetTokenBalance: %v", deployed, err)
+		}
+		if tb.Balance != "1.5" || tb.Symbol != "USDC" || tb.Decimals != 6 {
+			t.Errorf("deployed=%v: balance %+v", deployed, tb)
+		}
+		// One aggregate3, or a probe of the empty Multicall3 address and
+		// single balanceOf, decimals, symbol and name calls.
+		want := 1
+		if !deployed {
+			want = 5
+		}
+		if *requests != want {
+			t.Errorf("deployed=%v: %d eth_calls, want %d", deployed, *requests, want)
+		}
+
+		// A failing call is reported without failing the batch.
+		results, err := c.Multicall(context.Background(), []Call{
+			{To: testToken, Data: erc20Codec.PackDecimals()},
+			{To: testHolder, Data: erc20Codec.PackDecimals(), AllowFailure: true},
+		}, nil)
+		if err != nil {
+			t.Fatalf("deployed=%v: Multicall: %v", deployed, err)
+		}
+		if !results[0].Success || results[1].Success {
+			t.Errorf("deployed=%v: results %+v", deployed, results)
+		}
+
+		if _, err := c.Multicall(context.Background(), []Call{
+			{To: testHolder, Data: erc20Codec.PackDecimals()},
+		}, nil); err == nil {
+			t.Errorf("deployed=%v: required call reverted without error", deployed)
+		}
+	}
+}
+
+func TestMulticall_NoProbeAfterMissingDeployment(t *testing.T) {
+	c, requests := multicallRPC(t, false, testTokenCall)
+	for range 2 {
+		if _, err := c.GetTokenBalance(context.Background(), testToken.Hex(), testHolder.Hex()); err != nil {
+			t.Fatalf("GetTokenBalance: %v", err)
+		}
+	}
+	// One probe and four single calls, then a single balanceOf once the
+	// metadata is cached.
+	if *requests != 6 {
+		t.Errorf("%d eth_calls, want 6", *requests)
+	}
+}
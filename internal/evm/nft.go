@@ -0,0 +1,314 @@
+package evm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// NFT standards as reported by DetectNFTStandard.
+const (
+	StandardERC721  = "erc721"
+	StandardERC1155 = "erc1155"
+)
+
+var (
+	// TransferTopic is the ERC-20 and ERC-721 Transfer event. ERC-721 logs
+	// index the token ID as a fourth topic.
+	TransferTopic       = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	TransferSingleTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	TransferBatchTopic  = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+)
+
+// ERC-165 interface IDs.
+var (
+	erc721InterfaceID  = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	erc1155InterfaceID = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+)
+
+const nftABIJSON = `[
+{"type":"function","name":"ownerOf","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"tokenURI","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"uri","stateMutability":"view","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"supportsInterface","stateMutability":"view","inputs":[{"name":"interfaceId","type":"bytes4"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+{"type":"event","name":"TransferBatch","inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"ids","type":"uint256[]","indexed":false},{"name":"values","type":"uint256[]","indexed":false}]}
+]`
+
+// erc1155ABIJSON holds the ERC-1155 safeTransferFrom, which overloads the
+// ERC-721 name and so cannot share an abi.ABI with it.
+const erc1155ABIJSON = `[
+{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]}
+]`
+
+var (
+	nftABI     = mustParseABI(nftABIJSON)
+	erc1155ABI = mustParseABI(erc1155ABIJSON)
+)
+
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// PackERC721SafeTransfer returns the calldata for
+// safeTransferFrom(from, to, tokenId).
+func PackERC721SafeTransfer(from, to ethcommon.Address, tokenID *big.Int) ([]byte, error) {
+	return nftABI.Pack("safeTransferFrom", from, to, tokenID)
+}
+
+// PackERC1155SafeTransfer returns the calldata for
+// safeTransferFrom(from, to, id, value, "").
+func PackERC1155SafeTransfer(from, to ethcommon.Address, id, value *big.Int) ([]byte, error) {
+	return erc1155ABI.Pack("safeTransferFrom", from, to, id, value, []byte{})
+}
+
+// DecodeTransferBatch returns the ids and values of a TransferBatch log.
+func DecodeTransferBatch(data []byte) ([]*big.Int, []*big.Int, error) {
+	out, err := nftABI.Unpack("TransferBatch", data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out[0].([]*big.Int), out[1].([]*big.Int), nil
+}
+
+// callABI packs method from parsed, calls contract and returns the unpacked
+// outputs.
+func (c *Client) callABI(ctx context.Context, parsed abi.ABI, contract ethcommon.Address, method string, args ...any) ([]any, error) {
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.eth.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call %s(): %w", method, err)
+	}
+	out, err := parsed.Unpack(method, res)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", method, err)
+	}
+	return out, nil
+}
+
+// callNFT calls a single-output method of nftABI.
+func (c *Client) callNFT(ctx context.Context, contract ethcommon.Address, method string, args ...any) (any, error) {
+	out, err := c.callABI(ctx, nftABI, contract, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return out[0], nil
+}
+
+// OwnerOf returns the owner of an ERC-721 token.
+func (c *Client) OwnerOf(ctx context.Context, contract ethcommon.Address, tokenID *big.Int) (ethcommon.Address, error) {
+	out, err := c.callNFT(ctx, contract, "ownerOf", tokenID)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return out.(ethcommon.Address), nil
+}
+
+// ERC1155BalanceOf returns owner's balance of an ERC-1155 token ID.
+func (c *Client) ERC1155BalanceOf(ctx context.Context, contract, owner ethcommon.Address, id *big.Int) (*big.Int, error) {
+	out, err := c.callNFT(ctx, contract, "balanceOf", owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return out.(*big.Int), nil
+}
+
+// NFTCollectionName returns the contract's name(), which is optional for
+// both standards.
+func (c *Client) NFTCollectionName(ctx context.Context, contract ethcommon.Address) (string, error) {
+	out, err := c.callNFT(ctx, contract, "name")
+	if err != nil {
+		return "", err
+	}
+	return out.(string), nil
+}
+
+// NFTTokenURI returns the metadata URI of a token: tokenURI for ERC-721, or
+// uri with the {id} placeholder substituted for ERC-1155.
+func (c *Client) NFTTokenURI(ctx context.Context, contract ethcommon.Address, standard string, id *big.Int) (string, error) {
+	if standard == StandardERC1155 {
+		out, err := c.callNFT(ctx, contract, "uri", id)
+		if err != nil {
+			return "", err
+		}
+		return strings.ReplaceAll(out.(string), "{id}", fmt.Sprintf("%064x", id)), nil
+	}
+	out, err := c.callNFT(ctx, contract, "tokenURI", id)
+	if err != nil {
+		return "", err
+	}
+	return out.(string), nil
+}
+
+// DetectNFTStandard reports whether contract is ERC-721 or ERC-1155 via
+// ERC-165 supportsInterface.
+func (c *Client) DetectNFTStandard(ctx context.Context, contract ethcommon.Address) (string, error) {
+	for _, std := range []struct {
+		name string
+		id   [4]byte
+	}{
+		{StandardERC721, erc721InterfaceID},
+		{StandardERC1155, erc1155InterfaceID},
+	} {
+		out, err := c.callNFT(ctx, contract, "supportsInterface", std.id)
+		if err != nil {
+			return "", err
+		}
+		if out.(bool) {
+			return std.name, nil
+		}
+	}
+	return "", fmt.Errorf("contract %s supports neither ERC-721 nor ERC-1155", contract.Hex())
+}
+
+// IPFSGateway is the HTTP gateway used to fetch ipfs:// URIs.
+const IPFSGateway = "https://ipfs.io/ipfs/"
+
+// ArweaveGateway is the HTTP gateway used to fetch ar:// URIs.
+const ArweaveGateway = "https://arweave.net/"
+
+// NFTMetadata is the subset of the ERC-721/1155 metadata JSON schema worth
+// showing to a user.
+type NFTMetadata struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+}
+
+// metadataHTTP fetches token URIs. Whoever deploys a contract picks its
+// URIs, so the client only dials public addresses, checked after DNS
+// resolution, and follows redirects only to https.
+var metadataHTTP = &http.Client{
+	Timeout: 5 * time.Second,
+	Transport: &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: 5 * time.Second, Control: dialPublicOnly}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     30 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if req.URL.Scheme != "https" {
+			return fmt.Errorf("redirect to non-https URL %s", req.URL.Redacted())
+		}
+		if len(via) >= 5 {
+			return fmt.Errorf("stopped after %d redirects", len(via))
+		}
+		return nil
+	},
+}
+
+// dialPublicOnly refuses connections to loopback, private, link-local and
+// other non-public addresses.
+func dialPublicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("refusing to dial %s: %w", address, err)
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("refusing to dial non-public address %s", ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the RFC 6598 carrier-grade NAT range, which
+// netip does not count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// maxMetadataBytes bounds the metadata documents read from token URIs.
+const maxMetadataBytes = 1 << 20
+
+// GatewayURL rewrites ipfs:// and ar:// URIs to IPFSGateway and
+// ArweaveGateway and returns other URIs unchanged.
+func GatewayURL(uri string) string {
+	if rest, ok := strings.CutPrefix(uri, "ipfs://"); ok {
+		return IPFSGateway + strings.TrimPrefix(rest, "ipfs/")
+	}
+	if rest, ok := strings.CutPrefix(uri, "ar://"); ok {
+		return ArweaveGateway + rest
+	}
+	return uri
+}
+
+// FetchNFTMetadata resolves a token URI: data: URIs are decoded inline,
+// ipfs:// and ar:// go through their gateways and https is fetched
+// directly. Plain http and non-public hosts are refused.
+func FetchNFTMetadata(ctx context.Context, uri string) (*NFTMetadata, error) {
+	var body []byte
+	switch {
+	case strings.HasPrefix(uri, "data:"):
+		header, payload, ok := strings.Cut(uri, ",")
+		if !ok {
+			return nil, fmt.Errorf("malformed data URI")
+		}
+		if strings.HasSuffix(header, ";base64") {
+			decoded, err := base64.StdEncoding.DecodeString(payload)
+			if err != nil {
+				return nil, fmt.Errorf("decode data URI: %w", err)
+			}
+			body = decoded
+		} else {
+			unescaped, err := url.PathUnescape(payload)
+			if err != nil {
+				return nil, fmt.Errorf("decode data URI: %w", err)
+			}
+			body = []byte(unescaped)
+		}
+	default:
+		target := GatewayURL(uri)
+		if !strings.HasPrefix(target, "https://") {
+			return nil, fmt.Errorf("unsupported token URI scheme (only https, ipfs, ar and data are fetched): %s", uri)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := metadataHTTP.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetch metadata: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch metadata: HTTP %d", resp.StatusCode)
+		}
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxMetadataBytes))
+		if err != nil {
+			return nil, fmt.Errorf("read metadata: %w", err)
+		}
+	}
+
+	var meta NFTMetadata
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return nil, fmt.Errorf("decode metadata: %w", err)
+	}
+	meta.Image = GatewayURL(meta.Image)
+	return &meta, nil
+}
@@ -0,0 +1,77 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetchNFTMetadata(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/7.json":
+			fmt.Fprint(w, `{"name":"Token 7","description":"d","image":"ar://TxImg","attributes":[]}`)
+		case "/plain":
+			http.Redirect(w, r, "http://example.com/7.json", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// The default client refuses the loopback test server.
+	if _, err := FetchNFTMetadata(context.Background(), srv.URL+"/7.json"); err == nil || !strings.Contains(err.Error(), "non-public address") {
+		t.Fatalf("loopback fetch: err = %v", err)
+	}
+
+	// Trust the test server for the rest, keeping the redirect policy.
+	defaultHTTP := metadataHTTP
+	t.Cleanup(func() { metadataHTTP = defaultHTTP })
+	client := srv.Client()
+	client.CheckRedirect = defaultHTTP.CheckRedirect
+	metadataHTTP = client
+
+	meta, err := FetchNFTMetadata(context.Background(), srv.URL+"/7.json")
+	if err != nil {
+		t.Fatalf("FetchNFTMetadata: %v", err)
+	}
+	if meta.Name != "Token 7" || meta.Image != ArweaveGateway+"TxImg" {
+		t.Errorf("metadata = %+v", meta)
+	}
+
+	for _, uri := range []string{
+		srv.URL + "/8.json",
+		srv.URL + "/plain",
+		"http://169.254.169.254/latest/meta-data/",
+		"file:///etc/passwd",
+		"data:application/json;base64,!!",
+	} {
+		if _, err := FetchNFTMetadata(context.Background(), uri); err == nil {
+			t.Errorf("%s: expected error", uri)
+		}
+	}
+}
+
+func TestDialPublicOnly(t *testing.T) {
+	for address, public := range map[string]bool{
+		"93.184.216.34:443":    true,
+		"[2606:2800::1]:443":   true,
+		"127.0.0.1:443":        false,
+		"10.0.0.8:443":         false,
+		"192.168.1.1:443":      false,
+		"169.254.169.254:80":   false,
+		"100.64.0.1:443":       false,
+		"0.0.0.0:443":          false,
+		"[::1]:443":            false,
+		"[fe80::1]:443":        false,
+		"[fd00::1]:443":        false,
+		"[::ffff:10.0.0.1]:80": false,
+	} {
+		if err := dialPublicOnly("tcp", address, nil); (err == nil) != public {
+			t.Errorf("%s: err = %v, want public %v", address, err, public)
+		}
+	}
+}
@@ -0,0 +1,502 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Permit kinds.
+const (
+	// PermitKindEIP2612 is a token's own permit(owner, spender, value,
+	// deadline, v, r, s).
+	PermitKindEIP2612 = "eip2612"
+	// PermitKindPermit2 is a Uniswap Permit2 PermitSingle, usable with any
+	// token the owner has approved to Permit2.
+	PermitKindPermit2 = "permit2"
+)
+
+// Permit2Address is the canonical Uniswap Permit2 deployment, at the same
+// address on every chain it is deployed to.
+var Permit2Address = ethcommon.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+
+var (
+	eip2612PermitTypeHash = crypto.Keccak256Hash([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+
+	maxUint48  = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 48), big.NewInt(1))
+	maxUint160 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+)
+
+const permitABIJSON = `[
+{"type":"function","name":"DOMAIN_SEPARATOR","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]},
+{"type":"function","name":"PERMIT_TYPEHASH","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]},
+{"type":"function","name":"nonces","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"version","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"eip712Domain","stateMutability":"view","inputs":[],"outputs":[{"name":"fields","type":"bytes1"},{"name":"name","type":"string"},{"name":"version","type":"string"},{"name":"chainId","type":"uint256"},{"name":"verifyingContract","type":"address"},{"name":"salt","type":"bytes32"},{"name":"extensions","type":"uint256[]"}]},
+{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"user","type":"address"},{"name":"token","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"amount","type":"uint160"},{"name":"expiration","type":"uint48"},{"name":"nonce","type":"uint48"}]}
+]`
+
+var permitABI = mustParseABI(permitABIJSON)
+
+// PermitSupport reports whether a token implements EIP-2612.
+type PermitSupport struct {
+	Supported bool
+	// Name and Version are the token's EIP-712 domain, when supported.
+	Name    string
+	Version string
+	// Reason explains why the token is not supported.
+	Reason string
+}
+
+// EIP2612Support checks whether token implements a standard EIP-2612
+// permit: it must expose DOMAIN_SEPARATOR() and nonces(), its domain
+// separator must match a {name, version, chainId, verifyingContract} domain
+// and any PERMIT_TYPEHASH() must be the EIP-2612 one (DAI-style permits are
+// not compatible). Only transport failures are returned as errors.
+func (c *Client) EIP2612Support(ctx context.Context, token ethcommon.Address, chainID *big.Int) (*PermitSupport, error) {
+	out, err := c.callABI(ctx, permitABI, token, "DOMAIN_SEPARATOR")
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &PermitSupport{Reason: "token has no DOMAIN_SEPARATOR()"}, nil
+	}
+	separator := out[0].([32]byte)
+
+	if _, err := c.callABI(ctx, permitABI, token, "nonces", ethcommon.Address{}); err != nil {
+		return &PermitSupport{Reason: "token has no nonces()"}, nil
+	}
+	if out, err := c.callABI(ctx, permitABI, token, "PERMIT_TYPEHASH"); err == nil && out[0].([32]byte) != eip2612PermitTypeHash {
+		return &PermitSupport{Reason: "token uses a non-standard permit (e.g. DAI-style holder/nonce/expiry/allowed)"}, nil
+	}
+
+	var name string
+	var versions []string
+	if out, err := c.callABI(ctx, permitABI, token, "eip712Domain"); err == nil {
+		name = out[1].(string)
+		versions = append(versions, out[2].(string))
+	} else {
+		out, err := c.callABI(ctx, permitABI, token, "name")
+		if err != nil {
+			return &PermitSupport{Reason: "token has no name() to build its EIP-712 domain"}, nil
+		}
+		name = out[0].(string)
+	}
+	if out, err := c.callABI(ctx, permitABI, token, "version"); err == nil {
+		versions = append(versions, out[0].(string))
+	}
+	versions = append(versions, "1", "2")
+
+	for _, version := range versions {
+		p := &Permit{
+			Kind: PermitKindEIP2612, ChainID: chainID, Token: token,
+			Amount: new(big.Int), Nonce: new(big.Int), Deadline: new(big.Int),
+			DomainName: name, DomainVersion: version,
+		}
+		td, err := p.TypedData()
+		if err != nil {
+			continue
+		}
+		h, err := td.Hash()
+		if err != nil {
+			continue
+		}
+		if [32]byte(h.DomainSeparator) == separator {
+			return &PermitSupport{Supported: true, Name: name, Version: version}, nil
+		}
+	}
+	return &PermitSupport{Reason: "DOMAIN_SEPARATOR() does not match a standard EIP-712 domain for this token"}, nil
+}
+
+// PermitNonce returns owner's current EIP-2612 nonce on token.
+func (c *Client) PermitNonce(ctx context.Context, token, owner ethcommon.Address) (*big.Int, error) {
+	out, err := c.callABI(ctx, permitABI, token, "nonces", owner)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// Permit2Allowance is a Permit2 allowance: amount spender may pull until
+// Expiration (unix seconds), and the owner's next PermitSingle nonce.
+type Permit2Allowance struct {
+	Amount     *big.Int
+	Expiration uint64
+	Nonce      uint64
+}
+
+// Permit2Allowance reads Permit2's allowance(owner, token, spender).
+func (c *Client) Permit2Allowance(ctx context.Context, owner, token, spender ethcommon.Address) (*Permit2Allowance, error) {
+	out, err := c.callABI(ctx, permitABI, Permit2Address, "allowance", owner, token, spender)
+	if err != nil {
+		return nil, err
+	}
+	return &Permit2Allowance{
+		Amount:     out[0].(*big.Int),
+		Expiration: out[1].(*big.Int).Uint64(),
+		Nonce:      out[2].(*big.Int).Uint64(),
+	}, nil
+}
+
+// Permit is an off-chain token approval to be signed as EIP-712 typed data.
+type Permit struct {
+	Kind    string
+	ChainID *big.Int
+	Token   ethcommon.Address
+	Owner   ethcommon.Address
+	Spender ethcommon.Address
+	Amount  *big.Int
+	Nonce   *big.Int
+	// Deadline is the EIP-2612 deadline or the Permit2 sigDeadline.
+	Deadline *big.Int
+	// Expiration is when a Permit2 allowance lapses. Unused for EIP-2612.
+	Expiration *big.Int
+	// DomainName and DomainVersion are the token's EIP-712 domain. Unused
+	// for Permit2, whose domain is fixed.
+	DomainName    string
+	DomainVersion string
+}
+
+type permitJSON struct {
+	Kind          string `json:"kind"`
+	ChainID       string `json:"chain_id"`
+	Token         string `json:"token"`
+	Owner         string `json:"owner"`
+	Spender       string `json:"spender"`
+	Amount        string `json:"amount"`
+	Nonce         string `json:"nonce"`
+	Deadline      string `json:"deadline"`
+	Expiration    string `json:"expiration,omitempty"`
+	DomainName    string `json:"domain_name,omitempty"`
+	DomainVersion string `json:"domain_version,omitempty"`
+}
+
+// MarshalJSON encodes the permit with decimal-string amounts, the form the
+// builders accept back.
+func (p *Permit) MarshalJSON() ([]byte, error) {
+	j := permitJSON{
+		Kind:          p.Kind,
+		ChainID:       p.ChainID.String(),
+		Token:         p.Token.Hex(),
+		Owner:         p.Owner.Hex(),
+		Spender:       p.Spender.Hex(),
+		Amount:        p.Amount.String(),
+		Nonce:         p.Nonce.String(),
+		Deadline:      p.Deadline.String(),
+		DomainName:    p.DomainName,
+		DomainVersion: p.DomainVersion,
+	}
+	if p.Kind == PermitKindPermit2 {
+		j.Expiration = p.Expiration.String()
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes and validates a permit produced by MarshalJSON.
+func (p *Permit) UnmarshalJSON(data []byte) error {
+	var j permitJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	var errs []error
+	addr := func(name, s string) ethcommon.Address {
+		if !ethcommon.IsHexAddress(s) {
+			errs = append(errs, fmt.Errorf("invalid %s address: %q", name, s))
+		}
+		return ethcommon.HexToAddress(s)
+	}
+	num := func(name, s string, limit *big.Int) *big.Int {
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok || n.Sign() < 0 || (limit != nil && n.Cmp(limit) > 0) {
+			errs = append(errs, fmt.Errorf("invalid %s: %q", name, s))
+			return new(big.Int)
+		}
+		return n
+	}
+
+	*p = Permit{
+		Kind:          j.Kind,
+		ChainID:       num("chain_id", j.ChainID, nil),
+		Token:         addr("token", j.Token),
+		Owner:         addr("owner", j.Owner),
+		Spender:       addr("spender", j.Spender),
+		DomainName:    j.DomainName,
+		DomainVersion: j.DomainVersion,
+	}
+	switch j.Kind {
+	case PermitKindEIP2612:
+		p.Amount = num("amount", j.Amount, nil)
+		p.Nonce = num("nonce", j.Nonce, nil)
+		p.Deadline = num("deadline", j.Deadline, nil)
+		if j.DomainName == "" {
+			errs = append(errs, errors.New("domain_name is required for eip2612 permits"))
+		}
+	case PermitKindPermit2:
+		p.Amount = num("amount", j.Amount, maxUint160)
+		p.Nonce = num("nonce", j.Nonce, maxUint48)
+		p.Deadline = num("deadline", j.Deadline, nil)
+		p.Expiration = num("expiration", j.Expiration, maxUint48)
+	default:
+		errs = append(errs, fmt.Errorf("invalid kind %q (expected %q or %q)", j.Kind, PermitKindEIP2612, PermitKindPermit2))
+	}
+	return errors.Join(errs...)
+}
+
+// ParsePermit decodes a permit tool argument, given as an object or a JSON
+// string.
+func ParsePermit(arg any) (*Permit, error) {
+	var raw []byte
+	switch v := arg.(type) {
+	case string:
+		raw = []byte(v)
+	case map[string]any:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid permit: %w", err)
+		}
+		raw = encoded
+	default:
+		return nil, errors.New("permit must be the object returned by build_permit")
+	}
+	var p Permit
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("invalid permit: %w", err)
+	}
+	return &p, nil
+}
+
+// TypedData returns the EIP-712 payload the owner signs.
+func (p *Permit) TypedData() (*TypedData, error) {
+	var payload map[string]any
+	switch p.Kind {
+	case PermitKindEIP2612:
+		domain := map[string]any{
+			"name":              p.DomainName,
+			"chainId":           p.ChainID.String(),
+			"verifyingContract": p.Token.Hex(),
+		}
+		domainType := []map[string]string{{"name": "name", "type": "string"}}
+		if p.DomainVersion != "" {
+			domain["version"] = p.DomainVersion
+			domainType = append(domainType, map[string]string{"name": "version", "type": "string"})
+		}
+		domainType = append(domainType,
+			map[string]string{"name": "chainId", "type": "uint256"},
+			map[string]string{"name": "verifyingContract", "type": "address"},
+		)
+		payload = map[string]any{
+			"primaryType": "Permit",
+			"domain":      domain,
+			"types": map[string]any{
+				"EIP712Domain": domainType,
+				"Permit": []map[string]string{
+					{"name": "owner", "type": "address"},
+					{"name": "spender", "type": "address"},
+					{"name": "value", "type": "uint256"},
+					{"name": "nonce", "type": "uint256"},
+					{"name": "deadline", "type": "uint256"},
+				},
+			},
+			"message": map[string]any{
+				"owner":    p.Owner.Hex(),
+				"spender":  p.Spender.Hex(),
+				"value":    p.Amount.String(),
+				"nonce":    p.Nonce.String(),
+				"deadline": p.Deadline.String(),
+			},
+		}
+	case PermitKindPermit2:
+		payload = map[string]any{
+			"primaryType": "PermitSingle",
+			"domain": map[string]any{
+				"name":              "Permit2",
+				"chainId":           p.ChainID.String(),
+				"verifyingContract": Permit2Address.Hex(),
+			},
+			"types": map[string]any{
+				"PermitSingle": []map[string]string{
+					{"name": "details", "type": "PermitDetails"},
+					{"name": "spender", "type": "address"},
+					{"name": "sigDeadline", "type": "uint256"},
+				},
+				"PermitDetails": []map[string]string{
+					{"name": "token", "type": "address"},
+					{"name": "amount", "type": "uint160"},
+					{"name": "expiration", "type": "uint48"},
+					{"name": "nonce", "type": "uint48"},
+				},
+			},
+			"message": map[string]any{
+				"details": map[string]any{
+					"token":      p.Token.Hex(),
+					"amount":     p.Amount.String(),
+					"expiration": p.Expiration.String(),
+					"nonce":      p.Nonce.String(),
+				},
+				"spender":     p.Spender.Hex(),
+				"sigDeadline": p.Deadline.String(),
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported permit kind %q", p.Kind)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTypedData(raw)
+}
+
+// VerifySignature checks that sig is the owner's signature over the permit.
+func (p *Permit) VerifySignature(sig []byte) error {
+	td, err := p.TypedData()
+	if err != nil {
+		return err
+	}
+	h, err := td.Hash()
+	if err != nil {
+		return err
+	}
+	signer, err := RecoverTypedDataSigner(h.Digest, sig)
+	if err != nil {
+		return err
+	}
+	if signer != p.Owner {
+		return fmt.Errorf("permit signature is from %s, not the permit owner %s", signer.Hex(), p.Owner.Hex())
+	}
+	return nil
+}
+
+// ParseSignedPermit decodes the permit and permit_signature arguments of a
+// builder that can replace an approve transaction, and checks that the
+// permit is unexpired and signed by its owner. It returns nil, nil, nil when
+// neither argument is set.
+func ParseSignedPermit(permitArg any, sigHex string) (*Permit, []byte, error) {
+	if permitArg == nil && sigHex == "" {
+		return nil, nil, nil
+	}
+	if permitArg == nil || sigHex == "" {
+		return nil, nil, errors.New("permit and permit_signature must be passed together")
+	}
+	p, err := ParsePermit(permitArg)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := hexutil.Decode(sigHex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid permit_signature hex: %w", err)
+	}
+	if p.Deadline.Cmp(big.NewInt(time.Now().Unix())) <= 0 {
+		return nil, nil, fmt.Errorf("permit deadline %s has passed; build a new permit", p.Deadline)
+	}
+	if err := p.VerifySignature(sig); err != nil {
+		return nil, nil, err
+	}
+	return p, sig, nil
+}
+
+// SplitSignature splits a 65-byte r || s || v signature, returning v as
+// 27 or 28.
+func SplitSignature(sig []byte) (v uint8, r, s [32]byte, err error) {
+	if len(sig) != crypto.SignatureLength {
+		return 0, r, s, fmt.Errorf("signature must be %d bytes, got %d", crypto.SignatureLength, len(sig))
+	}
+	copy(r[:], sig[:32])
+	copy(s[:], sig[32:64])
+	v = sig[64]
+	if v < 27 {
+		v += 27
+	}
+	return v, r, s, nil
+}
+
+var (
+	abiAddress, _ = abi.NewType("address", "", nil)
+	abiUint256, _ = abi.NewType("uint256", "", nil)
+	abiUint8, _   = abi.NewType("uint8", "", nil)
+	abiBytes32, _ = abi.NewType("bytes32", "", nil)
+	abiBytes, _   = abi.NewType("bytes", "", nil)
+
+	permitSingleType, _ = abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "details", Type: "tuple", Components: []abi.ArgumentMarshaling{
+			{Name: "token", Type: "address"},
+			{Name: "amount", Type: "uint160"},
+			{Name: "expiration", Type: "uint48"},
+			{Name: "nonce", Type: "uint48"},
+		}},
+		{Name: "spender", Type: "address"},
+		{Name: "sigDeadline", Type: "uint256"},
+	})
+)
+
+// PackEIP2612Call returns abi.encode(owner, spender, value, deadline, v, r,
+// s), the arguments of the token's permit call.
+func (p *Permit) PackEIP2612Call(sig []byte) ([]byte, error) {
+	if p.Kind != PermitKindEIP2612 {
+		return nil, fmt.Errorf("expected an %s permit, got %s", PermitKindEIP2612, p.Kind)
+	}
+	v, r, s, err := SplitSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	args := abi.Arguments{
+		{Type: abiAddress}, {Type: abiAddress}, {Type: abiUint256}, {Type: abiUint256},
+		{Type: abiUint8}, {Type: abiBytes32}, {Type: abiBytes32},
+	}
+	return args.Pack(p.Owner, p.Spender, p.Amount, p.Deadline, v, r, s)
+}
+
+// PackPermit2Single returns abi.encode(PermitSingle, signature), the input
+// of Permit2's permit(owner, PermitSingle, signature) after the owner.
+func (p *Permit) PackPermit2Single(sig []byte) ([]byte, error) {
+	if p.Kind != PermitKindPermit2 {
+		return nil, fmt.Errorf("expected a %s permit, got %s", PermitKindPermit2, p.Kind)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("signature must be %d bytes, got %d", crypto.SignatureLength, len(sig))
+	}
+	type details struct {
+		Token      ethcommon.Address
+		Amount     *big.Int
+		Expiration *big.Int
+		Nonce      *big.Int
+	}
+	single := struct {
+		Details     details
+		Spender     ethcommon.Address
+		SigDeadline *big.Int
+	}{
+		Details:     details{Token: p.Token, Amount: p.Amount, Expiration: p.Expiration, Nonce: p.Nonce},
+		Spender:     p.Spender,
+		SigDeadline: p.Deadline,
+	}
+	args := abi.Arguments{{Type: permitSingleType}, {Type: abiBytes}}
+	return args.Pack(single, sig)
+}
+
+// Describe returns a one-line summary of the permit.
+func (p *Permit) Describe(symbol string, decimals int) string {
+	amount := FormatUnits(p.Amount, decimals) + " " + symbol
+	if p.Amount.Cmp(UnlimitedAllowance) >= 0 {
+		amount = "unlimited " + symbol
+	}
+	spender := p.Spender.Hex()
+	if label := SpenderLabel(p.Spender); label != "" {
+		spender = label
+	}
+	via := "EIP-2612 permit"
+	if p.Kind == PermitKindPermit2 {
+		via = "Permit2"
+	}
+	return strings.TrimSpace(fmt.Sprintf("Allow %s to spend %s from %s via %s", spender, amount, p.Owner.Hex(), via))
+}
@@ -0,0 +1,154 @@
+package evm
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testPermitKey is the private key of the example account in the web3.js
+// documentation.
+const testPermitKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+func testPermits(owner ethcommon.Address) []*Permit {
+	usdc := ethcommon.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	router := ethcommon.HexToAddress("0x111111125421cA6dc452d289314280a0f8842A65")
+	deadline := big.NewInt(time.Now().Add(time.Hour).Unix())
+	return []*Permit{
+		{
+			Kind: PermitKindEIP2612, ChainID: big.NewInt(1), Token: usdc, Owner: owner, Spender: router,
+			Amount: big.NewInt(100_000_000), Nonce: big.NewInt(3), Deadline: deadline,
+			DomainName: "USD Coin", DomainVersion: "2",
+		},
+		{
+			Kind: PermitKindPermit2, ChainID: big.NewInt(1), Token: usdc, Owner: owner, Spender: router,
+			Amount: big.NewInt(100_000_000), Nonce: big.NewInt(5), Deadline: deadline,
+			Expiration: big.NewInt(time.Now().Add(24 * time.Hour).Unix()),
+		},
+	}
+}
+
+func TestPermitTypedData(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPermitKey)
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	other, _ := crypto.GenerateKey()
+
+	typeHashes := map[string]ethcommon.Hash{
+		"Permit": eip2612PermitTypeHash,
+		"PermitSingle": crypto.Keccak256Hash([]byte("PermitSingle(PermitDetails details,address spender,uint256 sigDeadline)" +
+			"PermitDetails(address token,uint160 amount,uint48 expiration,uint48 nonce)")),
+	}
+
+	for _, p := range testPermits(owner) {
+		td, err := p.TypedData()
+		if err != nil {
+			t.Fatalf("%s: TypedData: %v", p.Kind, err)
+		}
+		if got := ethcommon.BytesToHash(td.TypeHash(td.PrimaryType)); got != typeHashes[td.PrimaryType] {
+			t.Errorf("%s: %s type hash = %s", p.Kind, td.PrimaryType, got.Hex())
+		}
+		h, err := td.Hash()
+		if err != nil {
+			t.Fatalf("%s: Hash: %v", p.Kind, err)
+		}
+
+		// The JSON form round-trips to the same digest.
+		raw, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", p.Kind, err)
+		}
+		parsed, err := ParsePermit(string(raw))
+		if err != nil {
+			t.Fatalf("%s: ParsePermit: %v", p.Kind, err)
+		}
+		td2, _ := parsed.TypedData()
+		h2, _ := td2.Hash()
+		if hexutil.Encode(h2.Digest) != hexutil.Encode(h.Digest) {
+			t.Errorf("%s: round-trip digest %x, want %x", p.Kind, h2.Digest, h.Digest)
+		}
+
+		sig, _ := crypto.Sign(h.Digest, key)
+		if err := p.VerifySignature(sig); err != nil {
+			t.Errorf("%s: VerifySignature: %v", p.Kind, err)
+		}
+		otherSig, _ := crypto.Sign(h.Digest, other)
+		if err := p.VerifySignature(otherSig); err == nil {
+			t.Errorf("%s: signature by another key accepted", p.Kind)
+		}
+
+		if _, _, err := ParseSignedPermit(json.RawMessage(raw), hexutil.Encode(sig)); err == nil {
+			t.Errorf("%s: permit of unsupported type accepted", p.Kind)
+		}
+		var obj map[string]any
+		_ = json.Unmarshal(raw, &obj)
+		if _, _, err := ParseSignedPermit(obj, hexutil.Encode(sig)); err != nil {
+			t.Errorf("%s: ParseSignedPermit: %v", p.Kind, err)
+		}
+	}
+}
+
+func TestParseSignedPermit_Invalid(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPermitKey)
+	p := testPermits(crypto.PubkeyToAddress(key.PublicKey))[0]
+	p.Deadline = big.NewInt(time.Now().Add(-time.Minute).Unix())
+	td, _ := p.TypedData()
+	h, _ := td.Hash()
+	sig, _ := crypto.Sign(h.Digest, key)
+	raw, _ := json.Marshal(p)
+
+	if got, sig, err := ParseSignedPermit(nil, ""); got != nil || sig != nil || err != nil {
+		t.Errorf("no permit: got %v, %x, %v", got, sig, err)
+	}
+	for name, tc := range map[string]struct {
+		permit any
+		sig    string
+	}{
+		"expired":        {string(raw), hexutil.Encode(sig)},
+		"no signature":   {string(raw), ""},
+		"no permit":      {nil, hexutil.Encode(sig)},
+		"bad kind":       {`{"kind":"dai"}`, hexutil.Encode(sig)},
+		"amount too big": {`{"kind":"permit2","chain_id":"1","amount":"` + new(big.Int).Lsh(big.NewInt(1), 160).String() + `"}`, hexutil.Encode(sig)},
+	} {
+		if _, _, err := ParseSignedPermit(tc.permit, tc.sig); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestPermitPacking(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPermitKey)
+	permits := testPermits(crypto.PubkeyToAddress(key.PublicKey))
+	sig := make([]byte, 65)
+	sig[64] = 1
+
+	args, err := permits[0].PackEIP2612Call(sig)
+	if err != nil {
+		t.Fatalf("PackEIP2612Call: %v", err)
+	}
+	// owner, spender, value, deadline, v, r, s; v normalised to 28.
+	if len(args) != 7*32 || args[4*32+31] != 28 {
+		t.Errorf("eip2612 args: %d bytes, v = %d", len(args), args[4*32+31])
+	}
+
+	single, err := permits[1].PackPermit2Single(sig)
+	if err != nil {
+		t.Fatalf("PackPermit2Single: %v", err)
+	}
+	// Six static PermitSingle words, the signature offset, its length and
+	// the signature padded to three words.
+	if len(single) != 11*32 || new(big.Int).SetBytes(single[6*32:7*32]).Int64() != 7*32 {
+		t.Errorf("permit2 input: %d bytes, offset %x", len(single), single[6*32:7*32])
+	}
+
+	if _, err := permits[0].PackPermit2Single(sig); err == nil {
+		t.Error("PackPermit2Single on an eip2612 permit: expected error")
+	}
+	if _, err := permits[1].PackEIP2612Call(sig); err == nil {
+		t.Error("PackEIP2612Call on a permit2 permit: expected error")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+
+	"github.com/vultisig/mcp/internal/tokenmeta"
 )
 
 type entry struct {
@@ -17,15 +19,26 @@ type Pool struct {
 	urls    map[string]string
 	mu      sync.Mutex
 	clients map[string]*entry
+	tokens  *tokenmeta.Registry
 }
 
+// NewPool returns a pool whose clients cache token metadata in memory.
 func NewPool(urls map[string]string) *Pool {
 	return &Pool{
 		urls:    urls,
 		clients: make(map[string]*entry),
+		tokens:  tokenmeta.NewMemoryRegistry(),
 	}
 }
 
+// SetTokenRegistry makes clients created from now on cache token metadata
+// in r. Call it before the first Get.
+func (p *Pool) SetTokenRegistry(r *tokenmeta.Registry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tokens = r
+}
+
 // Get returns the client and chain ID for the named EVM chain.
 // The client is created on first call and cached for subsequent calls.
 func (p *Pool) Get(ctx context.Context, chainName string) (*Client, *big.Int, error) {
@@ -45,12 +58,19 @@ func (p *Pool) Get(ctx context.Context, chainName string) (*Client, *big.Int, er
 	if err != nil {
 		return nil, nil, fmt.Errorf("connect to %s RPC: %w", chainName, err)
 	}
+	p.mu.Lock()
+	client.tokens = p.tokens.Chain(chainName)
+	p.mu.Unlock()
 
 	chainID, err := client.ChainID(ctx)
 	if err != nil {
 		client.Close()
 		return nil, nil, fmt.Errorf("get chain ID for %s: %w", chainName, err)
 	}
+	if want, ok := ChainIDByName(chainName); ok && want.Cmp(chainID) != 0 {
+		client.Close()
+		return nil, nil, fmt.Errorf("%s RPC reports chain ID %s, want %s", chainName, chainID, want)
+	}
 
 	p.mu.Lock()
 	if existing, ok := p.clients[chainName]; ok {
@@ -65,6 +85,18 @@ func (p *Pool) Get(ctx context.Context, chainName string) (*Client, *big.Int, er
 	return client, chainID, nil
 }
 
+// Chains returns the names of the chains with an RPC URL, in EVMChains
+// order.
+func (p *Pool) Chains() []string {
+	var chains []string
+	for _, name := range EVMChains {
+		if p.urls[name] != "" {
+			chains = append(chains, name)
+		}
+	}
+	return chains
+}
+
 // Close closes all open clients.
 func (p *Pool) Close() {
 	p.mu.Lock()
@@ -0,0 +1,94 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Proxy kinds reported by ProxyImplementation.
+const (
+	ProxyEIP1967       = "eip1967"
+	ProxyEIP1967Beacon = "eip1967-beacon"
+	ProxyEIP1822       = "eip1822"
+	ProxyZeppelinOS    = "zeppelinos"
+	ProxyEIP897        = "eip897"
+)
+
+var (
+	// eip1967ImplSlot is bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1).
+	eip1967ImplSlot = ethcommon.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// eip1967BeaconSlot is bytes32(uint256(keccak256("eip1967.proxy.beacon")) - 1).
+	eip1967BeaconSlot = ethcommon.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeee2e1f2cad0b1ed2d8e1ea0")
+	// eip1822Slot is keccak256("PROXIABLE"), used by UUPS proxies.
+	eip1822Slot = crypto.Keccak256Hash([]byte("PROXIABLE"))
+	// zeppelinOSSlot is keccak256("org.zeppelinos.proxy.implementation"),
+	// used by older OpenZeppelin proxies such as USDC's.
+	zeppelinOSSlot = crypto.Keccak256Hash([]byte("org.zeppelinos.proxy.implementation"))
+
+	implementationSelector = crypto.Keccak256([]byte("implementation()"))[:4]
+	proxyTypeSelector      = crypto.Keccak256([]byte("proxyType()"))[:4]
+)
+
+// ProxyImplementation returns the implementation contract behind proxy and
+// the proxy kind, checking the EIP-1967 implementation and beacon slots,
+// the EIP-1822 and ZeppelinOS slots, and finally EIP-897's proxyType() and
+// implementation(). A contract that is not a recognised proxy returns the
+// zero address.
+func (c *Client) ProxyImplementation(ctx context.Context, proxy ethcommon.Address) (ethcommon.Address, string, error) {
+	for _, s := range []struct {
+		slot ethcommon.Hash
+		kind string
+	}{
+		{eip1967ImplSlot, ProxyEIP1967},
+		{eip1967BeaconSlot, ProxyEIP1967Beacon},
+		{eip1822Slot, ProxyEIP1822},
+		{zeppelinOSSlot, ProxyZeppelinOS},
+	} {
+		value, err := c.eth.StorageAt(ctx, proxy, s.slot, nil)
+		if err != nil {
+			return ethcommon.Address{}, "", fmt.Errorf("read proxy slot: %w", err)
+		}
+		addr := ethcommon.BytesToAddress(value)
+		if addr == (ethcommon.Address{}) {
+			continue
+		}
+		if s.kind == ProxyEIP1967Beacon {
+			impl, ok := c.callAddress(ctx, addr, implementationSelector)
+			if !ok {
+				return ethcommon.Address{}, "", fmt.Errorf("beacon %s has no implementation()", addr.Hex())
+			}
+			return impl, s.kind, nil
+		}
+		return addr, s.kind, nil
+	}
+
+	// EIP-897 proxies answer proxyType() with 1 (forwarding) or 2
+	// (upgradeable); requiring it keeps other contracts with an
+	// implementation() getter from being taken for proxies.
+	out, err := c.eth.CallContract(ctx, ethereum.CallMsg{To: &proxy, Data: proxyTypeSelector}, nil)
+	if err != nil || len(out) != 32 {
+		return ethcommon.Address{}, "", nil
+	}
+	if kind := new(big.Int).SetBytes(out); kind.Cmp(big.NewInt(1)) != 0 && kind.Cmp(big.NewInt(2)) != 0 {
+		return ethcommon.Address{}, "", nil
+	}
+	if impl, ok := c.callAddress(ctx, proxy, implementationSelector); ok {
+		return impl, ProxyEIP897, nil
+	}
+	return ethcommon.Address{}, "", nil
+}
+
+// callAddress calls a getter returning a non-zero address.
+func (c *Client) callAddress(ctx context.Context, contract ethcommon.Address, calldata []byte) (ethcommon.Address, bool) {
+	out, err := c.eth.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: calldata}, nil)
+	if err != nil || len(out) != 32 {
+		return ethcommon.Address{}, false
+	}
+	addr := ethcommon.BytesToAddress(out)
+	return addr, addr != (ethcommon.Address{})
+}
@@ -0,0 +1,96 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// proxyRPC serves eth_getStorageAt from slots and answers eth_call with
+// calls, keyed by "to/selector"; anything else is zero.
+func proxyRPC(t *testing.T, slots map[ethcommon.Hash]ethcommon.Address, calls map[string]string) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []json.RawMessage
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_getStorageAt":
+			var slot string
+			_ = json.Unmarshal(req.Params[1], &slot)
+			resp["result"] = ethcommon.BytesToHash(slots[ethcommon.HexToHash(slot)].Bytes()).Hex()
+		case "eth_call":
+			var msg struct {
+				To    string `json:"to"`
+				Input string `json:"input"`
+			}
+			_ = json.Unmarshal(req.Params[0], &msg)
+			if out, ok := calls[strings.ToLower(msg.To)+"/"+msg.Input[:10]]; ok {
+				resp["result"] = out
+			} else {
+				resp["error"] = map[string]any{"code": 3, "message": "execution reverted"}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestProxyImplementation(t *testing.T) {
+	proxy := ethcommon.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	impl := ethcommon.HexToAddress("0x43506849d7c04f9138d1a2050bbf3a0c054402dd")
+	beacon := ethcommon.HexToAddress("0x5a2a4f2f3c18f09179b6703e63d9edd165909073")
+	word := func(a ethcommon.Address) string { return ethcommon.BytesToHash(a.Bytes()).Hex() }
+	key := func(a ethcommon.Address, sel []byte) string {
+		return strings.ToLower(a.Hex()) + "/0x" + ethcommon.Bytes2Hex(sel)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		slots map[ethcommon.Hash]ethcommon.Address
+		calls map[string]string
+		want  ethcommon.Address
+		kind  string
+	}{
+		{"eip1967", map[ethcommon.Hash]ethcommon.Address{eip1967ImplSlot: impl}, nil, impl, ProxyEIP1967},
+		{"beacon", map[ethcommon.Hash]ethcommon.Address{eip1967BeaconSlot: beacon},
+			map[string]string{key(beacon, implementationSelector): word(impl)}, impl, ProxyEIP1967Beacon},
+		{"eip1822", map[ethcommon.Hash]ethcommon.Address{eip1822Slot: impl}, nil, impl, ProxyEIP1822},
+		{"zeppelinos", map[ethcommon.Hash]ethcommon.Address{zeppelinOSSlot: impl}, nil, impl, ProxyZeppelinOS},
+		{"eip897", nil, map[string]string{
+			key(proxy, proxyTypeSelector):      fmt.Sprintf("0x%064x", 2),
+			key(proxy, implementationSelector): word(impl),
+		}, impl, ProxyEIP897},
+		{"implementation getter only", nil, map[string]string{
+			key(proxy, implementationSelector): word(impl),
+		}, ethcommon.Address{}, ""},
+		{"not a proxy", nil, nil, ethcommon.Address{}, ""},
+	} {
+		c := proxyRPC(t, tc.slots, tc.calls)
+		got, kind, err := c.ProxyImplementation(context.Background(), proxy)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got != tc.want || kind != tc.kind {
+			t.Errorf("%s: got %s %q, want %s %q", tc.name, got.Hex(), kind, tc.want.Hex(), tc.kind)
+		}
+	}
+}
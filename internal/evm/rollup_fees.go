@@ -0,0 +1,182 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Fee models. Rollups charge for posting transaction data to L1 on top of,
+// or folded into, L2 execution gas.
+const (
+	// FeeModelL1 is plain gas times gas price.
+	FeeModelL1 = "l1"
+	// FeeModelOPStack adds an L1 data fee, quoted by the GasPriceOracle
+	// predeploy, on top of gas.
+	FeeModelOPStack = "op_stack"
+	// FeeModelArbitrum folds the L1 data cost into the gas limit as extra
+	// gas priced at the L2 base fee.
+	FeeModelArbitrum = "arbitrum"
+	// FeeModelZkSync folds pubdata into the gas limit; zks_estimateFee
+	// quotes the gas and fees.
+	FeeModelZkSync = "zksync"
+)
+
+var (
+	// opGasPriceOracle is the OP-stack GasPriceOracle predeploy.
+	opGasPriceOracle = ethcommon.HexToAddress("0x420000000000000000000000000000000000000F")
+	// arbNodeInterface is Arbitrum's NodeInterface, a virtual contract
+	// that only answers eth_call.
+	arbNodeInterface = ethcommon.HexToAddress("0x00000000000000000000000000000000000000C8")
+)
+
+const rollupFeeABIJSON = `[
+{"type":"function","name":"getL1Fee","stateMutability":"view","inputs":[{"name":"_data","type":"bytes"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"gasEstimateComponents","stateMutability":"payable","inputs":[{"name":"to","type":"address"},{"name":"contractCreation","type":"bool"},{"name":"data","type":"bytes"}],"outputs":[{"name":"gasEstimate","type":"uint64"},{"name":"gasEstimateForL1","type":"uint64"},{"name":"baseFee","type":"uint256"},{"name":"l1BaseFeeEstimate","type":"uint256"}]}
+]`
+
+var rollupFeeABI = mustParseABI(rollupFeeABIJSON)
+
+// FeeModel returns the fee model of an EVM chain, FeeModelL1 unless it is
+// a known rollup.
+func FeeModel(chainName string) string {
+	if m := chainDefaults[chainName].feeModel; m != "" {
+		return m
+	}
+	return FeeModelL1
+}
+
+// RollupFee is the rollup-specific part of a transaction's cost.
+type RollupFee struct {
+	Model string
+	// L1Fee is the cost of posting the transaction to L1, in wei. OP-stack
+	// chains charge it on top of gas; on Arbitrum it is L1Gas at BaseFee
+	// and already part of GasEstimate.
+	L1Fee *big.Int
+	L1Gas uint64
+	// GasEstimate is the total gas including the L1 or pubdata part, on
+	// Arbitrum and zkSync.
+	GasEstimate uint64
+	// BaseFee and L1BaseFee are Arbitrum's L2 base fee and estimated L1
+	// base fee.
+	BaseFee   *big.Int
+	L1BaseFee *big.Int
+	// MaxFeePerGas, MaxPriorityFeePerGas and GasPerPubdataLimit are
+	// zks_estimateFee's suggestion on zkSync.
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	GasPerPubdataLimit   uint64
+}
+
+// ExtraFee returns the fee charged on top of gas times gas price: the L1
+// fee on OP-stack chains, zero otherwise. A nil fee has none.
+func (f *RollupFee) ExtraFee() *big.Int {
+	if f == nil || f.Model != FeeModelOPStack || f.L1Fee == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(f.L1Fee)
+}
+
+// EstimateRollupFee quotes the rollup fees of msg on chainName. unsignedTx
+// is the transaction's unsigned serialization, which OP-stack chains price
+// by size. It returns nil on chains with the L1 fee model.
+func (c *Client) EstimateRollupFee(ctx context.Context, chainName string, msg ethereum.CallMsg, unsignedTx []byte) (*RollupFee, error) {
+	switch model := FeeModel(chainName); model {
+	case FeeModelOPStack:
+		fee, err := c.OPStackL1Fee(ctx, unsignedTx)
+		if err != nil {
+			return nil, err
+		}
+		return &RollupFee{Model: model, L1Fee: fee}, nil
+	case FeeModelArbitrum:
+		return c.ArbitrumGasComponents(ctx, msg)
+	case FeeModelZkSync:
+		return c.ZkSyncEstimateFee(ctx, msg)
+	default:
+		return nil, nil
+	}
+}
+
+// OPStackL1Fee returns the L1 data fee of an unsigned serialized
+// transaction from the GasPriceOracle's getL1Fee.
+func (c *Client) OPStackL1Fee(ctx context.Context, unsignedTx []byte) (*big.Int, error) {
+	out, err := c.callABI(ctx, rollupFeeABI, opGasPriceOracle, "getL1Fee", unsignedTx)
+	if err != nil {
+		return nil, fmt.Errorf("l1 fee: %w", err)
+	}
+	return out[0].(*big.Int), nil
+}
+
+// ArbitrumGasComponents splits the gas of msg into L2 execution and L1
+// data with NodeInterface.gasEstimateComponents.
+func (c *Client) ArbitrumGasComponents(ctx context.Context, msg ethereum.CallMsg) (*RollupFee, error) {
+	var to ethcommon.Address
+	if msg.To != nil {
+		to = *msg.To
+	}
+	data, err := rollupFeeABI.Pack("gasEstimateComponents", to, msg.To == nil, msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.eth.CallContract(ctx, ethereum.CallMsg{
+		From:  msg.From,
+		To:    &arbNodeInterface,
+		Value: msg.Value,
+		Data:  data,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call gasEstimateComponents(): %w", err)
+	}
+	out, err := rollupFeeABI.Unpack("gasEstimateComponents", res)
+	if err != nil {
+		return nil, fmt.Errorf("decode gasEstimateComponents: %w", err)
+	}
+	f := &RollupFee{
+		Model:       FeeModelArbitrum,
+		GasEstimate: out[0].(uint64),
+		L1Gas:       out[1].(uint64),
+		BaseFee:     out[2].(*big.Int),
+		L1BaseFee:   out[3].(*big.Int),
+	}
+	f.L1Fee = new(big.Int).Mul(new(big.Int).SetUint64(f.L1Gas), f.BaseFee)
+	return f, nil
+}
+
+// ZkSyncEstimateFee quotes the gas limit and fees of msg with
+// zks_estimateFee. The gas limit covers pubdata.
+func (c *Client) ZkSyncEstimateFee(ctx context.Context, msg ethereum.CallMsg) (*RollupFee, error) {
+	arg := map[string]any{"from": msg.From}
+	if msg.To != nil {
+		arg["to"] = msg.To
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	var res struct {
+		GasLimit             hexutil.Big `json:"gas_limit"`
+		MaxFeePerGas         hexutil.Big `json:"max_fee_per_gas"`
+		MaxPriorityFeePerGas hexutil.Big `json:"max_priority_fee_per_gas"`
+		GasPerPubdataLimit   hexutil.Big `json:"gas_per_pubdata_limit"`
+	}
+	if err := c.rawRPC.CallContext(ctx, &res, "zks_estimateFee", arg); err != nil {
+		return nil, fmt.Errorf("zks_estimateFee: %w", err)
+	}
+	gas := res.GasLimit.ToInt()
+	if !gas.IsUint64() {
+		return nil, fmt.Errorf("zks_estimateFee: gas limit %s out of range", gas)
+	}
+	return &RollupFee{
+		Model:                FeeModelZkSync,
+		GasEstimate:          gas.Uint64(),
+		MaxFeePerGas:         res.MaxFeePerGas.ToInt(),
+		MaxPriorityFeePerGas: res.MaxPriorityFeePerGas.ToInt(),
+		GasPerPubdataLimit:   res.GasPerPubdataLimit.ToInt().Uint64(),
+	}, nil
+}
@@ -0,0 +1,140 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// rollupRPC serves each request with the result of answer.
+func rollupRPC(t *testing.T, answer func(method string, params json.RawMessage) any) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": answer(req.Method, req.Params)})
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// callTarget returns the to address and input of an eth_call.
+func callTarget(params json.RawMessage) (string, string) {
+	var args []struct {
+		To    string `json:"to"`
+		Input string `json:"input"`
+	}
+	_ = json.Unmarshal(params, &args)
+	return strings.ToLower(args[0].To), args[0].Input
+}
+
+func TestFeeModel(t *testing.T) {
+	for chain, want := range map[string]string{
+		"Ethereum": FeeModelL1,
+		"Optimism": FeeModelOPStack,
+		"Base":     FeeModelOPStack,
+		"Mantle":   FeeModelOPStack,
+		"Arbitrum": FeeModelArbitrum,
+		"Zksync":   FeeModelZkSync,
+		"Unknown":  FeeModelL1,
+	} {
+		if got := FeeModel(chain); got != want {
+			t.Errorf("FeeModel(%s) = %s, want %s", chain, got, want)
+		}
+	}
+}
+
+func TestEstimateRollupFee(t *testing.T) {
+	to := ethcommon.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	msg := ethereum.CallMsg{To: &to, Data: []byte{1, 2, 3}}
+
+	t.Run("op stack", func(t *testing.T) {
+		var gotTo, gotInput string
+		c := rollupRPC(t, func(method string, params json.RawMessage) any {
+			gotTo, gotInput = callTarget(params)
+			return hexutil.Encode(ethcommon.LeftPadBytes(big.NewInt(1234).Bytes(), 32))
+		})
+		fee, err := c.EstimateRollupFee(context.Background(), "Optimism", msg, []byte{0x02, 0xaa})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotTo != strings.ToLower(opGasPriceOracle.Hex()) || !strings.HasPrefix(gotInput, "0x49948e0e") {
+			t.Errorf("call to %s with %s", gotTo, gotInput)
+		}
+		if fee.Model != FeeModelOPStack || fee.L1Fee.Int64() != 1234 || fee.ExtraFee().Int64() != 1234 {
+			t.Errorf("fee = %+v", fee)
+		}
+	})
+
+	t.Run("arbitrum", func(t *testing.T) {
+		var gotTo string
+		c := rollupRPC(t, func(method string, params json.RawMessage) any {
+			gotTo, _ = callTarget(params)
+			out, _ := rollupFeeABI.Methods["gasEstimateComponents"].Outputs.Pack(
+				uint64(90_000), uint64(30_000), big.NewInt(10_000_000), big.NewInt(20_000_000_000))
+			return hexutil.Encode(out)
+		})
+		fee, err := c.EstimateRollupFee(context.Background(), "Arbitrum", msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotTo != strings.ToLower(arbNodeInterface.Hex()) {
+			t.Errorf("call to %s", gotTo)
+		}
+		// 30,000 L1 gas at the 0.01 gwei L2 base fee, already in the gas.
+		if fee.GasEstimate != 90_000 || fee.L1Gas != 30_000 || fee.L1Fee.Int64() != 300_000_000_000 || fee.ExtraFee().Sign() != 0 {
+			t.Errorf("fee = %+v", fee)
+		}
+	})
+
+	t.Run("zksync", func(t *testing.T) {
+		c := rollupRPC(t, func(method string, params json.RawMessage) any {
+			if method != "zks_estimateFee" {
+				t.Errorf("method = %s", method)
+			}
+			return map[string]string{
+				"gas_limit":                "0x3d090",
+				"max_fee_per_gas":          "0x2b275d0",
+				"max_priority_fee_per_gas": "0x0",
+				"gas_per_pubdata_limit":    "0xc350",
+			}
+		})
+		fee, err := c.EstimateRollupFee(context.Background(), "Zksync", msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fee.GasEstimate != 250_000 || fee.MaxFeePerGas.Int64() != 45_250_000 || fee.GasPerPubdataLimit != 50_000 || fee.ExtraFee().Sign() != 0 {
+			t.Errorf("fee = %+v", fee)
+		}
+	})
+
+	t.Run("l1", func(t *testing.T) {
+		c := rollupRPC(t, func(string, json.RawMessage) any {
+			t.Error("unexpected RPC call")
+			return nil
+		})
+		fee, err := c.EstimateRollupFee(context.Background(), "Ethereum", msg, nil)
+		if err != nil || fee != nil || fee.ExtraFee().Sign() != 0 {
+			t.Errorf("fee = %+v, err = %v", fee, err)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/vultisig/mcp/internal/tokenmeta"
+)
+
+// errNotERC20 is returned for contracts that answer none of decimals(),
+// symbol() and name().
+var errNotERC20 = errors.New("not an ERC-20 token: decimals(), symbol() and name() all failed")
+
+// requireDecimals fails for tokens without decimals(), such as ERC-721
+// collections, whose amounts cannot be converted to token units.
+func requireDecimals(token string, meta tokenmeta.Metadata) error {
+	if meta.NoDecimals {
+		return fmt.Errorf("token %s has no decimals() function; it may not be an ERC-20 token", token)
+	}
+	return nil
+}
+
+// erc20Read is a named view call for tokenReads.
+type erc20Read struct {
+	name string
+	data []byte
+}
+
+// metadataCalls returns the decimals(), symbol() and name() calls, in that
+// order, that make up a token's metadata.
+func metadataCalls(token ethcommon.Address) []Call {
+	return []Call{
+		{To: token, Data: erc20Codec.PackDecimals(), AllowFailure: true},
+		{To: token, Data: erc20Codec.PackSymbol(), AllowFailure: true},
+		{To: token, Data: erc20Codec.PackName(), AllowFailure: true},
+	}
+}
+
+// tokenReads makes reads against token in one Multicall batch and returns
+// their return data in order along with the token's metadata. Metadata that
+// is not cached yet is read in the same batch and cached. A reverted read
+// fails with its name. Metadata without decimals is returned but not
+// cached, since most such contracts are NFTs rather than tokens.
+func (c *Client) tokenReads(ctx context.Context, token ethcommon.Address, reads ...erc20Read) ([][]byte, tokenmeta.Metadata, error) {
+	meta, cached := c.tokens.Get(token.Hex())
+
+	calls := make([]Call, len(reads), len(reads)+3)
+	for i, r := range reads {
+		calls[i] = Call{To: token, Data: r.data, AllowFailure: true}
+	}
+	if !cached {
+		calls = append(calls, metadataCalls(token)...)
+	}
+	results, err := c.Multicall(ctx, calls, nil)
+	if err != nil {
+		return nil, tokenmeta.Metadata{}, fmt.Errorf("read token %s: %w", token.Hex(), err)
+	}
+
+	out := make([][]byte, len(reads))
+	for i := range reads {
+		if !results[i].Success {
+			return nil, tokenmeta.Metadata{}, fmt.Errorf("call %s(): execution reverted", reads[i].name)
+		}
+		out[i] = results[i].ReturnData
+	}
+	if !cached {
+		meta, err = decodeMetadata(results[len(reads):])
+		if err != nil {
+			return nil, tokenmeta.Metadata{}, fmt.Errorf("token %s: %w", token.Hex(), err)
+		}
+		if !meta.NoDecimals {
+			c.tokens.Put(token.Hex(), meta)
+		}
+	}
+	return out, meta, nil
+}
+
+// decodeMetadata builds token metadata from the results of metadataCalls.
+// Non-standard tokens are tolerated: a bytes32 symbol or name (e.g. MKR) is
+// decoded as a string, a missing decimals() sets NoDecimals, and a missing
+// or undecodable symbol or name is left empty.
+func decodeMetadata(results []CallResult) (tokenmeta.Metadata, error) {
+	dec, sym, name := results[0], results[1], results[2]
+
+	var meta tokenmeta.Metadata
+	if dec.Success && len(dec.ReturnData) > 0 {
+		decimals, err := erc20Codec.UnpackDecimals(dec.ReturnData)
+		if err != nil {
+			return tokenmeta.Metadata{}, fmt.Errorf("decode decimals: %w", err)
+		}
+		meta.Decimals = decimals
+	} else {
+		meta.NoDecimals = true
+	}
+	if sym.Success {
+		meta.Symbol, _ = DecodeABIString(sym.ReturnData)
+	}
+	if name.Success {
+		meta.Name, _ = DecodeABIString(name.ReturnData)
+	}
+
+	if meta.NoDecimals && meta.Symbol == "" && meta.Name == "" {
+		return tokenmeta.Metadata{}, errNotERC20
+	}
+	return meta, nil
+}
+
+// Token returns the metadata of the ERC-20 token at tokenAddr, reading it
+// on first use. Callers that convert amounts must check NoDecimals.
+func (c *Client) Token(ctx context.Context, tokenAddr string) (tokenmeta.Metadata, error) {
+	if meta, ok := c.tokens.Get(tokenAddr); ok {
+		return meta, nil
+	}
+	_, meta, err := c.tokenReads(ctx, ethcommon.HexToAddress(tokenAddr))
+	return meta, err
+}
+
+// TokenMetadata returns the ERC-20 token symbol and decimals. It fails for
+// tokens without decimals().
+func (c *Client) TokenMetadata(ctx context.Context, tokenAddr string) (string, uint8, error) {
+	meta, err := c.Token(ctx, tokenAddr)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := requireDecimals(tokenAddr, meta); err != nil {
+		return "", 0, err
+	}
+	return meta.Symbol, meta.Decimals, nil
+}
+
+// PrefetchTokens reads the metadata of every uncached token in one batch.
+// Contracts that are not ERC-20 tokens or lack decimals() are skipped.
+func (c *Client) PrefetchTokens(ctx context.Context, tokens []ethcommon.Address) error {
+	addrs := make([]string, len(tokens))
+	for i, t := range tokens {
+		addrs[i] = t.Hex()
+	}
+	missing := c.tokens.Missing(addrs)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	calls := make([]Call, 0, 3*len(missing))
+	for _, addr := range missing {
+		calls = append(calls, metadataCalls(ethcommon.HexToAddress(addr))...)
+	}
+	results, err := c.Multicall(ctx, calls, nil)
+	if err != nil {
+		return fmt.Errorf("read token metadata: %w", err)
+	}
+
+	found := make(map[string]tokenmeta.Metadata, len(missing))
+	for i, addr := range missing {
+		if meta, err := decodeMetadata(results[3*i : 3*i+3]); err == nil && !meta.NoDecimals {
+			found[addr] = meta
+		}
+	}
+	c.tokens.PutAll(found)
+	return nil
+}
+
+// Tokens returns the metadata of tokens, reading uncached ones in one
+// batch. Tokens whose metadata cannot be read or that lack decimals() are
+// left out.
+func (c *Client) Tokens(ctx context.Context, tokens []ethcommon.Address) (map[ethcommon.Address]tokenmeta.Metadata, error) {
+	if err := c.PrefetchTokens(ctx, tokens); err != nil {
+		return nil, err
+	}
+	out := make(map[ethcommon.Address]tokenmeta.Metadata, len(tokens))
+	for _, t := range tokens {
+		if meta, ok := c.tokens.Get(t.Hex()); ok {
+			out[t] = meta
+		}
+	}
+	return out, nil
+}
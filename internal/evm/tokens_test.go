@@ -0,0 +1,106 @@
+package evm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	// testMKR returns its symbol and name as bytes32 and has 18 decimals.
+	testMKR = ethcommon.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	// testNoDecimals has a symbol and balanceOf() but no decimals() or name().
+	testNoDecimals = ethcommon.HexToAddress("0x3333333333333333333333333333333333333333")
+)
+
+// nonStandardTokenCall extends testTokenCall with testMKR and
+// testNoDecimals.
+func nonStandardTokenCall(to ethcommon.Address, data []byte) ([]byte, bool) {
+	if len(data) < 4 {
+		return nil, false
+	}
+	selector := hexutil.Encode(data[:4])
+	switch {
+	case to == testMKR && selector == "0x313ce567":
+		return ethcommon.LeftPadBytes([]byte{18}, 32), true
+	case to == testMKR && selector == "0x95d89b41":
+		return ethcommon.RightPadBytes([]byte("MKR"), 32), true
+	case to == testMKR && selector == "0x06fdde03":
+		return ethcommon.RightPadBytes([]byte("Maker"), 32), true
+	case to == testNoDecimals && selector == "0x95d89b41":
+		return ethcommon.RightPadBytes([]byte("OLD"), 32), true
+	case to == testNoDecimals && selector == "0x70a08231":
+		return ethcommon.LeftPadBytes([]byte{1}, 32), true
+	}
+	return testTokenCall(to, data)
+}
+
+func TestToken_NonStandard(t *testing.T) {
+	c, _ := multicallRPC(t, true, nonStandardTokenCall)
+	ctx := context.Background()
+
+	mkr, err := c.Token(ctx, testMKR.Hex())
+	if err != nil {
+		t.Fatalf("MKR: %v", err)
+	}
+	if mkr.Symbol != "MKR" || mkr.Name != "Maker" || mkr.Decimals != 18 || mkr.NoDecimals {
+		t.Errorf("MKR metadata %+v", mkr)
+	}
+
+	old, err := c.Token(ctx, testNoDecimals.Hex())
+	if err != nil {
+		t.Fatalf("no decimals: %v", err)
+	}
+	if old.Symbol != "OLD" || old.Decimals != 0 || !old.NoDecimals {
+		t.Errorf("no-decimals metadata %+v", old)
+	}
+	// It is not cached, and amounts cannot be converted.
+	if _, ok := c.tokens.Get(testNoDecimals.Hex()); ok {
+		t.Error("token without decimals was cached")
+	}
+	if _, _, err := c.TokenMetadata(ctx, testNoDecimals.Hex()); err == nil || !strings.Contains(err.Error(), "no decimals()") {
+		t.Errorf("TokenMetadata err = %v", err)
+	}
+	if _, err := c.GetTokenBalance(ctx, testNoDecimals.Hex(), testHolder.Hex()); err == nil || !strings.Contains(err.Error(), "no decimals()") {
+		t.Errorf("GetTokenBalance err = %v", err)
+	}
+
+	if _, err := c.Token(ctx, testHolder.Hex()); err == nil {
+		t.Error("expected error for a contract that is not a token")
+	}
+	if _, ok := c.tokens.Get(testHolder.Hex()); ok {
+		t.Error("non-token was cached")
+	}
+}
+
+func TestPrefetchTokens(t *testing.T) {
+	c, requests := multicallRPC(t, true, nonStandardTokenCall)
+	ctx := context.Background()
+
+	tokens := []ethcommon.Address{testToken, testMKR, testNoDecimals, testHolder}
+	got, err := c.Tokens(ctx, tokens)
+	if err != nil {
+		t.Fatalf("Tokens: %v", err)
+	}
+	if len(got) != 2 || got[testToken].Symbol != "USDC" || got[testMKR].Symbol != "MKR" {
+		t.Errorf("tokens %+v", got)
+	}
+	if *requests != 1 {
+		t.Errorf("%d eth_calls for prefetch, want 1", *requests)
+	}
+
+	// Cached tokens are not read again; only the non-token and the token
+	// without decimals are retried.
+	if _, err := c.Tokens(ctx, tokens); err != nil {
+		t.Fatalf("Tokens: %v", err)
+	}
+	if _, err := c.GetTokenBalance(ctx, testToken.Hex(), testHolder.Hex()); err != nil {
+		t.Fatalf("GetTokenBalance: %v", err)
+	}
+	if *requests != 3 {
+		t.Errorf("%d eth_calls, want 3", *requests)
+	}
+}
@@ -0,0 +1,100 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ValueTransfer is a native coin transfer made by a contract during a
+// transaction.
+type ValueTransfer struct {
+	From  ethcommon.Address
+	To    ethcommon.Address
+	Value *big.Int
+}
+
+// InternalTransfers returns the native transfers made by the calls a
+// transaction's contracts make, from debug_traceTransaction's call tracer.
+// The top-level call is left out, as are calls that reverted and the
+// frames under them. RPCs without the debug namespace return an error.
+func (c *Client) InternalTransfers(ctx context.Context, txHash ethcommon.Hash) ([]ValueTransfer, error) {
+	var frame callFrame
+	if err := c.rawRPC.CallContext(ctx, &frame, "debug_traceTransaction", txHash, map[string]any{"tracer": "callTracer"}); err != nil {
+		return nil, fmt.Errorf("trace transaction: %w", err)
+	}
+	var transfers []ValueTransfer
+	if frame.Error == "" {
+		collectTransfers(frame.Calls, &transfers)
+	}
+	return transfers, nil
+}
+
+// callFrame is the part of a callTracer frame InternalTransfers reads.
+type callFrame struct {
+	Type  string             `json:"type"`
+	From  ethcommon.Address  `json:"from"`
+	To    *ethcommon.Address `json:"to"`
+	Value *hexutil.Big       `json:"value"`
+	Error string             `json:"error"`
+	Calls []callFrame        `json:"calls"`
+}
+
+func collectTransfers(frames []callFrame, out *[]ValueTransfer) {
+	for _, f := range frames {
+		if f.Error != "" {
+			continue
+		}
+		// DELEGATECALL and STATICCALL report the caller's value without
+		// moving it.
+		switch f.Type {
+		case "CALL", "CREATE", "CREATE2", "SELFDESTRUCT":
+			if f.To != nil && f.Value != nil && f.Value.ToInt().Sign() > 0 {
+				*out = append(*out, ValueTransfer{From: f.From, To: *f.To, Value: f.Value.ToInt()})
+			}
+		}
+		collectTransfers(f.Calls, out)
+	}
+}
+
+// BlockTx is the sender and recipient of a transaction in a block.
+type BlockTx struct {
+	Hash ethcommon.Hash     `json:"hash"`
+	From ethcommon.Address  `json:"from"`
+	To   *ethcommon.Address `json:"to"`
+}
+
+// BlockTransactions returns the sender and recipient of each transaction
+// in block number. It reads them from the RPC's JSON rather than decoding
+// the transactions, so rollup deposit types are included.
+func (c *Client) BlockTransactions(ctx context.Context, number *big.Int) ([]BlockTx, error) {
+	var block *struct {
+		Transactions []BlockTx `json:"transactions"`
+	}
+	if err := c.rawRPC.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeBig(number), true); err != nil {
+		return nil, fmt.Errorf("get block %s: %w", number, err)
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %s not found", number)
+	}
+	return block.Transactions, nil
+}
+
+// ReceiptL1Fee returns the L1 data fee an OP-stack chain charged a mined
+// transaction, from its receipt's l1Fee field, or nil if the receipt has
+// none.
+func (c *Client) ReceiptL1Fee(ctx context.Context, txHash ethcommon.Hash) (*big.Int, error) {
+	var receipt *struct {
+		L1Fee *hexutil.Big `json:"l1Fee"`
+	}
+	if err := c.rawRPC.CallContext(ctx, &receipt, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, fmt.Errorf("get receipt: %w", err)
+	}
+	if receipt == nil || receipt.L1Fee == nil {
+		return nil, nil
+	}
+	return receipt.L1Fee.ToInt(), nil
+}
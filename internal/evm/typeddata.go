@@ -0,0 +1,284 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const eip712DomainType = "EIP712Domain"
+
+// domainFields lists the EIP712Domain fields in the order EIP-712 defines,
+// with their types.
+var domainFields = []apitypes.Type{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+	{Name: "salt", Type: "bytes32"},
+}
+
+// TypedData is a validated EIP-712 payload.
+type TypedData struct {
+	apitypes.TypedData
+}
+
+// TypedDataHash holds the EIP-712 hashes of a payload. Digest is what gets
+// signed: keccak256(0x1901 || DomainSeparator || MessageHash).
+type TypedDataHash struct {
+	Digest          []byte
+	DomainSeparator []byte
+	MessageHash     []byte
+}
+
+// ParseTypedData decodes and normalises an EIP-712 payload
+// ({types, primaryType, domain, message}). A missing EIP712Domain type is
+// inferred from the domain fields, a missing primaryType from the one type
+// no other type references, and JSON numbers are kept exact. The payload is
+// hashed once so type errors surface here.
+func ParseTypedData(raw []byte) (*TypedData, error) {
+	var in struct {
+		Types       apitypes.Types  `json:"types"`
+		PrimaryType string          `json:"primaryType"`
+		Domain      map[string]any  `json:"domain"`
+		Message     json.RawMessage `json:"message"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&in); err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+	if len(in.Types) == 0 {
+		return nil, fmt.Errorf("invalid typed data: types is empty")
+	}
+	if len(in.Domain) == 0 {
+		return nil, fmt.Errorf("invalid typed data: domain is empty")
+	}
+
+	domain, err := parseDomain(in.Domain)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := in.Types[eip712DomainType]; !ok {
+		var inferred []apitypes.Type
+		for _, f := range domainFields {
+			if _, ok := in.Domain[f.Name]; ok {
+				inferred = append(inferred, f)
+			}
+		}
+		in.Types[eip712DomainType] = inferred
+	}
+	if err := checkDomainType(in.Types[eip712DomainType], in.Domain); err != nil {
+		return nil, err
+	}
+
+	if in.PrimaryType == "" {
+		in.PrimaryType, err = inferPrimaryType(in.Types)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if in.PrimaryType == eip712DomainType {
+		return nil, fmt.Errorf("invalid typed data: primaryType cannot be %s", eip712DomainType)
+	}
+	if _, ok := in.Types[in.PrimaryType]; !ok {
+		return nil, fmt.Errorf("invalid typed data: primaryType %q is not defined in types", in.PrimaryType)
+	}
+
+	var message map[string]any
+	dec = json.NewDecoder(bytes.NewReader(in.Message))
+	dec.UseNumber()
+	if err := dec.Decode(&message); err != nil || message == nil {
+		return nil, fmt.Errorf("invalid typed data: message must be an object")
+	}
+
+	td := &TypedData{apitypes.TypedData{
+		Types:       in.Types,
+		PrimaryType: in.PrimaryType,
+		Domain:      domain,
+		Message:     exactNumbers(message).(map[string]any),
+	}}
+	if _, err := td.Hash(); err != nil {
+		return nil, err
+	}
+	return td, nil
+}
+
+// parseDomain converts the domain object, rejecting fields EIP-712 does
+// not define.
+func parseDomain(m map[string]any) (apitypes.TypedDataDomain, error) {
+	var d apitypes.TypedDataDomain
+	for k, v := range m {
+		switch k {
+		case "chainId":
+			var id math.HexOrDecimal256
+			if err := id.UnmarshalText([]byte(fmt.Sprint(v))); err != nil {
+				return d, fmt.Errorf("invalid domain chainId: %v", v)
+			}
+			d.ChainId = &id
+			continue
+		case "name", "version", "verifyingContract", "salt":
+		default:
+			return d, fmt.Errorf("invalid typed data: unsupported domain field %q", k)
+		}
+		s, ok := v.(string)
+		if !ok {
+			return d, fmt.Errorf("invalid domain %s: expected a string, got %v", k, v)
+		}
+		switch k {
+		case "name":
+			d.Name = s
+		case "version":
+			d.Version = s
+		case "verifyingContract":
+			if !ethcommon.IsHexAddress(s) {
+				return d, fmt.Errorf("invalid domain verifyingContract: %s", s)
+			}
+			d.VerifyingContract = s
+		case "salt":
+			if b, err := hexutil.Decode(s); err != nil || len(b) != 32 {
+				return d, fmt.Errorf("invalid domain salt: expected 32 hex bytes, got %s", s)
+			}
+			d.Salt = s
+		}
+	}
+	return d, nil
+}
+
+// checkDomainType ensures the declared EIP712Domain fields and the domain
+// values match one to one, since the domain separator hashes exactly the
+// declared fields.
+func checkDomainType(fields []apitypes.Type, domain map[string]any) error {
+	declared := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		i := slices.IndexFunc(domainFields, func(d apitypes.Type) bool { return d.Name == f.Name })
+		if i < 0 {
+			return fmt.Errorf("invalid typed data: unsupported %s field %q", eip712DomainType, f.Name)
+		}
+		if domainFields[i].Type != f.Type {
+			return fmt.Errorf("invalid typed data: %s.%s must be %s, got %s", eip712DomainType, f.Name, domainFields[i].Type, f.Type)
+		}
+		v, ok := domain[f.Name]
+		if !ok || v == "" {
+			return fmt.Errorf("invalid typed data: domain is missing %s, which %s declares", f.Name, eip712DomainType)
+		}
+		declared[f.Name] = true
+	}
+	for k := range domain {
+		if !declared[k] {
+			return fmt.Errorf("invalid typed data: domain field %s is not declared in %s", k, eip712DomainType)
+		}
+	}
+	return nil
+}
+
+// inferPrimaryType returns the only struct type that no other type
+// references.
+func inferPrimaryType(types apitypes.Types) (string, error) {
+	referenced := make(map[string]bool)
+	for _, fields := range types {
+		for _, f := range fields {
+			referenced[strings.Split(f.Type, "[")[0]] = true
+		}
+	}
+	var roots []string
+	for name := range types {
+		if name != eip712DomainType && !referenced[name] {
+			roots = append(roots, name)
+		}
+	}
+	if len(roots) != 1 {
+		slices.Sort(roots)
+		return "", fmt.Errorf("invalid typed data: primaryType is missing and cannot be inferred (candidates: %s)", strings.Join(roots, ", "))
+	}
+	return roots[0], nil
+}
+
+// exactNumbers replaces json.Number values with their decimal strings,
+// which apitypes encodes without the float64 precision loss.
+func exactNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		return v.String()
+	case map[string]any:
+		for k, e := range v {
+			v[k] = exactNumbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = exactNumbers(e)
+		}
+	}
+	return v
+}
+
+// Hash returns the EIP-712 digest, domain separator and message hash.
+func (td *TypedData) Hash() (*TypedDataHash, error) {
+	domainSeparator, err := td.HashStruct(eip712DomainType, td.Domain.Map())
+	if err != nil {
+		return nil, fmt.Errorf("hash domain: %w", err)
+	}
+	messageHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, fmt.Errorf("hash message: %w", err)
+	}
+	digest := crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, messageHash)
+	return &TypedDataHash{Digest: digest, DomainSeparator: domainSeparator, MessageHash: messageHash}, nil
+}
+
+// ChainID returns the domain chainId, or nil if the domain has none.
+func (td *TypedData) ChainID() *big.Int {
+	if td.Domain.ChainId == nil {
+		return nil
+	}
+	return (*big.Int)(td.Domain.ChainId)
+}
+
+// Normalized returns the payload as eth_signTypedData_v4 JSON, with the
+// EIP712Domain type filled in and chainId as a number.
+func (td *TypedData) Normalized() map[string]any {
+	domain := td.Domain.Map()
+	if id := td.ChainID(); id != nil {
+		domain["chainId"] = id
+	}
+	return map[string]any{
+		"types":       td.Types,
+		"primaryType": td.PrimaryType,
+		"domain":      domain,
+		"message":     td.Message,
+	}
+}
+
+// RecoverTypedDataSigner returns the address that produced the 65-byte
+// r || s || v signature over digest. v may be 0/1 or 27/28.
+func RecoverTypedDataSigner(digest, sig []byte) (ethcommon.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return ethcommon.Address{}, fmt.Errorf("signature must be %d bytes, got %d", crypto.SignatureLength, len(sig))
+	}
+	normalized := slices.Clone(sig)
+	if normalized[64] >= 27 {
+		normalized[64] -= 27
+	}
+	if normalized[64] > 1 {
+		return ethcommon.Address{}, fmt.Errorf("invalid signature recovery id: %d", sig[64])
+	}
+	r := new(big.Int).SetBytes(normalized[:32])
+	s := new(big.Int).SetBytes(normalized[32:64])
+	if !crypto.ValidateSignatureValues(normalized[64], r, s, false) {
+		return ethcommon.Address{}, fmt.Errorf("invalid signature r/s values")
+	}
+	pub, err := crypto.SigToPub(digest, normalized)
+	if err != nil {
+		return ethcommon.Address{}, fmt.Errorf("recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
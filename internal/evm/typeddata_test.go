@@ -0,0 +1,112 @@
+package evm
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// mailTypedData is the example from EIP-712, with chainId as a JSON number.
+const mailTypedData = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "version", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "verifyingContract", "type": "address"}
+    ],
+    "Person": [
+      {"name": "name", "type": "string"},
+      {"name": "wallet", "type": "address"}
+    ],
+    "Mail": [
+      {"name": "from", "type": "Person"},
+      {"name": "to", "type": "Person"},
+      {"name": "contents", "type": "string"}
+    ]
+  },
+  "primaryType": "Mail",
+  "domain": {
+    "name": "Ether Mail",
+    "version": "1",
+    "chainId": 1,
+    "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+  },
+  "message": {
+    "from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+    "to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+    "contents": "Hello, Bob!"
+  }
+}`
+
+const (
+	mailDigest    = "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"
+	mailSignature = "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+	mailSigner = "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"
+)
+
+func TestTypedDataHash(t *testing.T) {
+	// Without EIP712Domain and primaryType both are inferred and the digest
+	// is unchanged.
+	inferred := strings.Replace(mailTypedData, `"primaryType": "Mail",`, "", 1)
+	start := strings.Index(inferred, `"EIP712Domain"`)
+	end := strings.Index(inferred, `"Person"`)
+	inferred = inferred[:start] + inferred[end:]
+
+	for name, raw := range map[string]string{"explicit": mailTypedData, "inferred": inferred} {
+		td, err := ParseTypedData([]byte(raw))
+		if err != nil {
+			t.Fatalf("%s: ParseTypedData: %v", name, err)
+		}
+		h, err := td.Hash()
+		if err != nil {
+			t.Fatalf("%s: Hash: %v", name, err)
+		}
+		if got := hex.EncodeToString(h.Digest); got != mailDigest {
+			t.Errorf("%s: digest = %s, want %s", name, got, mailDigest)
+		}
+		if td.PrimaryType != "Mail" || len(td.Types[eip712DomainType]) != 4 {
+			t.Errorf("%s: primaryType %q, %d domain fields", name, td.PrimaryType, len(td.Types[eip712DomainType]))
+		}
+	}
+}
+
+func TestParseTypedData_Invalid(t *testing.T) {
+	for name, raw := range map[string]string{
+		"not json":          `{`,
+		"extra field":       strings.Replace(mailTypedData, `"contents": "Hello, Bob!"`, `"contents": "Hi", "cc": "x"`, 1),
+		"undeclared domain": strings.Replace(mailTypedData, `"version": "1",`, `"version": "1", "salt": "0x`+strings.Repeat("00", 32)+`",`, 1),
+		"bad address":       strings.Replace(mailTypedData, `"0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"`, `"bob"`, 1),
+		"unknown primary":   strings.Replace(mailTypedData, `"primaryType": "Mail"`, `"primaryType": "Letter"`, 1),
+		"missing message":   strings.Replace(mailTypedData, `"message"`, `"msg"`, 1),
+	} {
+		if _, err := ParseTypedData([]byte(raw)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestRecoverTypedDataSigner(t *testing.T) {
+	digest, _ := hex.DecodeString(mailDigest)
+	sig, _ := hex.DecodeString(mailSignature)
+
+	signer, err := RecoverTypedDataSigner(digest, sig)
+	if err != nil {
+		t.Fatalf("RecoverTypedDataSigner: %v", err)
+	}
+	if signer != ethcommon.HexToAddress(mailSigner) {
+		t.Errorf("signer = %s, want %s", signer.Hex(), mailSigner)
+	}
+
+	// v as 0/1 recovers the same signer; a wrong length does not.
+	sig[64] = 1
+	if signer, err := RecoverTypedDataSigner(digest, sig); err != nil || signer != ethcommon.HexToAddress(mailSigner) {
+		t.Errorf("v=1: signer %s, err %v", signer.Hex(), err)
+	}
+	if _, err := RecoverTypedDataSigner(digest, sig[:64]); err == nil {
+		t.Error("64-byte signature: expected error")
+	}
+}
@@ -0,0 +1,98 @@
+// Package feerate defines priority-tiered fee rate sources for the UTXO
+// chains and routes each chain to the source configured for it.
+package feerate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vultisig/mcp/internal/thorchain"
+)
+
+// Tiers holds fee rates in sat/vB for three confirmation priorities.
+type Tiers struct {
+	Slow   uint64
+	Normal uint64
+	Fast   uint64
+	// Source names where the rates came from (e.g. "mempool", "thorchain").
+	Source string
+	// Derived is set when Slow and Fast are scaled from Normal rather than
+	// quoted by the source.
+	Derived bool
+}
+
+// Source returns fee rate tiers for a chain. Chains are named as in
+// blockchair.SupportedChains (e.g. "Bitcoin", "Bitcoin-Cash").
+type Source interface {
+	Tiers(ctx context.Context, chain string) (*Tiers, error)
+}
+
+// Router sends each chain to the source configured for it, falling back to
+// a default source when there is none or the chain's source fails.
+type Router struct {
+	fallback Source
+	chains   map[string]Source
+}
+
+var _ Source = (*Router)(nil)
+
+// NewRouter creates a Router. chains overrides fallback for the named chains.
+func NewRouter(fallback Source, chains map[string]Source) *Router {
+	return &Router{fallback: fallback, chains: chains}
+}
+
+func (r *Router) Tiers(ctx context.Context, chain string) (*Tiers, error) {
+	src, ok := r.chains[chain]
+	if !ok {
+		return r.fallback.Tiers(ctx, chain)
+	}
+	tiers, err := src.Tiers(ctx, chain)
+	if err == nil {
+		return tiers, nil
+	}
+	fallbackTiers, fallbackErr := r.fallback.Tiers(ctx, chain)
+	if fallbackErr != nil {
+		return nil, err
+	}
+	return fallbackTiers, nil
+}
+
+// thorchainTickers maps chain names to THORChain chain identifiers.
+var thorchainTickers = map[string]string{
+	"Bitcoin":      "BTC",
+	"Litecoin":     "LTC",
+	"Dogecoin":     "DOGE",
+	"Bitcoin-Cash": "BCH",
+}
+
+// ThorchainSource derives tiers from the single THORChain gas rate, which
+// is used as the normal tier. Slow is three quarters of it and fast one and
+// a half times it; these are rules of thumb, so the tiers are marked Derived.
+type ThorchainSource struct {
+	client *thorchain.Client
+}
+
+var _ Source = (*ThorchainSource)(nil)
+
+// NewThorchainSource wraps a THORChain client.
+func NewThorchainSource(client *thorchain.Client) *ThorchainSource {
+	return &ThorchainSource{client: client}
+}
+
+func (s *ThorchainSource) Tiers(ctx context.Context, chain string) (*Tiers, error) {
+	ticker, ok := thorchainTickers[chain]
+	if !ok {
+		return nil, fmt.Errorf("no THORChain fee rate for chain %s", chain)
+	}
+	rate, err := s.client.SatsPerByte(ctx, ticker)
+	if err != nil {
+		return nil, err
+	}
+	return &Tiers{
+		Slow:    max(rate*3/4, 1),
+		Normal:  rate,
+		Fast:    (rate*3 + 1) / 2,
+		Source:  "thorchain",
+		Derived: true,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package feerate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vultisig/mcp/internal/thorchain"
+)
+
+type stubSource struct {
+	tiers *Tiers
+	err   error
+}
+
+func (s stubSource) Tiers(context.Context, string) (*Tiers, error) {
+	return s.tiers, s.err
+}
+
+func TestRouter(t *testing.T) {
+	fallback := stubSource{tiers: &Tiers{Normal: 10, Source: "fallback"}}
+	r := NewRouter(fallback, map[string]Source{
+		"Bitcoin":  stubSource{tiers: &Tiers{Normal: 20, Source: "mempool"}},
+		"Litecoin": stubSource{err: errors.New("down")},
+	})
+
+	for chain, want := range map[string]string{
+		"Bitcoin":  "mempool",
+		"Litecoin": "fallback",
+		"Dogecoin": "fallback",
+	} {
+		tiers, err := r.Tiers(context.Background(), chain)
+		if err != nil || tiers.Source != want {
+			t.Errorf("%s: tiers %+v, err %v; want source %s", chain, tiers, err, want)
+		}
+	}
+
+	r = NewRouter(stubSource{err: errors.New("fallback down")}, map[string]Source{
+		"Bitcoin": stubSource{err: errors.New("mempool down")},
+	})
+	_, err := r.Tiers(context.Background(), "Bitcoin")
+	if err == nil || err.Error() != "mempool down" {
+		t.Errorf("err = %v, want the chain source's error", err)
+	}
+}
+
+func TestThorchainSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"chain":"BTC","gas_rate":"9","halted":false}]`))
+	}))
+	defer srv.Close()
+	src := NewThorchainSource(thorchain.NewClient(srv.URL))
+
+	tiers, err := src.Tiers(context.Background(), "Bitcoin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tiers.Slow != 6 || tiers.Normal != 9 || tiers.Fast != 14 || tiers.Source != "thorchain" || !tiers.Derived {
+		t.Errorf("tiers = %+v, want 6/9/14 from thorchain", tiers)
+	}
+
+	_, err = src.Tiers(context.Background(), "Dash")
+	if err == nil {
+		t.Error("expected error for a chain THORChain does not serve")
+	}
+}
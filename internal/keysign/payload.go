@@ -0,0 +1,97 @@
+package keysign
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	v1 "github.com/vultisig/commondata/go/vultisig/keysign/v1"
+	"github.com/vultisig/vultisig-go/address"
+	"github.com/vultisig/vultisig-go/common"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// Encoded holds a keysign payload in both of the forms the Vultisig apps accept:
+// protobuf JSON (field names as in the .proto) and base64 protobuf bytes.
+type Encoded struct {
+	JSON   json.RawMessage `json:"keysign_payload"`
+	Base64 string          `json:"keysign_payload_base64"`
+}
+
+// NewCoin builds the keysign Coin for a vault on the given chain. The address
+// and derived public key come from address.GetAddress, using the EdDSA root key
+// for EdDSA chains and the ECDSA root key otherwise. An empty contractAddr marks
+// the coin as the chain's native token.
+func NewCoin(vi *vault.Info, chain common.Chain, ticker string, decimals int32, contractAddr string) (*v1.Coin, error) {
+	if vi == nil {
+		return nil, fmt.Errorf("no vault info available — pass vault keys inline or call set_vault_info")
+	}
+
+	rootPubKey := vi.ECDSAPublicKey
+	if chain.IsEdDSA() {
+		rootPubKey = vi.EdDSAPublicKey
+	}
+
+	addr, derivedPubKey, _, err := address.GetAddress(rootPubKey, vi.ChainCode, chain)
+	if err != nil {
+		return nil, fmt.Errorf("derive %s address: %w", chain.String(), err)
+	}
+
+	return &v1.Coin{
+		Chain:           chain.String(),
+		Ticker:          ticker,
+		Address:         addr,
+		ContractAddress: contractAddr,
+		Decimals:        decimals,
+		IsNativeToken:   contractAddr == "",
+		HexPublicKey:    derivedPubKey,
+	}, nil
+}
+
+// NewPayload returns a keysign payload for sending amount (in base units) of
+// coin to toAddress. Callers attach the chain-specific block, memo, UTXOs and
+// swap payload before encoding.
+func NewPayload(vi *vault.Info, coin *v1.Coin, toAddress, amount string) *v1.KeysignPayload {
+	return &v1.KeysignPayload{
+		Coin:                coin,
+		ToAddress:           toAddress,
+		ToAmount:            amount,
+		VaultPublicKeyEcdsa: vi.ECDSAPublicKey,
+	}
+}
+
+// Encode serialises a keysign payload to protobuf JSON and base64 protobuf bytes.
+func Encode(p *v1.KeysignPayload) (*Encoded, error) {
+	jsonBytes, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal keysign payload json: %w", err)
+	}
+
+	raw, err := proto.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal keysign payload: %w", err)
+	}
+
+	return &Encoded{
+		JSON:   jsonBytes,
+		Base64: base64.StdEncoding.EncodeToString(raw),
+	}, nil
+}
+
+// Decode parses a base64 protobuf keysign payload.
+func Decode(encoded string) (*v1.KeysignPayload, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode keysign payload base64: %w", err)
+	}
+
+	var p v1.KeysignPayload
+	err = proto.Unmarshal(raw, &p)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal keysign payload: %w", err)
+	}
+	return &p, nil
+}
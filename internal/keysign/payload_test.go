@@ -0,0 +1,104 @@
+package keysign
+
+import (
+	"testing"
+
+	v1 "github.com/vultisig/commondata/go/vultisig/keysign/v1"
+	"github.com/vultisig/vultisig-go/address"
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+var testVault = &vault.Info{
+	ECDSAPublicKey: "02f6a8148a62320e149cb15c544fe8a25ab483a0095d2280d03b8a00a7feada13d",
+	ChainCode:      "27f8e61e8116e3cf83dbbfde96f23c3c9cd78e3d44695abdc5c6f2d58e92fc67",
+}
+
+func TestNewCoin(t *testing.T) {
+	coin, err := NewCoin(testVault, common.Ethereum, "USDC", 6, "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	if err != nil {
+		t.Fatalf("NewCoin: %v", err)
+	}
+
+	wantAddr, wantPub, _, err := address.GetAddress(testVault.ECDSAPublicKey, testVault.ChainCode, common.Ethereum)
+	if err != nil {
+		t.Fatalf("derive address: %v", err)
+	}
+	if coin.Address != wantAddr {
+		t.Errorf("address = %q, want %q", coin.Address, wantAddr)
+	}
+	if coin.HexPublicKey != wantPub {
+		t.Errorf("hex_public_key = %q, want %q", coin.HexPublicKey, wantPub)
+	}
+	if coin.Chain != "Ethereum" {
+		t.Errorf("chain = %q, want Ethereum", coin.Chain)
+	}
+	if coin.IsNativeToken {
+		t.Error("token with contract address should not be native")
+	}
+
+	native, err := NewCoin(testVault, common.Ethereum, "ETH", 18, "")
+	if err != nil {
+		t.Fatalf("NewCoin native: %v", err)
+	}
+	if !native.IsNativeToken {
+		t.Error("coin without contract address should be native")
+	}
+}
+
+func TestNewCoinNoVault(t *testing.T) {
+	_, err := NewCoin(nil, common.Bitcoin, "BTC", 8, "")
+	if err == nil {
+		t.Fatal("expected error for missing vault")
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	coin, err := NewCoin(testVault, common.Bitcoin, "BTC", 8, "")
+	if err != nil {
+		t.Fatalf("NewCoin: %v", err)
+	}
+	memo := "hello"
+	p := NewPayload(testVault, coin, "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh", "50000")
+	p.Memo = &memo
+	p.BlockchainSpecific = &v1.KeysignPayload_UtxoSpecific{
+		UtxoSpecific: &v1.UTXOSpecific{ByteFee: "10"},
+	}
+	p.UtxoInfo = []*v1.UtxoInfo{{Hash: "abcd", Amount: 100000, Index: 1}}
+
+	enc, err := Encode(p)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if len(enc.JSON) == 0 || enc.Base64 == "" {
+		t.Fatal("expected both JSON and base64 encodings")
+	}
+
+	got, err := Decode(enc.Base64)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.ToAddress != p.ToAddress || got.ToAmount != p.ToAmount {
+		t.Errorf("to = %q/%q, want %q/%q", got.ToAddress, got.ToAmount, p.ToAddress, p.ToAmount)
+	}
+	if got.GetMemo() != memo {
+		t.Errorf("memo = %q, want %q", got.GetMemo(), memo)
+	}
+	if got.GetUtxoSpecific().GetByteFee() != "10" {
+		t.Errorf("byte_fee = %q, want 10", got.GetUtxoSpecific().GetByteFee())
+	}
+	if len(got.UtxoInfo) != 1 || got.UtxoInfo[0].Hash != "abcd" {
+		t.Errorf("utxo_info = %+v", got.UtxoInfo)
+	}
+	if got.VaultPublicKeyEcdsa != testVault.ECDSAPublicKey {
+		t.Errorf("vault_public_key_ecdsa = %q", got.VaultPublicKeyEcdsa)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	_, err := Decode("not base64!")
+	if err == nil {
+		t.Fatal("expected error for invalid base64")
+	}
+}
@@ -0,0 +1,93 @@
+// Package mempool fetches recommended fee rates from a mempool.space-style
+// API (mempool.space for Bitcoin, litecoinspace.org for Litecoin).
+package mempool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vultisig/mcp/internal/feerate"
+)
+
+const feeCacheTTL = time.Minute
+
+// Client queries one mempool instance, which serves a single chain.
+type Client struct {
+	http    *http.Client
+	baseURL string
+
+	mu        sync.Mutex
+	cached    *feerate.Tiers
+	expiresAt time.Time
+}
+
+var _ feerate.Source = (*Client)(nil)
+
+// NewClient creates a client for the instance at baseURL
+// (e.g. "https://mempool.space").
+func NewClient(baseURL string) *Client {
+	return &Client{
+		http:    &http.Client{Timeout: 15 * time.Second},
+		baseURL: baseURL,
+	}
+}
+
+type recommendedFees struct {
+	FastestFee  uint64 `json:"fastestFee"`
+	HalfHourFee uint64 `json:"halfHourFee"`
+	HourFee     uint64 `json:"hourFee"`
+	EconomyFee  uint64 `json:"economyFee"`
+	MinimumFee  uint64 `json:"minimumFee"`
+}
+
+// Tiers returns the instance's recommended fees: fastest as fast, half-hour
+// as normal and hour as slow. chain is ignored since an instance serves one
+// chain. Results are cached for a minute.
+func (c *Client) Tiers(ctx context.Context, chain string) (*feerate.Tiers, error) {
+	c.mu.Lock()
+	if c.cached != nil && time.Now().Before(c.expiresAt) {
+		tiers := *c.cached
+		c.mu.Unlock()
+		return &tiers, nil
+	}
+	c.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/fees/recommended", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mempool fees: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mempool fees returned %d", resp.StatusCode)
+	}
+
+	var fees recommendedFees
+	err = json.NewDecoder(resp.Body).Decode(&fees)
+	if err != nil {
+		return nil, fmt.Errorf("mempool: decode recommended fees: %w", err)
+	}
+	if fees.FastestFee == 0 {
+		return nil, fmt.Errorf("mempool: recommended fees missing")
+	}
+
+	tiers := feerate.Tiers{
+		Slow:   max(fees.HourFee, 1),
+		Normal: max(fees.HalfHourFee, 1),
+		Fast:   fees.FastestFee,
+		Source: "mempool",
+	}
+	c.mu.Lock()
+	c.cached = &tiers
+	c.expiresAt = time.Now().Add(feeCacheTTL)
+	c.mu.Unlock()
+	return &tiers, nil
+}
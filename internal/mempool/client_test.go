@@ -0,0 +1,46 @@
+package mempool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTiers(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/api/v1/fees/recommended" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"fastestFee":25,"halfHourFee":18,"hourFee":12,"economyFee":6,"minimumFee":1}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	tiers, err := c.Tiers(context.Background(), "Bitcoin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tiers.Slow != 12 || tiers.Normal != 18 || tiers.Fast != 25 || tiers.Source != "mempool" {
+		t.Errorf("tiers = %+v", tiers)
+	}
+
+	_, err = c.Tiers(context.Background(), "Bitcoin")
+	if err != nil || calls != 1 {
+		t.Errorf("second call: err %v, %d requests; want a cache hit", err, calls)
+	}
+}
+
+func TestTiers_BadStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL).Tiers(context.Background(), "Bitcoin")
+	if err == nil {
+		t.Error("expected error")
+	}
+}
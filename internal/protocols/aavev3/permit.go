@@ -0,0 +1,84 @@
+package aavev3
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	aavev3sdk "github.com/vultisig/recipes/sdk/evm/aavev3"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+)
+
+const poolPermitABIJSON = `[
+{"type":"function","name":"supplyWithPermit","stateMutability":"nonpayable","inputs":[{"name":"asset","type":"address"},{"name":"amount","type":"uint256"},{"name":"onBehalfOf","type":"address"},{"name":"referralCode","type":"uint16"},{"name":"deadline","type":"uint256"},{"name":"permitV","type":"uint8"},{"name":"permitR","type":"bytes32"},{"name":"permitS","type":"bytes32"}],"outputs":[]},
+{"type":"function","name":"repayWithPermit","stateMutability":"nonpayable","inputs":[{"name":"asset","type":"address"},{"name":"amount","type":"uint256"},{"name":"interestRateMode","type":"uint256"},{"name":"onBehalfOf","type":"address"},{"name":"deadline","type":"uint256"},{"name":"permitV","type":"uint8"},{"name":"permitR","type":"bytes32"},{"name":"permitS","type":"bytes32"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+var poolPermitABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(poolPermitABIJSON))
+	if err != nil {
+		panic(fmt.Sprintf("aavev3: parse pool permit ABI: %v", err))
+	}
+	return parsed
+}()
+
+var interestRateVariable = big.NewInt(2)
+
+func withPermitOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithObject("permit",
+			mcp.Description("EIP-2612 permit from build_permit (spender \"aave_v3\", the same amount). "+
+				"With permit_signature, replaces the approve tx with a single *WithPermit tx."),
+		),
+		mcp.WithString("permit_signature",
+			mcp.Description("Owner's 65-byte signature over the permit's typed data (0x-prefixed)."),
+		),
+	}
+}
+
+// permitTx builds a supplyWithPermit or repayWithPermit call from the
+// request's permit arguments. It returns nil when no permit was passed.
+func permitTx(req mcp.CallToolRequest, chainID *big.Int, pool, asset, user ethcommon.Address, amount *big.Int, method string) (*aavev3sdk.TxData, error) {
+	permit, sig, err := evmclient.ParseSignedPermit(req.GetArguments()["permit"], req.GetString("permit_signature", ""))
+	if err != nil || permit == nil {
+		return nil, err
+	}
+
+	switch {
+	case permit.Kind != evmclient.PermitKindEIP2612:
+		return nil, fmt.Errorf("Aave V3 accepts only EIP-2612 permits, got %s", permit.Kind)
+	case permit.ChainID.Cmp(chainID) != 0:
+		return nil, fmt.Errorf("permit is for chain %s, not %s", permit.ChainID, chainID)
+	case permit.Token != asset:
+		return nil, fmt.Errorf("permit is for token %s, not %s", permit.Token.Hex(), asset.Hex())
+	case permit.Owner != user:
+		return nil, fmt.Errorf("permit owner %s is not the sender %s", permit.Owner.Hex(), user.Hex())
+	case permit.Spender != pool:
+		return nil, fmt.Errorf("permit spender %s is not the Aave V3 Pool %s", permit.Spender.Hex(), pool.Hex())
+	case permit.Amount.Cmp(amount) != 0:
+		return nil, fmt.Errorf("permit amount %s must equal the amount %s", permit.Amount, amount)
+	}
+
+	v, r, s, err := evmclient.SplitSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	switch method {
+	case "supplyWithPermit":
+		data, err = poolPermitABI.Pack(method, asset, amount, user, uint16(0), permit.Deadline, v, r, s)
+	case "repayWithPermit":
+		data, err = poolPermitABI.Pack(method, asset, amount, interestRateVariable, user, permit.Deadline, v, r, s)
+	default:
+		return nil, fmt.Errorf("unsupported permit method %q", method)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pack %s: %w", method, err)
+	}
+	return &aavev3sdk.TxData{To: pool, Data: data, Value: big.NewInt(0)}, nil
+}
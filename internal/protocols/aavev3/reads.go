@@ -0,0 +1,75 @@
+package aavev3
+
+import (
+	"context"
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	aavev3sdk "github.com/vultisig/recipes/sdk/evm/aavev3"
+	"github.com/vultisig/recipes/sdk/evm/codegen/aavev3_dataprovider"
+	"github.com/vultisig/recipes/sdk/evm/codegen/aavev3_pool"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+)
+
+var (
+	poolCodec         = aavev3_pool.NewAavev3Pool()
+	dataProviderCodec = aavev3_dataprovider.NewAavev3Dataprovider()
+)
+
+// reserveInfo is an asset's symbol, rates and configuration.
+type reserveInfo struct {
+	Symbol string
+	Data   *aavev3sdk.ReserveData
+	Config *aavev3sdk.ReserveConfigData
+}
+
+// readReserve reads an asset's symbol, reserve data and reserve
+// configuration in one Multicall batch.
+func readReserve(ctx context.Context, ethClient *evmclient.Client, deploy aavev3sdk.Deployment, asset ethcommon.Address) (*reserveInfo, error) {
+	results, err := ethClient.Multicall(ctx, []evmclient.Call{
+		{To: asset, Data: evmclient.PackERC20Symbol(), AllowFailure: true},
+		{To: deploy.Pool, Data: poolCodec.PackGetReserveData(asset), AllowFailure: true},
+		{To: deploy.DataProvider, Data: dataProviderCodec.PackGetReserveConfigurationData(asset), AllowFailure: true},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range []string{"symbol", "getReserveData", "getReserveConfigurationData"} {
+		if !results[i].Success {
+			return nil, fmt.Errorf("call %s: execution reverted", name)
+		}
+	}
+
+	symbol, err := evmclient.DecodeABIString(results[0].ReturnData)
+	if err != nil {
+		return nil, fmt.Errorf("decode symbol: %w", err)
+	}
+	data, err := poolCodec.UnpackGetReserveData(results[1].ReturnData)
+	if err != nil {
+		return nil, fmt.Errorf("unpack getReserveData: %w", err)
+	}
+	config, err := dataProviderCodec.UnpackGetReserveConfigurationData(results[2].ReturnData)
+	if err != nil {
+		return nil, fmt.Errorf("unpack getReserveConfigurationData: %w", err)
+	}
+	return &reserveInfo{
+		Symbol: symbol,
+		Data: &aavev3sdk.ReserveData{
+			LiquidityRate:      data.CurrentLiquidityRate,
+			VariableBorrowRate: data.CurrentVariableBorrowRate,
+		},
+		Config: &aavev3sdk.ReserveConfigData{
+			Decimals:                 config.Decimals,
+			LTV:                      config.Ltv,
+			LiquidationThreshold:     config.LiquidationThreshold,
+			LiquidationBonus:         config.LiquidationBonus,
+			ReserveFactor:            config.ReserveFactor,
+			UsageAsCollateralEnabled: config.UsageAsCollateralEnabled,
+			BorrowingEnabled:         config.BorrowingEnabled,
+			IsActive:                 config.IsActive,
+			IsFrozen:                 config.IsFrozen,
+		},
+	}, nil
+}
@@ -25,6 +25,9 @@ import (
 const (
 	gasLimitSupply = 300_000
 	gasLimitRepay  = 300_000
+	// gasPermitOverhead covers the token's permit call inside
+	// supplyWithPermit and repayWithPermit.
+	gasPermitOverhead = 80_000
 )
 
 type Protocol struct{}
@@ -40,21 +43,22 @@ func (p *Protocol) Register(s *server.MCPServer, store *vault.Store, ethClient *
 	deploy, _ := aavev3sdk.GetDeployment(chainID)
 	aaveClient := aavev3sdk.NewClient(ethClient, deploy)
 
-	toolmeta.Register(s, newDepositTool(), handleDeposit(store, evmSDK, aaveClient, chainID), "aave")
-	toolmeta.Register(s, newWithdrawTool(), handleWithdraw(store, evmSDK, aaveClient, chainID), "aave")
-	toolmeta.Register(s, newBorrowTool(), handleBorrow(store, evmSDK, aaveClient, chainID), "aave")
-	toolmeta.Register(s, newRepayTool(), handleRepay(store, evmSDK, aaveClient, chainID), "aave")
+	toolmeta.Register(s, newDepositTool(), handleDeposit(store, ethClient, evmSDK, aaveClient, chainID), "aave")
+	toolmeta.Register(s, newWithdrawTool(), handleWithdraw(store, ethClient, evmSDK, aaveClient, chainID), "aave")
+	toolmeta.Register(s, newBorrowTool(), handleBorrow(store, ethClient, evmSDK, aaveClient, chainID), "aave")
+	toolmeta.Register(s, newRepayTool(), handleRepay(store, ethClient, evmSDK, aaveClient, chainID), "aave")
 	toolmeta.Register(s, newGetBalancesTool(), handleGetBalances(store, aaveClient), "aave")
-	toolmeta.Register(s, newGetRatesTool(), handleGetRates(aaveClient), "aave")
+	toolmeta.Register(s, newGetRatesTool(), handleGetRates(ethClient, deploy), "aave")
 }
 
 func newDepositTool() mcp.Tool {
-	return mcp.NewTool("aave_v3_deposit",
-		mcp.WithDescription("Build unsigned transactions to deposit (supply) tokens into Aave V3. Returns an approve tx and a supply tx, both fully populated and ready to sign."),
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Build unsigned transactions to deposit (supply) tokens into Aave V3. Returns an approve tx and a supply tx, both fully populated and ready to sign. With a signed EIP-2612 permit from build_permit, returns a single supplyWithPermit tx instead."),
 		mcp.WithString("asset", mcp.Description("ERC-20 token contract address (0x-prefixed)"), mcp.Required()),
 		mcp.WithString("amount", mcp.Description("Amount to deposit in human-readable units (e.g. \"100.5\") or \"max\" for full balance"), mcp.Required()),
 		mcp.WithString("address", mcp.Description("Depositor's Ethereum address (0x-prefixed). Optional if vault info is set.")),
-	)
+	}
+	return mcp.NewTool("aave_v3_deposit", append(opts, withPermitOptions()...)...)
 }
 
 func newWithdrawTool() mcp.Tool {
@@ -76,12 +80,13 @@ func newBorrowTool() mcp.Tool {
 }
 
 func newRepayTool() mcp.Tool {
-	return mcp.NewTool("aave_v3_repay",
-		mcp.WithDescription("Build unsigned transactions to repay a borrow on Aave V3. Returns an approve tx and a repay tx, both fully populated and ready to sign. Use amount \"max\" to repay entire debt."),
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Build unsigned transactions to repay a borrow on Aave V3. Returns an approve tx and a repay tx, both fully populated and ready to sign. Use amount \"max\" to repay entire debt. With a signed EIP-2612 permit from build_permit, returns a single repayWithPermit tx instead."),
 		mcp.WithString("asset", mcp.Description("ERC-20 token contract address (0x-prefixed)"), mcp.Required()),
 		mcp.WithString("amount", mcp.Description("Amount to repay in human-readable units or \"max\""), mcp.Required()),
 		mcp.WithString("address", mcp.Description("Repayer's Ethereum address (0x-prefixed). Optional if vault info is set.")),
-	)
+	}
+	return mcp.NewTool("aave_v3_repay", append(opts, withPermitOptions()...)...)
 }
 
 func newGetBalancesTool() mcp.Tool {
@@ -126,7 +131,7 @@ type txBuildAction struct {
 	contractName string
 }
 
-func handleDeposit(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.Client, chainID *big.Int) server.ToolHandlerFunc {
+func handleDeposit(store *vault.Store, ethClient *evmclient.Client, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.Client, chainID *big.Int) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		assetStr, amountStr, addr, err := extractTxParams(ctx, req, store)
 		if err != nil {
@@ -141,13 +146,9 @@ func handleDeposit(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk
 			return mcp.NewToolResultError(fmt.Sprintf("build deposit: %v", err)), nil
 		}
 
-		symbol, err := aaveClient.GetTokenSymbol(ctx, asset)
+		symbol, decimals, err := ethClient.TokenMetadata(ctx, asset.Hex())
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token symbol: %v", err)), nil
-		}
-		decimals, err := aaveClient.GetTokenDecimals(ctx, asset)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token decimals: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("get token metadata: %v", err)), nil
 		}
 
 		amount, err := aavev3sdk.ParseAmount(amountStr, int(decimals))
@@ -161,11 +162,21 @@ func handleDeposit(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk
 		}
 		gasOverrides := []uint64{0, gasLimitSupply}
 
+		withPermit, err := permitTx(req, chainID, aaveClient.PoolAddress(), asset, user, amount, "supplyWithPermit")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid permit: %v", err)), nil
+		}
+		if withPermit != nil {
+			txs = []aavev3sdk.TxData{*withPermit}
+			actions = []txBuildAction{{"supply", fmt.Sprintf("Supply %s to Aave V3 with permit", symbol), "Aave V3 Pool"}}
+			gasOverrides = []uint64{gasLimitSupply + gasPermitOverhead}
+		}
+
 		return buildTxResult(ctx, evmSDK, user, chainID, txs, actions, gasOverrides, symbol, asset.Hex(), amountStr, amount.String(), fmt.Sprintf("%d", decimals))
 	}
 }
 
-func handleWithdraw(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.Client, chainID *big.Int) server.ToolHandlerFunc {
+func handleWithdraw(store *vault.Store, ethClient *evmclient.Client, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.Client, chainID *big.Int) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		assetStr, amountStr, addr, err := extractTxParams(ctx, req, store)
 		if err != nil {
@@ -180,13 +191,9 @@ func handleWithdraw(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sd
 			return mcp.NewToolResultError(fmt.Sprintf("build withdraw: %v", err)), nil
 		}
 
-		symbol, err := aaveClient.GetTokenSymbol(ctx, asset)
+		symbol, decimals, err := ethClient.TokenMetadata(ctx, asset.Hex())
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token symbol: %v", err)), nil
-		}
-		decimals, err := aaveClient.GetTokenDecimals(ctx, asset)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token decimals: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("get token metadata: %v", err)), nil
 		}
 
 		amount, err := aavev3sdk.ParseAmount(amountStr, int(decimals))
@@ -202,7 +209,7 @@ func handleWithdraw(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sd
 	}
 }
 
-func handleBorrow(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.Client, chainID *big.Int) server.ToolHandlerFunc {
+func handleBorrow(store *vault.Store, ethClient *evmclient.Client, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.Client, chainID *big.Int) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		assetStr, amountStr, addr, err := extractTxParams(ctx, req, store)
 		if err != nil {
@@ -217,13 +224,9 @@ func handleBorrow(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.
 			return mcp.NewToolResultError(fmt.Sprintf("build borrow: %v", err)), nil
 		}
 
-		symbol, err := aaveClient.GetTokenSymbol(ctx, asset)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token symbol: %v", err)), nil
-		}
-		decimals, err := aaveClient.GetTokenDecimals(ctx, asset)
+		symbol, decimals, err := ethClient.TokenMetadata(ctx, asset.Hex())
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token decimals: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("get token metadata: %v", err)), nil
 		}
 
 		amount, err := aavev3sdk.ParseAmount(amountStr, int(decimals))
@@ -239,7 +242,7 @@ func handleBorrow(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.
 	}
 }
 
-func handleRepay(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.Client, chainID *big.Int) server.ToolHandlerFunc {
+func handleRepay(store *vault.Store, ethClient *evmclient.Client, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.Client, chainID *big.Int) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		assetStr, amountStr, addr, err := extractTxParams(ctx, req, store)
 		if err != nil {
@@ -254,13 +257,9 @@ func handleRepay(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.C
 			return mcp.NewToolResultError(fmt.Sprintf("build repay: %v", err)), nil
 		}
 
-		symbol, err := aaveClient.GetTokenSymbol(ctx, asset)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token symbol: %v", err)), nil
-		}
-		decimals, err := aaveClient.GetTokenDecimals(ctx, asset)
+		symbol, decimals, err := ethClient.TokenMetadata(ctx, asset.Hex())
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token decimals: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("get token metadata: %v", err)), nil
 		}
 
 		amount, err := aavev3sdk.ParseAmount(amountStr, int(decimals))
@@ -274,6 +273,16 @@ func handleRepay(store *vault.Store, evmSDK *evmsdk.SDK, aaveClient *aavev3sdk.C
 		}
 		gasOverrides := []uint64{0, gasLimitRepay}
 
+		withPermit, err := permitTx(req, chainID, aaveClient.PoolAddress(), asset, user, amount, "repayWithPermit")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid permit: %v", err)), nil
+		}
+		if withPermit != nil {
+			txs = []aavev3sdk.TxData{*withPermit}
+			actions = []txBuildAction{{"repay", fmt.Sprintf("Repay %s to Aave V3 (variable rate) with permit", symbol), "Aave V3 Pool"}}
+			gasOverrides = []uint64{gasLimitRepay + gasPermitOverhead}
+		}
+
 		return buildTxResult(ctx, evmSDK, user, chainID, txs, actions, gasOverrides, symbol, asset.Hex(), amountStr, amount.String(), fmt.Sprintf("%d", decimals))
 	}
 }
@@ -323,7 +332,7 @@ Health Factor: %s`,
 	}
 }
 
-func handleGetRates(aaveClient *aavev3sdk.Client) server.ToolHandlerFunc {
+func handleGetRates(ethClient *evmclient.Client, deploy aavev3sdk.Deployment) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		assetStr, err := req.RequireString("asset")
 		if err != nil {
@@ -332,20 +341,11 @@ func handleGetRates(aaveClient *aavev3sdk.Client) server.ToolHandlerFunc {
 
 		asset := ethcommon.HexToAddress(assetStr)
 
-		symbol, err := aaveClient.GetTokenSymbol(ctx, asset)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get token symbol: %v", err)), nil
-		}
-
-		reserveData, err := aaveClient.GetReserveData(ctx, asset)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get reserve data: %v", err)), nil
-		}
-
-		configData, err := aaveClient.GetReserveConfigData(ctx, asset)
+		reserve, err := readReserve(ctx, ethClient, deploy, asset)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get reserve config: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("failed to read reserve: %v", err)), nil
 		}
+		symbol, reserveData, configData := reserve.Symbol, reserve.Data, reserve.Config
 
 		supplyAPY := aavev3sdk.RayToAPY(reserveData.LiquidityRate)
 		borrowAPY := aavev3sdk.RayToAPY(reserveData.VariableBorrowRate)
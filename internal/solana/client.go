@@ -13,18 +13,30 @@ import (
 	"github.com/gagliardetto/solana-go/programs/system"
 	"github.com/gagliardetto/solana-go/programs/token"
 	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/vultisig/mcp/internal/tokenmeta"
 )
 
 type Client struct {
-	rpc *rpc.Client
+	rpc    *rpc.Client
+	tokens *tokenmeta.Chain
 }
 
+// NewClient returns a client that caches mint metadata in memory until
+// SetTokenRegistry is called.
 func NewClient(rpcClient *rpc.Client) *Client {
 	return &Client{
-		rpc: rpcClient,
+		rpc:    rpcClient,
+		tokens: tokenmeta.NewMemoryRegistry().Chain("Solana"),
 	}
 }
 
+// SetTokenRegistry makes the client cache mint metadata in r. Call it
+// before the client is used.
+func (c *Client) SetTokenRegistry(r *tokenmeta.Registry) {
+	c.tokens = r.Chain("Solana")
+}
+
 func ParsePublicKey(addr string) (solana.PublicKey, error) {
 	return solana.PublicKeyFromBase58(addr)
 }
@@ -62,32 +74,47 @@ func (c *Client) GetNativeBalance(ctx context.Context, account solana.PublicKey)
 // GetTokenProgram queries the mint account to determine which token program owns it.
 // Returns (TokenProgramID, 9, nil) for the native SOL mint (So1111...1112) since
 // wSOL is an SPL token with 9 decimals transferred between ATAs like any other token.
+// The program and decimals are cached after the first lookup.
 func (c *Client) GetTokenProgram(ctx context.Context, mint solana.PublicKey) (solana.PublicKey, uint8, error) {
 	if mint == solana.SolMint {
 		return solana.TokenProgramID, 9, nil
 	}
+	if meta, ok := c.tokens.Get(mint.String()); ok {
+		return solana.MustPublicKeyFromBase58(meta.Program), meta.Decimals, nil
+	}
 
 	accountInfo, err := c.rpc.GetAccountInfo(ctx, mint)
 	if err != nil {
 		return solana.PublicKey{}, 0, fmt.Errorf("get mint account info: %w", err)
 	}
-	if accountInfo.Value == nil {
-		return solana.PublicKey{}, 0, fmt.Errorf("mint account not found: %s", mint)
+	meta, err := decodeMint(mint, accountInfo.Value)
+	if err != nil {
+		return solana.PublicKey{}, 0, err
+	}
+	c.tokens.Put(mint.String(), meta)
+
+	return solana.MustPublicKeyFromBase58(meta.Program), meta.Decimals, nil
+}
+
+// decodeMint reads the owning program and decimals from a mint account.
+func decodeMint(mint solana.PublicKey, account *rpc.Account) (tokenmeta.Metadata, error) {
+	if account == nil {
+		return tokenmeta.Metadata{}, fmt.Errorf("mint account not found: %s", mint)
 	}
 
-	owner := accountInfo.Value.Owner
+	owner := account.Owner
 	if owner != solana.TokenProgramID && owner != solana.Token2022ProgramID {
-		return solana.PublicKey{}, 0, fmt.Errorf("mint account not owned by token program: %s", owner)
+		return tokenmeta.Metadata{}, fmt.Errorf("mint account not owned by token program: %s", owner)
 	}
 
-	data := accountInfo.Value.Data.GetBinary()
+	data := account.Data.GetBinary()
 	var mintData token.Mint
-	err = mintData.UnmarshalWithDecoder(bin.NewBinDecoder(data))
+	err := mintData.UnmarshalWithDecoder(bin.NewBinDecoder(data))
 	if err != nil {
-		return solana.PublicKey{}, 0, fmt.Errorf("deserialize mint data: %w", err)
+		return tokenmeta.Metadata{}, fmt.Errorf("deserialize mint data: %w", err)
 	}
 
-	return owner, mintData.Decimals, nil
+	return tokenmeta.Metadata{Decimals: mintData.Decimals, Program: owner.String()}, nil
 }
 
 func (c *Client) GetTokenBalance(ctx context.Context, tokenAccount solana.PublicKey) (uint64, error) {
@@ -123,6 +150,15 @@ func (c *Client) GetMinimumRentExemption(ctx context.Context) (uint64, error) {
 	return lamports, nil
 }
 
+// GetRecentBlockhash returns the latest finalized blockhash in base58.
+func (c *Client) GetRecentBlockhash(ctx context.Context) (string, error) {
+	block, err := c.rpc.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return "", fmt.Errorf("get recent blockhash: %w", err)
+	}
+	return block.Value.Blockhash.String(), nil
+}
+
 func (c *Client) CheckAccountExists(ctx context.Context, account solana.PublicKey) (bool, error) {
 	accountInfo, err := c.rpc.GetAccountInfo(ctx, account)
 	if err != nil {
@@ -0,0 +1,185 @@
+// Package tokenmeta caches token metadata that never changes once a token
+// is deployed: ERC-20 and TRC-20 symbol, name and decimals, and SPL mint
+// decimals and token program.
+package tokenmeta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Metadata is a token's immutable metadata.
+type Metadata struct {
+	Symbol   string `json:"symbol,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Decimals uint8  `json:"decimals"`
+	// NoDecimals marks tokens without a decimals() function, whose amounts
+	// are whole units.
+	NoDecimals bool `json:"no_decimals,omitempty"`
+	// Program is the owning token program of an SPL mint.
+	Program string `json:"program,omitempty"`
+}
+
+// Registry holds token metadata for every chain. Entries are kept for the
+// life of the process and, if the registry has a file, written to it so
+// they survive restarts. It is safe for concurrent use.
+type Registry struct {
+	path string
+
+	mu      sync.RWMutex
+	entries map[string]Metadata
+	writeMu sync.Mutex
+}
+
+// NewMemoryRegistry returns a registry that is not persisted.
+func NewMemoryRegistry() *Registry {
+	return &Registry{entries: make(map[string]Metadata)}
+}
+
+// NewRegistry returns a registry persisted to path, loading any entries
+// already there. An empty path keeps entries in memory only.
+func NewRegistry(path string) (*Registry, error) {
+	r := NewMemoryRegistry()
+	if path == "" {
+		return r, nil
+	}
+	r.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read token cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.entries); err != nil {
+		return nil, fmt.Errorf("parse token cache %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Chain returns the view of the registry for one chain.
+func (r *Registry) Chain(chain string) *Chain {
+	return &Chain{registry: r, chain: chain}
+}
+
+// Len returns the number of cached tokens across all chains.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.entries)
+}
+
+func (r *Registry) get(key string) (Metadata, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.entries[key]
+	return m, ok
+}
+
+// put adds entries. A failure to persist them is logged; they stay cached
+// in memory regardless.
+func (r *Registry) put(entries map[string]Metadata) {
+	if len(entries) == 0 {
+		return
+	}
+	r.mu.Lock()
+	for k, m := range entries {
+		r.entries[k] = m
+	}
+	r.mu.Unlock()
+	if r.path == "" {
+		return
+	}
+	if err := r.save(); err != nil {
+		log.Printf("[tokenmeta] %v", err)
+	}
+}
+
+// save writes all entries to the registry's file, replacing it atomically.
+func (r *Registry) save() error {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	r.mu.RLock()
+	data, err := json.MarshalIndent(r.entries, "", "  ")
+	r.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("marshal token cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write token cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write token cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("write token cache: %w", err)
+	}
+	return nil
+}
+
+// Chain is a registry scoped to one chain. Hex addresses are
+// case-insensitive; base58 ones are not.
+type Chain struct {
+	registry *Registry
+	chain    string
+}
+
+func (c *Chain) key(address string) string {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		address = strings.ToLower(address)
+	}
+	return c.chain + "/" + address
+}
+
+// Get returns the cached metadata of the token at address.
+func (c *Chain) Get(address string) (Metadata, bool) {
+	return c.registry.get(c.key(address))
+}
+
+// Put caches the metadata of the token at address.
+func (c *Chain) Put(address string, m Metadata) {
+	c.PutAll(map[string]Metadata{address: m})
+}
+
+// PutAll caches the metadata of several tokens, keyed by address, with a
+// single write to disk.
+func (c *Chain) PutAll(tokens map[string]Metadata) {
+	entries := make(map[string]Metadata, len(tokens))
+	for address, m := range tokens {
+		entries[c.key(address)] = m
+	}
+	c.registry.put(entries)
+}
+
+// Missing returns the addresses that are not cached yet, without
+// duplicates, in their original order.
+func (c *Chain) Missing(addresses []string) []string {
+	var missing []string
+	seen := make(map[string]bool, len(addresses))
+	for _, a := range addresses {
+		key := c.key(a)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, ok := c.registry.get(key); !ok {
+			missing = append(missing, a)
+		}
+	}
+	return missing
+}
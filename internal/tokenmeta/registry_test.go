@@ -0,0 +1,56 @@
+package tokenmeta
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRegistry_Persist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	r, err := NewRegistry(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mkr := Metadata{Symbol: "MKR", Name: "Maker", Decimals: 18}
+	r.Chain("Ethereum").Put("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2", mkr)
+	r.Chain("Solana").Put("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", Metadata{Decimals: 6, Program: "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"})
+
+	reloaded, err := NewRegistry(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Len() != 2 {
+		t.Fatalf("reloaded %d tokens, want 2", reloaded.Len())
+	}
+	if got, ok := reloaded.Chain("Ethereum").Get("0x9f8f72aa9304c8b593d555f12ef6589cc3a579a2"); !ok || got != mkr {
+		t.Errorf("MKR = %+v, %v", got, ok)
+	}
+	if _, ok := reloaded.Chain("BSC").Get("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"); ok {
+		t.Error("entry leaked across chains")
+	}
+	if _, ok := reloaded.Chain("Solana").Get("epjfwdd5aufqssqem2qn1xzybapc8g4wegGkZwyTDt1v"); ok {
+		t.Error("base58 address matched case-insensitively")
+	}
+}
+
+func TestRegistry_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRegistry(path); err == nil {
+		t.Error("expected error for a corrupt cache file")
+	}
+}
+
+func TestChain_Missing(t *testing.T) {
+	c := NewMemoryRegistry().Chain("Ethereum")
+	c.Put("0xAAAA", Metadata{Symbol: "A"})
+
+	got := c.Missing([]string{"0xaaaa", "0xBBBB", "0xbbbb", "0xCCCC"})
+	if want := []string{"0xBBBB", "0xCCCC"}; !slices.Equal(got, want) {
+		t.Errorf("Missing = %v, want %v", got, want)
+	}
+}
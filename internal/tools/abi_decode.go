@@ -3,17 +3,26 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/vultisig/mcp/internal/abiregistry"
+	evmclient "github.com/vultisig/mcp/internal/evm"
 )
 
 func newABIDecodeTool() mcp.Tool {
 	return mcp.NewTool("abi_decode",
 		mcp.WithDescription(
 			"Decode ABI-encoded data given a list of Solidity types. "+
-				"Provide the hex data and comma-separated types (e.g. \"uint256,address,bool\").",
+				"Provide the hex data and comma-separated types (e.g. \"uint256,address,bool\"). "+
+				"Alternatively pass contract instead of types to decode with the contract's ABI from the explorer or evm_import_abi: "+
+				"calldata is decoded by its selector, and return data by the function named in method.",
 		),
 		mcp.WithString("data",
 			mcp.Description("0x-prefixed hex-encoded ABI data to decode."),
@@ -21,28 +30,43 @@ func newABIDecodeTool() mcp.Tool {
 		),
 		mcp.WithString("types",
 			mcp.Description("Comma-separated Solidity types (e.g. \"uint256,address,bool\")."),
-			mcp.Required(),
+		),
+		mcp.WithString("contract",
+			mcp.Description("Contract address (0x-prefixed) whose ABI decodes data, instead of types."),
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain of contract. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("method",
+			mcp.Description("With contract: function name or signature whose return data this is. Omit to decode data as calldata."),
 		),
 	)
 }
 
-func handleABIDecode() server.ToolHandlerFunc {
+func handleABIDecode(pool *evmclient.Pool, registry *abiregistry.Registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		dataHex, err := req.RequireString("data")
 		if err != nil {
 			return mcp.NewToolResultError("missing data parameter"), nil
 		}
 
-		typesStr, err := req.RequireString("types")
-		if err != nil {
-			return mcp.NewToolResultError("missing types parameter"), nil
-		}
-
 		raw, err := hexToBytes(dataHex)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid hex data: %v", err)), nil
 		}
 
+		typesStr := req.GetString("types", "")
+		contractAddr := req.GetString("contract", "")
+		switch {
+		case typesStr != "" && contractAddr != "":
+			return mcp.NewToolResultError("pass either types or contract, not both"), nil
+		case contractAddr != "":
+			return decodeWithContract(ctx, pool, registry, req.GetString("chain", "Ethereum"), contractAddr, req.GetString("method", ""), raw)
+		case typesStr == "":
+			return mcp.NewToolResultError("missing types parameter"), nil
+		}
+
 		args, err := parseABITypes(typesStr)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid types: %v", err)), nil
@@ -66,3 +90,75 @@ func handleABIDecode() server.ToolHandlerFunc {
 		return mcp.NewToolResultText(string(data)), nil
 	}
 }
+
+// decodeWithContract decodes raw with the registry ABI of contractAddr: as
+// the return data of methodName when it is set, otherwise as calldata.
+func decodeWithContract(ctx context.Context, pool *evmclient.Pool, registry *abiregistry.Registry, chainName, contractAddr, methodName string, raw []byte) (*mcp.CallToolResult, error) {
+	if !common.IsHexAddress(contractAddr) {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid contract address: %s", contractAddr)), nil
+	}
+	client, chainID, err := pool.Get(ctx, chainName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+	}
+	contract, err := registry.Contract(ctx, chainID.Uint64(), common.HexToAddress(contractAddr), client)
+	if errors.Is(err, abiregistry.ErrNotFound) {
+		return mcp.NewToolResultError(fmt.Sprintf("no ABI for %s on %s: the contract is not verified on the explorer; import one with evm_import_abi or pass types", contractAddr, chainName)), nil
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("ABI lookup failed: %v", err)), nil
+	}
+
+	resp := map[string]any{"contract": contractInfo(contract)}
+	if methodName == "" {
+		method, args, err := decodeCalldata(contract.ABI, raw)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("decode calldata: %v", err)), nil
+		}
+		resp["function"] = method.Sig
+		resp["args"] = args
+	} else {
+		method, err := findMethod(contract.ABI, methodName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%s: %v", contract.Address.Hex(), err)), nil
+		}
+		values, err := method.Outputs.UnpackValues(raw)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("decode %s return data: %v", method.Sig, err)), nil
+		}
+		resp["function"] = method.Sig
+		resp["outputs"] = renderArguments(method.Outputs, values, "output")
+	}
+	return marshalABIDecode(resp)
+}
+
+// findMethod returns the method of parsed named name, or with the full
+// signature name for overloaded functions.
+func findMethod(parsed abi.ABI, name string) (abi.Method, error) {
+	if strings.Contains(name, "(") {
+		canonical := strings.ReplaceAll(name, " ", "")
+		for _, m := range parsed.Methods {
+			if m.Sig == canonical {
+				return m, nil
+			}
+		}
+		return abi.Method{}, fmt.Errorf("no method %s", canonical)
+	}
+	m, ok := parsed.Methods[name]
+	if !ok {
+		return abi.Method{}, fmt.Errorf("no method %s", name)
+	}
+	// Overloads are stored as name, name0, name1, ...
+	if _, overloaded := parsed.Methods[name+"0"]; overloaded {
+		return abi.Method{}, fmt.Errorf("method %s is overloaded; pass its full signature, e.g. %s", name, m.Sig)
+	}
+	return m, nil
+}
+
+func marshalABIDecode(resp map[string]any) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("marshal abi_decode result: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
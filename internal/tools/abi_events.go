@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// eventSpec is an event to decode logs with. A bare signature such as
+// "Transfer(address,address,uint256)" does not say which parameters are
+// indexed, so inferIndexed treats the first len(topics)-1 as indexed.
+type eventSpec struct {
+	event        abi.Event
+	inferIndexed bool
+}
+
+// parseEvent parses an event signature, e.g.
+// "Transfer(address indexed from, address indexed to, uint256 value)", or
+// a JSON ABI fragment holding exactly one event.
+func parseEvent(s string) (*eventSpec, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+		return parseEventJSON(s)
+	}
+
+	s = strings.TrimSpace(strings.TrimPrefix(s, "event "))
+	open := strings.Index(s, "(")
+	if open <= 0 || !strings.HasSuffix(s, ")") {
+		return nil, fmt.Errorf("invalid event signature %q: want Name(type [indexed] [name], ...)", s)
+	}
+	name := strings.TrimSpace(s[:open])
+	body := strings.TrimSpace(s[open+1 : len(s)-1])
+
+	var inputs abi.Arguments
+	explicit := false
+	if body != "" {
+		for i, param := range strings.Split(body, ",") {
+			fields := strings.Fields(param)
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("invalid event signature %q: empty parameter %d", s, i)
+			}
+			if strings.HasPrefix(fields[0], "(") || strings.HasPrefix(fields[0], "tuple") {
+				return nil, fmt.Errorf("tuple parameters need a JSON ABI fragment")
+			}
+			typ, err := abi.NewType(fields[0], "", nil)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ABI type %q: %w", fields[0], err)
+			}
+			arg := abi.Argument{Name: fmt.Sprintf("arg%d", i), Type: typ}
+			for _, f := range fields[1:] {
+				if f == "indexed" {
+					arg.Indexed = true
+					explicit = true
+				} else {
+					arg.Name = f
+				}
+			}
+			inputs = append(inputs, arg)
+		}
+	}
+
+	event := abi.NewEvent(name, name, false, inputs)
+	return &eventSpec{event: event, inferIndexed: !explicit}, nil
+}
+
+func parseEventJSON(s string) (*eventSpec, error) {
+	if strings.HasPrefix(s, "{") {
+		s = "[" + s + "]"
+	}
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI fragment: %w", err)
+	}
+	if len(parsed.Events) != 1 {
+		return nil, fmt.Errorf("ABI fragment must declare exactly one event, got %d", len(parsed.Events))
+	}
+	for _, event := range parsed.Events {
+		if event.Anonymous {
+			return nil, fmt.Errorf("anonymous events have no topic to filter on")
+		}
+		for i := range event.Inputs {
+			if event.Inputs[i].Name == "" {
+				event.Inputs[i].Name = fmt.Sprintf("arg%d", i)
+			}
+		}
+		return &eventSpec{event: event}, nil
+	}
+	return nil, nil
+}
+
+// decodeLog decodes a log emitted by the event into its parameters by
+// name. Indexed parameters of dynamic types (string, bytes, arrays, tuples)
+// are only stored as their keccak256 hash, which is returned as hex.
+func (e *eventSpec) decodeLog(lg types.Log) (map[string]any, error) {
+	if len(lg.Topics) == 0 || lg.Topics[0] != e.event.ID {
+		return nil, fmt.Errorf("log is not a %s event", e.event.Sig)
+	}
+
+	inputs := e.event.Inputs
+	if e.inferIndexed {
+		if len(lg.Topics)-1 > len(inputs) {
+			return nil, fmt.Errorf("log has %d indexed values, %s has %d parameters", len(lg.Topics)-1, e.event.Sig, len(inputs))
+		}
+		inputs = make(abi.Arguments, len(e.event.Inputs))
+		copy(inputs, e.event.Inputs)
+		for i := range inputs {
+			inputs[i].Indexed = i < len(lg.Topics)-1
+		}
+	}
+
+	out := make(map[string]any, len(inputs))
+	topics := lg.Topics[1:]
+	for _, arg := range inputs {
+		if !arg.Indexed {
+			continue
+		}
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("log is missing indexed parameter %s", arg.Name)
+		}
+		topic := topics[0]
+		topics = topics[1:]
+		out[arg.Name] = decodeTopic(arg.Type, topic)
+	}
+
+	values, err := inputs.NonIndexed().UnpackValues(lg.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode data: %w", err)
+	}
+	for i, arg := range inputs.NonIndexed() {
+		out[arg.Name] = formatABIValue(values[i])
+	}
+	return out, nil
+}
+
+// decodeTopic decodes an indexed parameter from its topic.
+func decodeTopic(typ abi.Type, topic common.Hash) any {
+	switch typ.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return topic.Hex()
+	}
+	values, err := abi.Arguments{{Type: typ}}.UnpackValues(topic.Bytes())
+	if err != nil || len(values) != 1 {
+		return topic.Hex()
+	}
+	return formatABIValue(values[0])
+}
@@ -0,0 +1,330 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// parseFunctionSignature parses a human-readable function signature with
+// optional return types into an ABI method, e.g. "balanceOf(address)(uint256)",
+// "function getReserveData(address asset) view returns (uint256 a, uint128 b)"
+// or "positions(uint256)((uint96 nonce, address operator))". Tuples are
+// written as parenthesised component lists.
+func parseFunctionSignature(sig string) (abi.Method, error) {
+	s := strings.TrimSpace(sig)
+	s = strings.TrimSpace(strings.TrimPrefix(s, "function "))
+	open := strings.Index(s, "(")
+	if open <= 0 {
+		return abi.Method{}, fmt.Errorf("invalid function signature %q: want name(types)(return types)", sig)
+	}
+	name := strings.TrimSpace(s[:open])
+
+	inEnd, err := matchingParen(s, open)
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("invalid function signature %q: %w", sig, err)
+	}
+	inputs, err := parseParamList(s[open+1 : inEnd])
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("invalid function signature %q: %w", sig, err)
+	}
+
+	var outputs []abi.ArgumentMarshaling
+	rest := strings.TrimSpace(s[inEnd+1:])
+	for _, kw := range []string{"external", "public", "view", "pure", "payable", "nonpayable", "returns"} {
+		rest = strings.TrimSpace(strings.ReplaceAll(" "+rest+" ", " "+kw+" ", " "))
+	}
+	if rest != "" {
+		if !strings.HasPrefix(rest, "(") {
+			return abi.Method{}, fmt.Errorf("invalid function signature %q: unexpected %q", sig, rest)
+		}
+		outEnd, err := matchingParen(rest, 0)
+		if err != nil || outEnd != len(rest)-1 {
+			return abi.Method{}, fmt.Errorf("invalid function signature %q: malformed return types", sig)
+		}
+		if outputs, err = parseParamList(rest[1:outEnd]); err != nil {
+			return abi.Method{}, fmt.Errorf("invalid function signature %q: %w", sig, err)
+		}
+	}
+
+	fragment, err := json.Marshal([]map[string]any{{
+		"type":            "function",
+		"name":            name,
+		"stateMutability": "view",
+		"inputs":          nonNilParams(inputs),
+		"outputs":         nonNilParams(outputs),
+	}})
+	if err != nil {
+		return abi.Method{}, err
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(fragment)))
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("invalid function signature %q: %w", sig, err)
+	}
+	return parsed.Methods[name], nil
+}
+
+func nonNilParams(params []abi.ArgumentMarshaling) []abi.ArgumentMarshaling {
+	if params == nil {
+		return []abi.ArgumentMarshaling{}
+	}
+	return params
+}
+
+// matchingParen returns the index of the parenthesis closing s[open].
+func matchingParen(s string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses")
+}
+
+// splitTopLevel splits s at commas outside parentheses.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseParamList parses "type [location] [name], ..." where a type may be
+// a parenthesised tuple with array suffixes, e.g. "(uint256 a, address b)[]".
+func parseParamList(s string) ([]abi.ArgumentMarshaling, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var params []abi.ArgumentMarshaling
+	for i, part := range splitTopLevel(s) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty parameter %d", i)
+		}
+
+		var p abi.ArgumentMarshaling
+		var rest string
+		if strings.HasPrefix(part, "(") || strings.HasPrefix(part, "tuple(") {
+			part = strings.TrimPrefix(part, "tuple")
+			end, err := matchingParen(part, 0)
+			if err != nil {
+				return nil, err
+			}
+			components, err := parseParamList(part[1:end])
+			if err != nil {
+				return nil, err
+			}
+			for j := range components {
+				if components[j].Name == "" {
+					components[j].Name = fmt.Sprintf("field%d", j)
+				}
+			}
+			rest = part[end+1:]
+			dims := rest
+			if k := strings.IndexAny(rest, " \t"); k >= 0 {
+				dims = rest[:k]
+			}
+			p = abi.ArgumentMarshaling{Type: "tuple" + dims, Components: components}
+			rest = rest[len(dims):]
+		} else {
+			fields := strings.Fields(part)
+			p.Type = fields[0]
+			rest = strings.Join(fields[1:], " ")
+		}
+		for _, f := range strings.Fields(rest) {
+			switch f {
+			case "memory", "calldata", "storage", "indexed":
+			default:
+				p.Name = f
+			}
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// methodFromABI returns the method of a JSON ABI (an array or a single
+// fragment) named method. Overloaded methods are selected by their full
+// signature, e.g. "safeTransferFrom(address,address,uint256)".
+func methodFromABI(abiJSON, method string) (abi.Method, error) {
+	abiJSON = strings.TrimSpace(abiJSON)
+	if strings.HasPrefix(abiJSON, "{") {
+		abiJSON = "[" + abiJSON + "]"
+	}
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("invalid ABI: %w", err)
+	}
+
+	return findMethod(parsed, method)
+}
+
+// abiValueFromJSON converts a JSON argument to the Go value the ABI encoder
+// expects for typ. Scalars may be strings as for abi_encode; integers may
+// also be JSON numbers and bools JSON booleans. Arrays take JSON arrays and
+// tuples take an object keyed by component name or a positional array.
+func abiValueFromJSON(v any, typ abi.Type) (any, error) {
+	switch typ.T {
+	case abi.SliceTy, abi.ArrayTy:
+		list, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("want an array for %s", typ)
+		}
+		var rv reflect.Value
+		if typ.T == abi.SliceTy {
+			rv = reflect.MakeSlice(typ.GetType(), len(list), len(list))
+		} else {
+			if len(list) != typ.Size {
+				return nil, fmt.Errorf("want %d elements for %s, got %d", typ.Size, typ, len(list))
+			}
+			rv = reflect.New(typ.GetType()).Elem()
+		}
+		for i, item := range list {
+			elem, err := abiValueFromJSON(item, *typ.Elem)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			rv.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return rv.Interface(), nil
+
+	case abi.TupleTy:
+		rv := reflect.New(typ.GetType()).Elem()
+		var fields []any
+		switch t := v.(type) {
+		case []any:
+			fields = t
+		case map[string]any:
+			fields = make([]any, len(typ.TupleRawNames))
+			for i, name := range typ.TupleRawNames {
+				field, ok := t[name]
+				if !ok {
+					return nil, fmt.Errorf("missing tuple field %q", name)
+				}
+				fields[i] = field
+			}
+		default:
+			return nil, fmt.Errorf("want an object or array for %s", typ)
+		}
+		if len(fields) != len(typ.TupleElems) {
+			return nil, fmt.Errorf("want %d tuple fields, got %d", len(typ.TupleElems), len(fields))
+		}
+		for i, field := range fields {
+			val, err := abiValueFromJSON(field, *typ.TupleElems[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", typ.TupleRawNames[i], err)
+			}
+			rv.Field(i).Set(reflect.ValueOf(val))
+		}
+		return rv.Interface(), nil
+	}
+
+	switch t := v.(type) {
+	case string:
+		return convertStringArg(t, typ)
+	case float64:
+		if typ.T != abi.UintTy && typ.T != abi.IntTy {
+			return nil, fmt.Errorf("unexpected number for %s", typ)
+		}
+		f := new(big.Float).SetFloat64(t)
+		n, acc := f.Int(nil)
+		if acc != big.Exact {
+			return nil, fmt.Errorf("want an integer for %s, got %v", typ, t)
+		}
+		return convertStringArg(n.String(), typ)
+	case json.Number:
+		return convertStringArg(t.String(), typ)
+	case bool:
+		return convertStringArg(strconv.FormatBool(t), typ)
+	}
+	return nil, fmt.Errorf("unsupported value %v for %s", v, typ)
+}
+
+// renderABIValue converts a decoded value to JSON, rendering tuples as
+// objects keyed by component name.
+func renderABIValue(v any, typ abi.Type) any {
+	rv := reflect.ValueOf(v)
+	switch typ.T {
+	case abi.TupleTy:
+		out := make(map[string]any, len(typ.TupleElems))
+		for i, elem := range typ.TupleElems {
+			name := typ.TupleRawNames[i]
+			if name == "" {
+				name = fmt.Sprintf("field%d", i)
+			}
+			out[name] = renderABIValue(rv.Field(i).Interface(), *elem)
+		}
+		return out
+	case abi.SliceTy, abi.ArrayTy:
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = renderABIValue(rv.Index(i).Interface(), *typ.Elem)
+		}
+		return out
+	}
+	return formatABIValue(v)
+}
+
+// renderOutputs renders a method's decoded return values: the value itself
+// for a single output, otherwise renderArguments.
+func renderOutputs(outputs abi.Arguments, values []any) any {
+	if len(outputs) == 1 {
+		return renderABIValue(values[0], outputs[0].Type)
+	}
+	return renderArguments(outputs, values, "output")
+}
+
+// renderArguments renders decoded arguments as an object keyed by name,
+// using prefix and the position for unnamed ones.
+func renderArguments(args abi.Arguments, values []any, prefix string) map[string]any {
+	out := make(map[string]any, len(args))
+	for i, arg := range args {
+		name := arg.Name
+		if name == "" {
+			name = fmt.Sprintf("%s%d", prefix, i)
+		}
+		out[name] = renderABIValue(values[i], arg.Type)
+	}
+	return out
+}
+
+// decodeCalldata decodes calldata as a call to one of the functions of
+// parsed, returning the function and its arguments by name.
+func decodeCalldata(parsed abi.ABI, data []byte) (*abi.Method, map[string]any, error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("calldata is shorter than a 4-byte selector")
+	}
+	method, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := method.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode %s arguments: %w", method.Sig, err)
+	}
+	return method, renderArguments(method.Inputs, values, "arg"), nil
+}
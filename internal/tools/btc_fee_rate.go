@@ -1,20 +1,17 @@
 package tools
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
-
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
-	"github.com/vultisig/mcp/internal/thorchain"
+	"github.com/vultisig/mcp/internal/coingecko"
+	"github.com/vultisig/mcp/internal/feerate"
+	"github.com/vultisig/mcp/internal/vault"
 )
 
 func newBTCFeeRateTool() mcp.Tool {
-	return mcp.NewTool("btc_fee_rate",
-		mcp.WithDescription("Get the recommended Bitcoin fee rate in sat/vB from THORChain inbound addresses."),
-	)
+	return newUTXOFeeRateTool("btc_fee_rate", "Bitcoin",
+		"Get recommended Bitcoin fee rates in sat/vB from THORChain inbound addresses, or from a configured mempool.space-style API with THORChain as fallback.")
 }
 
 type feeRateResult struct {
@@ -24,25 +21,6 @@ type feeRateResult struct {
 	FeeRateUnit string `json:"fee_rate_unit"`
 }
 
-func handleBTCFeeRate(tcClient *thorchain.Client) server.ToolHandlerFunc {
-	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		rate, err := tcClient.SatsPerByte(ctx, "BTC")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get BTC fee rate: %v", err)), nil
-		}
-
-		result := feeRateResult{
-			Chain:       "Bitcoin",
-			Ticker:      "BTC",
-			FeeRate:     rate,
-			FeeRateUnit: "sat/vB",
-		}
-
-		data, err := json.Marshal(result)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("marshal fee rate result: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(data)), nil
-	}
+func handleBTCFeeRate(store *vault.Store, fees feerate.Source, cgClient *coingecko.Client) server.ToolHandlerFunc {
+	return handleUTXOFeeRate("Bitcoin", store, fees, cgClient)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/vultisig/vultisig-go/address"
 	"github.com/vultisig/vultisig-go/common"
 
+	"github.com/vultisig/mcp/internal/feerate"
 	"github.com/vultisig/mcp/internal/thorchain"
 	"github.com/vultisig/mcp/internal/vault"
 )
@@ -64,7 +65,7 @@ func TestBTCFeeRate(t *testing.T) {
 	defer srv.Close()
 
 	tcClient := thorchain.NewClient(srv.URL)
-	handler := handleBTCFeeRate(tcClient)
+	handler := handleBTCFeeRate(vault.NewStore(), feerate.NewThorchainSource(tcClient), nil)
 
 	req := callToolReq("btc_fee_rate", map[string]any{})
 	res, err := handler(context.Background(), req)
@@ -105,7 +106,7 @@ func TestBTCFeeRate_HaltedChain(t *testing.T) {
 	defer srv.Close()
 
 	tcClient := thorchain.NewClient(srv.URL)
-	handler := handleBTCFeeRate(tcClient)
+	handler := handleBTCFeeRate(vault.NewStore(), feerate.NewThorchainSource(tcClient), nil)
 
 	req := callToolReq("btc_fee_rate", map[string]any{})
 	res, err := handler(context.Background(), req)
@@ -121,7 +122,7 @@ func TestBuildBTCSend_Basic(t *testing.T) {
 	store := setupBTCVault(t)
 	senderAddr := deriveBTCAddress(t, store)
 
-	handler := handleBuildBTCSend(store, nil)
+	handler := handleBuildBTCSend(store, mockBlockchair(t, 1_000_000_000))
 
 	req := callToolReq("build_btc_send", map[string]any{
 		"to_address": "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
@@ -163,7 +164,7 @@ func TestBuildBTCSend_Basic(t *testing.T) {
 func TestBuildBTCSend_WithMemo(t *testing.T) {
 	store := setupBTCVault(t)
 
-	handler := handleBuildBTCSend(store, nil)
+	handler := handleBuildBTCSend(store, mockBlockchair(t, 1_000_000_000))
 
 	req := callToolReq("build_btc_send", map[string]any{
 		"to_address": "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+func newBuildERC20TransferTool() mcp.Tool {
+	return mcp.NewTool("build_erc20_transfer",
+		mcp.WithDescription(
+			"Build an unsigned ERC-20 token transfer on any EVM chain in one call. "+
+				"Reads the token's symbol and decimals, converts the human-readable amount, checks the sender's token balance, "+
+				"fetches nonce and fees, estimates gas, checks the native balance covers the max network fee (including any rollup L1 fee) "+
+				"and returns the unsigned transaction with its signing hash and a summary. "+
+				"Sender falls back to the vault-derived address if not provided.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("token",
+			mcp.Description("ERC-20 token contract address (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("to",
+			mcp.Description("Recipient address (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("amount",
+			mcp.Description("Amount in human-readable token units (e.g. \"1.5\" for 1.5 USDC). Decimals are read from the token."),
+			mcp.Required(),
+		),
+		mcp.WithString("from",
+			mcp.Description("Sender address (0x-prefixed). Optional if vault info is set."),
+		),
+		mcp.WithNumber("tx_type",
+			mcp.Description("Transaction type: 2 (EIP-1559, default), 1 (EIP-2930) or 0 (legacy) for chains without EIP-1559 fees."),
+		),
+		withOutputFormat(),
+	)
+}
+
+func handleBuildERC20Transfer(store *vault.Store, pool *evmclient.Pool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		chainID, err := evmChainID(req, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		txType, err := parseEVMTxType(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		tokenStr, err := req.RequireString("token")
+		if err != nil {
+			return mcp.NewToolResultError("missing token parameter"), nil
+		}
+		if !common.IsHexAddress(tokenStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid token address: %s", tokenStr)), nil
+		}
+		token := common.HexToAddress(tokenStr)
+
+		toStr, err := req.RequireString("to")
+		if err != nil {
+			return mcp.NewToolResultError("missing to parameter"), nil
+		}
+		if !common.IsHexAddress(toStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid to address: %s", toStr)), nil
+		}
+		to := common.HexToAddress(toStr)
+		if to == token {
+			return mcp.NewToolResultError("to is the token contract; pass the recipient's address"), nil
+		}
+
+		amountStr, err := req.RequireString("amount")
+		if err != nil {
+			return mcp.NewToolResultError("missing amount parameter"), nil
+		}
+
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if asKeysign && txType != ethtypes.DynamicFeeTxType {
+			return mcp.NewToolResultError("keysign output supports only tx_type 2"), nil
+		}
+
+		explicit := req.GetString("from", "")
+		if explicit != "" && !common.IsHexAddress(explicit) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid from address: %s", explicit)), nil
+		}
+		v := resolve.ResolveVault(ctx, req, store)
+		fromStr, err := resolve.EVMAddress(explicit, v)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		from := common.HexToAddress(fromStr)
+
+		client, _, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+
+		tb, err := client.GetTokenBalance(ctx, token.Hex(), from.Hex())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to read token %s: %v", token.Hex(), err)), nil
+		}
+
+		amount, err := evmclient.ParseUnits(amountStr, int(tb.Decimals))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if amount.Sign() <= 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("amount must be positive: %s", amountStr)), nil
+		}
+		if tb.Raw.Cmp(amount) < 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("insufficient %s balance: %s has %s, transfer needs %s",
+				tb.Symbol, from.Hex(), tb.Balance, evmclient.FormatUnits(amount, int(tb.Decimals)))), nil
+		}
+
+		p := &evmTxParams{
+			txType:  txType,
+			chainID: chainID,
+			to:      token,
+			value:   new(big.Int),
+			data:    evmclient.PackERC20Transfer(to, amount),
+		}
+		if txType == ethtypes.AccessListTxType {
+			p.accessList = ethtypes.AccessList{}
+		}
+		autoFilled, err := fillEVMTxParams(ctx, client, p, func() (string, error) { return from.Hex(), nil })
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		networkFee, rollup, err := evmNetworkFee(ctx, client, chainName, from, p)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if asKeysign {
+			if v == nil {
+				return mcp.NewToolResultError("build keysign payload: vault info required"), nil
+			}
+			vaultAddr, err := resolve.EVMAddress("", v)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			if !strings.EqualFold(vaultAddr, from.Hex()) {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"from address %q does not match vault-derived address %q", from.Hex(), vaultAddr)), nil
+			}
+			if *p.nonce > math.MaxInt64 {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid nonce: %d", *p.nonce)), nil
+			}
+			payload, err := erc20KeysignPayload(v, chainName, token.Hex(), tb.Symbol, tb.Decimals, to.Hex(), amount.String(),
+				int64(*p.nonce), fmt.Sprint(*p.gasLimit), p.maxFee.String(), p.maxPriorityFee.String())
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			return keysignToolResult(chainName, "transfer", payload)
+		}
+
+		result, err := evmTxResult(chainName, p, autoFilled)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		ticker := evmclient.NativeTicker(chainName)
+		amountDisplay := evmclient.FormatUnits(amount, int(tb.Decimals))
+		maxFee := evmclient.FormatUnits(networkFee, 18)
+
+		result["from"] = from.Hex()
+		result["token"] = token.Hex()
+		result["symbol"] = tb.Symbol
+		result["decimals"] = tb.Decimals
+		result["recipient"] = to.Hex()
+		result["amount"] = amountDisplay
+		result["amount_base_units"] = amount.String()
+		result["token_balance"] = tb.Balance
+		result["max_network_fee"] = maxFee + " " + ticker
+		if rollup != nil {
+			result["rollup_fee"] = newRollupFeeJSON(rollup, ticker)
+		}
+		result["summary"] = fmt.Sprintf("Send %s %s from %s to %s on %s. Max network fee: %s %s.",
+			amountDisplay, tb.Symbol, from.Hex(), to.Hex(), chainName, maxFee, ticker)
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
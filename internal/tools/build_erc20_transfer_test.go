@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/vultisig/mcp/internal/keysign"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// mockERC20Call answers eth_call for decimals(), symbol() and balanceOf()
+// of a token with the given decimals, symbol and raw balance.
+func mockERC20Call(decimals uint8, symbol string, balance *big.Int) func(json.RawMessage) any {
+	return func(params json.RawMessage) any {
+		var args []struct {
+			Data  string `json:"data"`
+			Input string `json:"input"`
+		}
+		_ = json.Unmarshal(params, &args)
+		data := args[0].Input
+		if data == "" {
+			data = args[0].Data
+		}
+		word := func(v *big.Int) string { return fmt.Sprintf("0x%064x", v) }
+		switch {
+		case strings.HasPrefix(data, "0x313ce567"):
+			return word(big.NewInt(int64(decimals)))
+		case strings.HasPrefix(data, "0x95d89b41"):
+			enc := hex.EncodeToString([]byte(symbol))
+			return word(big.NewInt(32)) + fmt.Sprintf("%064x", len(symbol)) + enc + strings.Repeat("0", 64-len(enc))
+		case strings.HasPrefix(data, "0x70a08231"):
+			return word(balance)
+		}
+		return "0x"
+	}
+}
+
+func erc20TransferPool(t *testing.T, balance *big.Int) (*mockEVMRPC, func(map[string]any) *mcp.CallToolResult) {
+	t.Helper()
+	pool, rpc := mockEVMPool(t, map[string]any{
+		"eth_call":                 mockERC20Call(6, "USDC", balance),
+		"eth_getTransactionCount":  "0x7",
+		"eth_maxPriorityFeePerGas": "0x5f5e100",
+		"eth_getBlockByNumber":     mockHeader("0x3b9aca00"),
+		"eth_estimateGas":          "0xfde8",
+		"eth_getBalance":           "0xde0b6b3a7640000",
+	})
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+	handler := handleBuildERC20Transfer(store, pool)
+	return rpc, func(args map[string]any) *mcp.CallToolResult {
+		res, err := handler(context.Background(), callToolReq("build_erc20_transfer", args))
+		if err != nil {
+			t.Fatalf("handler error: %v", err)
+		}
+		return res
+	}
+}
+
+func TestBuildERC20Transfer(t *testing.T) {
+	_, call := erc20TransferPool(t, big.NewInt(5_000_000))
+	result := decodeResult(t, call(map[string]any{
+		"token":  usdt,
+		"to":     sparkVault,
+		"amount": "1.5",
+	}))
+
+	wantData := "0xa9059cbb000000000000000000000000e2e7a17dff93280dec073c995595155283e3c372000000000000000000000000000000000000000000000000000000000016e360"
+	if result["data"] != wantData {
+		t.Errorf("data = %v, want %s", result["data"], wantData)
+	}
+	if result["to"] != usdt || result["value"] != "0" || result["recipient"] != sparkVault {
+		t.Errorf("to/value/recipient = %v/%v/%v", result["to"], result["value"], result["recipient"])
+	}
+	if result["amount"] != "1.5" || result["amount_base_units"] != "1500000" || result["symbol"] != "USDC" || result["decimals"] != float64(6) {
+		t.Errorf("amount = %v (%v base) %v, decimals %v", result["amount"], result["amount_base_units"], result["symbol"], result["decimals"])
+	}
+	if result["from"] != testAddress || result["nonce"] != "7" || result["gas_limit"] != "65000" {
+		t.Errorf("from/nonce/gas_limit = %v/%v/%v", result["from"], result["nonce"], result["gas_limit"])
+	}
+	// 65000 gas * (2 * 1 gwei + 0.1 gwei).
+	if result["max_network_fee"] != "0.0001365 ETH" {
+		t.Errorf("max_network_fee = %v", result["max_network_fee"])
+	}
+	if s, _ := result["summary"].(string); !strings.HasPrefix(s, "Send 1.5 USDC from "+testAddress+" to "+sparkVault+" on Ethereum") {
+		t.Errorf("summary = %q", s)
+	}
+	if h, _ := result["signing_hash"].(string); len(h) != 66 {
+		t.Errorf("signing_hash = %v", result["signing_hash"])
+	}
+}
+
+func TestBuildERC20Transfer_InsufficientBalance(t *testing.T) {
+	rpc, call := erc20TransferPool(t, big.NewInt(1_000_000))
+	res := call(map[string]any{"token": usdt, "to": sparkVault, "amount": "1.5"})
+	if !res.IsError || !strings.Contains(res.Content[0].(mcp.TextContent).Text, "insufficient USDC balance") {
+		t.Errorf("expected insufficient balance error, got %+v", res.Content)
+	}
+	if rpc.callCount("eth_estimateGas") != 0 {
+		t.Error("gas estimated for an unaffordable transfer")
+	}
+}
+
+func TestBuildERC20Transfer_InvalidAmount(t *testing.T) {
+	_, call := erc20TransferPool(t, big.NewInt(5_000_000))
+	for _, amount := range []string{"0", "1.0000001", "abc", "-1"} {
+		res := call(map[string]any{"token": usdt, "to": sparkVault, "amount": amount})
+		if !res.IsError {
+			t.Errorf("amount %q: expected tool error", amount)
+		}
+	}
+	if res := call(map[string]any{"token": usdt, "to": usdt, "amount": "1"}); !res.IsError {
+		t.Error("transfer to the token contract: expected tool error")
+	}
+}
+
+func TestBuildERC20Transfer_Keysign(t *testing.T) {
+	_, call := erc20TransferPool(t, big.NewInt(5_000_000))
+	res := call(map[string]any{"token": usdt, "to": sparkVault, "amount": "2", "output_format": "keysign"})
+
+	var out struct {
+		Action string `json:"action"`
+		Base64 string `json:"keysign_payload_base64"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, res)), &out); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	p, err := keysign.Decode(out.Base64)
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if p.GetCoin().GetContractAddress() != usdt || p.GetCoin().GetTicker() != "USDC" || p.GetCoin().GetDecimals() != 6 {
+		t.Errorf("coin = %+v", p.GetCoin())
+	}
+	if p.GetToAddress() != sparkVault || p.GetToAmount() != "2000000" {
+		t.Errorf("to/amount = %s/%s", p.GetToAddress(), p.GetToAmount())
+	}
+	if eth := p.GetEthereumSpecific(); eth.GetNonce() != 7 || eth.GetGasLimit() != "65000" {
+		t.Errorf("ethereum_specific = %+v", eth)
+	}
+}
@@ -0,0 +1,324 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+const (
+	replaceSpeedup = "speedup"
+	replaceCancel  = "cancel"
+
+	// replacementBumpPercent is the minimum fee increase nodes require to
+	// accept a same-nonce replacement (geth's default txpool price bump).
+	replacementBumpPercent = 10
+
+	// cancelGasLimit is the gas of the 0-value self-send that cancels a
+	// transaction on chains with plain gas pricing, and the floor of its
+	// estimate there. Rollups charge more gas for the L1 data or pubdata.
+	cancelGasLimit = 21_000
+)
+
+func newBuildEVMSpeedupTool() mcp.Tool {
+	return newEVMReplacementTool("build_evm_speedup",
+		"Build a replacement for a pending EVM transaction that pays higher fees, e.g. one stuck on a stale tip. "+
+			"Fetches the original and returns the same transaction (nonce, to, value, data, gas limit) "+
+			fmt.Sprintf("with its tip and max fee (or gas price) raised by at least %d%%, ", replacementBumpPercent)+
+			"and at least the node's suggested tip and a max fee covering twice the current base fee. "+
+			"Sign and broadcast it like the original; whichever is mined first wins.",
+	)
+}
+
+func newBuildEVMCancelTool() mcp.Tool {
+	return newEVMReplacementTool("build_evm_cancel",
+		"Build a transaction that cancels a pending EVM transaction: a 0-value send to the sender itself with the same nonce "+
+			fmt.Sprintf("and fees raised by at least %d%%, so it replaces the original once mined. ", replacementBumpPercent)+
+			"Its gas limit is estimated, covering the L1 or pubdata gas of rollups such as Arbitrum and zkSync, "+
+			"so the cancel costs the network fee of a plain transfer. It only works while the original is still pending.",
+	)
+}
+
+// newEVMReplacementTool declares build_evm_speedup and build_evm_cancel,
+// which share their parameters.
+func newEVMReplacementTool(name, description string) mcp.Tool {
+	return mcp.NewTool(name,
+		mcp.WithDescription(description),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("tx_hash",
+			mcp.Description("Hash of the pending transaction (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("max_priority_fee_per_gas",
+			mcp.Description("Type 2 only. Tip in wei (decimal string) instead of the computed one; must still be a sufficient bump."),
+		),
+		mcp.WithString("max_fee_per_gas",
+			mcp.Description("Type 2 only. Max fee in wei (decimal string) instead of the computed one; must still be a sufficient bump."),
+		),
+		mcp.WithString("gas_price",
+			mcp.Description("Types 0 and 1 only. Gas price in wei (decimal string) instead of the computed one; must still be a sufficient bump."),
+		),
+		withOutputFormat(),
+	)
+}
+
+func handleBuildEVMReplacement(store *vault.Store, pool *evmclient.Pool, kind string) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		txHash, err := req.RequireString("tx_hash")
+		if err != nil {
+			return mcp.NewToolResultError("missing tx_hash parameter"), nil
+		}
+		if !evmTxHashRE.MatchString(txHash) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid tx_hash: %s (expected 0x + 64 hex chars)", txHash)), nil
+		}
+
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		client, chainID, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+
+		orig, from, err := loadPendingEVMTx(ctx, client, chainID, common.HexToHash(txHash))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		p := &evmTxParams{
+			txType:     orig.Type(),
+			chainID:    chainID,
+			value:      orig.Value(),
+			data:       orig.Data(),
+			accessList: orig.AccessList(),
+		}
+		nonce, gasLimit := orig.Nonce(), orig.Gas()
+		p.nonce, p.gasLimit = &nonce, &gasLimit
+		if orig.To() != nil {
+			p.to = *orig.To()
+		}
+		if kind == replaceCancel {
+			gas, err := cancelGas(ctx, client, chainName, from)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			p.to, p.value, p.data, p.accessList, p.gasLimit = from, new(big.Int), nil, nil, &gas
+			if p.txType == ethtypes.AccessListTxType {
+				p.accessList = ethtypes.AccessList{}
+			}
+		} else if orig.To() == nil {
+			return mcp.NewToolResultError("contract creations cannot be sped up with this tool"), nil
+		}
+
+		if err := replacementFees(ctx, client, req, orig, p); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		networkFee, rollup, err := evmNetworkFee(ctx, client, chainName, from, p)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if asKeysign {
+			if p.txType != ethtypes.DynamicFeeTxType {
+				return mcp.NewToolResultError("keysign output supports only tx_type 2"), nil
+			}
+			vi := resolve.ResolveVault(ctx, req, store)
+			vaultAddr, err := resolve.EVMAddress("", vi)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if !strings.EqualFold(vaultAddr, from.Hex()) {
+				return mcp.NewToolResultError(fmt.Sprintf("transaction was sent by %s, not the vault address %s", from.Hex(), vaultAddr)), nil
+			}
+			if nonce > math.MaxInt64 {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid nonce: %d", nonce)), nil
+			}
+			payload, err := evmKeysignPayload(vi, chainName,
+				p.to.Hex(), p.value.String(), "0x"+hex.EncodeToString(p.data), int64(nonce),
+				fmt.Sprint(*p.gasLimit), p.maxFee.String(), p.maxPriorityFee.String())
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			action := "transfer"
+			if len(p.data) > 0 {
+				action = "contract_call"
+			}
+			return keysignToolResult(chainName, action, payload)
+		}
+
+		result, err := evmTxResult(chainName, p, nil)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		result["action"] = kind
+		result["from"] = from.Hex()
+		result["original_tx_hash"] = txHash
+		if p.txType == ethtypes.DynamicFeeTxType {
+			result["original_max_fee_per_gas"] = orig.GasFeeCap().String()
+			result["original_max_priority_fee_per_gas"] = orig.GasTipCap().String()
+		} else {
+			result["original_gas_price"] = orig.GasPrice().String()
+		}
+		ticker := evmclient.NativeTicker(chainName)
+		result["max_network_fee"] = evmclient.FormatUnits(networkFee, 18) + " " + ticker
+		if rollup != nil {
+			result["rollup_fee"] = newRollupFeeJSON(rollup, ticker)
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("marshal result: %w", err)
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// cancelGas estimates the gas of a 0-value send from from to itself. On
+// chains with plain gas pricing it is at least cancelGasLimit, which is
+// also used if the estimate fails; rollups need the estimate for their L1
+// or pubdata gas.
+func cancelGas(ctx context.Context, client *evmclient.Client, chainName string, from common.Address) (uint64, error) {
+	gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &from, Value: new(big.Int)})
+	if evmclient.FeeModel(chainName) != evmclient.FeeModelL1 {
+		if err != nil {
+			return 0, fmt.Errorf("gas estimation failed: %v", err)
+		}
+		return gas, nil
+	}
+	if err != nil {
+		return cancelGasLimit, nil
+	}
+	return max(gas, cancelGasLimit), nil
+}
+
+// loadPendingEVMTx fetches a transaction that is still waiting to be mined
+// and its sender.
+func loadPendingEVMTx(ctx context.Context, client *evmclient.Client, chainID *big.Int, hash common.Hash) (*ethtypes.Transaction, common.Address, error) {
+	tx, pending, err := client.ETH().TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, common.Address{}, fmt.Errorf("transaction %s not found; it may have been dropped from the mempool", hash.Hex())
+	}
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("fetch transaction: %v", err)
+	}
+	if !pending {
+		return nil, common.Address{}, fmt.Errorf("transaction %s is already mined; check it with get_tx_status", hash.Hex())
+	}
+	switch tx.Type() {
+	case ethtypes.LegacyTxType, ethtypes.AccessListTxType, ethtypes.DynamicFeeTxType:
+	default:
+		return nil, common.Address{}, fmt.Errorf("replacing type %d transactions is not supported", tx.Type())
+	}
+
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("recover sender: %v", err)
+	}
+	mined, err := client.ETH().NonceAt(ctx, from, nil)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get nonce: %v", err)
+	}
+	if mined > tx.Nonce() {
+		return nil, common.Address{}, fmt.Errorf("nonce %d of %s is already used by a mined transaction", tx.Nonce(), from.Hex())
+	}
+	return tx, from, nil
+}
+
+// replacementFees sets the fees of p to replace orig: the fee parameters
+// when given, otherwise orig's fees bumped by replacementBumpPercent, raised
+// to the node's suggestion and, for type 2, a max fee of twice the current
+// base fee plus the tip.
+func replacementFees(ctx context.Context, client *evmclient.Client, req mcp.CallToolRequest, orig *ethtypes.Transaction, p *evmTxParams) error {
+	var err error
+	if p.maxFee, err = optionalWeiParam(req, "max_fee_per_gas", false); err != nil {
+		return err
+	}
+	if p.maxPriorityFee, err = optionalWeiParam(req, "max_priority_fee_per_gas", true); err != nil {
+		return err
+	}
+	if p.gasPrice, err = optionalWeiParam(req, "gas_price", false); err != nil {
+		return err
+	}
+
+	if p.txType != ethtypes.DynamicFeeTxType {
+		if p.maxFee != nil || p.maxPriorityFee != nil {
+			return fmt.Errorf("the original is a type %d transaction; use gas_price", p.txType)
+		}
+		minPrice := bumpFee(orig.GasPrice())
+		if p.gasPrice == nil {
+			suggested, err := client.SuggestGasPrice(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get gas price: %v", err)
+			}
+			p.gasPrice = bigMax(minPrice, suggested)
+		} else if p.gasPrice.Cmp(minPrice) < 0 {
+			return fmt.Errorf("gas_price must be at least %s (original %s + %d%%)", minPrice, orig.GasPrice(), replacementBumpPercent)
+		}
+		return nil
+	}
+
+	if p.gasPrice != nil {
+		return errors.New("the original is a type 2 transaction; use max_fee_per_gas and max_priority_fee_per_gas")
+	}
+	minTip, minFeeCap := bumpFee(orig.GasTipCap()), bumpFee(orig.GasFeeCap())
+	if p.maxPriorityFee == nil {
+		suggested, err := client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get gas tip cap: %v", err)
+		}
+		p.maxPriorityFee = bigMax(minTip, suggested)
+	} else if p.maxPriorityFee.Cmp(minTip) < 0 {
+		return fmt.Errorf("max_priority_fee_per_gas must be at least %s (original %s + %d%%)", minTip, orig.GasTipCap(), replacementBumpPercent)
+	}
+	if p.maxFee == nil {
+		p.maxFee = bigMax(minFeeCap, p.maxPriorityFee)
+		baseFee, err := client.LatestBaseFee(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get base fee: %v", err)
+		}
+		current := new(big.Int).Mul(baseFee, big.NewInt(2))
+		p.maxFee = bigMax(p.maxFee, current.Add(current, p.maxPriorityFee))
+	} else if p.maxFee.Cmp(minFeeCap) < 0 {
+		return fmt.Errorf("max_fee_per_gas must be at least %s (original %s + %d%%)", minFeeCap, orig.GasFeeCap(), replacementBumpPercent)
+	}
+	if p.maxPriorityFee.Cmp(p.maxFee) > 0 {
+		return fmt.Errorf("max_priority_fee_per_gas %s exceeds max_fee_per_gas %s", p.maxPriorityFee, p.maxFee)
+	}
+	return nil
+}
+
+// bumpFee returns fee raised by replacementBumpPercent, rounded up.
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+replacementBumpPercent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return new(big.Int).Set(a)
+	}
+	return new(big.Int).Set(b)
+}
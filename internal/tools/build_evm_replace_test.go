@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const gwei = 1_000_000_000
+
+// pendingTxPool serves tx as pending, a sender nonce of minedNonce, a
+// suggested tip of 0.5 gwei, a base fee of 15 gwei and a balance of 1 ETH.
+func pendingTxPool(t *testing.T, tx *ethtypes.Transaction, minedNonce string) map[string]any {
+	t.Helper()
+	return map[string]any{
+		"eth_getTransactionByHash": tx,
+		"eth_getTransactionCount":  minedNonce,
+		"eth_maxPriorityFeePerGas": "0x1dcd6500",
+		"eth_gasPrice":             "0x37e11d600",
+		"eth_getBlockByNumber":     mockHeader("0x37e11d600"),
+		"eth_getBalance":           "0xde0b6b3a7640000",
+	}
+}
+
+// signTestTx signs txData with a random key for chain 1 and returns the
+// transaction and its sender.
+func signTestTx(t *testing.T, txData ethtypes.TxData) (*ethtypes.Transaction, ethcommon.Address) {
+	t.Helper()
+	return signTestTxForChain(t, big.NewInt(1), txData)
+}
+
+// signTestTxForChain is signTestTx for chainID.
+func signTestTxForChain(t *testing.T, chainID *big.Int, txData ethtypes.TxData) (*ethtypes.Transaction, ethcommon.Address) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := ethtypes.SignNewTx(key, ethtypes.LatestSignerForChainID(chainID), txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestBuildEVMReplacement(t *testing.T) {
+	to := ethcommon.HexToAddress(usdt)
+	calldata, _ := hexToBytes(transferCalldata(testAddress, 1000))
+	orig, from := signTestTx(t, &ethtypes.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     5,
+		GasTipCap: big.NewInt(1 * gwei),
+		GasFeeCap: big.NewInt(20 * gwei),
+		Gas:       65_000,
+		To:        &to,
+		Value:     new(big.Int),
+		Data:      calldata,
+	})
+
+	tests := []struct {
+		kind  string
+		to    string
+		data  string
+		gas   string
+		value string
+	}{
+		{replaceSpeedup, usdt, transferCalldata(testAddress, 1000), "65000", "0"},
+		{replaceCancel, from.Hex(), "0x", "21000", "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			pool, _ := mockEVMPool(t, pendingTxPool(t, orig, "0x5"))
+			res, err := handleBuildEVMReplacement(nil, pool, tt.kind)(context.Background(), callToolReq("build_evm_"+tt.kind, map[string]any{
+				"tx_hash": orig.Hash().Hex(),
+			}))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result := decodeResult(t, res)
+			// The tip is the original's +10% since it beats the suggested
+			// 0.5 gwei; the max fee covers twice the 15 gwei base fee.
+			if result["nonce"] != "5" || result["to"] != tt.to || result["data"] != strings.ToLower(tt.data) ||
+				result["gas_limit"] != tt.gas || result["value"] != tt.value ||
+				result["max_priority_fee_per_gas"] != "1100000000" || result["max_fee_per_gas"] != "31100000000" {
+				t.Errorf("result = %v", result)
+			}
+			if result["action"] != tt.kind || result["from"] != from.Hex() || result["original_max_fee_per_gas"] != "20000000000" {
+				t.Errorf("result = %v", result)
+			}
+		})
+	}
+}
+
+func TestBuildEVMCancel_ArbitrumGas(t *testing.T) {
+	to := ethcommon.HexToAddress(usdt)
+	orig, from := signTestTxForChain(t, big.NewInt(42161), &ethtypes.DynamicFeeTx{
+		ChainID:   big.NewInt(42161),
+		Nonce:     5,
+		GasTipCap: big.NewInt(0),
+		GasFeeCap: big.NewInt(20_000_000),
+		Gas:       400_000,
+		To:        &to,
+	})
+	results := pendingTxPool(t, orig, "0x5")
+	// The self-send needs 21,000 gas for execution plus 9,000 for L1 data.
+	results["eth_estimateGas"] = "0x7530"
+	results["eth_call"] = fmt.Sprintf("0x%064x%064x%064x%064x", 30_000, 9_000, 10_000_000, 20_000_000_000)
+	pool, _ := mockEVMChainPool(t, "Arbitrum", results)
+
+	res, err := handleBuildEVMReplacement(nil, pool, replaceCancel)(context.Background(), callToolReq("build_evm_cancel", map[string]any{
+		"chain":   "Arbitrum",
+		"tx_hash": orig.Hash().Hex(),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	if result["gas_limit"] != "30000" || result["to"] != from.Hex() {
+		t.Errorf("result = %v", result)
+	}
+	rollup, _ := result["rollup_fee"].(map[string]any)
+	if rollup["model"] != "arbitrum" || rollup["l1_gas"] != float64(9_000) {
+		t.Errorf("rollup_fee = %v", rollup)
+	}
+}
+
+func TestBuildEVMSpeedup_Legacy(t *testing.T) {
+	to := ethcommon.HexToAddress(testAddress)
+	orig, _ := signTestTx(t, &ethtypes.LegacyTx{
+		Nonce:    0,
+		GasPrice: big.NewInt(40 * gwei),
+		Gas:      21_000,
+		To:       &to,
+		Value:    big.NewInt(1),
+	})
+	pool, _ := mockEVMPool(t, pendingTxPool(t, orig, "0x0"))
+
+	res, err := handleBuildEVMReplacement(nil, pool, replaceSpeedup)(context.Background(), callToolReq("build_evm_speedup", map[string]any{
+		"tx_hash": orig.Hash().Hex(),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 40 gwei +10% beats the node's 15 gwei gas price.
+	result := decodeResult(t, res)
+	if result["tx_type"] != float64(0) || result["gas_price"] != "44000000000" || result["value"] != "1" {
+		t.Errorf("result = %v", result)
+	}
+}
+
+func TestBuildEVMReplacement_Errors(t *testing.T) {
+	to := ethcommon.HexToAddress(usdt)
+	orig, _ := signTestTx(t, &ethtypes.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     5,
+		GasTipCap: big.NewInt(1 * gwei),
+		GasFeeCap: big.NewInt(20 * gwei),
+		Gas:       65_000,
+		To:        &to,
+	})
+	mined, _ := json.Marshal(orig)
+	var minedJSON map[string]any
+	_ = json.Unmarshal(mined, &minedJSON)
+	minedJSON["blockNumber"] = "0x10"
+	minedJSON["blockHash"] = ethcommon.Hash{1}.Hex()
+
+	tests := map[string]struct {
+		results map[string]any
+		args    map[string]any
+		want    string
+	}{
+		"tip too low": {
+			results: pendingTxPool(t, orig, "0x5"),
+			args:    map[string]any{"max_priority_fee_per_gas": "1050000000"},
+			want:    "at least 1100000000",
+		},
+		"gas_price on type 2": {
+			results: pendingTxPool(t, orig, "0x5"),
+			args:    map[string]any{"gas_price": "50000000000"},
+			want:    "type 2",
+		},
+		"nonce used": {
+			results: pendingTxPool(t, orig, "0x6"),
+			want:    "already used",
+		},
+		"mined": {
+			results: map[string]any{"eth_getTransactionByHash": minedJSON},
+			want:    "already mined",
+		},
+		"not found": {
+			results: map[string]any{"eth_getTransactionByHash": nil},
+			want:    "not found",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pool, _ := mockEVMPool(t, tt.results)
+			args := map[string]any{"tx_hash": orig.Hash().Hex()}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			res, err := handleBuildEVMReplacement(nil, pool, replaceSpeedup)(context.Background(), callToolReq("build_evm_speedup", args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !res.IsError || !strings.Contains(res.Content[0].(mcp.TextContent).Text, tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, res.Content)
+			}
+		})
+	}
+}
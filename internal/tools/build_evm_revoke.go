@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// maxRevokes caps the number of revoke transactions built in one call.
+const maxRevokes = 50
+
+func newBuildEVMRevokeTool() mcp.Tool {
+	return mcp.NewTool("build_evm_revoke",
+		mcp.WithDescription(
+			"Build unsigned transactions that revoke token approvals on one EVM chain: approve(spender, 0) for ERC-20 "+
+				"and setApprovalForAll(operator, false) for NFT operators. "+
+				"Pass entries from evm_list_approvals. Returns one transaction per approval with consecutive nonces, "+
+				"each with its signing hash and max network fee (including any rollup L1 fee), "+
+				"and checks the native balance covers their total. Sender falls back to the vault-derived address if not provided. "+
+				"Set output_format to \"keysign\" to receive one Vultisig keysign payload per revoke, in nonce order (tx_type 2 only).",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithArray("approvals",
+			mcp.Description(fmt.Sprintf("Approvals to revoke (max %d): objects with \"token\", \"spender\" and \"type\" "+
+				"(\"erc20\", the default, or \"nft_operator\").", maxRevokes)),
+			mcp.Required(),
+		),
+		mcp.WithString("from",
+			mcp.Description("Owner address (0x-prefixed). Optional if vault info is set."),
+		),
+		mcp.WithNumber("tx_type",
+			mcp.Description("Transaction type: 2 (EIP-1559, default), 1 (EIP-2930) or 0 (legacy)."),
+		),
+		withOutputFormat(),
+	)
+}
+
+type revokeItem struct {
+	Token   string `json:"token"`
+	Spender string `json:"spender"`
+	Type    string `json:"type"`
+}
+
+func handleBuildEVMRevoke(store *vault.Store, pool *evmclient.Pool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		chainID, err := evmChainID(req, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		txType, err := parseEVMTxType(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		items, err := parseRevokeItems(req.GetArguments()["approvals"])
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if asKeysign && txType != ethtypes.DynamicFeeTxType {
+			return mcp.NewToolResultError("keysign output supports only tx_type 2"), nil
+		}
+
+		explicit := req.GetString("from", "")
+		if explicit != "" && !common.IsHexAddress(explicit) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid from address: %s", explicit)), nil
+		}
+		v := resolve.ResolveVault(ctx, req, store)
+		fromStr, err := resolve.EVMAddress(explicit, v)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		from := common.HexToAddress(fromStr)
+
+		if asKeysign {
+			if v == nil {
+				return mcp.NewToolResultError("build keysign payload: vault info required"), nil
+			}
+			vaultAddr, err := resolve.EVMAddress("", v)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			if !strings.EqualFold(vaultAddr, from.Hex()) {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"from address %q does not match vault-derived address %q", from.Hex(), vaultAddr)), nil
+			}
+		}
+
+		client, _, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+
+		ticker := evmclient.NativeTicker(chainName)
+		totalFee := new(big.Int)
+		var txs []map[string]any
+		var steps []keysignStep
+		var first *evmTxParams
+		for i, item := range items {
+			token := common.HexToAddress(item.Token)
+			spender := common.HexToAddress(item.Spender)
+
+			p := &evmTxParams{txType: txType, chainID: chainID, to: token, value: new(big.Int)}
+			description := fmt.Sprintf("Revoke %s's ERC-20 allowance on %s", spenderName(spender), token.Hex())
+			if item.Type == approvalTypeNFTOperator {
+				p.data = evmclient.PackSetApprovalForAll(spender, false)
+				description = fmt.Sprintf("Revoke %s as operator of %s", spenderName(spender), token.Hex())
+			} else {
+				p.data = evmclient.PackERC20Approve(spender, new(big.Int))
+			}
+			if txType == ethtypes.AccessListTxType {
+				p.accessList = ethtypes.AccessList{}
+			}
+			// Later revokes reuse the first one's fees and take the next nonces.
+			if first != nil {
+				nonce := *first.nonce + uint64(i)
+				p.nonce = &nonce
+				p.gasPrice, p.maxFee, p.maxPriorityFee = first.gasPrice, first.maxFee, first.maxPriorityFee
+			}
+
+			if _, err := fillEVMTxParams(ctx, client, p, func() (string, error) { return from.Hex(), nil }); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("revoke %d (%s / %s): %v", i+1, token.Hex(), spender.Hex(), err)), nil
+			}
+			if first == nil {
+				first = p
+			}
+			rollup, err := evmRollupFee(ctx, client, chainName, from, p)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("revoke %d (%s / %s): %v", i+1, token.Hex(), spender.Hex(), err)), nil
+			}
+			networkFee := new(big.Int).Add(p.maxNetworkFee(), rollup.ExtraFee())
+			totalFee.Add(totalFee, networkFee)
+
+			if asKeysign {
+				if *p.nonce > math.MaxInt64 {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid nonce: %d", *p.nonce)), nil
+				}
+				payload, err := evmKeysignPayload(v, chainName,
+					token.Hex(), "0", "0x"+hex.EncodeToString(p.data), int64(*p.nonce),
+					fmt.Sprint(*p.gasLimit), p.maxFee.String(), p.maxPriorityFee.String())
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+				}
+				steps = append(steps, keysignStep{Description: description, Payload: payload})
+				continue
+			}
+
+			tx, err := evmTxResult(chainName, p, nil)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			tx["sequence"] = i + 1
+			tx["type"] = item.Type
+			tx["token"] = token.Hex()
+			tx["spender"] = spender.Hex()
+			if label := evmclient.SpenderLabel(spender); label != "" {
+				tx["spender_name"] = label
+			}
+			tx["description"] = description
+			tx["max_network_fee"] = evmclient.FormatUnits(networkFee, 18) + " " + ticker
+			if rollup != nil {
+				tx["rollup_fee"] = newRollupFeeJSON(rollup, ticker)
+			}
+			txs = append(txs, tx)
+		}
+
+		// The revokes are sent together, so the balance must cover all of them.
+		if err := checkEVMFunds(ctx, client, chainName, from, new(big.Int), totalFee); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if asKeysign {
+			return keysignBatchToolResult(chainName, "revoke", steps)
+		}
+
+		data, err := json.Marshal(map[string]any{
+			"chain":           chainName,
+			"from":            from.Hex(),
+			"transactions":    txs,
+			"max_network_fee": evmclient.FormatUnits(totalFee, 18) + " " + ticker,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// parseRevokeItems validates the approvals parameter.
+func parseRevokeItems(raw any) ([]revokeItem, error) {
+	list, ok := raw.([]any)
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("approvals must be a non-empty list")
+	}
+	if len(list) > maxRevokes {
+		return nil, fmt.Errorf("at most %d approvals can be revoked at once, got %d", maxRevokes, len(list))
+	}
+
+	encoded, err := json.Marshal(list)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approvals: %v", err)
+	}
+	var items []revokeItem
+	if err := json.Unmarshal(encoded, &items); err != nil {
+		return nil, fmt.Errorf("approvals must be objects with token, spender and type: %v", err)
+	}
+	for i := range items {
+		if !common.IsHexAddress(items[i].Token) {
+			return nil, fmt.Errorf("approvals[%d]: invalid token address: %q", i, items[i].Token)
+		}
+		if !common.IsHexAddress(items[i].Spender) {
+			return nil, fmt.Errorf("approvals[%d]: invalid spender address: %q", i, items[i].Spender)
+		}
+		switch items[i].Type {
+		case "":
+			items[i].Type = approvalTypeERC20
+		case approvalTypeERC20, approvalTypeNFTOperator:
+		default:
+			return nil, fmt.Errorf("approvals[%d]: invalid type %q (expected %q or %q)", i, items[i].Type, approvalTypeERC20, approvalTypeNFTOperator)
+		}
+	}
+	return items, nil
+}
+
+// spenderName returns the spender's label, or its address if unknown.
+func spenderName(spender common.Address) string {
+	if label := evmclient.SpenderLabel(spender); label != "" {
+		return label
+	}
+	return spender.Hex()
+}
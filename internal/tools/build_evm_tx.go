@@ -2,28 +2,45 @@ package tools
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"strings"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	reth "github.com/vultisig/recipes/chain/evm/ethereum"
 
 	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/types"
+	"github.com/vultisig/mcp/internal/vault"
 )
 
 func newBuildEVMTxTool() mcp.Tool {
 	return mcp.NewTool("build_evm_tx",
 		mcp.WithDescription(
-			"Return EVM transaction arguments for an EIP-1559 (type 2) transaction. "+
-				"Use evm_tx_info to obtain nonce, gas prices, and chain ID first. "+
-				"The client is responsible for assembling and signing the transaction.",
+			"Build an unsigned EVM transaction: EIP-1559 (type 2, default), EIP-2930 (type 1) or legacy (type 0). "+
+				"Returns the unsigned serialization, the keccak256 signing hash for MPC signing and the EIP-155 chain ID. "+
+				"Nonce, gas limit and fees are fetched from the chain's RPC when omitted, the same way evm_tx_info does; "+
+				"the sender for nonce and gas estimation comes from 'from' or the vault. "+
+				"Set output_format to \"keysign\" to receive a Vultisig keysign payload for the vault-derived sender (type 2 only).",
 		),
 		mcp.WithString("chain",
 			mcp.Description("EVM chain name. One of: "+chainEnumDesc()+". Determines chain_id when not explicitly set."),
 			mcp.DefaultString("Ethereum"),
 		),
+		mcp.WithString("from",
+			mcp.Description("Sender address (0x-prefixed), used to auto-fill nonce and gas_limit. Falls back to the vault-derived address."),
+		),
 		mcp.WithString("to",
 			mcp.Description("Destination address (0x-prefixed)."),
 			mcp.Required(),
@@ -35,122 +52,471 @@ func newBuildEVMTxTool() mcp.Tool {
 		mcp.WithString("data",
 			mcp.Description("Hex-encoded calldata (0x-prefixed). Default \"0x\" (empty)."),
 		),
+		mcp.WithNumber("tx_type",
+			mcp.Description("Transaction type: 2 (EIP-1559, default), 1 (EIP-2930 access list) or 0 (legacy). "+
+				"Use 0 or 1 on chains or RPCs without EIP-1559 fees."),
+		),
 		mcp.WithString("nonce",
-			mcp.Description("Sender nonce (decimal string)."),
-			mcp.Required(),
+			mcp.Description("Sender nonce (decimal string). Fetched from the pending state when omitted."),
 		),
 		mcp.WithString("gas_limit",
-			mcp.Description("Gas limit (decimal string)."),
-			mcp.Required(),
+			mcp.Description("Gas limit (decimal string). Estimated when omitted."),
 		),
 		mcp.WithString("max_fee_per_gas",
-			mcp.Description("Max fee per gas in wei (decimal string)."),
-			mcp.Required(),
+			mcp.Description("Type 2 only. Max fee per gas in wei (decimal string). Defaults to 2x the latest base fee plus the priority fee."),
 		),
 		mcp.WithString("max_priority_fee_per_gas",
-			mcp.Description("Max priority fee (tip) per gas in wei (decimal string)."),
-			mcp.Required(),
+			mcp.Description("Type 2 only. Max priority fee (tip) per gas in wei (decimal string). Defaults to the node's suggestion."),
+		),
+		mcp.WithString("gas_price",
+			mcp.Description("Types 0 and 1 only. Gas price in wei (decimal string). Defaults to the node's suggestion."),
+		),
+		mcp.WithArray("access_list",
+			mcp.Description("Type 1 only. EIP-2930 access list: objects with \"address\" and \"storageKeys\" (32-byte hex strings)."),
 		),
 		mcp.WithString("chain_id",
-			mcp.Description("Chain ID override (decimal string). Defaults to the chain's known ID."),
+			mcp.Description("Chain ID override (decimal string). Defaults to the chain's known ID. Keysign output requires the chain's own ID."),
 		),
+		withOutputFormat(),
 	)
 }
 
-func handleBuildEVMTx() server.ToolHandlerFunc {
+// evmTxParams holds the parsed build_evm_tx arguments. Nil nonce, gas and
+// fee fields are filled from the chain before the transaction is built.
+type evmTxParams struct {
+	txType         uint8
+	chainID        *big.Int
+	to             common.Address
+	value          *big.Int
+	data           []byte
+	accessList     ethtypes.AccessList
+	nonce          *uint64
+	gasLimit       *uint64
+	gasPrice       *big.Int
+	maxFee         *big.Int
+	maxPriorityFee *big.Int
+}
+
+// needsRPC reports whether any field must be fetched from the chain.
+func (p *evmTxParams) needsRPC() bool {
+	if p.nonce == nil || p.gasLimit == nil {
+		return true
+	}
+	if p.txType == ethtypes.DynamicFeeTxType {
+		return p.maxFee == nil || p.maxPriorityFee == nil
+	}
+	return p.gasPrice == nil
+}
+
+// txData returns the go-ethereum transaction for the params. All fields must
+// be filled.
+func (p *evmTxParams) txData() ethtypes.TxData {
+	to := p.to
+	switch p.txType {
+	case ethtypes.LegacyTxType:
+		return &ethtypes.LegacyTx{Nonce: *p.nonce, GasPrice: p.gasPrice, Gas: *p.gasLimit, To: &to, Value: p.value, Data: p.data}
+	case ethtypes.AccessListTxType:
+		return &ethtypes.AccessListTx{ChainID: p.chainID, Nonce: *p.nonce, GasPrice: p.gasPrice, Gas: *p.gasLimit, To: &to, Value: p.value, Data: p.data, AccessList: p.accessList}
+	default:
+		return &ethtypes.DynamicFeeTx{ChainID: p.chainID, Nonce: *p.nonce, GasTipCap: p.maxPriorityFee, GasFeeCap: p.maxFee, Gas: *p.gasLimit, To: &to, Value: p.value, Data: p.data, AccessList: p.accessList}
+	}
+}
+
+func handleBuildEVMTx(store *vault.Store, pool *evmclient.Pool) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		chainName := req.GetString("chain", "Ethereum")
 
-		chainID, ok := evmclient.ChainIDByName(chainName)
-		if !ok {
-			return mcp.NewToolResultError(fmt.Sprintf("unsupported chain: %s", chainName)), nil
-		}
-		if cidStr := req.GetString("chain_id", ""); cidStr != "" {
-			cid, cidOK := new(big.Int).SetString(cidStr, 10)
-			if !cidOK || cid.Sign() <= 0 {
-				return mcp.NewToolResultError(fmt.Sprintf("invalid chain_id: %s", cidStr)), nil
-			}
-			chainID = cid
+		p, err := parseEVMTxParams(req, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		toStr, err := req.RequireString("to")
+		asKeysign, err := keysignRequested(req)
 		if err != nil {
-			return mcp.NewToolResultError("missing to parameter"), nil
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if asKeysign && p.txType != ethtypes.DynamicFeeTxType {
+			return mcp.NewToolResultError("keysign output supports only tx_type 2"), nil
 		}
-		if !common.IsHexAddress(toStr) {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid to address: %s", toStr)), nil
+		// Keysign payloads identify the chain by name only, so an
+		// overridden chain ID would be silently dropped.
+		if known, _ := evmclient.ChainIDByName(chainName); asKeysign && p.chainID.Cmp(known) != 0 {
+			return mcp.NewToolResultError(fmt.Sprintf(
+				"keysign output signs for %s (chain ID %s); chain_id %s is only supported with output_format \"args\"", chainName, known, p.chainID)), nil
 		}
 
-		valueStr, err := req.RequireString("value")
-		if err != nil {
-			return mcp.NewToolResultError("missing value parameter"), nil
+		fromStr := req.GetString("from", "")
+		if fromStr != "" && !common.IsHexAddress(fromStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid from address: %s", fromStr)), nil
 		}
-		valueInt, valueOK := new(big.Int).SetString(valueStr, 10)
-		if !valueOK || valueInt.Sign() < 0 {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid value: %s", valueStr)), nil
+
+		var autoFilled []string
+		var networkFee *big.Int
+		var rollup *evmclient.RollupFee
+		if p.needsRPC() {
+			if pool == nil {
+				return mcp.NewToolResultError("nonce, gas_limit and fees are required when no RPC is configured"), nil
+			}
+			client, _, err := pool.Get(ctx, chainName)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+			}
+			sender := func() (string, error) {
+				return resolve.EVMAddress(fromStr, resolve.ResolveVault(ctx, req, store))
+			}
+			autoFilled, err = fillEVMTxParams(ctx, client, p, sender)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			// With nonce and gas_limit given the sender is optional; without
+			// one there is no balance to check the fee against.
+			if addr, err := sender(); err == nil {
+				networkFee, rollup, err = evmNetworkFee(ctx, client, chainName, common.HexToAddress(addr), p)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+			}
 		}
 
-		dataHex := req.GetString("data", "0x")
-		if dataHex != "" && dataHex != "0x" {
-			_, hexErr := hexToBytes(dataHex)
-			if hexErr != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("invalid data hex: %v", hexErr)), nil
+		dataHex := "0x" + hex.EncodeToString(p.data)
+		if asKeysign {
+			v := resolve.ResolveVault(ctx, req, store)
+			if v == nil {
+				return mcp.NewToolResultError("build keysign payload: vault info required"), nil
+			}
+			vaultAddr, err := resolve.EVMAddress("", v)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
 			}
+			if fromStr != "" && !strings.EqualFold(vaultAddr, common.HexToAddress(fromStr).Hex()) {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"from address %q does not match vault-derived address %q", fromStr, vaultAddr)), nil
+			}
+			if *p.nonce > math.MaxInt64 {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid nonce: %d", *p.nonce)), nil
+			}
+			payload, err := evmKeysignPayload(v, chainName,
+				p.to.Hex(), p.value.String(), dataHex, int64(*p.nonce),
+				fmt.Sprint(*p.gasLimit), p.maxFee.String(), p.maxPriorityFee.String())
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			action := "transfer"
+			if len(p.data) > 0 {
+				action = "contract_call"
+			}
+			return keysignToolResult(chainName, action, payload)
 		}
 
-		nonceStr, err := req.RequireString("nonce")
+		result, err := evmTxResult(chainName, p, autoFilled)
 		if err != nil {
-			return mcp.NewToolResultError("missing nonce parameter"), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		nonceInt, nonceOK := new(big.Int).SetString(nonceStr, 10)
-		if !nonceOK || nonceInt.Sign() < 0 {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid nonce: %s", nonceStr)), nil
+		if networkFee != nil {
+			ticker := evmclient.NativeTicker(chainName)
+			result["max_network_fee"] = evmclient.FormatUnits(networkFee, 18) + " " + ticker
+			if rollup != nil {
+				result["rollup_fee"] = newRollupFeeJSON(rollup, ticker)
+			}
 		}
 
-		gasLimitStr, err := req.RequireString("gas_limit")
+		data, err := json.Marshal(result)
 		if err != nil {
-			return mcp.NewToolResultError("missing gas_limit parameter"), nil
+			return nil, fmt.Errorf("marshal result: %w", err)
 		}
-		gasInt, gasOK := new(big.Int).SetString(gasLimitStr, 10)
-		if !gasOK || gasInt.Sign() <= 0 {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid gas_limit: %s", gasLimitStr)), nil
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// evmTxResult assembles p and returns its fields together with the unsigned
+// serialization and signing hash.
+func evmTxResult(chainName string, p *evmTxParams, autoFilled []string) (map[string]any, error) {
+	unsigned, err := evmSigningPayload(ethtypes.NewTx(p.txData()), p.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("encode transaction: %v", err)
+	}
+
+	result := map[string]any{
+		"chain":           chainName,
+		"chain_id":        p.chainID.String(),
+		"to":              p.to.Hex(),
+		"value":           p.value.String(),
+		"data":            "0x" + hex.EncodeToString(p.data),
+		"nonce":           fmt.Sprint(*p.nonce),
+		"gas_limit":       fmt.Sprint(*p.gasLimit),
+		"tx_type":         int(p.txType),
+		"tx_encoding":     types.TxEncodingEIP1559RLP,
+		"unsigned_tx_hex": "0x" + hex.EncodeToString(unsigned),
+		"signing_hash":    crypto.Keccak256Hash(unsigned).Hex(),
+	}
+	if p.txType == ethtypes.DynamicFeeTxType {
+		result["max_fee_per_gas"] = p.maxFee.String()
+		result["max_priority_fee_per_gas"] = p.maxPriorityFee.String()
+	} else {
+		result["gas_price"] = p.gasPrice.String()
+	}
+	switch p.txType {
+	case ethtypes.LegacyTxType:
+		result["tx_encoding"] = types.TxEncodingLegacyRLP
+	case ethtypes.AccessListTxType:
+		result["tx_encoding"] = types.TxEncodingEIP2930RLP
+		result["access_list"] = p.accessList
+	}
+	if len(autoFilled) > 0 {
+		result["auto_filled"] = autoFilled
+	}
+	return result, nil
+}
+
+// maxNetworkFee returns the most p can pay in fees: the gas limit times the
+// max fee per gas, or the gas price for type 0 and 1.
+func (p *evmTxParams) maxNetworkFee() *big.Int {
+	price := p.gasPrice
+	if p.txType == ethtypes.DynamicFeeTxType {
+		price = p.maxFee
+	}
+	return new(big.Int).Mul(price, new(big.Int).SetUint64(*p.gasLimit))
+}
+
+// evmChainID returns the known chain ID of chainName, or the chain_id
+// parameter when set.
+func evmChainID(req mcp.CallToolRequest, chainName string) (*big.Int, error) {
+	chainID, ok := evmclient.ChainIDByName(chainName)
+	if !ok {
+		return nil, fmt.Errorf("unsupported chain: %s", chainName)
+	}
+	if cidStr := req.GetString("chain_id", ""); cidStr != "" {
+		cid, cidOK := new(big.Int).SetString(cidStr, 10)
+		if !cidOK || cid.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid chain_id: %s", cidStr)
 		}
+		chainID = cid
+	}
+	return chainID, nil
+}
+
+// parseEVMTxType reads the tx_type parameter, defaulting to EIP-1559.
+func parseEVMTxType(req mcp.CallToolRequest) (uint8, error) {
+	switch t := req.GetFloat("tx_type", ethtypes.DynamicFeeTxType); t {
+	case ethtypes.LegacyTxType, ethtypes.AccessListTxType, ethtypes.DynamicFeeTxType:
+		return uint8(t), nil
+	default:
+		return 0, fmt.Errorf("invalid tx_type: %v (expected 0, 1 or 2)", t)
+	}
+}
 
-		maxFeeStr, err := req.RequireString("max_fee_per_gas")
+// parseEVMTxParams validates the build_evm_tx arguments.
+func parseEVMTxParams(req mcp.CallToolRequest, chainName string) (*evmTxParams, error) {
+	chainID, err := evmChainID(req, chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &evmTxParams{chainID: chainID}
+
+	if p.txType, err = parseEVMTxType(req); err != nil {
+		return nil, err
+	}
+
+	toStr, err := req.RequireString("to")
+	if err != nil {
+		return nil, errors.New("missing to parameter")
+	}
+	if !common.IsHexAddress(toStr) {
+		return nil, fmt.Errorf("invalid to address: %s", toStr)
+	}
+	p.to = common.HexToAddress(toStr)
+
+	valueStr, err := req.RequireString("value")
+	if err != nil {
+		return nil, errors.New("missing value parameter")
+	}
+	value, valueOK := new(big.Int).SetString(valueStr, 10)
+	if !valueOK || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid value: %s", valueStr)
+	}
+	p.value = value
+
+	if dataHex := req.GetString("data", "0x"); dataHex != "" && dataHex != "0x" {
+		p.data, err = hexToBytes(dataHex)
 		if err != nil {
-			return mcp.NewToolResultError("missing max_fee_per_gas parameter"), nil
+			return nil, fmt.Errorf("invalid data hex: %v", err)
 		}
-		maxFeeInt, maxFeeOK := new(big.Int).SetString(maxFeeStr, 10)
-		if !maxFeeOK || maxFeeInt.Sign() <= 0 {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid max_fee_per_gas: %s", maxFeeStr)), nil
+	}
+
+	if p.nonce, err = optionalUint64Param(req, "nonce", true); err != nil {
+		return nil, err
+	}
+	if p.gasLimit, err = optionalUint64Param(req, "gas_limit", false); err != nil {
+		return nil, err
+	}
+	if p.maxFee, err = optionalWeiParam(req, "max_fee_per_gas", false); err != nil {
+		return nil, err
+	}
+	if p.maxPriorityFee, err = optionalWeiParam(req, "max_priority_fee_per_gas", true); err != nil {
+		return nil, err
+	}
+	if p.gasPrice, err = optionalWeiParam(req, "gas_price", false); err != nil {
+		return nil, err
+	}
+
+	if p.txType == ethtypes.DynamicFeeTxType {
+		if p.gasPrice != nil {
+			return nil, errors.New("gas_price applies to tx_type 0 and 1; use max_fee_per_gas and max_priority_fee_per_gas for tx_type 2")
 		}
+	} else if p.maxFee != nil || p.maxPriorityFee != nil {
+		return nil, errors.New("max_fee_per_gas and max_priority_fee_per_gas apply to tx_type 2; use gas_price for tx_type 0 and 1")
+	}
 
-		maxPriorityFeeStr, err := req.RequireString("max_priority_fee_per_gas")
+	if raw, ok := req.GetArguments()["access_list"]; ok && raw != nil {
+		if p.txType == ethtypes.LegacyTxType {
+			return nil, errors.New("access_list is not supported by legacy (tx_type 0) transactions")
+		}
+		encoded, err := json.Marshal(raw)
 		if err != nil {
-			return mcp.NewToolResultError("missing max_priority_fee_per_gas parameter"), nil
+			return nil, fmt.Errorf("invalid access_list: %v", err)
 		}
-		maxPriorityInt, maxPriorityOK := new(big.Int).SetString(maxPriorityFeeStr, 10)
-		if !maxPriorityOK || maxPriorityInt.Sign() < 0 {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid max_priority_fee_per_gas: %s", maxPriorityFeeStr)), nil
+		if err := json.Unmarshal(encoded, &p.accessList); err != nil {
+			return nil, fmt.Errorf("invalid access_list: %v", err)
 		}
+	}
+	if p.txType == ethtypes.AccessListTxType && p.accessList == nil {
+		p.accessList = ethtypes.AccessList{}
+	}
+
+	return p, nil
+}
+
+// optionalUint64Param parses an optional decimal string parameter.
+func optionalUint64Param(req mcp.CallToolRequest, name string, allowZero bool) (*uint64, error) {
+	s := req.GetString(name, "")
+	if s == "" {
+		return nil, nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 || !n.IsUint64() || (!allowZero && n.Sign() == 0) {
+		return nil, fmt.Errorf("invalid %s: %s", name, s)
+	}
+	v := n.Uint64()
+	return &v, nil
+}
 
-		result := map[string]any{
-			"chain":                    chainName,
-			"chain_id":                 chainID.String(),
-			"to":                       common.HexToAddress(toStr).Hex(),
-			"value":                    valueStr,
-			"data":                     dataHex,
-			"nonce":                    nonceStr,
-			"gas_limit":                gasLimitStr,
-			"max_fee_per_gas":          maxFeeStr,
-			"max_priority_fee_per_gas": maxPriorityFeeStr,
-			"tx_type":                  2,
+// optionalWeiParam parses an optional decimal wei amount.
+func optionalWeiParam(req mcp.CallToolRequest, name string, allowZero bool) (*big.Int, error) {
+	s := req.GetString(name, "")
+	if s == "" {
+		return nil, nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 || (!allowZero && n.Sign() == 0) {
+		return nil, fmt.Errorf("invalid %s: %s", name, s)
+	}
+	return n, nil
+}
+
+// fillEVMTxParams fetches the missing nonce, gas limit and fees from client
+// and returns the names of the filled fields. sender is only called when the
+// nonce or gas limit is missing.
+func fillEVMTxParams(ctx context.Context, client *evmclient.Client, p *evmTxParams, sender func() (string, error)) ([]string, error) {
+	var filled []string
+
+	if p.txType == ethtypes.DynamicFeeTxType {
+		if p.maxPriorityFee == nil {
+			tip, err := client.SuggestGasTipCap(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get gas tip cap: %v", err)
+			}
+			if p.maxFee != nil && tip.Cmp(p.maxFee) > 0 {
+				tip = new(big.Int).Set(p.maxFee)
+			}
+			p.maxPriorityFee = tip
+			filled = append(filled, "max_priority_fee_per_gas")
 		}
+		if p.maxFee == nil {
+			baseFee, err := client.LatestBaseFee(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get base fee: %v (use tx_type 0 or 1 with gas_price on chains without EIP-1559)", err)
+			}
+			p.maxFee = new(big.Int).Mul(baseFee, big.NewInt(2))
+			p.maxFee.Add(p.maxFee, p.maxPriorityFee)
+			filled = append(filled, "max_fee_per_gas")
+		}
+		if p.maxPriorityFee.Cmp(p.maxFee) > 0 {
+			return nil, fmt.Errorf("max_priority_fee_per_gas %s exceeds max_fee_per_gas %s", p.maxPriorityFee, p.maxFee)
+		}
+	} else if p.gasPrice == nil {
+		price, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get gas price: %v", err)
+		}
+		p.gasPrice = price
+		filled = append(filled, "gas_price")
+	}
 
-		data, err := json.Marshal(result)
+	if p.nonce != nil && p.gasLimit != nil {
+		return filled, nil
+	}
+	addr, err := sender()
+	if err != nil {
+		return nil, fmt.Errorf("sender needed to fill nonce or gas_limit: %v", err)
+	}
+	from := common.HexToAddress(addr)
+
+	if p.nonce == nil {
+		nonce, err := client.PendingNonce(ctx, from)
 		if err != nil {
-			return nil, fmt.Errorf("marshal result: %w", err)
+			return nil, fmt.Errorf("failed to get nonce: %v", err)
 		}
-		return mcp.NewToolResultText(string(data)), nil
+		p.nonce = &nonce
+		filled = append(filled, "nonce")
+	}
+	if p.gasLimit == nil {
+		to := p.to
+		gas, err := client.EstimateGas(ctx, ethereum.CallMsg{
+			From:       from,
+			To:         &to,
+			Value:      p.value,
+			Data:       p.data,
+			AccessList: p.accessList,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("gas estimation failed: %v", err)
+		}
+		p.gasLimit = &gas
+		filled = append(filled, "gas_limit")
+	}
+	return filled, nil
+}
+
+// evmSigningPayload returns the unsigned serialization of tx, which is also
+// the preimage of its signing hash. Typed transactions are the type byte
+// followed by the RLP of their unsigned fields, the layout the recipes
+// decoder reads. Legacy transactions are the EIP-155 list with the chain ID
+// and two zero placeholders.
+func evmSigningPayload(tx *ethtypes.Transaction, chainID *big.Int) ([]byte, error) {
+	var fields any
+	switch tx.Type() {
+	case ethtypes.LegacyTxType:
+		return rlp.EncodeToBytes([]any{
+			tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data(),
+			chainID, uint(0), uint(0),
+		})
+	case ethtypes.AccessListTxType:
+		fields = reth.AccessListTxWithoutSignature{
+			ChainID: chainID, Nonce: tx.Nonce(), GasPrice: tx.GasPrice(), Gas: tx.Gas(),
+			To: tx.To(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList(),
+		}
+	case ethtypes.DynamicFeeTxType:
+		fields = reth.DynamicFeeTxWithoutSignature{
+			ChainID: chainID, Nonce: tx.Nonce(), GasTipCap: tx.GasTipCap(), GasFeeCap: tx.GasFeeCap(), Gas: tx.Gas(),
+			To: tx.To(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList(),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+	enc, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, err
 	}
+	return append([]byte{tx.Type()}, enc...), nil
 }
@@ -0,0 +1,333 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mark3labs/mcp-go/mcp"
+	reth "github.com/vultisig/recipes/chain/evm/ethereum"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// mockEVMRPC is a JSON-RPC server answering each method with a canned
+// result, or with the return value of a func(json.RawMessage) any called
+// with the request params. Unknown methods return an RPC error. Calls records
+// the params of each request by method.
+type mockEVMRPC struct {
+	mu      sync.Mutex
+	results map[string]any
+	calls   map[string][]json.RawMessage
+}
+
+// mockEVMPool starts a mockEVMRPC and returns a pool serving it as Ethereum.
+func mockEVMPool(t *testing.T, results map[string]any) (*evmclient.Pool, *mockEVMRPC) {
+	t.Helper()
+	return mockEVMChainPool(t, "Ethereum", results)
+}
+
+// mockEVMChainPool is mockEVMPool for chainName, answering eth_chainId with
+// the chain's ID.
+func mockEVMChainPool(t *testing.T, chainName string, results map[string]any) (*evmclient.Pool, *mockEVMRPC) {
+	t.Helper()
+	m := &mockEVMRPC{results: results, calls: make(map[string][]json.RawMessage)}
+	if _, ok := results["eth_chainId"]; !ok {
+		chainID, _ := evmclient.ChainIDByName(chainName)
+		results["eth_chainId"] = hexutil.EncodeBig(chainID)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m.mu.Lock()
+		m.calls[req.Method] = append(m.calls[req.Method], req.Params)
+		result, ok := m.results[req.Method]
+		m.mu.Unlock()
+		if fn, isFunc := result.(func(json.RawMessage) any); isFunc {
+			result = fn(req.Params)
+		}
+
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if rpcErr, isErr := result.(mockRPCError); isErr {
+			resp["error"] = rpcErr
+		} else if ok {
+			resp["result"] = result
+		} else {
+			resp["error"] = map[string]any{"code": -32601, "message": "method not found: " + req.Method}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	pool := evmclient.NewPool(map[string]string{chainName: srv.URL})
+	t.Cleanup(pool.Close)
+	return pool, m
+}
+
+// mockRPCError is a result that mockEVMPool answers with as a JSON-RPC
+// error, e.g. a revert carrying its data.
+type mockRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+func (m *mockEVMRPC) callCount(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls[method])
+}
+
+// mockHeader returns an eth_getBlockByNumber result. An empty baseFee gives a
+// pre-London header.
+func mockHeader(baseFee string) map[string]any {
+	h := map[string]any{
+		"parentHash":       ethcommon.Hash{}.Hex(),
+		"sha3Uncles":       ethtypes.EmptyUncleHash.Hex(),
+		"miner":            ethcommon.Address{}.Hex(),
+		"stateRoot":        ethcommon.Hash{}.Hex(),
+		"transactionsRoot": ethtypes.EmptyTxsHash.Hex(),
+		"receiptsRoot":     ethtypes.EmptyReceiptsHash.Hex(),
+		"logsBloom":        "0x" + strings.Repeat("00", 256),
+		"difficulty":       "0x0",
+		"number":           "0x100",
+		"gasLimit":         "0x1c9c380",
+		"gasUsed":          "0x0",
+		"timestamp":        "0x0",
+		"extraData":        "0x",
+	}
+	if baseFee != "" {
+		h["baseFeePerGas"] = baseFee
+	}
+	return h
+}
+
+func TestBuildEVMTx_SigningHash(t *testing.T) {
+	data := "0xb460af9400000000000000000000000000000000000000000000000000000000001e849d000000000000000000000000e721dd7a654d7e95518014526f6897def6a44933000000000000000000000000e721dd7a654d7e95518014526f6897def6a44933"
+	calldata, _ := hexToBytes(data)
+	to := ethcommon.HexToAddress(sparkVault)
+	storageKey := ethcommon.HexToHash("0x01")
+	accessList := ethtypes.AccessList{{Address: to, StorageKeys: []ethcommon.Hash{storageKey}}}
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		tx       ethtypes.TxData
+		encoding string
+	}{
+		{
+			name: "eip1559",
+			args: map[string]any{"max_fee_per_gas": "133342876", "max_priority_fee_per_gas": "15750"},
+			tx: &ethtypes.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 13, GasTipCap: big.NewInt(15750), GasFeeCap: big.NewInt(133342876),
+				Gas: 104414, To: &to, Value: big.NewInt(0), Data: calldata},
+			encoding: "eip1559_rlp",
+		},
+		{
+			name: "eip2930",
+			args: map[string]any{"tx_type": float64(1), "gas_price": "133342876", "access_list": []any{
+				map[string]any{"address": sparkVault, "storageKeys": []any{storageKey.Hex()}},
+			}},
+			tx: &ethtypes.AccessListTx{ChainID: big.NewInt(1), Nonce: 13, GasPrice: big.NewInt(133342876),
+				Gas: 104414, To: &to, Value: big.NewInt(0), Data: calldata, AccessList: accessList},
+			encoding: "eip2930_rlp",
+		},
+		{
+			name:     "legacy",
+			args:     map[string]any{"tx_type": float64(0), "gas_price": "133342876"},
+			tx:       &ethtypes.LegacyTx{Nonce: 13, GasPrice: big.NewInt(133342876), Gas: 104414, To: &to, Value: big.NewInt(0), Data: calldata},
+			encoding: "legacy_rlp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"to": sparkVault, "value": "0", "data": data, "nonce": "13", "gas_limit": "104414", "chain_id": "1"}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			res, err := handleBuildEVMTx(vault.NewStore(), nil)(context.Background(), callToolReq("build_evm_tx", args))
+			if err != nil {
+				t.Fatalf("handler error: %v", err)
+			}
+			var result struct {
+				ChainID       string `json:"chain_id"`
+				TxType        int    `json:"tx_type"`
+				TxEncoding    string `json:"tx_encoding"`
+				UnsignedTxHex string `json:"unsigned_tx_hex"`
+				SigningHash   string `json:"signing_hash"`
+			}
+			if err := json.Unmarshal([]byte(resultText(t, res)), &result); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			want := ethtypes.NewTx(tt.tx)
+			if result.TxType != int(want.Type()) || result.TxEncoding != tt.encoding || result.ChainID != "1" {
+				t.Errorf("tx_type/encoding/chain_id = %d/%s/%s", result.TxType, result.TxEncoding, result.ChainID)
+			}
+			wantHash := ethtypes.LatestSignerForChainID(big.NewInt(1)).Hash(want)
+			if result.SigningHash != wantHash.Hex() {
+				t.Errorf("signing_hash = %s, want %s", result.SigningHash, wantHash.Hex())
+			}
+			unsigned, err := hex.DecodeString(strings.TrimPrefix(result.UnsignedTxHex, "0x"))
+			if err != nil {
+				t.Fatalf("decode unsigned_tx_hex: %v", err)
+			}
+			if crypto.Keccak256Hash(unsigned) != wantHash {
+				t.Error("keccak256(unsigned_tx_hex) does not match signing_hash")
+			}
+			if want.Type() == ethtypes.LegacyTxType {
+				return
+			}
+			decoded, err := reth.DecodeUnsignedPayload(unsigned)
+			if err != nil {
+				t.Fatalf("DecodeUnsignedPayload: %v", err)
+			}
+			if got := ethtypes.NewTx(decoded); got.Nonce() != 13 || got.Gas() != 104414 || *got.To() != to || len(got.AccessList()) != len(want.AccessList()) {
+				t.Errorf("decoded tx = nonce %d gas %d to %s", got.Nonce(), got.Gas(), got.To())
+			}
+		})
+	}
+}
+
+func TestBuildEVMTx_AutoFill(t *testing.T) {
+	pool, rpc := mockEVMPool(t, map[string]any{
+		"eth_getTransactionCount":  "0x2a",
+		"eth_maxPriorityFeePerGas": "0x3b9aca00",
+		"eth_getBlockByNumber":     mockHeader("0x2540be400"),
+		"eth_estimateGas":          "0xb411",
+		"eth_getBalance":           "0xde0b6b3a7640000",
+	})
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+
+	res, err := handleBuildEVMTx(store, pool)(context.Background(), callToolReq("build_evm_tx", map[string]any{
+		"to":    usdt,
+		"value": "0",
+		"data":  "0x095ea7b3000000000000000000000000e2e7a17dff93280dec073c995595155283e3c3720000000000000000000000000000000000000000000000000000000000000000",
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	result := decodeResult(t, res)
+
+	if result["nonce"] != "42" || result["gas_limit"] != "46097" {
+		t.Errorf("nonce/gas_limit = %v/%v, want 42/46097", result["nonce"], result["gas_limit"])
+	}
+	// 2 * 10 gwei base fee + 1 gwei tip.
+	if result["max_fee_per_gas"] != "21000000000" || result["max_priority_fee_per_gas"] != "1000000000" {
+		t.Errorf("fees = %v/%v", result["max_fee_per_gas"], result["max_priority_fee_per_gas"])
+	}
+	// 46,097 gas at 21 gwei.
+	if result["max_network_fee"] != "0.000968037 ETH" {
+		t.Errorf("max_network_fee = %v", result["max_network_fee"])
+	}
+	filled, _ := json.Marshal(result["auto_filled"])
+	if string(filled) != `["max_priority_fee_per_gas","max_fee_per_gas","nonce","gas_limit"]` {
+		t.Errorf("auto_filled = %s", filled)
+	}
+	if calls := rpc.calls["eth_estimateGas"]; len(calls) != 1 || !strings.Contains(strings.ToLower(string(calls[0])), strings.ToLower(testAddress)) {
+		t.Errorf("eth_estimateGas calls = %s, want sender %s", calls, testAddress)
+	}
+}
+
+func TestBuildEVMTx_AutoFillLegacy(t *testing.T) {
+	pool, rpc := mockEVMPool(t, map[string]any{
+		"eth_gasPrice":         "0x77359400",
+		"eth_getBlockByNumber": mockHeader(""),
+	})
+
+	res, err := handleBuildEVMTx(vault.NewStore(), pool)(context.Background(), callToolReq("build_evm_tx", map[string]any{
+		"to":        usdt,
+		"value":     "1000",
+		"nonce":     "0",
+		"gas_limit": "21000",
+		"tx_type":   float64(0),
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	result := decodeResult(t, res)
+	if result["gas_price"] != "2000000000" || result["tx_type"] != float64(0) {
+		t.Errorf("gas_price/tx_type = %v/%v", result["gas_price"], result["tx_type"])
+	}
+	if _, ok := result["max_fee_per_gas"]; ok {
+		t.Error("legacy result has max_fee_per_gas")
+	}
+	if rpc.callCount("eth_getTransactionCount") != 0 || rpc.callCount("eth_estimateGas") != 0 {
+		t.Error("explicit nonce and gas_limit were fetched")
+	}
+
+	// The same chain without a base fee cannot fill EIP-1559 fees.
+	res, err = handleBuildEVMTx(vault.NewStore(), pool)(context.Background(), callToolReq("build_evm_tx", map[string]any{
+		"to":                       usdt,
+		"value":                    "1000",
+		"nonce":                    "0",
+		"gas_limit":                "21000",
+		"max_priority_fee_per_gas": "1",
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(mcp.TextContent).Text, "tx_type 0 or 1") {
+		t.Errorf("expected EIP-1559 fallback hint, got %+v", res.Content)
+	}
+}
+
+func TestBuildEVMTx_InvalidParams(t *testing.T) {
+	base := map[string]any{"to": usdt, "value": "0", "nonce": "0", "gas_limit": "21000"}
+	for name, extra := range map[string]map[string]any{
+		"gas_price_on_type2": {"gas_price": "1", "max_fee_per_gas": "2", "max_priority_fee_per_gas": "1"},
+		"max_fee_on_legacy":  {"tx_type": float64(0), "gas_price": "1", "max_fee_per_gas": "2"},
+		"access_list_legacy": {"tx_type": float64(0), "gas_price": "1", "access_list": []any{}},
+		"bad_tx_type":        {"tx_type": float64(3), "gas_price": "1"},
+		"keysign_legacy":     {"tx_type": float64(0), "gas_price": "1", "output_format": "keysign"},
+		"fees_without_rpc":   {},
+	} {
+		args := map[string]any{}
+		for k, v := range base {
+			args[k] = v
+		}
+		for k, v := range extra {
+			args[k] = v
+		}
+		res, err := handleBuildEVMTx(vault.NewStore(), nil)(context.Background(), callToolReq("build_evm_tx", args))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+}
+
+func TestBuildEVMTx_KeysignChainIDMismatch(t *testing.T) {
+	res, err := handleBuildEVMTx(vault.NewStore(), nil)(context.Background(), callToolReq("build_evm_tx", map[string]any{
+		"chain": "Ethereum", "chain_id": "10", "to": usdt, "value": "0", "nonce": "0", "gas_limit": "21000",
+		"max_fee_per_gas": "2", "max_priority_fee_per_gas": "1", "output_format": "keysign",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(mcp.TextContent).Text, "chain_id 10") {
+		t.Errorf("result = %v, want a chain_id mismatch error", res.Content)
+	}
+}
@@ -9,14 +9,19 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	v1 "github.com/vultisig/commondata/go/vultisig/keysign/v1"
 	"github.com/vultisig/vultisig-go/address"
 	"github.com/vultisig/vultisig-go/common"
 
 	"github.com/vultisig/mcp/internal/gaia"
+	"github.com/vultisig/mcp/internal/keysign"
 	"github.com/vultisig/mcp/internal/resolve"
 	"github.com/vultisig/mcp/internal/vault"
 )
 
+// gaiaFeeUatom is the flat MsgSend fee the Vultisig apps pay on Cosmos Hub.
+const gaiaFeeUatom = 7500
+
 func newBuildGaiaSendTool() mcp.Tool {
 	return mcp.NewTool("build_gaia_send",
 		mcp.WithDescription(
@@ -39,6 +44,7 @@ func newBuildGaiaSendTool() mcp.Tool {
 		mcp.WithString("from",
 			mcp.Description("Sender Cosmos address. Falls back to vault-derived address if omitted."),
 		),
+		withOutputFormat(),
 	)
 }
 
@@ -63,6 +69,11 @@ func handleBuildGaiaSend(store *vault.Store, gaiaClient *gaia.Client) server.Too
 		}
 
 		memo := req.GetString("memo", "")
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		explicit := req.GetString("from", "")
 
 		v := resolve.ResolveVault(ctx, req, store)
@@ -96,6 +107,33 @@ func handleBuildGaiaSend(store *vault.Store, gaiaClient *gaia.Client) server.Too
 			action = "swap"
 		}
 
+		if asKeysign {
+			accountNumber, err := strconv.ParseUint(account.AccountNumber, 10, 64)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid account number %q: %v", account.AccountNumber, err)), nil
+			}
+			sequence, err := strconv.ParseUint(account.Sequence, 10, 64)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid sequence %q: %v", account.Sequence, err)), nil
+			}
+			coin, err := keysign.NewCoin(v, common.GaiaChain, "ATOM", 6, "")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			payload := keysign.NewPayload(v, coin, toAddr, amountStr)
+			payload.BlockchainSpecific = &v1.KeysignPayload_CosmosSpecific{
+				CosmosSpecific: &v1.CosmosSpecific{
+					AccountNumber: accountNumber,
+					Sequence:      sequence,
+					Gas:           gaiaFeeUatom,
+				},
+			}
+			if memo != "" {
+				payload.Memo = &memo
+			}
+			return keysignToolResult("Cosmos", action, payload)
+		}
+
 		uatomBig := new(big.Int).SetUint64(amountUatom)
 
 		result := map[string]any{
@@ -5,33 +5,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"strconv"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/vultisig/vultisig-go/address"
 	"github.com/vultisig/vultisig-go/common"
 
-	"github.com/vultisig/mcp/internal/blockchair"
 	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/utxo"
+	"github.com/vultisig/mcp/internal/utxobackend"
 	"github.com/vultisig/mcp/internal/vault"
 )
 
 func newBuildLTCSendTool() mcp.Tool {
 	return mcp.NewTool("build_ltc_send",
 		mcp.WithDescription(
-			"Return Litecoin transaction arguments for a send or swap. "+
-				"Validates addresses and returns parameters for the client to build the PSBT. "+
+			"Build an unsigned Litecoin PSBT for a send or swap. "+
+				"Fetches the sender's UTXOs, selects inputs, adds change and any OP_RETURN memo, "+
+				"and sets the fee from the exact signed size. "+
 				"For THORChain swaps, provide the memo parameter. "+
 				"Accepts inline vault keys (ecdsa_public_key, eddsa_public_key, chain_code) or falls back to set_vault_info session state.",
 		),
 		mcp.WithString("to_address",
-			mcp.Description("Recipient Litecoin address (or THORChain vault address for swaps)"),
-			mcp.Required(),
+			mcp.Description("Recipient Litecoin address (or THORChain vault address for swaps). Omit when using recipients."),
 		),
 		mcp.WithString("amount",
-			mcp.Description("Amount to send in litoshis (decimal string)"),
-			mcp.Required(),
+			mcp.Description("Amount to send in litoshis (decimal string). Omit when using recipients."),
 		),
 		mcp.WithNumber("fee_rate",
 			mcp.Description("Fee rate in litoshi/vB (use ltc_fee_rate tool to get recommended rate)"),
@@ -43,23 +42,17 @@ func newBuildLTCSendTool() mcp.Tool {
 		mcp.WithString("address",
 			mcp.Description("Sender Litecoin address. Falls back to vault-derived address if omitted."),
 		),
+		withRecipients(),
+		withCoinSelection(),
+		withOutputFormat(),
 	)
 }
 
-func handleBuildLTCSend(store *vault.Store, _ *blockchair.Client) server.ToolHandlerFunc {
+func handleBuildLTCSend(store *vault.Store, utxoBackend utxobackend.Backend) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		toAddress, err := req.RequireString("to_address")
+		recipients, err := parseUTXORecipients(req)
 		if err != nil {
-			return mcp.NewToolResultError("missing to_address"), nil
-		}
-
-		amountStr, err := req.RequireString("amount")
-		if err != nil {
-			return mcp.NewToolResultError("missing amount"), nil
-		}
-		amount, parseErr := strconv.ParseInt(amountStr, 10, 64)
-		if parseErr != nil || amount <= 0 {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid amount: %q", amountStr)), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		feeRateFloat := req.GetFloat("fee_rate", 0)
@@ -73,6 +66,16 @@ func handleBuildLTCSend(store *vault.Store, _ *blockchair.Client) server.ToolHan
 			return mcp.NewToolResultError(fmt.Sprintf("memo too long: %d bytes (max 80)", len(memo))), nil
 		}
 
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		strategy, err := utxo.ParseStrategy(req.GetString("coin_selection", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		explicitAddr := req.GetString("address", "")
 
 		v := resolve.ResolveVault(ctx, req, store)
@@ -80,7 +83,7 @@ func handleBuildLTCSend(store *vault.Store, _ *blockchair.Client) server.ToolHan
 			return mcp.NewToolResultError("no vault info available — pass vault keys inline or call set_vault_info"), nil
 		}
 
-		senderAddr, _, _, err := address.GetAddress(v.ECDSAPublicKey, v.ChainCode, common.Litecoin)
+		senderAddr, senderPubKey, _, err := address.GetAddress(v.ECDSAPublicKey, v.ChainCode, common.Litecoin)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("derive Litecoin address: %v", err)), nil
 		}
@@ -90,9 +93,9 @@ func handleBuildLTCSend(store *vault.Store, _ *blockchair.Client) server.ToolHan
 		}
 
 		ltcChain := utxoChains["Litecoin"]
-		_, err = ltcChain.addressToPkScript(toAddress)
+		payments, err := recipients.outputs(ltcChain)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid to_address: %v", err)), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		action := "transfer"
@@ -100,16 +103,39 @@ func handleBuildLTCSend(store *vault.Store, _ *blockchair.Client) server.ToolHan
 			action = "swap"
 		}
 
+		btx, err := buildUTXOTx(ctx, utxoBackend, utxoSendParams{
+			chain:     "Litecoin",
+			sender:    senderAddr,
+			pubKeyHex: senderPubKey,
+			payments:  payments,
+			memo:      memo,
+			fee:       utxo.RateFee(feeRate),
+			strategy:  strategy,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("build transaction: %v", err)), nil
+		}
+
+		if asKeysign {
+			if len(recipients) > 1 {
+				return mcp.NewToolResultError("keysign output supports a single recipient; omit output_format for batched sends"), nil
+			}
+			payload, err := utxoKeysignPayload(v, "Litecoin", recipients[0].Address, recipients[0].Amount, feeRate, memo, btx.selection.Inputs)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			return keysignToolResult("Litecoin", action, payload)
+		}
+
 		result := map[string]any{
-			"chain":       "Litecoin",
-			"action":      action,
-			"from":        senderAddr,
-			"to":          toAddress,
-			"amount":      amount,
-			"fee_rate":    feeRate,
-			"memo":        memo,
-			"tx_encoding": "psbt",
+			"chain":    "Litecoin",
+			"action":   action,
+			"from":     senderAddr,
+			"fee_rate": feeRate,
+			"memo":     memo,
 		}
+		recipients.addTo(result)
+		btx.addTo(result)
 
 		data, err := json.Marshal(result)
 		if err != nil {
@@ -0,0 +1,270 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+func newBuildNFTTransferTool() mcp.Tool {
+	return mcp.NewTool("build_nft_transfer",
+		mcp.WithDescription(
+			"Build an unsigned NFT transfer on any EVM chain using safeTransferFrom. "+
+				"Detects ERC-721 or ERC-1155 via supportsInterface, checks the sender owns the token "+
+				"(ownerOf or balanceOf), fetches nonce and fees, estimates gas, checks the native balance covers the max network fee "+
+				"(including any rollup L1 fee) and returns the unsigned transaction "+
+				"with its signing hash and a summary. Sender falls back to the vault-derived address if not provided. "+
+				"Set output_format to \"keysign\" to receive a Vultisig keysign payload for the vault-derived sender (tx_type 2 only).",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("contract",
+			mcp.Description("NFT collection contract address (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("token_id",
+			mcp.Description("Token ID as a decimal string."),
+			mcp.Required(),
+		),
+		mcp.WithString("to",
+			mcp.Description("Recipient address (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("amount",
+			mcp.Description("Number of tokens to send for ERC-1155 (default \"1\"). Must be 1 for ERC-721."),
+		),
+		mcp.WithString("standard",
+			mcp.Description("Token standard, detected on chain if omitted."),
+			mcp.Enum(evmclient.StandardERC721, evmclient.StandardERC1155),
+		),
+		mcp.WithString("from",
+			mcp.Description("Sender address (0x-prefixed). Optional if vault info is set."),
+		),
+		mcp.WithNumber("tx_type",
+			mcp.Description("Transaction type: 2 (EIP-1559, default), 1 (EIP-2930) or 0 (legacy) for chains without EIP-1559 fees."),
+		),
+		withOutputFormat(),
+	)
+}
+
+func handleBuildNFTTransfer(store *vault.Store, pool *evmclient.Pool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		chainID, err := evmChainID(req, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		txType, err := parseEVMTxType(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if asKeysign && txType != ethtypes.DynamicFeeTxType {
+			return mcp.NewToolResultError("keysign output supports only tx_type 2"), nil
+		}
+
+		contractStr, err := req.RequireString("contract")
+		if err != nil {
+			return mcp.NewToolResultError("missing contract parameter"), nil
+		}
+		if !common.IsHexAddress(contractStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid contract address: %s", contractStr)), nil
+		}
+		contract := common.HexToAddress(contractStr)
+
+		idStr, err := req.RequireString("token_id")
+		if err != nil {
+			return mcp.NewToolResultError("missing token_id parameter"), nil
+		}
+		tokenID, ok := new(big.Int).SetString(idStr, 10)
+		if !ok || tokenID.Sign() < 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid token_id: %s", idStr)), nil
+		}
+
+		toStr, err := req.RequireString("to")
+		if err != nil {
+			return mcp.NewToolResultError("missing to parameter"), nil
+		}
+		if !common.IsHexAddress(toStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid to address: %s", toStr)), nil
+		}
+		to := common.HexToAddress(toStr)
+		if to == (common.Address{}) {
+			return mcp.NewToolResultError("to is the zero address; safeTransferFrom would revert"), nil
+		}
+		if to == contract {
+			return mcp.NewToolResultError("to is the NFT contract; pass the recipient's address"), nil
+		}
+
+		amountStr := req.GetString("amount", "1")
+		amount, ok := new(big.Int).SetString(amountStr, 10)
+		if !ok || amount.Sign() <= 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid amount: %s", amountStr)), nil
+		}
+
+		standard := req.GetString("standard", "")
+		switch standard {
+		case "", evmclient.StandardERC721, evmclient.StandardERC1155:
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("invalid standard %q (expected %q or %q)",
+				standard, evmclient.StandardERC721, evmclient.StandardERC1155)), nil
+		}
+
+		explicit := req.GetString("from", "")
+		if explicit != "" && !common.IsHexAddress(explicit) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid from address: %s", explicit)), nil
+		}
+		v := resolve.ResolveVault(ctx, req, store)
+		fromStr, err := resolve.EVMAddress(explicit, v)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		from := common.HexToAddress(fromStr)
+		if to == from {
+			return mcp.NewToolResultError("to is the sender's own address"), nil
+		}
+
+		client, _, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+
+		if standard == "" {
+			standard, err = client.DetectNFTStandard(ctx, contract)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("detect NFT standard: %v; pass standard explicitly", err)), nil
+			}
+		}
+
+		var data []byte
+		if standard == evmclient.StandardERC1155 {
+			balance, err := client.ERC1155BalanceOf(ctx, contract, from, tokenID)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("check balance: %v", err)), nil
+			}
+			if balance.Cmp(amount) < 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("insufficient balance: %s holds %s of token %s, transfer needs %s",
+					from.Hex(), balance, tokenID, amount)), nil
+			}
+			data, err = evmclient.PackERC1155SafeTransfer(from, to, tokenID, amount)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("encode transfer: %v", err)), nil
+			}
+		} else {
+			if amount.Cmp(big.NewInt(1)) != 0 {
+				return mcp.NewToolResultError("amount must be 1 for ERC-721"), nil
+			}
+			owner, err := client.OwnerOf(ctx, contract, tokenID)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("check owner: %v", err)), nil
+			}
+			if owner != from {
+				return mcp.NewToolResultError(fmt.Sprintf("token %s is owned by %s, not %s", tokenID, owner.Hex(), from.Hex())), nil
+			}
+			data, err = evmclient.PackERC721SafeTransfer(from, to, tokenID)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("encode transfer: %v", err)), nil
+			}
+		}
+
+		p := &evmTxParams{
+			txType:  txType,
+			chainID: chainID,
+			to:      contract,
+			value:   new(big.Int),
+			data:    data,
+		}
+		if txType == ethtypes.AccessListTxType {
+			p.accessList = ethtypes.AccessList{}
+		}
+		autoFilled, err := fillEVMTxParams(ctx, client, p, func() (string, error) { return from.Hex(), nil })
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		networkFee, rollup, err := evmNetworkFee(ctx, client, chainName, from, p)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if asKeysign {
+			if v == nil {
+				return mcp.NewToolResultError("build keysign payload: vault info required"), nil
+			}
+			vaultAddr, err := resolve.EVMAddress("", v)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			if !strings.EqualFold(vaultAddr, from.Hex()) {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"from address %q does not match vault-derived address %q", from.Hex(), vaultAddr)), nil
+			}
+			if *p.nonce > math.MaxInt64 {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid nonce: %d", *p.nonce)), nil
+			}
+			payload, err := evmKeysignPayload(v, chainName, contract.Hex(), "0", "0x"+hex.EncodeToString(p.data),
+				int64(*p.nonce), fmt.Sprint(*p.gasLimit), p.maxFee.String(), p.maxPriorityFee.String())
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			return keysignToolResult(chainName, "nft_transfer", payload)
+		}
+
+		result, err := evmTxResult(chainName, p, autoFilled)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		collection, _ := client.NFTCollectionName(ctx, contract)
+		label := collection
+		if label == "" {
+			label = contract.Hex()
+		}
+		ticker := evmclient.NativeTicker(chainName)
+		maxFee := evmclient.FormatUnits(networkFee, 18)
+
+		result["from"] = from.Hex()
+		result["contract"] = contract.Hex()
+		if collection != "" {
+			result["collection"] = collection
+		}
+		result["standard"] = standard
+		result["token_id"] = tokenID.String()
+		result["amount"] = amount.String()
+		result["recipient"] = to.Hex()
+		result["max_network_fee"] = maxFee + " " + ticker
+		if rollup != nil {
+			result["rollup_fee"] = newRollupFeeJSON(rollup, ticker)
+		}
+		what := fmt.Sprintf("%s #%s", label, tokenID)
+		if standard == evmclient.StandardERC1155 {
+			what = fmt.Sprintf("%s x %s", amount, what)
+		}
+		result["summary"] = fmt.Sprintf("Send %s from %s to %s on %s. Max network fee: %s %s.",
+			what, from.Hex(), to.Hex(), chainName, maxFee, ticker)
+
+		out, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(out)), nil
+	}
+}
@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	aavev3sdk "github.com/vultisig/recipes/sdk/evm/aavev3"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+const (
+	defaultPermitDeadline   = 30 * time.Minute
+	defaultPermit2Expiry    = 30 * 24 * time.Hour
+	maxPermitDeadlineMinute = 7 * 24 * 60
+)
+
+var (
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	maxUint160 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+)
+
+func newBuildPermitTool() mcp.Tool {
+	return mcp.NewTool("build_permit",
+		mcp.WithDescription(
+			"Build an off-chain token approval (EIP-2612 permit or Uniswap Permit2 PermitSingle) as EIP-712 typed data to sign. "+
+				"Reads the token's permit domain and the owner's nonce, and returns the permit, the typed data and its digest. "+
+				"After signing, pass permit and permit_signature to aave_v3_deposit, aave_v3_repay or build_swap_tx "+
+				"to get a single transaction instead of approve + action. "+
+				"kind is detected when omitted: EIP-2612 if the token supports it, otherwise Permit2 if the owner has approved Permit2.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("token",
+			mcp.Description("ERC-20 token contract address (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("spender",
+			mcp.Description("Contract allowed to spend the tokens (0x-prefixed), or \"aave_v3\" for the chain's Aave V3 Pool. "+
+				"For swaps use the router returned by build_swap_tx (swap_tx.to)."),
+			mcp.Required(),
+		),
+		mcp.WithString("amount",
+			mcp.Description("Amount in human-readable token units (e.g. \"100.5\"), or \"max\" for an unlimited permit. "+
+				"Aave requires the exact deposit or repay amount."),
+			mcp.Required(),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Permit kind. Detected from the token if omitted."),
+			mcp.Enum(evmclient.PermitKindEIP2612, evmclient.PermitKindPermit2),
+		),
+		mcp.WithString("owner",
+			mcp.Description("Token holder address (0x-prefixed). Optional if vault info is set."),
+		),
+		mcp.WithNumber("deadline_minutes",
+			mcp.Description("Minutes until the signature expires. Default 30, max one week."),
+		),
+	)
+}
+
+func handleBuildPermit(store *vault.Store, pool *evmclient.Pool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		tokenStr, err := req.RequireString("token")
+		if err != nil {
+			return mcp.NewToolResultError("missing token parameter"), nil
+		}
+		if !common.IsHexAddress(tokenStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid token address: %s", tokenStr)), nil
+		}
+		token := common.HexToAddress(tokenStr)
+
+		spenderStr, err := req.RequireString("spender")
+		if err != nil {
+			return mcp.NewToolResultError("missing spender parameter"), nil
+		}
+		amountStr, err := req.RequireString("amount")
+		if err != nil {
+			return mcp.NewToolResultError("missing amount parameter"), nil
+		}
+		kind := req.GetString("kind", "")
+		if kind != "" && kind != evmclient.PermitKindEIP2612 && kind != evmclient.PermitKindPermit2 {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid kind %q (expected %q or %q)", kind, evmclient.PermitKindEIP2612, evmclient.PermitKindPermit2)), nil
+		}
+		deadlineMinutes := req.GetFloat("deadline_minutes", defaultPermitDeadline.Minutes())
+		if deadlineMinutes <= 0 || deadlineMinutes > maxPermitDeadlineMinute {
+			return mcp.NewToolResultError(fmt.Sprintf("deadline_minutes must be between 1 and %d", maxPermitDeadlineMinute)), nil
+		}
+
+		explicit := req.GetString("owner", "")
+		if explicit != "" && !common.IsHexAddress(explicit) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid owner: %s", explicit)), nil
+		}
+		ownerStr, err := resolve.EVMAddress(explicit, resolve.ResolveVault(ctx, req, store))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		owner := common.HexToAddress(ownerStr)
+
+		client, chainID, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+
+		var spender common.Address
+		switch {
+		case strings.EqualFold(spenderStr, "aave_v3"):
+			deploy, ok := aavev3sdk.GetDeployment(chainID)
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("Aave V3 is not deployed on %s", chainName)), nil
+			}
+			spender = deploy.Pool
+		case common.IsHexAddress(spenderStr):
+			spender = common.HexToAddress(spenderStr)
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("invalid spender: %s", spenderStr)), nil
+		}
+
+		symbol, decimals, err := client.TokenMetadata(ctx, token.Hex())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to read token %s: %v", token.Hex(), err)), nil
+		}
+		unlimited := strings.EqualFold(strings.TrimSpace(amountStr), "max")
+		var amount *big.Int
+		if !unlimited {
+			amount, err = evmclient.ParseUnits(amountStr, int(decimals))
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if amount.Sign() <= 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("amount must be positive: %s", amountStr)), nil
+			}
+		}
+
+		var support *evmclient.PermitSupport
+		if kind != evmclient.PermitKindPermit2 {
+			support, err = client.EIP2612Support(ctx, token, chainID)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to check permit support: %v", err)), nil
+			}
+			if kind == evmclient.PermitKindEIP2612 && !support.Supported {
+				return mcp.NewToolResultError(fmt.Sprintf("%s does not support EIP-2612 permit: %s", symbol, support.Reason)), nil
+			}
+		}
+
+		var warnings []string
+		if kind == "" && support.Supported {
+			kind = evmclient.PermitKindEIP2612
+		}
+		if kind != evmclient.PermitKindEIP2612 {
+			permit2Allowance, err := client.Allowance(ctx, token.Hex(), owner.Hex(), evmclient.Permit2Address.Hex())
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get allowance: %v", err)), nil
+			}
+			covered := permit2Allowance.Sign() > 0 && (unlimited && permit2Allowance.Cmp(evmclient.UnlimitedAllowance) >= 0 ||
+				!unlimited && permit2Allowance.Cmp(amount) >= 0)
+			switch {
+			case kind == "" && !covered:
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"%s does not support EIP-2612 permit (%s) and %s has not approved Permit2 (%s) for this amount: "+
+						"approve the spender on-chain instead, or approve Permit2 once to use permit2",
+					symbol, support.Reason, owner.Hex(), evmclient.Permit2Address.Hex())), nil
+			case !covered:
+				warnings = append(warnings, fmt.Sprintf(
+					"%s's ERC-20 allowance to Permit2 does not cover this amount: the permit is usable only after approving Permit2",
+					owner.Hex()))
+			}
+			kind = evmclient.PermitKindPermit2
+		}
+
+		now := time.Now()
+		deadline := now.Add(time.Duration(deadlineMinutes * float64(time.Minute))).Unix()
+		permit := &evmclient.Permit{
+			Kind:     kind,
+			ChainID:  chainID,
+			Token:    token,
+			Owner:    owner,
+			Spender:  spender,
+			Deadline: big.NewInt(deadline),
+		}
+		if kind == evmclient.PermitKindEIP2612 {
+			nonce, err := client.PermitNonce(ctx, token, owner)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to read permit nonce: %v", err)), nil
+			}
+			permit.Nonce = nonce
+			permit.DomainName = support.Name
+			permit.DomainVersion = support.Version
+			permit.Amount = maxUint256
+		} else {
+			allowance, err := client.Permit2Allowance(ctx, owner, token, spender)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Permit2 is not available on %s: %v", chainName, err)), nil
+			}
+			permit.Nonce = new(big.Int).SetUint64(allowance.Nonce)
+			permit.Expiration = big.NewInt(now.Add(defaultPermit2Expiry).Unix())
+			permit.Amount = maxUint160
+		}
+		if !unlimited {
+			permit.Amount = amount
+		}
+
+		td, err := permit.TypedData()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("build typed data: %v", err)), nil
+		}
+		h, err := td.Hash()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("hash typed data: %v", err)), nil
+		}
+
+		amountDisplay := "unlimited"
+		if !unlimited {
+			amountDisplay = evmclient.FormatUnits(amount, int(decimals))
+		}
+		result := map[string]any{
+			"chain":             chainName,
+			"kind":              kind,
+			"token":             token.Hex(),
+			"symbol":            symbol,
+			"decimals":          decimals,
+			"owner":             owner.Hex(),
+			"spender":           spender.Hex(),
+			"amount":            amountDisplay,
+			"amount_base_units": permit.Amount.String(),
+			"deadline":          deadline,
+			"permit":            permit,
+			"typed_data":        td.Normalized(),
+			"digest":            hexutil.Encode(h.Digest),
+			"summary":           permit.Describe(symbol, int(decimals)) + fmt.Sprintf(" on %s, signature valid until %s.", chainName, time.Unix(deadline, 0).UTC().Format(time.RFC3339)),
+		}
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
@@ -0,0 +1,277 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/vultisig/recipes/sdk/swap"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+const (
+	// permitOwnerKey is the example account key from the web3.js docs; the
+	// vault's own key is not available to sign test permits.
+	permitOwnerKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	oneInchRouter  = "0x111111125421cA6dc452d289314280a0f8842A65"
+	universalRtr   = "0x66a9893cC07D91D95644AEDD05D03f95e1dBA8Af"
+)
+
+func selector(sig string) string {
+	return "0x" + hex.EncodeToString(crypto.Keccak256([]byte(sig))[:4])
+}
+
+// mockPermitToken answers eth_call for a 6-decimal "USDC" at usdt that
+// implements EIP-2612 (domain "USD Coin", version "2", nonce 3) when eip2612
+// is set, has granted Permit2 permit2Allowance and has Permit2 nonce 5.
+func mockPermitToken(eip2612 bool, permit2Allowance *big.Int) func(json.RawMessage) any {
+	domain := &evmclient.Permit{
+		Kind: evmclient.PermitKindEIP2612, ChainID: big.NewInt(1), Token: ethcommon.HexToAddress(usdt),
+		Amount: new(big.Int), Nonce: new(big.Int), Deadline: new(big.Int),
+		DomainName: "USD Coin", DomainVersion: "2",
+	}
+	td, _ := domain.TypedData()
+	h, _ := td.Hash()
+	separator := hexutil.Encode(h.DomainSeparator)
+
+	return func(params json.RawMessage) any {
+		var args []struct {
+			To    string `json:"to"`
+			Input string `json:"input"`
+		}
+		_ = json.Unmarshal(params, &args)
+		data := args[0].Input
+		word := func(v *big.Int) string { return fmt.Sprintf("%064x", v) }
+		switch {
+		case strings.HasPrefix(data, selector("decimals()")):
+			return "0x" + word(big.NewInt(6))
+		case strings.HasPrefix(data, selector("symbol()")):
+			return abiString("USDC")
+		case strings.HasPrefix(data, selector("name()")):
+			return abiString("USD Coin")
+		case strings.HasPrefix(data, selector("version()")):
+			return abiString("2")
+		case eip2612 && strings.HasPrefix(data, selector("DOMAIN_SEPARATOR()")):
+			return separator
+		case eip2612 && strings.HasPrefix(data, selector("nonces(address)")):
+			return "0x" + word(big.NewInt(3))
+		case strings.HasPrefix(data, selector("allowance(address,address)")):
+			return "0x" + word(permit2Allowance)
+		case strings.HasPrefix(data, selector("allowance(address,address,address)")) && strings.EqualFold(args[0].To, permit2):
+			return "0x" + word(new(big.Int)) + word(new(big.Int)) + word(big.NewInt(5))
+		}
+		return "0x"
+	}
+}
+
+func TestBuildPermit(t *testing.T) {
+	key, _ := crypto.HexToECDSA(permitOwnerKey)
+	owner := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	unlimited := new(big.Int).Lsh(big.NewInt(1), 200)
+
+	tests := []struct {
+		name      string
+		eip2612   bool
+		allowance *big.Int
+		args      map[string]any
+		kind      string
+		spender   string
+		nonce     string
+		warnings  bool
+	}{
+		{"eip2612", true, new(big.Int), map[string]any{"spender": oneInchRouter}, evmclient.PermitKindEIP2612, oneInchRouter, "3", false},
+		{"aave alias", true, new(big.Int), map[string]any{"spender": "aave_v3"}, evmclient.PermitKindEIP2612, "0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2", "3", false},
+		{"permit2 fallback", false, unlimited, map[string]any{"spender": universalRtr}, evmclient.PermitKindPermit2, universalRtr, "5", false},
+		{"permit2 requested", true, new(big.Int), map[string]any{"spender": universalRtr, "kind": "permit2"}, evmclient.PermitKindPermit2, universalRtr, "5", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pool, _ := mockEVMPool(t, map[string]any{"eth_call": mockPermitToken(tc.eip2612, tc.allowance)})
+			args := map[string]any{"token": usdt, "amount": "100", "owner": owner}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			res, err := handleBuildPermit(vault.NewStore(), pool)(context.Background(), callToolReq("build_permit", args))
+			if err != nil {
+				t.Fatalf("handler error: %v", err)
+			}
+			result := decodeResult(t, res)
+			if result["kind"] != tc.kind || result["spender"] != tc.spender || result["amount_base_units"] != "100000000" {
+				t.Errorf("kind/spender/amount = %v/%v/%v", result["kind"], result["spender"], result["amount_base_units"])
+			}
+			if (result["warnings"] != nil) != tc.warnings {
+				t.Errorf("warnings = %v", result["warnings"])
+			}
+
+			permit, err := evmclient.ParsePermit(result["permit"])
+			if err != nil {
+				t.Fatalf("ParsePermit: %v", err)
+			}
+			if permit.Nonce.String() != tc.nonce {
+				t.Errorf("nonce = %s, want %s", permit.Nonce, tc.nonce)
+			}
+			td, _ := permit.TypedData()
+			h, _ := td.Hash()
+			if result["digest"] != hexutil.Encode(h.Digest) {
+				t.Errorf("digest %v does not match the returned permit", result["digest"])
+			}
+		})
+	}
+
+	pool, _ := mockEVMPool(t, map[string]any{"eth_call": mockPermitToken(false, new(big.Int))})
+	res, err := handleBuildPermit(vault.NewStore(), pool)(context.Background(), callToolReq("build_permit", map[string]any{
+		"token": usdt, "amount": "100", "owner": owner, "spender": oneInchRouter,
+	}))
+	if err != nil || !res.IsError {
+		t.Errorf("no permit support: expected tool error, got %v / %v", res, err)
+	}
+}
+
+func TestEVMCheckPermitSupport(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		eip2612     bool
+		allowance   *big.Int
+		recommended string
+	}{
+		{"eip2612", true, new(big.Int), evmclient.PermitKindEIP2612},
+		{"permit2", false, big.NewInt(1), evmclient.PermitKindPermit2},
+		{"neither", false, new(big.Int), ""},
+	} {
+		pool, _ := mockEVMPool(t, map[string]any{"eth_call": mockPermitToken(tc.eip2612, tc.allowance)})
+		res, err := handleEVMCheckPermitSupport(vault.NewStore(), pool)(context.Background(), callToolReq("evm_check_permit_support", map[string]any{
+			"token": usdt, "owner": testAddress, "spender": universalRtr,
+		}))
+		if err != nil {
+			t.Fatalf("%s: handler error: %v", tc.name, err)
+		}
+		result := decodeResult(t, res)
+		if result["recommended_kind"] != tc.recommended {
+			t.Errorf("%s: recommended_kind = %v", tc.name, result["recommended_kind"])
+		}
+		eip2612, _ := result["eip2612"].(map[string]any)
+		if eip2612["supported"] != tc.eip2612 {
+			t.Errorf("%s: eip2612 = %v", tc.name, eip2612)
+		}
+		if tc.eip2612 && (eip2612["domain_version"] != "2" || eip2612["nonce"] != "3") {
+			t.Errorf("%s: eip2612 = %v", tc.name, eip2612)
+		}
+	}
+}
+
+// signedTestPermit returns a permit of kind from the permitOwnerKey account
+// for 100 USDC to spender, and its signature.
+func signedTestPermit(t *testing.T, kind, spender string) (*evmclient.Permit, []byte) {
+	t.Helper()
+	key, _ := crypto.HexToECDSA(permitOwnerKey)
+	p := &evmclient.Permit{
+		Kind: kind, ChainID: big.NewInt(1), Token: ethcommon.HexToAddress(usdt),
+		Owner: crypto.PubkeyToAddress(key.PublicKey), Spender: ethcommon.HexToAddress(spender),
+		Amount: big.NewInt(100_000_000), Nonce: big.NewInt(0), Deadline: big.NewInt(4_000_000_000),
+		DomainName: "USD Coin", DomainVersion: "2",
+	}
+	if kind == evmclient.PermitKindPermit2 {
+		p.Expiration = big.NewInt(4_000_000_000)
+	}
+	td, err := p.TypedData()
+	if err != nil {
+		t.Fatalf("TypedData: %v", err)
+	}
+	h, _ := td.Hash()
+	sig, _ := crypto.Sign(h.Digest, key)
+	return p, sig
+}
+
+func TestApplySwapPermit(t *testing.T) {
+	bundle := func(provider, router string, data []byte) *swap.SwapTxBundle {
+		return &swap.SwapTxBundle{
+			Provider:      provider,
+			NeedsApproval: true,
+			ApprovalTx:    &swap.TxData{To: usdt},
+			SwapTx:        &swap.TxData{To: router, Data: data, GasLimit: 200_000},
+		}
+	}
+	key, _ := crypto.HexToECDSA(permitOwnerKey)
+	params := swap.SwapParams{
+		FromChain: "Ethereum", FromSymbol: "USDC", FromAddress: usdt,
+		Sender: crypto.PubkeyToAddress(key.PublicKey).Hex(), Amount: big.NewInt(100_000_000),
+	}
+
+	t.Run("1inch", func(t *testing.T) {
+		permit, sig := signedTestPermit(t, evmclient.PermitKindEIP2612, oneInchRouter)
+		action := []byte{0x12, 0x34, 0x56, 0x78}
+		b := bundle("1inch", oneInchRouter, action)
+		if err := applySwapPermit(b, params, permit, sig); err != nil {
+			t.Fatalf("applySwapPermit: %v", err)
+		}
+		if b.NeedsApproval || b.ApprovalTx != nil || b.SwapTx.GasLimit != 200_000+swapPermitGasOverhead {
+			t.Errorf("bundle still needs approval or gas unchanged: %+v", b)
+		}
+		method, err := swapPermitABI.MethodById(b.SwapTx.Data[:4])
+		if err != nil || method.Name != "permitAndCall" {
+			t.Fatalf("calldata is not permitAndCall: %x", b.SwapTx.Data[:4])
+		}
+		args, _ := method.Inputs.Unpack(b.SwapTx.Data[4:])
+		packedPermit, gotAction := args[0].([]byte), args[1].([]byte)
+		if !strings.EqualFold(hexutil.Encode(packedPermit[:20]), usdt) || len(packedPermit) != 20+7*32 {
+			t.Errorf("permit bytes: %x", packedPermit)
+		}
+		if hexutil.Encode(gotAction) != hexutil.Encode(action) {
+			t.Errorf("action = %x", gotAction)
+		}
+	})
+
+	t.Run("Uniswap", func(t *testing.T) {
+		permit, sig := signedTestPermit(t, evmclient.PermitKindPermit2, universalRtr)
+		calldata, _ := swapPermitABI.Pack("execute", []byte{0x00, 0x0c}, [][]byte{{0x01}, {0x02}}, big.NewInt(99))
+		b := bundle("Uniswap", universalRtr, calldata)
+		if err := applySwapPermit(b, params, permit, sig); err != nil {
+			t.Fatalf("applySwapPermit: %v", err)
+		}
+		if hexutil.Encode(b.SwapTx.Data[:4]) != hexutil.Encode(calldata[:4]) {
+			t.Fatalf("selector changed: %x", b.SwapTx.Data[:4])
+		}
+		method, _ := swapPermitABI.MethodById(b.SwapTx.Data[:4])
+		args, err := method.Inputs.Unpack(b.SwapTx.Data[4:])
+		if err != nil {
+			t.Fatalf("unpack execute: %v", err)
+		}
+		commands, inputs, deadline := args[0].([]byte), args[1].([][]byte), args[2].(*big.Int)
+		want, _ := permit.PackPermit2Single(sig)
+		if hexutil.Encode(commands) != "0x0a000c" || len(inputs) != 3 || hexutil.Encode(inputs[0]) != hexutil.Encode(want) || deadline.Int64() != 99 {
+			t.Errorf("commands %x, %d inputs, deadline %s", commands, len(inputs), deadline)
+		}
+	})
+
+	eip2612, eip2612Sig := signedTestPermit(t, evmclient.PermitKindEIP2612, oneInchRouter)
+	permit2, permit2Sig := signedTestPermit(t, evmclient.PermitKindPermit2, universalRtr)
+	for name, tc := range map[string]struct {
+		bundle *swap.SwapTxBundle
+		permit *evmclient.Permit
+		sig    []byte
+	}{
+		"wrong spender":  {bundle("1inch", universalRtr, []byte{1, 2, 3, 4}), eip2612, eip2612Sig},
+		"wrong kind":     {bundle("Uniswap", universalRtr, []byte{1, 2, 3, 4}), eip2612, eip2612Sig},
+		"not execute":    {bundle("Uniswap", universalRtr, []byte{1, 2, 3, 4}), permit2, permit2Sig},
+		"other provider": {bundle("LiFi", oneInchRouter, []byte{1, 2, 3, 4}), eip2612, eip2612Sig},
+	} {
+		if err := applySwapPermit(tc.bundle, params, tc.permit, tc.sig); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	small := params
+	small.Amount = big.NewInt(200_000_000)
+	if err := applySwapPermit(bundle("1inch", oneInchRouter, []byte{1, 2, 3, 4}), small, eip2612, eip2612Sig); err == nil {
+		t.Error("permit below swap amount: expected error")
+	}
+}
@@ -47,11 +47,20 @@ func newBuildPumpfunCreateTool() mcp.Tool {
 		mcp.WithString("initial_buy_amount",
 			mcp.Description("Optional initial buy amount in lamports."),
 		),
+		withOutputFormat(),
 	)
 }
 
 func handleBuildPumpfunCreate(store *vault.Store) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if asKeysign {
+			return mcp.NewToolResultError("keysign output is not supported for pump.fun token creation; use the default args output"), nil
+		}
+
 		name, err := req.RequireString("name")
 		if err != nil {
 			return mcp.NewToolResultError("missing name parameter"), nil
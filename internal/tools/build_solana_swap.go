@@ -39,11 +39,20 @@ func newBuildSolanaSwapTool() mcp.Tool {
 		mcp.WithNumber("slippage_bps",
 			mcp.Description("Slippage tolerance in basis points (default: 100 = 1%)."),
 		),
+		withOutputFormat(),
 	)
 }
 
 func handleBuildSolanaSwap(store *vault.Store, jupClient *jupiter.Client) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if asKeysign {
+			return mcp.NewToolResultError("keysign output is not supported for Jupiter swaps; use the default args output"), nil
+		}
+
 		explicit := req.GetString("from", "")
 		fromAddr, err := resolve.ChainAddress(explicit, resolve.ResolveVault(ctx, req, store), "Solana")
 		if err != nil {
@@ -8,7 +8,10 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	v1 "github.com/vultisig/commondata/go/vultisig/keysign/v1"
+	"github.com/vultisig/vultisig-go/common"
 
+	"github.com/vultisig/mcp/internal/keysign"
 	"github.com/vultisig/mcp/internal/resolve"
 	solanaclient "github.com/vultisig/mcp/internal/solana"
 	"github.com/vultisig/mcp/internal/vault"
@@ -31,13 +34,20 @@ func newBuildSolanaTxTool() mcp.Tool {
 			mcp.Description("Amount in lamports (decimal string)."),
 			mcp.Required(),
 		),
+		withOutputFormat(),
 	)
 }
 
 func handleBuildSolanaTx(store *vault.Store, solClient *solanaclient.Client) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		explicit := req.GetString("from", "")
-		fromAddr, err := resolve.ChainAddress(explicit, resolve.ResolveVault(ctx, req, store), "Solana")
+		v := resolve.ResolveVault(ctx, req, store)
+		fromAddr, err := resolve.ChainAddress(explicit, v, "Solana")
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -83,6 +93,24 @@ func handleBuildSolanaTx(store *vault.Store, solClient *solanaclient.Client) ser
 			}
 		}
 
+		if asKeysign {
+			coin, err := keysign.NewCoin(v, common.Solana, "SOL", 9, "")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			if coin.Address != fromAddr {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"from address %q does not match vault-derived address %q", fromAddr, coin.Address)), nil
+			}
+			specific, err := solanaKeysignSpecific(ctx, solClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			payload := keysign.NewPayload(v, coin, toStr, amountStr)
+			payload.BlockchainSpecific = &v1.KeysignPayload_SolanaSpecific{SolanaSpecific: specific}
+			return keysignToolResult("Solana", "transfer", payload)
+		}
+
 		result := map[string]any{
 			"chain":        "Solana",
 			"action":       "transfer",
@@ -9,7 +9,10 @@ import (
 	"github.com/gagliardetto/solana-go"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	v1 "github.com/vultisig/commondata/go/vultisig/keysign/v1"
+	"github.com/vultisig/vultisig-go/common"
 
+	"github.com/vultisig/mcp/internal/keysign"
 	"github.com/vultisig/mcp/internal/resolve"
 	solanaclient "github.com/vultisig/mcp/internal/solana"
 	"github.com/vultisig/mcp/internal/vault"
@@ -38,13 +41,20 @@ func newBuildSPLTransferTxTool() mcp.Tool {
 			mcp.Description("Amount in base units (decimal string)."),
 			mcp.Required(),
 		),
+		withOutputFormat(),
 	)
 }
 
 func handleBuildSPLTransferTx(store *vault.Store, solClient *solanaclient.Client) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		explicit := req.GetString("from", "")
-		fromAddr, err := resolve.ChainAddress(explicit, resolve.ResolveVault(ctx, req, store), "Solana")
+		v := resolve.ResolveVault(ctx, req, store)
+		fromAddr, err := resolve.ChainAddress(explicit, v, "Solana")
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -101,6 +111,31 @@ func handleBuildSPLTransferTx(store *vault.Store, solClient *solanaclient.Client
 			return mcp.NewToolResultError(fmt.Sprintf("derive recipient ATA: %v", err)), nil
 		}
 
+		if asKeysign {
+			// The ticker is left empty; the app resolves SPL tokens by mint.
+			coin, err := keysign.NewCoin(v, common.Solana, "", int32(decimals), mintStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			if coin.Address != fromAddr {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"from address %q does not match vault-derived address %q", fromAddr, coin.Address)), nil
+			}
+			specific, err := solanaKeysignSpecific(ctx, solClient)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			fromATAStr := fromATA.String()
+			toATAStr := toATA.String()
+			isToken2022 := tokenProgram == solana.Token2022ProgramID
+			specific.FromTokenAssociatedAddress = &fromATAStr
+			specific.ToTokenAssociatedAddress = &toATAStr
+			specific.ProgramId = &isToken2022
+			payload := keysign.NewPayload(v, coin, toStr, amountStr)
+			payload.BlockchainSpecific = &v1.KeysignPayload_SolanaSpecific{SolanaSpecific: specific}
+			return keysignToolResult("Solana", "spl_transfer", payload)
+		}
+
 		result := map[string]any{
 			"chain":         "Solana",
 			"action":        "spl_transfer",
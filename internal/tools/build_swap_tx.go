@@ -5,16 +5,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	v1 "github.com/vultisig/commondata/go/vultisig/keysign/v1"
+	"github.com/vultisig/vultisig-go/common"
 
 	"github.com/vultisig/recipes/sdk/swap"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/keysign"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
 )
 
 func newBuildSwapTxTool() mcp.Tool {
 	return mcp.NewTool("build_swap_tx",
-		mcp.WithDescription("Build unsigned transaction(s) for a token swap. Supports THORChain, Mayachain, 1inch, LiFi, Jupiter, and Uniswap providers. Returns the swap transaction and an optional ERC20 approval transaction. Load the 'swap-trading' skill for required pre-checks and confirmation flow."),
+		mcp.WithDescription("Build unsigned transaction(s) for a token swap. Supports THORChain, Mayachain, 1inch, LiFi, Jupiter, and Uniswap providers. Returns the swap transaction and an optional ERC20 approval transaction, or a single transaction when a signed permit is passed. With output_format=keysign, returns a keysign payload for THORChain, Mayachain and EVM aggregator swaps; chain fee fields are filled by the app at signing. Load the 'swap-trading' skill for required pre-checks and confirmation flow."),
 		mcp.WithString("from_chain", mcp.Description("Source chain (e.g. \"Ethereum\", \"Bitcoin\", \"Solana\")"), mcp.Required()),
 		mcp.WithString("from_symbol", mcp.Description("Source token symbol (e.g. \"ETH\", \"USDC\")"), mcp.Required()),
 		mcp.WithString("from_address", mcp.Description("Source token contract address (empty for native coins)")),
@@ -26,6 +34,9 @@ func newBuildSwapTxTool() mcp.Tool {
 		mcp.WithString("amount", mcp.Description("Amount in base units (e.g. \"1000000\" for 1 USDC)"), mcp.Required()),
 		mcp.WithString("sender", mcp.Description("Sender wallet address"), mcp.Required()),
 		mcp.WithString("destination", mcp.Description("Destination wallet address"), mcp.Required()),
+		mcp.WithObject("permit", mcp.Description("Signed-off-chain approval from build_permit for the source token, with spender set to the router (swap_tx.to of a previous call). EIP-2612 for 1inch, Permit2 for Uniswap. With permit_signature, the approval tx is dropped and the swap consumes the permit.")),
+		mcp.WithString("permit_signature", mcp.Description("Owner's 65-byte signature over the permit's typed data (0x-prefixed).")),
+		withOutputFormat(),
 	)
 }
 
@@ -47,7 +58,7 @@ type swapTxJSON struct {
 	GasLimit uint64 `json:"gas_limit,omitempty"`
 }
 
-func handleBuildSwapTx(svc *swap.Service) server.ToolHandlerFunc {
+func handleBuildSwapTx(store *vault.Store, svc *swap.Service) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		fromChain, err := req.RequireString("from_chain")
 		if err != nil {
@@ -89,6 +100,16 @@ func handleBuildSwapTx(svc *swap.Service) server.ToolHandlerFunc {
 			return mcp.NewToolResultError("missing destination"), nil
 		}
 
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		permit, permitSig, err := evmclient.ParseSignedPermit(req.GetArguments()["permit"], req.GetString("permit_signature", ""))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid permit: %v", err)), nil
+		}
+
 		params := swap.SwapParams{
 			FromChain:    fromChain,
 			FromSymbol:   fromSymbol,
@@ -107,6 +128,19 @@ func handleBuildSwapTx(svc *swap.Service) server.ToolHandlerFunc {
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("swap failed: %v", err)), nil
 		}
+		if permit != nil {
+			if err := applySwapPermit(bundle, params, permit, permitSig); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid permit: %v", err)), nil
+			}
+		}
+
+		if asKeysign {
+			payload, err := swapKeysignPayload(resolve.ResolveVault(ctx, req, store), params, bundle)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			return keysignToolResult(fromChain, "swap", payload)
+		}
 
 		result := swapResult{
 			Provider:       bundle.Provider,
@@ -148,3 +182,105 @@ func txDataToJSON(tx *swap.TxData) swapTxJSON {
 	}
 	return result
 }
+
+// swapKeysignPayload maps a swap bundle onto a keysign payload. THORChain and
+// Mayachain swaps use the THORChain swap payload; EVM aggregator swaps use the
+// 1inch-shaped payload the apps share across aggregators. Chain-specific fee
+// fields are left for the app to fill at signing time.
+func swapKeysignPayload(vi *vault.Info, params swap.SwapParams, bundle *swap.SwapTxBundle) (*v1.KeysignPayload, error) {
+	fromChain, err := common.FromString(params.FromChain)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported chain %q: %w", params.FromChain, err)
+	}
+	toChain, err := common.FromString(params.ToChain)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported chain %q: %w", params.ToChain, err)
+	}
+
+	fromCoin, err := keysign.NewCoin(vi, fromChain, params.FromSymbol, int32(params.FromDecimals), params.FromAddress)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(fromCoin.Address, params.Sender) {
+		return nil, fmt.Errorf("sender %q does not match vault-derived address %q", params.Sender, fromCoin.Address)
+	}
+	toCoin, err := keysign.NewCoin(vi, toChain, params.ToSymbol, int32(params.ToDecimals), params.ToAddress)
+	if err != nil {
+		return nil, err
+	}
+	toCoin.Address = params.Destination
+
+	fromAmount := params.Amount.String()
+	toAmountDecimal := evmclient.FormatUnits(bundle.ExpectedOutput, params.ToDecimals)
+
+	p := keysign.NewPayload(vi, fromCoin, bundle.SwapTx.To, fromAmount)
+	if bundle.Memo != "" {
+		p.Memo = &bundle.Memo
+	}
+
+	switch {
+	case bundle.Provider == "THORChain" || bundle.Provider == "Mayachain":
+		tc := &v1.THORChainSwapPayload{
+			FromAddress:     params.Sender,
+			FromCoin:        fromCoin,
+			ToCoin:          toCoin,
+			VaultAddress:    bundle.SwapTx.To,
+			FromAmount:      fromAmount,
+			ToAmountDecimal: toAmountDecimal,
+			ToAmountLimit:   bundle.MinimumOutput.String(),
+		}
+		if bundle.Quote != nil {
+			if bundle.Quote.InboundAddress != "" {
+				tc.VaultAddress = bundle.Quote.InboundAddress
+			}
+			if bundle.Quote.Router != "" {
+				tc.RouterAddress = &bundle.Quote.Router
+			}
+			if bundle.Quote.Expiry > 0 {
+				tc.ExpirationTime = uint64(bundle.Quote.Expiry)
+			}
+		}
+		if bundle.Provider == "THORChain" {
+			p.SwapPayload = &v1.KeysignPayload_ThorchainSwapPayload{ThorchainSwapPayload: tc}
+		} else {
+			p.SwapPayload = &v1.KeysignPayload_MayachainSwapPayload{MayachainSwapPayload: tc}
+		}
+	case fromChain.IsEvm():
+		value := "0"
+		if bundle.SwapTx.Value != nil {
+			value = bundle.SwapTx.Value.String()
+		}
+		p.SwapPayload = &v1.KeysignPayload_OneinchSwapPayload{
+			OneinchSwapPayload: &v1.OneInchSwapPayload{
+				FromCoin:        fromCoin,
+				ToCoin:          toCoin,
+				FromAmount:      fromAmount,
+				ToAmountDecimal: toAmountDecimal,
+				Quote: &v1.OneInchQuote{
+					DstAmount: bundle.ExpectedOutput.String(),
+					Tx: &v1.OneInchTransaction{
+						From:  params.Sender,
+						To:    bundle.SwapTx.To,
+						Data:  fmt.Sprintf("0x%x", bundle.SwapTx.Data),
+						Value: value,
+						Gas:   int64(bundle.SwapTx.GasLimit),
+					},
+				},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("keysign output is not supported for %s swaps from %s", bundle.Provider, params.FromChain)
+	}
+
+	if bundle.NeedsApproval && bundle.Quote != nil && bundle.Quote.ApprovalSpender != "" {
+		amount := params.Amount.String()
+		if bundle.Quote.ApprovalAmount != nil {
+			amount = bundle.Quote.ApprovalAmount.String()
+		}
+		p.Erc20ApprovePayload = &v1.Erc20ApprovePayload{
+			Amount:  amount,
+			Spender: bundle.Quote.ApprovalSpender,
+		}
+	}
+	return p, nil
+}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"math/big"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/vultisig/vultisig-go/common"
 
+	"github.com/vultisig/mcp/internal/keysign"
 	"github.com/vultisig/mcp/internal/resolve"
 	"github.com/vultisig/mcp/internal/tron"
 	"github.com/vultisig/mcp/internal/vault"
@@ -45,6 +46,7 @@ func newBuildTRC20TransferTool() mcp.Tool {
 		mcp.WithNumber("fee_limit",
 			mcp.Description("Maximum energy cost in SUN (default: 100,000,000 = 100 TRX)."),
 		),
+		withOutputFormat(),
 	)
 }
 
@@ -77,6 +79,11 @@ func handleBuildTRC20Transfer(store *vault.Store, tronClient *tron.Client) serve
 			return mcp.NewToolResultError(fmt.Sprintf("invalid amount: %s", amountStr)), nil
 		}
 
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		explicit := req.GetString("from", "")
 		if explicit != "" {
 			err = tron.ValidateAddress(explicit)
@@ -105,37 +112,37 @@ func handleBuildTRC20Transfer(store *vault.Store, tronClient *tron.Client) serve
 		paddedAddr := strings.Repeat("0", tron.ABIWordHexLen-len(toHex)) + toHex
 		parameter := paddedAddr + fmt.Sprintf("%064x", amount)
 
-		symbol := "UNKNOWN"
-		var decimals uint8
-
-		result, err := tronClient.TriggerConstantContract(ctx, fromAddr, contractAddr, "symbol()", "")
+		meta, err := tronClient.TokenMetadata(ctx, fromAddr, contractAddr)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token symbol: %v", err)), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		if len(result.ConstantResult) > 0 {
-			decoded, err := tron.DecodeTRC20Symbol(result.ConstantResult[0])
-			if err != nil {
-				log.Printf("[build_trc20_transfer] decode symbol for %s: %v", contractAddr, err)
-			} else {
-				symbol = decoded
-			}
+		symbol := meta.Symbol
+		if symbol == "" {
+			symbol = "UNKNOWN"
 		}
-
+		decimals := meta.Decimals
 		var decimalsWarning string
-		result, err = tronClient.TriggerConstantContract(ctx, fromAddr, contractAddr, "decimals()", "")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get token decimals: %v", err)), nil
+		if meta.NoDecimals {
+			decimalsWarning = "decimals unavailable, amount_display may be incorrect"
 		}
-		if len(result.ConstantResult) > 0 {
-			d, err := tron.DecodeTRC20Decimals(result.ConstantResult[0])
+
+		if asKeysign {
+			v := resolve.ResolveVault(ctx, req, store)
+			coin, err := keysign.NewCoin(v, common.Tron, symbol, int32(decimals), contractAddr)
 			if err != nil {
-				log.Printf("[build_trc20_transfer] decode decimals for %s: %v", contractAddr, err)
-				decimalsWarning = "failed to decode decimals, amount_display may be incorrect"
-			} else {
-				decimals = d
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
 			}
-		} else {
-			decimalsWarning = "decimals unavailable, amount_display may be incorrect"
+			if coin.Address != fromAddr {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"from address %q does not match vault-derived address %q", fromAddr, coin.Address)), nil
+			}
+			specific, err := tronKeysignSpecific(ctx, tronClient, uint64(feeLimit))
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			payload := keysign.NewPayload(v, coin, toAddr, amountStr)
+			payload.BlockchainSpecific = specific
+			return keysignToolResult("Tron", "transfer", payload)
 		}
 
 		out := map[string]any{
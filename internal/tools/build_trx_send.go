@@ -11,6 +11,7 @@ import (
 	"github.com/vultisig/vultisig-go/address"
 	"github.com/vultisig/vultisig-go/common"
 
+	"github.com/vultisig/mcp/internal/keysign"
 	"github.com/vultisig/mcp/internal/resolve"
 	"github.com/vultisig/mcp/internal/tron"
 	"github.com/vultisig/mcp/internal/vault"
@@ -35,10 +36,11 @@ func newBuildTRXSendTool() mcp.Tool {
 		mcp.WithString("from",
 			mcp.Description("Sender's TRON address (base58). Optional if vault info is set."),
 		),
+		withOutputFormat(),
 	)
 }
 
-func handleBuildTRXSend(store *vault.Store) server.ToolHandlerFunc {
+func handleBuildTRXSend(store *vault.Store, tronClient *tron.Client) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		toAddr, err := req.RequireString("to")
 		if err != nil {
@@ -58,6 +60,11 @@ func handleBuildTRXSend(store *vault.Store) server.ToolHandlerFunc {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid amount: %s", amountStr)), nil
 		}
 
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		explicit := req.GetString("from", "")
 
 		v := resolve.ResolveVault(ctx, req, store)
@@ -81,6 +88,20 @@ func handleBuildTRXSend(store *vault.Store) server.ToolHandlerFunc {
 			}
 		}
 
+		if asKeysign {
+			coin, err := keysign.NewCoin(v, common.Tron, "TRX", 6, "")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			specific, err := tronKeysignSpecific(ctx, tronClient, 0)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			payload := keysign.NewPayload(v, coin, toAddr, amountStr)
+			payload.BlockchainSpecific = specific
+			return keysignToolResult("Tron", "transfer", payload)
+		}
+
 		result := map[string]any{
 			"chain":           "Tron",
 			"action":          "transfer",
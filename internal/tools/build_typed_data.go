@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// typedDataOptions declares the typed-data input shared by
+// build_typed_data and verify_typed_data_signature: either a full
+// typed_data payload or its four parts.
+func typedDataOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithObject("typed_data",
+			mcp.Description("Full EIP-712 payload as used by eth_signTypedData_v4: {types, primaryType, domain, message}. "+
+				"May also be passed as a JSON string. Takes precedence over domain/types/primary_type/message. "+
+				"Pass integers above 2^53 as decimal strings."),
+		),
+		mcp.WithObject("domain",
+			mcp.Description("EIP-712 domain: any of name, version, chainId, verifyingContract, salt."),
+		),
+		mcp.WithObject("types",
+			mcp.Description("EIP-712 struct types, e.g. {\"Permit\": [{\"name\": \"owner\", \"type\": \"address\"}, ...]}. "+
+				"EIP712Domain is inferred from the domain if omitted."),
+		),
+		mcp.WithString("primary_type",
+			mcp.Description("Name of the message's struct type. Inferred if exactly one type is not referenced by another."),
+		),
+		mcp.WithObject("message",
+			mcp.Description("The message to sign, matching primary_type."),
+		),
+	}
+}
+
+// typedDataParam parses the typed-data input declared by typedDataOptions.
+func typedDataParam(req mcp.CallToolRequest) (*evmclient.TypedData, error) {
+	args := req.GetArguments()
+	var raw []byte
+	switch v := args["typed_data"].(type) {
+	case string:
+		raw = []byte(v)
+	case map[string]any:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid typed_data: %v", err)
+		}
+		raw = encoded
+	case nil:
+		if args["domain"] == nil || args["types"] == nil || args["message"] == nil {
+			return nil, errors.New("pass typed_data, or domain, types and message")
+		}
+		encoded, err := json.Marshal(map[string]any{
+			"domain":      args["domain"],
+			"types":       args["types"],
+			"primaryType": req.GetString("primary_type", ""),
+			"message":     args["message"],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid typed data: %v", err)
+		}
+		raw = encoded
+	default:
+		return nil, errors.New("typed_data must be an object or a JSON string")
+	}
+	return evmclient.ParseTypedData(raw)
+}
+
+func newBuildTypedDataTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription(
+			"Validate and normalise an EIP-712 typed-data payload (Permit, Permit2, Seaport and CoW Swap orders, " +
+				"Snapshot votes, ...) and return the digest to sign. Fills in the EIP712Domain type and primaryType when " +
+				"omitted, checks every field against its declared type and returns the domain separator, message hash and " +
+				"the normalised payload for eth_signTypedData_v4. Use verify_typed_data_signature to check the resulting signature.",
+		),
+	}
+	opts = append(opts, typedDataOptions()...)
+	return mcp.NewTool("build_typed_data", opts...)
+}
+
+func handleBuildTypedData(store *vault.Store) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		td, err := typedDataParam(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		h, err := td.Hash()
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result := map[string]any{
+			"primary_type":     td.PrimaryType,
+			"digest":           hexutil.Encode(h.Digest),
+			"domain_separator": hexutil.Encode(h.DomainSeparator),
+			"message_hash":     hexutil.Encode(h.MessageHash),
+			"typed_data":       td.Normalized(),
+		}
+		if td.Domain.Name != "" {
+			result["domain_name"] = td.Domain.Name
+		}
+		if id := td.ChainID(); id != nil {
+			result["chain_id"] = id.String()
+			if name, ok := evmclient.ChainNameByID(id); ok {
+				result["chain"] = name
+			}
+		}
+		if td.Domain.VerifyingContract != "" {
+			result["verifying_contract"] = td.Domain.VerifyingContract
+		}
+		if v := resolve.ResolveVault(ctx, req, store); v != nil {
+			if addr, err := resolve.EVMAddress("", v); err == nil {
+				result["signer"] = addr
+			}
+		}
+
+		var warnings []string
+		if td.ChainID() == nil {
+			warnings = append(warnings, "domain has no chainId: the signature is valid on every chain")
+		}
+		if td.Domain.VerifyingContract == "" {
+			warnings = append(warnings, "domain has no verifyingContract: any contract accepting this type can use the signature")
+		}
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
@@ -0,0 +1,167 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// permitTypedData is an EIP-2612 Permit for USDC on Ethereum, with the
+// EIP712Domain type and primaryType left for the tool to infer.
+func permitTypedData() map[string]any {
+	return map[string]any{
+		"domain": map[string]any{
+			"name":              "USD Coin",
+			"version":           "2",
+			"chainId":           float64(1),
+			"verifyingContract": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		},
+		"types": map[string]any{
+			"Permit": []any{
+				map[string]any{"name": "owner", "type": "address"},
+				map[string]any{"name": "spender", "type": "address"},
+				map[string]any{"name": "value", "type": "uint256"},
+				map[string]any{"name": "nonce", "type": "uint256"},
+				map[string]any{"name": "deadline", "type": "uint256"},
+			},
+		},
+		"message": map[string]any{
+			"owner":    testAddress,
+			"spender":  permit2,
+			"value":    "115792089237316195423570985008687907853269984665640564039457584007913129639935",
+			"nonce":    float64(0),
+			"deadline": "1700000000",
+		},
+	}
+}
+
+func TestBuildTypedData(t *testing.T) {
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+	handler := handleBuildTypedData(store)
+
+	whole, _ := json.Marshal(permitTypedData())
+	var digests []any
+	for name, args := range map[string]map[string]any{
+		"parts":       permitTypedData(),
+		"typed_data":  {"typed_data": permitTypedData()},
+		"json string": {"typed_data": string(whole)},
+	} {
+		res, err := handler(context.Background(), callToolReq("build_typed_data", args))
+		if err != nil {
+			t.Fatalf("%s: handler error: %v", name, err)
+		}
+		result := decodeResult(t, res)
+		if result["primary_type"] != "Permit" || result["chain"] != "Ethereum" || result["signer"] != testAddress {
+			t.Errorf("%s: primary_type/chain/signer = %v/%v/%v", name, result["primary_type"], result["chain"], result["signer"])
+		}
+		if result["warnings"] != nil {
+			t.Errorf("%s: warnings = %v", name, result["warnings"])
+		}
+		typed, _ := result["typed_data"].(map[string]any)
+		types, _ := typed["types"].(map[string]any)
+		if domain, _ := types["EIP712Domain"].([]any); len(domain) != 4 {
+			t.Errorf("%s: EIP712Domain = %v", name, types["EIP712Domain"])
+		}
+		digests = append(digests, result["digest"])
+	}
+	if digests[0] != digests[1] || digests[1] != digests[2] {
+		t.Errorf("digests differ across input forms: %v", digests)
+	}
+}
+
+func TestBuildTypedData_Invalid(t *testing.T) {
+	handler := handleBuildTypedData(vault.NewStore())
+
+	missingField := permitTypedData()
+	delete(missingField["message"].(map[string]any), "deadline")
+	overflow := permitTypedData()
+	overflow["message"].(map[string]any)["nonce"] = "-1"
+
+	for name, args := range map[string]map[string]any{
+		"no input":      {},
+		"missing field": missingField,
+		"negative uint": overflow,
+		"bad json":      {"typed_data": "{"},
+	} {
+		res, err := handler(context.Background(), callToolReq("build_typed_data", args))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+}
+
+func TestVerifyTypedDataSignature(t *testing.T) {
+	// The EIP-712 Mail example, signed by the spec's "Cow" key.
+	mail := map[string]any{
+		"types": map[string]any{
+			"Person": []any{
+				map[string]any{"name": "name", "type": "string"},
+				map[string]any{"name": "wallet", "type": "address"},
+			},
+			"Mail": []any{
+				map[string]any{"name": "from", "type": "Person"},
+				map[string]any{"name": "to", "type": "Person"},
+				map[string]any{"name": "contents", "type": "string"},
+			},
+		},
+		"domain": map[string]any{
+			"name":              "Ether Mail",
+			"version":           "1",
+			"chainId":           float64(1),
+			"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+		},
+		"message": map[string]any{
+			"from":     map[string]any{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			"to":       map[string]any{"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+			"contents": "Hello, Bob!",
+		},
+	}
+	const (
+		signature = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+			"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+		cow = "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"
+	)
+
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+	handler := handleVerifyTypedDataSignature(store)
+
+	for _, tc := range []struct {
+		name     string
+		address  string
+		valid    bool
+		expected string
+	}{
+		{"explicit signer", cow, true, cow},
+		{"vault fallback", "", false, testAddress},
+	} {
+		args := map[string]any{"typed_data": mail, "signature": signature}
+		if tc.address != "" {
+			args["address"] = tc.address
+		}
+		res, err := handler(context.Background(), callToolReq("verify_typed_data_signature", args))
+		if err != nil {
+			t.Fatalf("%s: handler error: %v", tc.name, err)
+		}
+		result := decodeResult(t, res)
+		if result["valid"] != tc.valid || result["signer"] != cow || result["expected_signer"] != tc.expected {
+			t.Errorf("%s: result = %v", tc.name, result)
+		}
+		if result["digest"] != "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2" {
+			t.Errorf("%s: digest = %v", tc.name, result["digest"])
+		}
+	}
+
+	res, err := handler(context.Background(), callToolReq("verify_typed_data_signature", map[string]any{
+		"typed_data": mail, "signature": "0x1234", "address": cow,
+	}))
+	if err != nil || !res.IsError {
+		t.Errorf("short signature: expected tool error, got %v / %v", res, err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/vultisig/vultisig-go/common"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vultisig/mcp/internal/feerate"
 	"github.com/vultisig/mcp/internal/mayachain"
 	"github.com/vultisig/mcp/internal/thorchain"
 	"github.com/vultisig/mcp/internal/vault"
@@ -73,7 +74,7 @@ func TestLTCFeeRate(t *testing.T) {
 	srv := mockThorchainMulti(t, map[string]string{"LTC": "12"})
 	defer srv.Close()
 
-	handler := handleLTCFeeRate(thorchain.NewClient(srv.URL))
+	handler := handleLTCFeeRate(vault.NewStore(), feerate.NewThorchainSource(thorchain.NewClient(srv.URL)), nil)
 	res, err := handler(context.Background(), callToolReq("ltc_fee_rate", map[string]any{}))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -103,7 +104,7 @@ func TestDOGEFeeRate(t *testing.T) {
 	srv := mockThorchainMulti(t, map[string]string{"DOGE": "250000000"})
 	defer srv.Close()
 
-	handler := handleDOGEFeeRate(thorchain.NewClient(srv.URL))
+	handler := handleDOGEFeeRate(vault.NewStore(), feerate.NewThorchainSource(thorchain.NewClient(srv.URL)), nil)
 	res, err := handler(context.Background(), callToolReq("doge_fee_rate", map[string]any{}))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -130,7 +131,7 @@ func TestBCHFeeRate(t *testing.T) {
 	srv := mockThorchainMulti(t, map[string]string{"BCH": "3"})
 	defer srv.Close()
 
-	handler := handleBCHFeeRate(thorchain.NewClient(srv.URL))
+	handler := handleBCHFeeRate(vault.NewStore(), feerate.NewThorchainSource(thorchain.NewClient(srv.URL)), nil)
 	res, err := handler(context.Background(), callToolReq("bch_fee_rate", map[string]any{}))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -211,7 +212,7 @@ func TestBuildLTCSend_Basic(t *testing.T) {
 	store := setupVaultForChain(t)
 	senderAddr := deriveChainAddr(t, common.Litecoin)
 
-	handler := handleBuildLTCSend(store, nil)
+	handler := handleBuildLTCSend(store, mockBlockchair(t, 1_000_000_000))
 
 	req := callToolReq("build_ltc_send", map[string]any{
 		"to_address": senderAddr,
@@ -251,7 +252,7 @@ func TestBuildDOGESend_Basic(t *testing.T) {
 	store := setupVaultForChain(t)
 	senderAddr := deriveChainAddr(t, common.Dogecoin)
 
-	handler := handleBuildDOGESend(store, nil)
+	handler := handleBuildDOGESend(store, mockBlockchair(t, 1_000_000_000))
 
 	req := callToolReq("build_doge_send", map[string]any{
 		"to_address": "DDogepartyxxxxxxxxxxxxxxxxxxw1dfzr",
@@ -288,7 +289,7 @@ func TestBuildBCHSend_Basic(t *testing.T) {
 	store := setupVaultForChain(t)
 	senderAddr := deriveChainAddr(t, common.BitcoinCash)
 
-	handler := handleBuildBCHSend(store, nil)
+	handler := handleBuildBCHSend(store, mockBlockchair(t, 1_000_000_000))
 
 	req := callToolReq("build_bch_send", map[string]any{
 		"to_address": "bitcoincash:qp3wjpa3tjlj042z2wv7hahsldgwhwy0rq9sywjpyy",
@@ -325,7 +326,7 @@ func TestBuildDASHSend_Basic(t *testing.T) {
 	store := setupVaultForChain(t)
 	senderAddr := deriveChainAddr(t, common.Dash)
 
-	handler := handleBuildDASHSend(store, nil)
+	handler := handleBuildDASHSend(store, mockBlockchair(t, 1_000_000_000))
 
 	req := callToolReq("build_dash_send", map[string]any{
 		"to_address": senderAddr,
@@ -362,7 +363,7 @@ func TestBuildZECSend_Basic(t *testing.T) {
 	store := setupVaultForChain(t)
 	senderAddr := deriveChainAddr(t, common.Zcash)
 
-	handler := handleBuildZECSend(store, nil)
+	handler := handleBuildZECSend(store, mockBlockchair(t, 1_000_000_000))
 
 	req := callToolReq("build_zec_send", map[string]any{
 		"to_address": senderAddr,
@@ -404,7 +405,7 @@ func TestBuildZECSend_WithMemo(t *testing.T) {
 	store := setupVaultForChain(t)
 	recipientAddr := deriveChainAddr(t, common.Zcash)
 
-	handler := handleBuildZECSend(store, nil)
+	handler := handleBuildZECSend(store, mockBlockchair(t, 1_000_000_000))
 
 	req := callToolReq("build_zec_send", map[string]any{
 		"to_address": recipientAddr,
@@ -0,0 +1,212 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vultisig/vultisig-go/address"
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/mcp/internal/feerate"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/utxo"
+	"github.com/vultisig/mcp/internal/utxobackend"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// maxConsolidateInputs keeps a consolidation well inside the 100,000 vbyte
+// standard transaction limit even with legacy inputs.
+const maxConsolidateInputs = 500
+
+func newBuildUTXOConsolidateTool() mcp.Tool {
+	return mcp.NewTool("build_utxo_consolidate",
+		mcp.WithDescription(
+			"Build an unsigned transaction that merges the vault's small UTXOs into a single output back to the vault. "+
+				"Run it while fees are low so later sends need fewer inputs: "+
+				"fee_rate is refused when it is above max_fee_rate or, if max_fee_rate is omitted, above the network's current slow rate. "+
+				"UTXOs worth less than the fee to spend them are left out. "+
+				"Reports the fee saved compared with sweeping each UTXO on its own. "+
+				"Accepts inline vault keys (ecdsa_public_key, eddsa_public_key, chain_code) or falls back to set_vault_info session state.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("UTXO chain to consolidate"),
+			mcp.Required(),
+			mcp.Enum("Bitcoin", "Litecoin", "Dogecoin", "Bitcoin-Cash", "Dash", "Zcash"),
+		),
+		mcp.WithString("max_utxo_value",
+			mcp.Description("Only merge UTXOs worth at most this many base units (decimal string). Merges every UTXO if omitted."),
+		),
+		mcp.WithNumber("max_inputs",
+			mcp.Description(fmt.Sprintf("Maximum number of UTXOs to merge, smallest first (default and cap %d)", maxConsolidateInputs)),
+		),
+		mcp.WithNumber("fee_rate",
+			mcp.Description("Fee rate in sat/vB. Required for every chain except Zcash, which uses the ZIP-317 fee."),
+		),
+		mcp.WithNumber("max_fee_rate",
+			mcp.Description("Refuse to consolidate when fee_rate is above this many sat/vB. Defaults to the network's current slow rate where one is available."),
+		),
+		withOutputFormat(),
+	)
+}
+
+func handleBuildUTXOConsolidate(store *vault.Store, utxoBackend utxobackend.Backend, fees feerate.Source) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return mcp.NewToolResultError("missing chain"), nil
+		}
+		params, ok := utxoChains[chainName]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported UTXO chain: %q", chainName)), nil
+		}
+
+		var maxValue int64
+		if s := req.GetString("max_utxo_value", ""); s != "" {
+			maxValue, err = strconv.ParseInt(s, 10, 64)
+			if err != nil || maxValue <= 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid max_utxo_value: %q", s)), nil
+			}
+		}
+
+		maxInputs := int(req.GetFloat("max_inputs", maxConsolidateInputs))
+		if maxInputs < 2 || maxInputs > maxConsolidateInputs {
+			return mcp.NewToolResultError(fmt.Sprintf("max_inputs must be between 2 and %d", maxConsolidateInputs)), nil
+		}
+
+		var feeRate uint64
+		fee := utxo.ZIP317Fee
+		if chainName != "Zcash" {
+			feeRateFloat := req.GetFloat("fee_rate", 0)
+			if math.IsNaN(feeRateFloat) || math.IsInf(feeRateFloat, 0) || feeRateFloat <= 0 {
+				return mcp.NewToolResultError("fee_rate must be a valid positive number"), nil
+			}
+			feeRate = uint64(math.Ceil(feeRateFloat))
+			fee = utxo.RateFee(feeRate)
+
+			if err := checkConsolidateFeeRate(ctx, req, fees, chainName, feeRate); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		v := resolve.ResolveVault(ctx, req, store)
+		if v == nil {
+			return mcp.NewToolResultError("no vault info available — pass vault keys inline or call set_vault_info"), nil
+		}
+
+		chain, err := common.FromString(chainName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		senderAddr, senderPubKey, _, err := address.GetAddress(v.ECDSAPublicKey, v.ChainCode, chain)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("derive %s address: %v", chainName, err)), nil
+		}
+		senderScript, err := params.addressToPkScript(senderAddr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("sender script: %v", err)), nil
+		}
+		inputType, err := utxo.InputTypeOf(senderScript)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("sender address: %v", err)), nil
+		}
+		pubKey, err := hex.DecodeString(senderPubKey)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("decode sender public key: %v", err)), nil
+		}
+
+		coins, err := fetchUTXOs(ctx, utxoBackend, chainName, senderAddr)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		c, err := utxo.Consolidate(utxo.ConsolidateRequest{
+			UTXOs:      coins,
+			InputType:  inputType,
+			MaxValue:   maxValue,
+			MaxInputs:  maxInputs,
+			Script:     senderScript,
+			DustLimit:  params.dustLimit,
+			Fee:        fee,
+			ExtraBytes: params.extraBytes,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		amount := c.Outputs[0].Value
+
+		if asKeysign {
+			payload, err := utxoKeysignPayload(v, chainName, senderAddr, amount, feeRate, "", c.Inputs)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			return keysignToolResult(chainName, "consolidate", payload)
+		}
+
+		btx, err := assembleUTXOTx(ctx, utxoBackend, chainName, params, c.Selection, senderScript, pubKey)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("build transaction: %v", err)), nil
+		}
+		btx.separateFee = c.SeparateFee
+
+		result := map[string]any{
+			"chain":              chainName,
+			"action":             "consolidate",
+			"from":               senderAddr,
+			"to":                 senderAddr,
+			"amount":             amount,
+			"utxos_consolidated": len(c.Inputs),
+			"utxos_uneconomical": len(c.Uneconomical),
+		}
+		if chainName != "Zcash" {
+			result["fee_rate"] = feeRate
+		}
+		btx.addTo(result)
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// checkConsolidateFeeRate refuses fee rates above max_fee_rate or, when that
+// is omitted, above the chain's current slow tier. Consolidation is never
+// urgent, so paying more than the slow rate defeats its purpose. Chains
+// without a fee source are not checked.
+func checkConsolidateFeeRate(ctx context.Context, req mcp.CallToolRequest, fees feerate.Source, chainName string, feeRate uint64) error {
+	if _, ok := req.GetArguments()["max_fee_rate"]; ok {
+		maxRate := req.GetFloat("max_fee_rate", 0)
+		if math.IsNaN(maxRate) || math.IsInf(maxRate, 0) || maxRate <= 0 {
+			return fmt.Errorf("max_fee_rate must be a valid positive number")
+		}
+		if float64(feeRate) > maxRate {
+			return fmt.Errorf("fee_rate %d sat/vB is above max_fee_rate %s sat/vB", feeRate, strconv.FormatFloat(maxRate, 'f', -1, 64))
+		}
+		return nil
+	}
+
+	if fees == nil {
+		return nil
+	}
+	tiers, err := fees.Tiers(ctx, chainName)
+	if err != nil || tiers.Slow == 0 {
+		return nil
+	}
+	if feeRate > tiers.Slow {
+		return fmt.Errorf("fee_rate %d sat/vB is above the current slow rate of %d sat/vB (%s); "+
+			"wait for lower fees, or set max_fee_rate to consolidate anyway", feeRate, tiers.Slow, tiers.Source)
+	}
+	return nil
+}
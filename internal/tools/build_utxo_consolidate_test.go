@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/vultisig/mcp/internal/feerate"
+	"github.com/vultisig/mcp/internal/keysign"
+)
+
+func TestBuildUTXOConsolidate(t *testing.T) {
+	store := setupBTCVault(t)
+	senderAddr := deriveBTCAddress(t, store)
+	handler := handleBuildUTXOConsolidate(store, mockBlockchair(t, 5_000, 20_000, 30_000, 40, 9_000_000), nil)
+
+	res, err := handler(context.Background(), callToolReq("build_utxo_consolidate", map[string]any{
+		"chain":          "Bitcoin",
+		"max_utxo_value": "100000",
+		"fee_rate":       float64(5),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	tx := decodePSBT(t, result).UnsignedTx
+
+	if len(tx.TxIn) != 3 {
+		t.Fatalf("inputs = %d, want the three coins under the threshold", len(tx.TxIn))
+	}
+	senderScript, _ := utxoChains["Bitcoin"].addressToPkScript(senderAddr)
+	if len(tx.TxOut) != 1 || !bytes.Equal(tx.TxOut[0].PkScript, senderScript) {
+		t.Fatalf("expected a single output back to the vault, got %+v", tx.TxOut)
+	}
+
+	fee := int64(result["fee"].(float64))
+	if tx.TxOut[0].Value != 55_000-fee {
+		t.Errorf("output = %d, want %d", tx.TxOut[0].Value, 55_000-fee)
+	}
+	if result["utxos_uneconomical"].(float64) != 1 {
+		t.Errorf("utxos_uneconomical = %v, want 1", result["utxos_uneconomical"])
+	}
+	separate := int64(result["fee_if_sent_separately"].(float64))
+	if saved := int64(result["fee_saved"].(float64)); saved <= 0 || saved != separate-fee {
+		t.Errorf("fee_saved = %d, separate = %d, fee = %d", saved, separate, fee)
+	}
+}
+
+func TestBuildUTXOConsolidate_Keysign(t *testing.T) {
+	store := setupVaultForChain(t)
+	handler := handleBuildUTXOConsolidate(store, mockBlockchair(t, 200_000_000, 300_000_000), nil)
+
+	res, err := handler(context.Background(), callToolReq("build_utxo_consolidate", map[string]any{
+		"chain":         "Dogecoin",
+		"fee_rate":      float64(1000),
+		"output_format": "keysign",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	p, err := keysign.Decode(result["keysign_payload_base64"].(string))
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if len(p.UtxoInfo) != 2 || p.ToAddress != p.Coin.Address {
+		t.Errorf("utxo_info = %d, to = %q, want 2 inputs back to %q", len(p.UtxoInfo), p.ToAddress, p.Coin.Address)
+	}
+}
+
+func TestBuildUTXOConsolidate_NothingToDo(t *testing.T) {
+	store := setupBTCVault(t)
+	handler := handleBuildUTXOConsolidate(store, mockBlockchair(t, 1_000_000), nil)
+
+	res, err := handler(context.Background(), callToolReq("build_utxo_consolidate", map[string]any{
+		"chain":    "Bitcoin",
+		"fee_rate": float64(5),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Fatal("expected an error with a single UTXO")
+	}
+}
+
+func TestBuildUTXOConsolidate_FeeRateGuard(t *testing.T) {
+	store := setupBTCVault(t)
+	fees := stubFeeSource{feerate.Tiers{Slow: 4, Normal: 10, Fast: 20, Source: "mempool"}}
+	handler := handleBuildUTXOConsolidate(store, mockBlockchair(t, 50_000, 60_000), fees)
+
+	for name, tc := range map[string]struct {
+		args    map[string]any
+		wantErr string
+	}{
+		"above slow tier":     {map[string]any{"fee_rate": float64(5)}, "above the current slow rate of 4 sat/vB"},
+		"at slow tier":        {map[string]any{"fee_rate": float64(4)}, ""},
+		"above max_fee_rate":  {map[string]any{"fee_rate": float64(5), "max_fee_rate": float64(3)}, "above max_fee_rate 3 sat/vB"},
+		"within max_fee_rate": {map[string]any{"fee_rate": float64(5), "max_fee_rate": float64(6)}, ""},
+	} {
+		tc.args["chain"] = "Bitcoin"
+		res, err := handler(context.Background(), callToolReq("build_utxo_consolidate", tc.args))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if tc.wantErr == "" {
+			if res.IsError {
+				t.Errorf("%s: unexpected tool error: %v", name, res.Content[0].(mcp.TextContent).Text)
+			}
+			continue
+		}
+		if !res.IsError || !strings.Contains(res.Content[0].(mcp.TextContent).Text, tc.wantErr) {
+			t.Errorf("%s: result = %v, want error containing %q", name, res.Content, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vultisig/vultisig-go/address"
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/utxo"
+	"github.com/vultisig/mcp/internal/utxobackend"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+const (
+	feeBumpRBF  = "rbf"
+	feeBumpCPFP = "cpfp"
+
+	// incrementalRelayFee is the default minimum fee rate, in sat/vB, that a
+	// replacement must add on top of the fee it replaces (BIP125 rule 4).
+	incrementalRelayFee = 1
+)
+
+func newBuildUTXOFeeBumpTool() mcp.Tool {
+	return mcp.NewTool("build_utxo_fee_bump",
+		mcp.WithDescription(
+			"Build an unsigned PSBT that speeds up a pending UTXO transaction. "+
+				"\"rbf\" replaces the transaction at the target fee rate, keeping every recipient and paying the extra fee out of change. "+
+				"\"cpfp\" spends the transaction's output to the vault in a child transaction whose fee lifts the parent and child package to the target rate. "+
+				"Extra confirmed UTXOs are added when change alone cannot cover the fee. "+
+				"Accepts inline vault keys (ecdsa_public_key, eddsa_public_key, chain_code) or falls back to set_vault_info session state.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("UTXO chain of the pending transaction. Replace-by-fee is available on Bitcoin and Litecoin only."),
+			mcp.Required(),
+			mcp.Enum("Bitcoin", "Litecoin", "Dogecoin", "Bitcoin-Cash", "Dash"),
+		),
+		mcp.WithString("txid",
+			mcp.Description("Transaction hash of the pending transaction"),
+			mcp.Required(),
+		),
+		mcp.WithString("method",
+			mcp.Description("\"rbf\" (default) to replace the transaction, or \"cpfp\" to spend its output in a child"),
+			mcp.Enum(feeBumpRBF, feeBumpCPFP),
+			mcp.DefaultString(feeBumpRBF),
+		),
+		mcp.WithNumber("fee_rate",
+			mcp.Description("Target fee rate in sat/vB. For cpfp this is the rate of the parent and child together."),
+			mcp.Required(),
+		),
+	)
+}
+
+func handleBuildUTXOFeeBump(store *vault.Store, utxoBackend utxobackend.Backend) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return mcp.NewToolResultError("missing chain"), nil
+		}
+		params, ok := utxoChains[chainName]
+		if !ok || chainName == "Zcash" {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported chain for fee bumping: %q", chainName)), nil
+		}
+
+		txid, err := req.RequireString("txid")
+		if err != nil {
+			return mcp.NewToolResultError("missing txid"), nil
+		}
+		if !utxoTxHashRE.MatchString(txid) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid txid: %q", txid)), nil
+		}
+
+		method := req.GetString("method", feeBumpRBF)
+		switch method {
+		case feeBumpRBF:
+			if params.sequence != rbfSequence {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"%s does not support replace-by-fee; use method %q", chainName, feeBumpCPFP)), nil
+			}
+		case feeBumpCPFP:
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf(
+				"invalid method %q (expected %q or %q)", method, feeBumpRBF, feeBumpCPFP)), nil
+		}
+
+		feeRateFloat := req.GetFloat("fee_rate", 0)
+		if math.IsNaN(feeRateFloat) || math.IsInf(feeRateFloat, 0) || feeRateFloat <= 0 {
+			return mcp.NewToolResultError("fee_rate must be a valid positive number"), nil
+		}
+		feeRate := int64(math.Ceil(feeRateFloat))
+
+		// Keysign payloads carry a single recipient and leave the fee to the
+		// signer, so they cannot express a replacement or a package fee.
+		if f := req.GetString("output_format", outputFormatArgs); f != outputFormatArgs {
+			return mcp.NewToolResultError("build_utxo_fee_bump returns PSBTs only and takes no output_format"), nil
+		}
+
+		v := resolve.ResolveVault(ctx, req, store)
+		if v == nil {
+			return mcp.NewToolResultError("no vault info available — pass vault keys inline or call set_vault_info"), nil
+		}
+
+		chain, err := common.FromString(chainName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		senderAddr, senderPubKey, _, err := address.GetAddress(v.ECDSAPublicKey, v.ChainCode, chain)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("derive %s address: %v", chainName, err)), nil
+		}
+		senderScript, err := params.addressToPkScript(senderAddr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("sender script: %v", err)), nil
+		}
+		inputType, err := utxo.InputTypeOf(senderScript)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("sender address: %v", err)), nil
+		}
+		pubKey, err := hex.DecodeString(senderPubKey)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("decode sender public key: %v", err)), nil
+		}
+
+		parent, err := loadPendingTx(ctx, utxoBackend, chainName, txid)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if parent.feeRate() >= float64(feeRate) {
+			return mcp.NewToolResultError(fmt.Sprintf(
+				"transaction already pays %.2f sat/vB; fee_rate must be higher", parent.feeRate())), nil
+		}
+
+		utxos, err := utxoBackend.GetUTXOs(ctx, chainName, senderAddr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("fetch UTXOs: %v", err)), nil
+		}
+
+		bump := feeBump{
+			parent:       parent,
+			senderScript: senderScript,
+			inputType:    inputType,
+			params:       params,
+			feeRate:      feeRate,
+			spare:        spareCoins(utxos, parent),
+		}
+
+		var sel *utxo.Selection
+		var effectiveRate float64
+		if method == feeBumpRBF {
+			var inputs []utxo.UTXO
+			inputs, err = bump.replaceableInputs(ctx, utxoBackend, chainName)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			sel, err = bump.replace(inputs)
+			if err == nil {
+				effectiveRate = float64(sel.Fee) / float64(sel.Shape.VSize)
+			}
+		} else {
+			sel, err = bump.child(utxos)
+			if err == nil {
+				effectiveRate = float64(parent.fee+sel.Fee) / float64(parent.vsize+sel.Shape.VSize)
+			}
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("build transaction: %v", err)), nil
+		}
+
+		btx, err := buildPSBT(ctx, utxoBackend, chainName, params, sel, senderScript, pubKey)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("build transaction: %v", err)), nil
+		}
+
+		result := map[string]any{
+			"chain":              chainName,
+			"method":             method,
+			"original_txid":      txid,
+			"from":               senderAddr,
+			"fee_rate":           feeRate,
+			"original_fee":       parent.fee,
+			"original_vsize":     parent.vsize,
+			"original_fee_rate":  roundRate(parent.feeRate()),
+			"effective_fee_rate": roundRate(effectiveRate),
+		}
+		btx.addTo(result)
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// pendingTx is an unconfirmed transaction as seen in the mempool.
+type pendingTx struct {
+	txid  string
+	tx    *wire.MsgTx
+	fee   int64
+	vsize int
+}
+
+func (p *pendingTx) feeRate() float64 {
+	return float64(p.fee) / float64(p.vsize)
+}
+
+// loadPendingTx fetches txid from the backend and checks it is still unconfirmed.
+func loadPendingTx(ctx context.Context, utxoBackend utxobackend.Backend, chain, txid string) (*pendingTx, error) {
+	dash, err := utxoBackend.GetTxDashboard(ctx, chain, txid)
+	if err != nil {
+		return nil, fmt.Errorf("fetch transaction: %w", err)
+	}
+	if dash == nil {
+		return nil, fmt.Errorf("transaction %s not found", txid)
+	}
+	if dash.BlockID >= 0 {
+		return nil, fmt.Errorf("transaction %s is already confirmed in block %d", txid, dash.BlockID)
+	}
+
+	raw, err := utxoBackend.GetRawTransaction(ctx, chain, txid)
+	if err != nil {
+		return nil, fmt.Errorf("fetch raw transaction: %w", err)
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	err = tx.Deserialize(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decode raw transaction: %w", err)
+	}
+
+	weight := tx.SerializeSizeStripped()*3 + tx.SerializeSize()
+	return &pendingTx{
+		txid:  txid,
+		tx:    tx,
+		fee:   dash.Fee,
+		vsize: (weight + 3) / 4,
+	}, nil
+}
+
+// spareCoins returns the vault's confirmed UTXOs that parent does not create,
+// largest first. Only confirmed coins are used so a replacement adds no new
+// unconfirmed inputs (BIP125 rule 2) and a child's package is just the parent.
+func spareCoins(utxos []utxobackend.UTXO, parent *pendingTx) []utxo.UTXO {
+	var coins []utxo.UTXO
+	for _, u := range utxos {
+		if u.BlockID <= 0 || u.TransactionHash == parent.txid {
+			continue
+		}
+		coins = append(coins, utxo.UTXO{TxHash: u.TransactionHash, Index: uint32(u.Index), Value: u.Value})
+	}
+	sort.SliceStable(coins, func(i, j int) bool { return coins[i].Value > coins[j].Value })
+	return coins
+}
+
+// feeBump plans a replacement or child transaction for a pending parent.
+type feeBump struct {
+	parent       *pendingTx
+	senderScript []byte
+	inputType    utxo.InputType
+	params       utxoChainParams
+	feeRate      int64
+	// spare are confirmed coins to draw on when change cannot cover the fee.
+	spare []utxo.UTXO
+}
+
+// replaceableInputs verifies that the parent signals BIP125 and that every
+// input spends a vault coin, since the replacement must re-sign all of them.
+// It returns the parent's inputs with their values.
+func (b *feeBump) replaceableInputs(ctx context.Context, utxoBackend utxobackend.Backend, chain string) ([]utxo.UTXO, error) {
+	signals := false
+	for _, in := range b.parent.tx.TxIn {
+		if in.Sequence < wire.MaxTxInSequenceNum-1 {
+			signals = true
+			break
+		}
+	}
+	if !signals {
+		return nil, fmt.Errorf("transaction %s does not signal replace-by-fee; use method %q", b.parent.txid, feeBumpCPFP)
+	}
+
+	inputs := make([]utxo.UTXO, len(b.parent.tx.TxIn))
+	for i, in := range b.parent.tx.TxIn {
+		prevOut, err := fetchPrevOut(ctx, utxoBackend, chain, in.PreviousOutPoint)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(prevOut.PkScript, b.senderScript) {
+			return nil, fmt.Errorf("input %d does not spend a vault coin; only transactions sent from the vault can be replaced", i)
+		}
+		inputs[i] = utxo.UTXO{
+			TxHash: in.PreviousOutPoint.Hash.String(),
+			Index:  in.PreviousOutPoint.Index,
+			Value:  prevOut.Value,
+		}
+	}
+	return inputs, nil
+}
+
+// fetchPrevOut returns the output spent by op.
+func fetchPrevOut(ctx context.Context, utxoBackend utxobackend.Backend, chain string, op wire.OutPoint) (*wire.TxOut, error) {
+	raw, err := utxoBackend.GetRawTransaction(ctx, chain, op.Hash.String())
+	if err != nil {
+		return nil, fmt.Errorf("fetch previous transaction: %w", err)
+	}
+	prev := wire.NewMsgTx(wire.TxVersion)
+	err = prev.Deserialize(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decode previous transaction %s: %w", op.Hash, err)
+	}
+	if int(op.Index) >= len(prev.TxOut) {
+		return nil, fmt.Errorf("previous transaction %s has no output %d", op.Hash, op.Index)
+	}
+	return prev.TxOut[op.Index], nil
+}
+
+// replace builds the RBF replacement: the parent's inputs and recipients
+// with the change output shrunk (or dropped) to pay the higher fee.
+func (b *feeBump) replace(inputs []utxo.UTXO) (*utxo.Selection, error) {
+	var totalIn int64
+	for _, in := range inputs {
+		totalIn += in.Value
+	}
+
+	var payments []utxo.Output
+	changeAt := -1
+	for _, out := range b.parent.tx.TxOut {
+		if changeAt < 0 && bytes.Equal(out.PkScript, b.senderScript) {
+			changeAt = len(payments)
+			continue
+		}
+		payments = append(payments, utxo.Output{PkScript: out.PkScript, Value: out.Value})
+	}
+	if changeAt < 0 {
+		changeAt = len(payments)
+	}
+
+	fee := func(s utxo.Shape) int64 {
+		return max(int64(s.VSize)*b.feeRate, b.parent.fee+int64(s.VSize)*incrementalRelayFee)
+	}
+	return b.fund(inputs, totalIn, payments, changeAt, fee)
+}
+
+// child builds the CPFP transaction spending the parent's largest unspent
+// output to the vault back to the vault.
+func (b *feeBump) child(utxos []utxobackend.UTXO) (*utxo.Selection, error) {
+	var anchor *utxo.UTXO
+	for _, u := range utxos {
+		if u.TransactionHash != b.parent.txid || u.Index < 0 || u.Index >= len(b.parent.tx.TxOut) {
+			continue
+		}
+		if !bytes.Equal(b.parent.tx.TxOut[u.Index].PkScript, b.senderScript) {
+			continue
+		}
+		if anchor == nil || u.Value > anchor.Value {
+			anchor = &utxo.UTXO{TxHash: u.TransactionHash, Index: uint32(u.Index), Value: u.Value}
+		}
+	}
+	if anchor == nil {
+		return nil, fmt.Errorf("transaction %s has no unspent output to the vault address", b.parent.txid)
+	}
+
+	fee := func(s utxo.Shape) int64 {
+		pkg := int64(b.parent.vsize+s.VSize)*b.feeRate - b.parent.fee
+		return max(pkg, int64(s.VSize)*incrementalRelayFee)
+	}
+	return b.fund([]utxo.UTXO{*anchor}, anchor.Value, nil, 0, fee)
+}
+
+// fund pays payments from inputs plus as many spare coins as needed, with
+// change to the vault inserted at changeAt when it clears the dust limit.
+func (b *feeBump) fund(inputs []utxo.UTXO, totalIn int64, payments []utxo.Output, changeAt int, fee utxo.FeeFunc) (*utxo.Selection, error) {
+	var totalOut int64
+	for _, p := range payments {
+		totalOut += p.Value
+	}
+
+	spare := b.spare
+	for {
+		withChange := make([]utxo.Output, 0, len(payments)+1)
+		withChange = append(withChange, payments[:changeAt]...)
+		withChange = append(withChange, utxo.Output{PkScript: b.senderScript})
+		withChange = append(withChange, payments[changeAt:]...)
+
+		shape := utxo.ShapeOf(b.inputType, len(inputs), withChange, b.params.extraBytes)
+		f := fee(shape)
+		if change := totalIn - totalOut - f; change >= b.params.dustLimit {
+			withChange[changeAt].Value = change
+			return &utxo.Selection{
+				Inputs: inputs, Outputs: withChange, ChangeIndex: changeAt,
+				Change: change, Fee: f, Shape: shape,
+			}, nil
+		}
+
+		// Outputs must not be empty, so a child always keeps its change.
+		if len(payments) > 0 {
+			shape = utxo.ShapeOf(b.inputType, len(inputs), payments, b.params.extraBytes)
+			if f = fee(shape); totalIn-totalOut >= f {
+				return &utxo.Selection{
+					Inputs: inputs, Outputs: payments, ChangeIndex: -1,
+					Fee: totalIn - totalOut, Shape: shape,
+				}, nil
+			}
+		}
+
+		if len(spare) == 0 {
+			return nil, fmt.Errorf("insufficient funds: %d available, need more than %d for outputs and fee", totalIn, totalOut+f)
+		}
+		inputs = append(inputs, spare[0])
+		totalIn += spare[0].Value
+		spare = spare[1:]
+	}
+}
+
+func roundRate(r float64) float64 {
+	return math.Round(r*100) / 100
+}
@@ -0,0 +1,263 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/vultisig/mcp/internal/blockchair"
+)
+
+const feeBumpRecipient = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+
+// feeBumpFixture is a pending vault send of 60000 sats with change, funded
+// by a 100000 sat coin, plus a confirmed 50000 sat spare coin.
+type feeBumpFixture struct {
+	parent    *wire.MsgTx
+	parentFee int64
+	spare     *wire.MsgTx
+	client    *blockchair.Client
+}
+
+func newFeeBumpFixture(t *testing.T, senderAddr string, sequence uint32, parentBlock int64) *feeBumpFixture {
+	t.Helper()
+	senderScript, err := utxoChains["Bitcoin"].addressToPkScript(senderAddr)
+	if err != nil {
+		t.Fatalf("sender script: %v", err)
+	}
+	recipientScript, _ := utxoChains["Bitcoin"].addressToPkScript(feeBumpRecipient)
+
+	funding := wire.NewMsgTx(2)
+	funding.AddTxIn(&wire.TxIn{Sequence: wire.MaxTxInSequenceNum})
+	funding.AddTxOut(wire.NewTxOut(100_000, senderScript))
+
+	spare := wire.NewMsgTx(2)
+	spare.AddTxIn(&wire.TxIn{PreviousOutPoint: wire.OutPoint{Index: 1}, Sequence: wire.MaxTxInSequenceNum})
+	spare.AddTxOut(wire.NewTxOut(50_000, senderScript))
+
+	const parentFee = 200
+	parent := wire.NewMsgTx(2)
+	parent.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: funding.TxHash(), Index: 0},
+		Sequence:         sequence,
+		Witness:          wire.TxWitness{make([]byte, 72), make([]byte, 33)},
+	})
+	parent.AddTxOut(wire.NewTxOut(60_000, recipientScript))
+	parent.AddTxOut(wire.NewTxOut(100_000-60_000-parentFee, senderScript))
+
+	raw := make(map[string]string)
+	for _, tx := range []*wire.MsgTx{funding, spare, parent} {
+		var buf bytes.Buffer
+		err := tx.Serialize(&buf)
+		if err != nil {
+			t.Fatalf("serialize: %v", err)
+		}
+		raw[tx.TxHash().String()] = hex.EncodeToString(buf.Bytes())
+	}
+	parentID := parent.TxHash().String()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 4 || parts[0] != "bitcoin" {
+			http.NotFound(w, r)
+			return
+		}
+		key := parts[3]
+
+		var body any
+		switch parts[1] + "/" + parts[2] {
+		case "dashboards/address":
+			body = map[string]any{"data": map[string]any{key: map[string]any{"utxo": []map[string]any{
+				{"block_id": parentBlock, "transaction_hash": parentID, "index": 1, "value": parent.TxOut[1].Value},
+				{"block_id": 800001, "transaction_hash": spare.TxHash().String(), "index": 0, "value": 50_000},
+			}}}}
+		case "dashboards/transaction":
+			if key != parentID {
+				http.NotFound(w, r)
+				return
+			}
+			body = map[string]any{"data": map[string]any{key: map[string]any{"transaction": map[string]any{
+				"block_id": parentBlock, "fee": parentFee, "input_total": 100_000, "output_total": 100_000 - parentFee,
+			}}}}
+		case "raw/transaction":
+			hexTx, ok := raw[key]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			body = map[string]any{"data": map[string]any{key: map[string]any{"raw_transaction": hexTx}}}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(body)
+		if err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return &feeBumpFixture{
+		parent:    parent,
+		parentFee: parentFee,
+		spare:     spare,
+		client:    blockchair.NewClient(srv.URL),
+	}
+}
+
+func TestBuildUTXOFeeBump_RBF(t *testing.T) {
+	store := setupBTCVault(t)
+	senderAddr := deriveBTCAddress(t, store)
+	fx := newFeeBumpFixture(t, senderAddr, rbfSequence, -1)
+	handler := handleBuildUTXOFeeBump(store, fx.client)
+
+	res, err := handler(context.Background(), callToolReq("build_utxo_fee_bump", map[string]any{
+		"chain":    "Bitcoin",
+		"txid":     fx.parent.TxHash().String(),
+		"method":   "rbf",
+		"fee_rate": float64(10),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	tx := decodePSBT(t, result).UnsignedTx
+
+	if len(tx.TxIn) != 1 || tx.TxIn[0].PreviousOutPoint != fx.parent.TxIn[0].PreviousOutPoint {
+		t.Fatalf("replacement must spend the original input, got %+v", tx.TxIn)
+	}
+	if tx.TxIn[0].Sequence != rbfSequence {
+		t.Errorf("sequence = %#x, want RBF signal", tx.TxIn[0].Sequence)
+	}
+	if len(tx.TxOut) != 2 || tx.TxOut[0].Value != 60_000 || !bytes.Equal(tx.TxOut[0].PkScript, fx.parent.TxOut[0].PkScript) {
+		t.Fatalf("recipient not preserved: %+v", tx.TxOut)
+	}
+
+	fee := int64(result["fee"].(float64))
+	vsize := int64(result["vsize"].(float64))
+	if fee != vsize*10 {
+		t.Errorf("fee %d != vsize %d * 10", fee, vsize)
+	}
+	if want := fx.parent.TxOut[1].Value - (fee - fx.parentFee); tx.TxOut[1].Value != want {
+		t.Errorf("change = %d, want %d", tx.TxOut[1].Value, want)
+	}
+	if result["effective_fee_rate"].(float64) != 10 {
+		t.Errorf("effective_fee_rate = %v, want 10", result["effective_fee_rate"])
+	}
+	if _, ok := result["coin_selection"]; ok {
+		t.Error("unexpected coin_selection for a fee bump")
+	}
+}
+
+func TestBuildUTXOFeeBump_RBFAddsConfirmedCoin(t *testing.T) {
+	store := setupBTCVault(t)
+	senderAddr := deriveBTCAddress(t, store)
+	fx := newFeeBumpFixture(t, senderAddr, rbfSequence, -1)
+	handler := handleBuildUTXOFeeBump(store, fx.client)
+
+	// At 400 sat/vB even dropping the change leaves the fee short.
+	res, err := handler(context.Background(), callToolReq("build_utxo_fee_bump", map[string]any{
+		"chain":    "Bitcoin",
+		"txid":     fx.parent.TxHash().String(),
+		"fee_rate": float64(400),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	tx := decodePSBT(t, result).UnsignedTx
+
+	if len(tx.TxIn) != 2 || tx.TxIn[1].PreviousOutPoint.Hash != fx.spare.TxHash() {
+		t.Fatalf("expected the spare coin as a second input, got %+v", tx.TxIn)
+	}
+	if tx.TxOut[0].Value != 60_000 {
+		t.Errorf("recipient = %d, want 60000", tx.TxOut[0].Value)
+	}
+	fee := int64(result["fee"].(float64))
+	if in, out := int64(150_000), tx.TxOut[0].Value+tx.TxOut[1].Value; in-out != fee {
+		t.Errorf("inputs - outputs = %d, want fee %d", in-out, fee)
+	}
+}
+
+func TestBuildUTXOFeeBump_CPFP(t *testing.T) {
+	store := setupBTCVault(t)
+	senderAddr := deriveBTCAddress(t, store)
+	fx := newFeeBumpFixture(t, senderAddr, wire.MaxTxInSequenceNum, -1)
+	handler := handleBuildUTXOFeeBump(store, fx.client)
+
+	res, err := handler(context.Background(), callToolReq("build_utxo_fee_bump", map[string]any{
+		"chain":    "Bitcoin",
+		"txid":     fx.parent.TxHash().String(),
+		"method":   "cpfp",
+		"fee_rate": float64(20),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	tx := decodePSBT(t, result).UnsignedTx
+
+	want := wire.OutPoint{Hash: fx.parent.TxHash(), Index: 1}
+	if len(tx.TxIn) != 1 || tx.TxIn[0].PreviousOutPoint != want {
+		t.Fatalf("child must spend the parent's change, got %+v", tx.TxIn)
+	}
+	if len(tx.TxOut) != 1 {
+		t.Fatalf("outputs = %d, want 1", len(tx.TxOut))
+	}
+
+	parentVSize := int64(result["original_vsize"].(float64))
+	childFee := int64(result["fee"].(float64))
+	childVSize := int64(result["vsize"].(float64))
+	if want := 20*(parentVSize+childVSize) - fx.parentFee; childFee != want {
+		t.Errorf("child fee = %d, want %d", childFee, want)
+	}
+	if rate := result["effective_fee_rate"].(float64); rate < 20 || rate > 20.1 {
+		t.Errorf("package rate = %v, want 20", rate)
+	}
+	if tx.TxOut[0].Value != fx.parent.TxOut[1].Value-childFee {
+		t.Errorf("child output = %d", tx.TxOut[0].Value)
+	}
+}
+
+func TestBuildUTXOFeeBump_Errors(t *testing.T) {
+	store := setupBTCVault(t)
+	senderAddr := deriveBTCAddress(t, store)
+
+	tests := []struct {
+		name     string
+		sequence uint32
+		block    int64
+		args     map[string]any
+	}{
+		{"not signaling", wire.MaxTxInSequenceNum, -1, map[string]any{"method": "rbf", "fee_rate": float64(10)}},
+		{"confirmed", rbfSequence, 800002, map[string]any{"method": "cpfp", "fee_rate": float64(10)}},
+		{"rate below original", rbfSequence, -1, map[string]any{"fee_rate": float64(1)}},
+		{"no rbf on dogecoin", rbfSequence, -1, map[string]any{"chain": "Dogecoin", "fee_rate": float64(10)}},
+		{"keysign", rbfSequence, -1, map[string]any{"fee_rate": float64(10), "output_format": "keysign"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := newFeeBumpFixture(t, senderAddr, tt.sequence, tt.block)
+			args := map[string]any{"chain": "Bitcoin", "txid": fx.parent.TxHash().String()}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			res, err := handleBuildUTXOFeeBump(store, fx.client)(context.Background(), callToolReq("build_utxo_fee_bump", args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !res.IsError {
+				t.Fatalf("expected tool error, got %v", resultText(t, res))
+			}
+		})
+	}
+}
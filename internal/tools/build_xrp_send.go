@@ -8,10 +8,12 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	v1 "github.com/vultisig/commondata/go/vultisig/keysign/v1"
 	"github.com/vultisig/vultisig-go/address"
 	"github.com/vultisig/vultisig-go/common"
 	addresscodec "github.com/xyield/xrpl-go/address-codec"
 
+	"github.com/vultisig/mcp/internal/keysign"
 	"github.com/vultisig/mcp/internal/resolve"
 	"github.com/vultisig/mcp/internal/vault"
 	xrpclient "github.com/vultisig/mcp/internal/xrp"
@@ -39,6 +41,7 @@ func newBuildXRPSendTool() mcp.Tool {
 		mcp.WithString("from",
 			mcp.Description("Sender XRP address. Falls back to vault-derived address if omitted."),
 		),
+		withOutputFormat(),
 	)
 }
 
@@ -62,6 +65,11 @@ func handleBuildXRPSend(store *vault.Store, xrpClient *xrpclient.Client) server.
 		}
 
 		memo := req.GetString("memo", "")
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		explicit := req.GetString("from", "")
 
 		v := resolve.ResolveVault(ctx, req, store)
@@ -108,6 +116,25 @@ func handleBuildXRPSend(store *vault.Store, xrpClient *xrpclient.Client) server.
 			action = "swap"
 		}
 
+		if asKeysign {
+			coin, err := keysign.NewCoin(v, common.XRP, "XRP", 6, "")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			payload := keysign.NewPayload(v, coin, toAddr, amountStr)
+			payload.BlockchainSpecific = &v1.KeysignPayload_RippleSpecific{
+				RippleSpecific: &v1.RippleSpecific{
+					Sequence:           uint64(info.Sequence),
+					Gas:                feeDrops,
+					LastLedgerSequence: uint64(currentLedger + 100),
+				},
+			}
+			if memo != "" {
+				payload.Memo = &memo
+			}
+			return keysignToolResult("Ripple", action, payload)
+		}
+
 		result := map[string]any{
 			"chain":                "Ripple",
 			"action":               action,
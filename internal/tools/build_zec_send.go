@@ -4,33 +4,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/vultisig/vultisig-go/address"
 	"github.com/vultisig/vultisig-go/common"
 
-	"github.com/vultisig/mcp/internal/blockchair"
 	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/utxo"
+	"github.com/vultisig/mcp/internal/utxobackend"
 	"github.com/vultisig/mcp/internal/vault"
 )
 
 func newBuildZECSendTool() mcp.Tool {
 	return mcp.NewTool("build_zec_send",
 		mcp.WithDescription(
-			"Return Zcash transaction arguments for a send or swap. "+
-				"Validates addresses and returns parameters for the client to build the transaction. "+
-				"Fee is computed automatically using ZIP-317 on the client side. "+
+			"Build an unsigned Zcash v4 transaction for a send or swap. "+
+				"Fetches the sender's UTXOs, selects inputs, adds change and any OP_RETURN memo, "+
+				"and sets the ZIP-317 fee from the transaction's logical actions. "+
 				"Accepts inline vault keys (ecdsa_public_key, eddsa_public_key, chain_code) or falls back to set_vault_info session state.",
 		),
 		mcp.WithString("to_address",
-			mcp.Description("Recipient Zcash transparent address (t1... or t3...)"),
-			mcp.Required(),
+			mcp.Description("Recipient Zcash transparent address (t1... or t3...). Omit when using recipients."),
 		),
 		mcp.WithString("amount",
-			mcp.Description("Amount to send in zatoshis (1 ZEC = 100,000,000 zatoshis, decimal string)"),
-			mcp.Required(),
+			mcp.Description("Amount to send in zatoshis (1 ZEC = 100,000,000 zatoshis, decimal string). Omit when using recipients."),
 		),
 		mcp.WithString("memo",
 			mcp.Description("Optional OP_RETURN memo (e.g. MayaChain swap instruction, max 80 bytes)"),
@@ -38,23 +36,17 @@ func newBuildZECSendTool() mcp.Tool {
 		mcp.WithString("address",
 			mcp.Description("Sender Zcash address. Falls back to vault-derived address if omitted."),
 		),
+		withRecipients(),
+		withCoinSelection(),
+		withOutputFormat(),
 	)
 }
 
-func handleBuildZECSend(store *vault.Store, _ *blockchair.Client) server.ToolHandlerFunc {
+func handleBuildZECSend(store *vault.Store, utxoBackend utxobackend.Backend) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		toAddress, err := req.RequireString("to_address")
+		recipients, err := parseUTXORecipients(req)
 		if err != nil {
-			return mcp.NewToolResultError("missing to_address"), nil
-		}
-
-		amountStr, err := req.RequireString("amount")
-		if err != nil {
-			return mcp.NewToolResultError("missing amount"), nil
-		}
-		amount, parseErr := strconv.ParseInt(amountStr, 10, 64)
-		if parseErr != nil || amount <= 0 {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid amount: %q", amountStr)), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		memo := req.GetString("memo", "")
@@ -62,6 +54,16 @@ func handleBuildZECSend(store *vault.Store, _ *blockchair.Client) server.ToolHan
 			return mcp.NewToolResultError(fmt.Sprintf("memo too long: %d bytes (max 80)", len(memo))), nil
 		}
 
+		asKeysign, err := keysignRequested(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		strategy, err := utxo.ParseStrategy(req.GetString("coin_selection", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		explicitAddr := req.GetString("address", "")
 
 		v := resolve.ResolveVault(ctx, req, store)
@@ -69,7 +71,7 @@ func handleBuildZECSend(store *vault.Store, _ *blockchair.Client) server.ToolHan
 			return mcp.NewToolResultError("no vault info available — pass vault keys inline or call set_vault_info"), nil
 		}
 
-		senderAddr, _, _, err := address.GetAddress(v.ECDSAPublicKey, v.ChainCode, common.Zcash)
+		senderAddr, senderPubKey, _, err := address.GetAddress(v.ECDSAPublicKey, v.ChainCode, common.Zcash)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("derive Zcash address: %v", err)), nil
 		}
@@ -79,9 +81,9 @@ func handleBuildZECSend(store *vault.Store, _ *blockchair.Client) server.ToolHan
 		}
 
 		zcashChain := utxoChains["Zcash"]
-		_, err = zcashChain.addressToPkScript(toAddress)
+		payments, err := recipients.outputs(zcashChain)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid to_address: %v", err)), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		action := "transfer"
@@ -89,16 +91,38 @@ func handleBuildZECSend(store *vault.Store, _ *blockchair.Client) server.ToolHan
 			action = "swap"
 		}
 
+		btx, err := buildUTXOTx(ctx, utxoBackend, utxoSendParams{
+			chain:     "Zcash",
+			sender:    senderAddr,
+			pubKeyHex: senderPubKey,
+			payments:  payments,
+			memo:      memo,
+			fee:       utxo.ZIP317Fee,
+			strategy:  strategy,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("build transaction: %v", err)), nil
+		}
+
+		if asKeysign {
+			if len(recipients) > 1 {
+				return mcp.NewToolResultError("keysign output supports a single recipient; omit output_format for batched sends"), nil
+			}
+			payload, err := utxoKeysignPayload(v, "Zcash", recipients[0].Address, recipients[0].Amount, 0, memo, btx.selection.Inputs)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build keysign payload: %v", err)), nil
+			}
+			return keysignToolResult("Zcash", action, payload)
+		}
+
 		result := map[string]any{
-			"chain":       "Zcash",
-			"action":      action,
-			"from":        senderAddr,
-			"to":          toAddress,
-			"amount":      amount,
-			"memo":        memo,
-			"fee_note":    "fee computed automatically via ZIP-317 by the client",
-			"tx_encoding": "zcash_v4",
+			"chain":  "Zcash",
+			"action": action,
+			"from":   senderAddr,
+			"memo":   memo,
 		}
+		recipients.addTo(result)
+		btx.addTo(result)
 
 		data, err := json.Marshal(result)
 		if err != nil {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
@@ -12,6 +14,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
+	"github.com/vultisig/mcp/internal/abiregistry"
 	evmclient "github.com/vultisig/mcp/internal/evm"
 )
 
@@ -19,7 +22,9 @@ func newEVMCallTool() mcp.Tool {
 	return mcp.NewTool("evm_call",
 		mcp.WithDescription(
 			"Execute an eth_call (read-only) against a contract on any EVM chain. "+
-				"Returns raw hex output, and optionally decodes it if output_types is provided.",
+				"Returns raw hex output, decoded by output_types if provided. "+
+				"Otherwise, when the contract's ABI is known from the explorer or evm_import_abi, the output is decoded by the called function's named return values. "+
+				"A revert is returned as an error with a decoded revert field: the Error(string) reason, Panic code or custom error and its arguments.",
 		),
 		mcp.WithString("chain",
 			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
@@ -43,16 +48,16 @@ func newEVMCallTool() mcp.Tool {
 			mcp.Description("Block number (decimal) or \"latest\" (default \"latest\")."),
 		),
 		mcp.WithString("output_types",
-			mcp.Description("Comma-separated ABI types to decode the output (e.g. \"uint256,address\"). If omitted, only raw hex is returned."),
+			mcp.Description("Comma-separated ABI types to decode the output (e.g. \"uint256,address\"). If omitted, the contract's ABI is used when known."),
 		),
 	)
 }
 
-func handleEVMCall(pool *evmclient.Pool) server.ToolHandlerFunc {
+func handleEVMCall(pool *evmclient.Pool, registry *abiregistry.Registry, reverts *revertDecoder) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		chainName := req.GetString("chain", "Ethereum")
 
-		client, _, err := pool.Get(ctx, chainName)
+		client, chainID, err := pool.Get(ctx, chainName)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
 		}
@@ -106,6 +111,9 @@ func handleEVMCall(pool *evmclient.Pool) server.ToolHandlerFunc {
 
 		output, err := client.CallContract(ctx, msg, blockNum)
 		if err != nil {
+			if revert, ok := reverts.fromError(ctx, client, chainID.Uint64(), &to, err); ok {
+				return revertResult("eth_call failed", revert), nil
+			}
 			return mcp.NewToolResultError(fmt.Sprintf("eth_call failed: %v", err)), nil
 		}
 
@@ -127,6 +135,20 @@ func handleEVMCall(pool *evmclient.Pool) server.ToolHandlerFunc {
 				formatted[i] = formatABIValue(v)
 			}
 			resp["decoded"] = formatted
+		} else if len(calldata) >= 4 {
+			// The ABI only adds names here, so a failed lookup leaves the
+			// raw result.
+			contract, err := registry.Contract(ctx, chainID.Uint64(), to, client)
+			if err != nil {
+				if !errors.Is(err, abiregistry.ErrNotFound) {
+					log.Printf("[evm_call] ABI lookup for %s on %s: %v", to.Hex(), chainName, err)
+				}
+			} else if method, err := contract.ABI.MethodById(calldata[:4]); err == nil && len(method.Outputs) > 0 {
+				if values, err := method.Outputs.UnpackValues(output); err == nil {
+					resp["function"] = method.Sig
+					resp["decoded"] = renderArguments(method.Outputs, values, "output")
+				}
+			}
 		}
 
 		data, err := json.Marshal(resp)
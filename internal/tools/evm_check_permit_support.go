@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+func newEVMCheckPermitSupportTool() mcp.Tool {
+	return mcp.NewTool("evm_check_permit_support",
+		mcp.WithDescription(
+			"Check whether an ERC-20 token can be approved with an off-chain signature instead of an approve transaction. "+
+				"Reports EIP-2612 permit support (domain name, version and the owner's nonce), the owner's ERC-20 allowance "+
+				"to Uniswap Permit2 and, if spender is given, the Permit2 allowance and nonce for that spender. "+
+				"Use build_permit to create the permit to sign.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("token",
+			mcp.Description("ERC-20 token contract address (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("owner",
+			mcp.Description("Token holder address (0x-prefixed). Optional if vault info is set."),
+		),
+		mcp.WithString("spender",
+			mcp.Description("Contract that will use the permit (e.g. a DEX router). Optional; needed for the Permit2 allowance."),
+		),
+	)
+}
+
+func handleEVMCheckPermitSupport(store *vault.Store, pool *evmclient.Pool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		tokenStr, err := req.RequireString("token")
+		if err != nil {
+			return mcp.NewToolResultError("missing token parameter"), nil
+		}
+		if !common.IsHexAddress(tokenStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid token address: %s", tokenStr)), nil
+		}
+		token := common.HexToAddress(tokenStr)
+
+		spenderStr := req.GetString("spender", "")
+		if spenderStr != "" && !common.IsHexAddress(spenderStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid spender: %s", spenderStr)), nil
+		}
+		explicit := req.GetString("owner", "")
+		if explicit != "" && !common.IsHexAddress(explicit) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid owner: %s", explicit)), nil
+		}
+		ownerStr, err := resolve.EVMAddress(explicit, resolve.ResolveVault(ctx, req, store))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		owner := common.HexToAddress(ownerStr)
+
+		client, chainID, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+
+		symbol, decimals, err := client.TokenMetadata(ctx, token.Hex())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to read token %s: %v", token.Hex(), err)), nil
+		}
+
+		support, err := client.EIP2612Support(ctx, token, chainID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to check permit support: %v", err)), nil
+		}
+		eip2612 := map[string]any{"supported": support.Supported}
+		if support.Supported {
+			nonce, err := client.PermitNonce(ctx, token, owner)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to read permit nonce: %v", err)), nil
+			}
+			eip2612["domain_name"] = support.Name
+			eip2612["domain_version"] = support.Version
+			eip2612["nonce"] = nonce.String()
+		} else {
+			eip2612["reason"] = support.Reason
+		}
+
+		erc20Allowance, err := client.Allowance(ctx, token.Hex(), owner.Hex(), evmclient.Permit2Address.Hex())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get allowance: %v", err)), nil
+		}
+		permit2 := map[string]any{
+			"address":                   evmclient.Permit2Address.Hex(),
+			"erc20_allowance":           erc20Allowance.String(),
+			"erc20_allowance_formatted": evmclient.FormatUnits(erc20Allowance, int(decimals)),
+			"approved":                  erc20Allowance.Sign() > 0,
+		}
+		if spenderStr != "" {
+			allowance, err := client.Permit2Allowance(ctx, owner, token, common.HexToAddress(spenderStr))
+			if err != nil {
+				permit2["available"] = false
+				permit2["reason"] = fmt.Sprintf("Permit2 is not deployed or not readable on %s: %v", chainName, err)
+			} else {
+				permit2["spender"] = common.HexToAddress(spenderStr).Hex()
+				permit2["allowance"] = allowance.Amount.String()
+				permit2["allowance_formatted"] = evmclient.FormatUnits(allowance.Amount, int(decimals))
+				permit2["expiration"] = allowance.Expiration
+				permit2["nonce"] = allowance.Nonce
+			}
+		}
+
+		recommended := ""
+		switch {
+		case support.Supported:
+			recommended = evmclient.PermitKindEIP2612
+		case erc20Allowance.Sign() > 0 && permit2["available"] != false:
+			recommended = evmclient.PermitKindPermit2
+		}
+
+		result := map[string]any{
+			"chain":            chainName,
+			"token":            token.Hex(),
+			"symbol":           symbol,
+			"decimals":         decimals,
+			"owner":            owner.Hex(),
+			"eip2612":          eip2612,
+			"permit2":          permit2,
+			"recommended_kind": recommended,
+		}
+		if recommended == "" {
+			result["note"] = "no gasless approval available: approve the spender on-chain, or approve Permit2 once to use permit2 from then on"
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
@@ -0,0 +1,184 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/vultisig/mcp/internal/abiregistry"
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/fourbyte"
+)
+
+func newEVMDecodeCalldataTool() mcp.Tool {
+	return mcp.NewTool("evm_decode_calldata",
+		mcp.WithDescription(
+			"Explain what calldata sent to a contract does: the function called and its arguments by name. "+
+				"Uses the contract's verified ABI from the block explorer or one stored with evm_import_abi, following proxies to their implementation. "+
+				"Falls back to 4byte.directory signatures, which lack parameter names and can be ambiguous; the result's source says which was used.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("to",
+			mcp.Description("Contract the calldata is sent to (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("data",
+			mcp.Description("Hex-encoded calldata (0x-prefixed), starting with the 4-byte function selector."),
+			mcp.Required(),
+		),
+	)
+}
+
+func handleEVMDecodeCalldata(pool *evmclient.Pool, registry *abiregistry.Registry, fbClient *fourbyte.Client) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		toStr, err := req.RequireString("to")
+		if err != nil {
+			return mcp.NewToolResultError("missing to parameter"), nil
+		}
+		if !common.IsHexAddress(toStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid to address: %s", toStr)), nil
+		}
+		to := common.HexToAddress(toStr)
+
+		dataHex, err := req.RequireString("data")
+		if err != nil {
+			return mcp.NewToolResultError("missing data parameter"), nil
+		}
+		calldata, err := hexToBytes(dataHex)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid data hex: %v", err)), nil
+		}
+		if len(calldata) < 4 {
+			return mcp.NewToolResultError("data must start with a 4-byte function selector; empty calldata is a plain native transfer"), nil
+		}
+		selector := "0x" + hex.EncodeToString(calldata[:4])
+
+		client, chainID, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+
+		resp := map[string]any{
+			"chain":    chainName,
+			"to":       to.Hex(),
+			"selector": selector,
+		}
+
+		contract, err := registry.Contract(ctx, chainID.Uint64(), to, client)
+		switch {
+		case err == nil:
+			resp["contract"] = contractInfo(contract)
+			method, args, err := decodeCalldata(contract.ABI, calldata)
+			if err == nil {
+				resp["function"] = method.RawName
+				resp["signature"] = method.Sig
+				resp["args"] = args
+				resp["source"] = contract.Source
+				return marshalDecodedCalldata(resp)
+			}
+			resp["abi_error"] = err.Error()
+		case !errors.Is(err, abiregistry.ErrNotFound):
+			log.Printf("[evm_decode_calldata] ABI lookup for %s on %s: %v", to.Hex(), chainName, err)
+			resp["abi_error"] = err.Error()
+		}
+
+		sigs, err := fbClient.ResolveSelector(ctx, selector)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("no ABI for %s and 4byte lookup failed: %v", to.Hex(), err)), nil
+		}
+		matches := decodeWithSignatures(sigs, calldata)
+		if len(matches) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("selector %s is not in the contract's ABI and no known signature decodes its arguments", selector)), nil
+		}
+
+		best := matches[0]
+		resp["function"] = best.method.RawName
+		resp["signature"] = best.method.Sig
+		resp["args"] = best.args
+		resp["source"] = "4byte"
+		resp["note"] = "Matched by function selector only: parameter names are unknown and another function could share the selector."
+		if len(matches) > 1 {
+			alternatives := make([]string, len(matches)-1)
+			for i, m := range matches[1:] {
+				alternatives[i] = m.method.Sig
+			}
+			resp["alternatives"] = alternatives
+		}
+		return marshalDecodedCalldata(resp)
+	}
+}
+
+func marshalDecodedCalldata(resp map[string]any) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("marshal evm_decode_calldata result: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+type signatureMatch struct {
+	method abi.Method
+	args   map[string]any
+}
+
+// decodeWithSignatures returns the 4byte signatures that decode calldata,
+// oldest registration first since later ones are more often deliberate
+// collisions. A signature only matches if its arguments re-encode to the
+// exact calldata.
+func decodeWithSignatures(sigs []fourbyte.Signature, calldata []byte) []signatureMatch {
+	sort.Slice(sigs, func(i, j int) bool { return sigs[i].ID < sigs[j].ID })
+	var matches []signatureMatch
+	for _, sig := range sigs {
+		method, err := parseFunctionSignature(sig.TextSignature)
+		if err != nil || !bytes.Equal(method.ID, calldata[:4]) {
+			continue
+		}
+		values, err := method.Inputs.UnpackValues(calldata[4:])
+		if err != nil {
+			continue
+		}
+		packed, err := method.Inputs.Pack(values...)
+		if err != nil || !bytes.Equal(packed, calldata[4:]) {
+			continue
+		}
+		matches = append(matches, signatureMatch{
+			method: method,
+			args:   renderArguments(method.Inputs, values, "arg"),
+		})
+	}
+	return matches
+}
+
+// contractInfo describes a contract whose ABI came from the registry.
+func contractInfo(c *abiregistry.Contract) map[string]any {
+	info := map[string]any{
+		"address":    c.Address.Hex(),
+		"abi_source": c.Source,
+	}
+	if c.Name != "" {
+		info["name"] = c.Name
+	}
+	if c.IsProxy() {
+		info["proxy_kind"] = c.ProxyKind
+		info["implementation"] = c.Implementation.Hex()
+		if c.ImplementationName != "" {
+			info["implementation_name"] = c.ImplementationName
+		}
+	}
+	return info
+}
@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/vultisig/mcp/internal/abiregistry"
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/fourbyte"
+)
+
+const erc20TestABI = `[
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]}
+]`
+
+// eip1967Slot is the storage slot EIP-1967 proxies keep their
+// implementation in.
+const eip1967Slot = "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"
+
+// proxyStorage answers eth_getStorageAt with impl in proxy's EIP-1967 slot
+// and zero everywhere else.
+func proxyStorage(proxy, impl string) func(json.RawMessage) any {
+	return func(params json.RawMessage) any {
+		var args []string
+		_ = json.Unmarshal(params, &args)
+		if strings.EqualFold(args[0], proxy) && args[1] == eip1967Slot {
+			return ethcommon.BytesToHash(ethcommon.HexToAddress(impl).Bytes()).Hex()
+		}
+		return ethcommon.Hash{}.Hex()
+	}
+}
+
+func transferCalldata(to string, amount int64) string {
+	return fmt.Sprintf("0xa9059cbb%064s%064x", strings.ToLower(to[2:]), amount)
+}
+
+func TestEVMDecodeCalldata_ImportedABI(t *testing.T) {
+	registry := abiregistry.NewMemoryRegistry(nil)
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_getStorageAt": proxyStorage("", ""),
+		"eth_call":         "0x",
+	})
+	handler := handleEVMImportABI(registry)
+	res, err := handler(context.Background(), callToolReq("evm_import_abi", map[string]any{
+		"address": usdt,
+		"abi":     erc20TestABI,
+		"name":    "TetherToken",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	imported := decodeResult(t, res)
+	if fns := imported["functions"].([]any); len(fns) != 2 || fns[0] != "balanceOf(address)" {
+		t.Errorf("functions = %v", fns)
+	}
+
+	res, err = handleEVMDecodeCalldata(pool, registry, nil)(context.Background(), callToolReq("evm_decode_calldata", map[string]any{
+		"to":   usdt,
+		"data": transferCalldata(testAddress, 2_500_000),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	args := result["args"].(map[string]any)
+	if result["function"] != "transfer" || result["source"] != abiregistry.SourceImport ||
+		!strings.EqualFold(args["to"].(string), testAddress) || args["amount"] != "2500000" {
+		t.Errorf("result = %v", result)
+	}
+	if contract := result["contract"].(map[string]any); contract["name"] != "TetherToken" || contract["implementation"] != nil {
+		t.Errorf("contract = %v", contract)
+	}
+}
+
+func TestEVMDecodeCalldata_Proxy(t *testing.T) {
+	impl := "0x43506849D7C04F9138D1A2050bbF3A0c054402dd"
+	registry := abiregistry.NewMemoryRegistry(nil)
+	if _, err := registry.Import(1, ethcommon.HexToAddress(impl), "FiatTokenV2", []byte(erc20TestABI)); err != nil {
+		t.Fatal(err)
+	}
+	pool, _ := mockEVMPool(t, map[string]any{"eth_getStorageAt": proxyStorage(usdt, impl)})
+
+	res, err := handleEVMDecodeCalldata(pool, registry, nil)(context.Background(), callToolReq("evm_decode_calldata", map[string]any{
+		"to":   usdt,
+		"data": transferCalldata(permit2, 1),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	contract := result["contract"].(map[string]any)
+	if result["signature"] != "transfer(address,uint256)" || contract["implementation"] != impl ||
+		contract["proxy_kind"] != evmclient.ProxyEIP1967 || contract["implementation_name"] != "FiatTokenV2" {
+		t.Errorf("result = %v", result)
+	}
+}
+
+func TestDecodeWithSignatures(t *testing.T) {
+	calldata, _ := hexToBytes(transferCalldata(testAddress, 7))
+	matches := decodeWithSignatures([]fourbyte.Signature{
+		// Same selector, but the calldata does not re-encode as a string.
+		{ID: 3, TextSignature: "many_msg_babbage(bytes1)"},
+		{ID: 2, TextSignature: "transfer(address,uint256)"},
+		{ID: 1, TextSignature: "approve(address,uint256)"},
+	}, calldata)
+	if len(matches) != 1 || matches[0].method.Sig != "transfer(address,uint256)" || matches[0].args["arg1"] != "7" {
+		t.Fatalf("matches = %+v", matches)
+	}
+}
+
+func TestEVMCall_DecodesWithRegistryABI(t *testing.T) {
+	registry := abiregistry.NewMemoryRegistry(nil)
+	if _, err := registry.Import(1, ethcommon.HexToAddress(usdt), "", []byte(erc20TestABI)); err != nil {
+		t.Fatal(err)
+	}
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_getStorageAt": proxyStorage("", ""),
+		"eth_call":         fmt.Sprintf("0x%064x", big.NewInt(42)),
+	})
+	data := fmt.Sprintf("0x70a08231%064s", strings.ToLower(testAddress[2:]))
+
+	res, err := handleEVMCall(pool, registry, nil)(context.Background(), callToolReq("evm_call", map[string]any{
+		"to":   usdt,
+		"data": data,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	decoded, _ := json.Marshal(result["decoded"])
+	if result["function"] != "balanceOf(address)" || string(decoded) != `{"balance":"42"}` {
+		t.Errorf("result = %v", result)
+	}
+
+	res, err = handleABIDecode(pool, registry)(context.Background(), callToolReq("abi_decode", map[string]any{
+		"data":     fmt.Sprintf("0x%064x", 42),
+		"contract": usdt,
+		"method":   "balanceOf",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outputs, _ := json.Marshal(decodeResult(t, res)["outputs"])
+	if string(outputs) != `{"balance":"42"}` {
+		t.Errorf("abi_decode outputs = %s", outputs)
+	}
+}
+
+func TestABIDecode_NoContractABI(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{})
+	res, err := handleABIDecode(pool, abiregistry.NewMemoryRegistry(nil))(context.Background(), callToolReq("abi_decode", map[string]any{
+		"data":     transferCalldata(testAddress, 1),
+		"contract": usdt,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(mcp.TextContent).Text, "evm_import_abi") {
+		t.Errorf("expected a tool error pointing at evm_import_abi, got %v", res.Content)
+	}
+}
@@ -0,0 +1,290 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/vultisig/mcp/internal/coingecko"
+	evmclient "github.com/vultisig/mcp/internal/evm"
+)
+
+const (
+	gasOracleDefaultBlocks = 20
+	gasOracleMaxBlocks     = 100
+)
+
+// gasOracleSampleRecipient receives the sample transfers whose rollup fees
+// are quoted.
+var gasOracleSampleRecipient = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+func newEVMGasOracleTool() mcp.Tool {
+	return mcp.NewTool("evm_gas_oracle",
+		mcp.WithDescription(
+			"Suggest slow, standard and fast EIP-1559 fees from eth_feeHistory on one or more EVM chains. "+
+				"Tips are the 10th, 50th and 90th percentile priority fees of recent blocks; max fees add headroom over the projected next base fee. "+
+				"Includes the estimated USD cost of a native and an ERC-20 transfer at the standard tier, and the cheapest chain for an ERC-20 transfer. "+
+				"On rollups the transfer costs include the L1 data fee, quoted for sample transfers by the chain's fee model. "+
+				"Queries every configured EVM chain unless chains is set.",
+		),
+		mcp.WithArray("chains",
+			mcp.Description("EVM chain names to compare. Any of: "+chainEnumDesc()+". All configured chains if omitted."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("blocks",
+			mcp.Description(fmt.Sprintf("Number of recent blocks to sample (default %d, max %d).", gasOracleDefaultBlocks, gasOracleMaxBlocks)),
+		),
+	)
+}
+
+type gasTierJSON struct {
+	MaxPriorityFeePerGas     string `json:"max_priority_fee_per_gas"`
+	MaxPriorityFeePerGasGwei string `json:"max_priority_fee_per_gas_gwei"`
+	MaxFeePerGas             string `json:"max_fee_per_gas"`
+	MaxFeePerGasGwei         string `json:"max_fee_per_gas_gwei"`
+}
+
+type transferCostJSON struct {
+	Gas uint64 `json:"gas"`
+	// Fee is the expected cost at the next base fee plus the standard tip;
+	// MaxFee the most the standard tier can cost.
+	Fee          string   `json:"fee"`
+	FeeFormatted string   `json:"fee_formatted"`
+	MaxFee       string   `json:"max_fee"`
+	FeeUSD       *float64 `json:"fee_usd,omitempty"`
+	// L1Fee is the rollup's L1 data fee, included in Fee and MaxFee.
+	L1Fee string `json:"l1_fee,omitempty"`
+}
+
+type chainGasJSON struct {
+	Chain           string                      `json:"chain"`
+	ChainID         string                      `json:"chain_id,omitempty"`
+	Ticker          string                      `json:"ticker"`
+	FeeModel        string                      `json:"fee_model,omitempty"`
+	LatestBlock     uint64                      `json:"latest_block,omitempty"`
+	BlocksSampled   int                         `json:"blocks_sampled,omitempty"`
+	BaseFeeGwei     string                      `json:"base_fee_gwei,omitempty"`
+	NextBaseFeeGwei string                      `json:"next_base_fee_gwei,omitempty"`
+	GasUsedRatio    float64                     `json:"gas_used_ratio,omitempty"`
+	Tiers           map[string]gasTierJSON      `json:"tiers,omitempty"`
+	TransferCost    map[string]transferCostJSON `json:"transfer_cost,omitempty"`
+	PriceUSD        float64                     `json:"price_usd,omitempty"`
+	Note            string                      `json:"note,omitempty"`
+	RollupFeeError  string                      `json:"rollup_fee_error,omitempty"`
+	Error           string                      `json:"error,omitempty"`
+}
+
+func handleEVMGasOracle(pool *evmclient.Pool, cgClient *coingecko.Client) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chains := req.GetStringSlice("chains", nil)
+		if len(chains) == 0 {
+			chains = pool.Chains()
+		}
+		seen := make(map[string]bool, len(chains))
+		for _, chainName := range chains {
+			if _, ok := evmclient.ChainIDByName(chainName); !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("unsupported chain: %s", chainName)), nil
+			}
+			if seen[chainName] {
+				return mcp.NewToolResultError(fmt.Sprintf("duplicate chain: %s", chainName)), nil
+			}
+			seen[chainName] = true
+		}
+		if len(chains) == 0 {
+			return mcp.NewToolResultError("no EVM chains configured"), nil
+		}
+
+		blocks := int(req.GetFloat("blocks", gasOracleDefaultBlocks))
+		if blocks < 1 || blocks > gasOracleMaxBlocks {
+			return mcp.NewToolResultError(fmt.Sprintf("blocks must be between 1 and %d", gasOracleMaxBlocks)), nil
+		}
+
+		results := make([]chainGasJSON, len(chains))
+		oracles := make([]*evmclient.FeeOracle, len(chains))
+		rollups := make([]map[string]*evmclient.RollupFee, len(chains))
+		var wg sync.WaitGroup
+		for i, chainName := range chains {
+			wg.Add(1)
+			go func(i int, chainName string) {
+				defer wg.Done()
+				results[i] = chainGasJSON{Chain: chainName, Ticker: evmclient.NativeTicker(chainName)}
+				client, chainID, err := pool.Get(ctx, chainName)
+				if err != nil {
+					results[i].Error = err.Error()
+					return
+				}
+				results[i].ChainID = chainID.String()
+				oracle, err := client.FeeOracle(ctx, blocks)
+				if err != nil {
+					results[i].Error = err.Error()
+					return
+				}
+				oracles[i] = oracle
+				if model := evmclient.FeeModel(chainName); model != evmclient.FeeModelL1 {
+					results[i].FeeModel = model
+					rollups[i], err = transferRollupFees(ctx, client, chainName, chainID, oracle)
+					if err != nil {
+						results[i].RollupFeeError = err.Error()
+					}
+				}
+			}(i, chainName)
+		}
+		wg.Wait()
+
+		prices := make(map[string]float64)
+		for i, oracle := range oracles {
+			if oracle == nil {
+				continue
+			}
+			r := &results[i]
+			id, known := nativePriceID(r.Chain)
+			price, ok := prices[id]
+			if !ok {
+				if known {
+					if pd, err := cgClient.GetSimplePrice(ctx, id); err == nil {
+						price = pd.USD
+					}
+				}
+				prices[id] = price
+			}
+			fillChainGas(r, oracle, rollups[i], price)
+		}
+
+		cheapest := ""
+		best := math.Inf(1)
+		for _, r := range results {
+			cost, ok := r.TransferCost["erc20"]
+			if ok && cost.FeeUSD != nil && *cost.FeeUSD < best {
+				best, cheapest = *cost.FeeUSD, r.Chain
+			}
+		}
+
+		out := map[string]any{
+			"chains": results,
+		}
+		if cheapest != "" {
+			out["cheapest_chain"] = cheapest
+			out["cheapest_erc20_transfer_usd"] = best
+		}
+		data, err := json.Marshal(out)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// fillChainGas copies a chain's fee oracle into its result and prices the
+// transfer costs, with their rollup fees if any, at price USD per native
+// coin (zero if unknown).
+func fillChainGas(r *chainGasJSON, o *evmclient.FeeOracle, rollup map[string]*evmclient.RollupFee, price float64) {
+	r.LatestBlock = o.LatestBlock
+	r.BlocksSampled = o.Blocks
+	r.BaseFeeGwei = evmclient.FormatUnits(o.BaseFee, 9)
+	r.NextBaseFeeGwei = evmclient.FormatUnits(o.NextBaseFee, 9)
+	r.GasUsedRatio = math.Round(o.GasUsedRatio*10_000) / 10_000
+	r.PriceUSD = price
+	if o.TipSamples == 0 {
+		r.Note = "recent blocks were empty; tips fall back to the node's eth_maxPriorityFeePerGas"
+	}
+
+	r.Tiers = make(map[string]gasTierJSON, 3)
+	for name, tier := range map[string]evmclient.FeeTier{"slow": o.Slow, "standard": o.Standard, "fast": o.Fast} {
+		r.Tiers[name] = gasTierJSON{
+			MaxPriorityFeePerGas:     tier.MaxPriorityFeePerGas.String(),
+			MaxPriorityFeePerGasGwei: evmclient.FormatUnits(tier.MaxPriorityFeePerGas, 9),
+			MaxFeePerGas:             tier.MaxFeePerGas.String(),
+			MaxFeePerGasGwei:         evmclient.FormatUnits(tier.MaxFeePerGas, 9),
+		}
+	}
+
+	// The expected price is the next base fee plus the tip, capped by the
+	// max fee.
+	expected := new(big.Int).Add(o.NextBaseFee, o.Standard.MaxPriorityFeePerGas)
+	if expected.Cmp(o.Standard.MaxFeePerGas) > 0 {
+		expected.Set(o.Standard.MaxFeePerGas)
+	}
+	r.TransferCost = make(map[string]transferCostJSON, 2)
+	for name, gas := range map[string]uint64{"native": evmclient.GasNativeTransfer, "erc20": evmclient.GasERC20Transfer} {
+		var l1Fee string
+		if f := rollup[name]; f != nil {
+			// Arbitrum charges the L1 data as extra gas; zkSync's estimate
+			// covers pubdata on top of execution.
+			switch f.Model {
+			case evmclient.FeeModelArbitrum:
+				gas += f.L1Gas
+			case evmclient.FeeModelZkSync:
+				gas = max(gas, f.GasEstimate)
+			}
+			if f.L1Fee != nil {
+				l1Fee = f.L1Fee.String()
+			}
+		}
+		extra := rollup[name].ExtraFee()
+		g := new(big.Int).SetUint64(gas)
+		fee := new(big.Int).Mul(expected, g)
+		fee.Add(fee, extra)
+		maxFee := new(big.Int).Mul(o.Standard.MaxFeePerGas, g)
+		cost := transferCostJSON{
+			Gas:          gas,
+			Fee:          fee.String(),
+			FeeFormatted: evmclient.FormatUnits(fee, 18) + " " + r.Ticker,
+			MaxFee:       maxFee.Add(maxFee, extra).String(),
+			L1Fee:        l1Fee,
+		}
+		if price > 0 {
+			usd := weiFeeUSD(fee, price)
+			cost.FeeUSD = &usd
+		}
+		r.TransferCost[name] = cost
+	}
+}
+
+// weiFeeUSD converts a fee in wei to USD at price per coin, keeping four
+// decimals like feeUSD.
+func weiFeeUSD(fee *big.Int, price float64) float64 {
+	coins, _ := new(big.Float).Quo(new(big.Float).SetInt(fee), big.NewFloat(1e18)).Float64()
+	return math.Round(coins*price*10_000) / 10_000
+}
+
+// transferRollupFees quotes the rollup fees of a sample native and ERC-20
+// transfer at o's standard tier. The samples go to an EOA, so the quote
+// covers their data and signature but not token contract execution.
+func transferRollupFees(ctx context.Context, client *evmclient.Client, chainName string, chainID *big.Int, o *evmclient.FeeOracle) (map[string]*evmclient.RollupFee, error) {
+	samples := map[string]struct {
+		gas  uint64
+		data []byte
+	}{
+		"native": {evmclient.GasNativeTransfer, nil},
+		"erc20":  {evmclient.GasERC20Transfer, evmclient.PackERC20Transfer(gasOracleSampleRecipient, big.NewInt(1_000_000))},
+	}
+	fees := make(map[string]*evmclient.RollupFee, len(samples))
+	for name, sample := range samples {
+		nonce, gas := uint64(0), sample.gas
+		p := &evmTxParams{
+			txType:         ethtypes.DynamicFeeTxType,
+			chainID:        chainID,
+			to:             gasOracleSampleRecipient,
+			value:          new(big.Int),
+			data:           sample.data,
+			nonce:          &nonce,
+			gasLimit:       &gas,
+			maxFee:         o.Standard.MaxFeePerGas,
+			maxPriorityFee: o.Standard.MaxPriorityFeePerGas,
+		}
+		fee, err := evmRollupFee(ctx, client, chainName, common.Address{}, p)
+		if err != nil {
+			return nil, err
+		}
+		fees[name] = fee
+	}
+	return fees, nil
+}
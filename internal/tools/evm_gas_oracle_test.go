@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEVMGasOracle(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_feeHistory": map[string]any{
+			"oldestBlock":   "0x10",
+			"baseFeePerGas": []string{"0x2540be400", "0x2540be400"},
+			"gasUsedRatio":  []float64{0.5},
+			"reward":        [][]string{{"0x3b9aca00", "0x77359400", "0xb2d05e00"}},
+		},
+	})
+	handler := handleEVMGasOracle(pool, mockCoinGeckoPrice(t, "ethereum", 2000))
+
+	res, err := handler(context.Background(), callToolReq("evm_gas_oracle", map[string]any{
+		"chains": []any{"Ethereum"},
+		"blocks": float64(5),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	if result["cheapest_chain"] != "Ethereum" {
+		t.Errorf("cheapest_chain = %v", result["cheapest_chain"])
+	}
+	chain := result["chains"].([]any)[0].(map[string]any)
+	if chain["error"] != nil {
+		t.Fatalf("chain error: %v", chain["error"])
+	}
+	if chain["next_base_fee_gwei"] != "10" || chain["price_usd"] != float64(2000) {
+		t.Errorf("next base fee %v gwei, price %v", chain["next_base_fee_gwei"], chain["price_usd"])
+	}
+	standard := chain["tiers"].(map[string]any)["standard"].(map[string]any)
+	// 2 gwei tip plus 150% of the 10 gwei base fee.
+	if standard["max_priority_fee_per_gas_gwei"] != "2" || standard["max_fee_per_gas_gwei"] != "17" {
+		t.Errorf("standard tier = %v", standard)
+	}
+	// 12 gwei expected price: 21000 and 65000 gas at $2000/ETH.
+	costs := chain["transfer_cost"].(map[string]any)
+	for name, want := range map[string]float64{"native": 0.504, "erc20": 1.56} {
+		if got := costs[name].(map[string]any)["fee_usd"]; got != want {
+			t.Errorf("%s transfer fee_usd = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestEVMGasOracle_InvalidParams(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{})
+	handler := handleEVMGasOracle(pool, mockCoinGeckoPrice(t, "ethereum", 2000))
+
+	for name, args := range map[string]map[string]any{
+		"unknown chain":   {"chains": []any{"Dogecoin"}},
+		"duplicate chain": {"chains": []any{"Ethereum", "Ethereum"}},
+		"too many blocks": {"blocks": float64(1000)},
+	} {
+		res, err := handler(context.Background(), callToolReq("evm_gas_oracle", args))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+}
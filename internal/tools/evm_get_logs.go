@@ -0,0 +1,300 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+)
+
+const (
+	// getLogsMaxQueries caps the eth_getLogs requests per evm_get_logs call.
+	getLogsMaxQueries = 50
+	// defaultLogBlocks is the range searched when from_block is omitted.
+	defaultLogBlocks = 10_000
+	defaultLogsLimit = 100
+	maxLogsLimit     = 1_000
+)
+
+func newEVMGetLogsTool() mcp.Tool {
+	return mcp.NewTool("evm_get_logs",
+		mcp.WithDescription(
+			"Query historical event logs on an EVM chain, newest first, e.g. to find when an address last received a token. "+
+				"Filter by contract address and topics; pass event to filter on its topic and decode each log's indexed and non-indexed parameters. "+
+				"Large block ranges are split automatically to stay within RPC limits. "+
+				"If the range is not fully covered or more logs match than limit, next_to_block gives the to_block for the next page.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("address",
+			mcp.Description("Emitting contract address (0x-prefixed). Comma-separate several addresses to match any of them."),
+		),
+		mcp.WithString("event",
+			mcp.Description("Event to match and decode: a signature such as \"Transfer(address indexed from, address indexed to, uint256 value)\" "+
+				"or a JSON ABI fragment. Without indexed markers, the leading parameters are taken as indexed from each log's topic count."),
+		),
+		mcp.WithArray("topics",
+			mcp.Description("Topic filters by position (topic0 to topic3). Each entry is null or \"\" for any value, a 32-byte hex topic, "+
+				"an address (padded automatically), or a list of alternatives. When event is set, topic0 is filled in from it, "+
+				"so entries 1-3 filter its indexed parameters in order."),
+		),
+		mcp.WithString("from_block",
+			mcp.Description(fmt.Sprintf("First block to search, decimal (default: the last %d blocks).", defaultLogBlocks)),
+		),
+		mcp.WithString("to_block",
+			mcp.Description("Last block to search, decimal or \"latest\" (default \"latest\")."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum logs to return (default %d, max %d). Logs of the boundary block are never split across pages.", defaultLogsLimit, maxLogsLimit)),
+		),
+	)
+}
+
+type logJSON struct {
+	Address     string         `json:"address"`
+	BlockNumber uint64         `json:"block_number"`
+	TxHash      string         `json:"tx_hash"`
+	LogIndex    uint           `json:"log_index"`
+	Event       string         `json:"event,omitempty"`
+	Args        map[string]any `json:"args,omitempty"`
+	Topics      []string       `json:"topics,omitempty"`
+	Data        string         `json:"data,omitempty"`
+	DecodeError string         `json:"decode_error,omitempty"`
+}
+
+func handleEVMGetLogs(pool *evmclient.Pool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		var q ethereum.FilterQuery
+		if addrs := req.GetString("address", ""); addrs != "" {
+			for _, a := range strings.Split(addrs, ",") {
+				a = strings.TrimSpace(a)
+				if !common.IsHexAddress(a) {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid address: %s", a)), nil
+				}
+				q.Addresses = append(q.Addresses, common.HexToAddress(a))
+			}
+		}
+
+		topics, err := parseTopicFilters(req.GetArguments()["topics"])
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		var spec *eventSpec
+		if ev := req.GetString("event", ""); ev != "" {
+			spec, err = parseEvent(ev)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if len(topics) == 0 {
+				topics = make([][]common.Hash, 1)
+			}
+			if len(topics[0]) > 0 && (len(topics[0]) != 1 || topics[0][0] != spec.event.ID) {
+				return mcp.NewToolResultError(fmt.Sprintf("topics[0] conflicts with event %s (topic %s)", spec.event.Sig, spec.event.ID.Hex())), nil
+			}
+			topics[0] = []common.Hash{spec.event.ID}
+		}
+		q.Topics = topics
+		if len(q.Addresses) == 0 && len(q.Topics) == 0 {
+			return mcp.NewToolResultError("set address, event or topics to narrow the query"), nil
+		}
+
+		limit := int(req.GetFloat("limit", defaultLogsLimit))
+		if limit < 1 || limit > maxLogsLimit {
+			return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxLogsLimit)), nil
+		}
+
+		client, _, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+
+		toBlock, err := parseLogBlock(req.GetString("to_block", ""))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid to_block: %v", err)), nil
+		}
+		if toBlock < 0 {
+			latest, err := client.BlockNumber(ctx)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("get block number: %v", err)), nil
+			}
+			toBlock = int64(latest)
+		}
+		fromBlock, err := parseLogBlock(req.GetString("from_block", ""))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid from_block: %v", err)), nil
+		}
+		if fromBlock < 0 {
+			fromBlock = max(toBlock-defaultLogBlocks+1, 0)
+		}
+		if fromBlock > toBlock {
+			return mcp.NewToolResultError(fmt.Sprintf("from_block %d is after to_block %d", fromBlock, toBlock)), nil
+		}
+
+		scan, err := client.ScanLogsLimit(ctx, q, uint64(fromBlock), uint64(toBlock), getLogsMaxQueries, limit)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("get logs: %v", err)), nil
+		}
+
+		logs, truncated := newestLogs(scan.Logs, limit)
+		out := make([]logJSON, len(logs))
+		for i, lg := range logs {
+			out[i] = formatLog(lg, spec)
+		}
+
+		result := map[string]any{
+			"chain":      chainName,
+			"logs":       out,
+			"count":      len(out),
+			"from_block": scan.FromBlock,
+			"to_block":   scan.ToBlock,
+			"queries":    scan.Queries,
+		}
+		switch {
+		case truncated:
+			boundary := logs[len(logs)-1].BlockNumber
+			result["from_block"] = boundary
+			if boundary > uint64(fromBlock) {
+				result["next_to_block"] = boundary - 1
+			}
+		case scan.FromBlock > uint64(fromBlock):
+			result["next_to_block"] = scan.FromBlock - 1
+		}
+		result["complete"] = result["next_to_block"] == nil
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal evm_get_logs result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// parseLogBlock parses a decimal block number; "" and "latest" return -1.
+func parseLogBlock(s string) (int64, error) {
+	if s == "" || s == "latest" {
+		return -1, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("want a decimal block number or \"latest\", got %q", s)
+	}
+	return n, nil
+}
+
+// parseTopicFilters parses the topics parameter into an eth_getLogs topic
+// filter.
+func parseTopicFilters(raw any) ([][]common.Hash, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("topics must be a list")
+	}
+	if len(list) > 4 {
+		return nil, fmt.Errorf("at most 4 topics can be filtered, got %d", len(list))
+	}
+
+	topics := make([][]common.Hash, len(list))
+	for i, entry := range list {
+		var values []string
+		switch v := entry.(type) {
+		case nil:
+		case string:
+			if v != "" {
+				values = []string{v}
+			}
+		case []any:
+			for _, alt := range v {
+				s, ok := alt.(string)
+				if !ok {
+					return nil, fmt.Errorf("topics[%d]: alternatives must be strings", i)
+				}
+				values = append(values, s)
+			}
+		default:
+			return nil, fmt.Errorf("topics[%d]: want a string, null or a list of strings", i)
+		}
+		for _, s := range values {
+			h, err := parseTopic(s)
+			if err != nil {
+				return nil, fmt.Errorf("topics[%d]: %v", i, err)
+			}
+			topics[i] = append(topics[i], h)
+		}
+	}
+	return topics, nil
+}
+
+// parseTopic parses a 32-byte topic or a 20-byte address, which is
+// left-padded as indexed addresses are.
+func parseTopic(s string) (common.Hash, error) {
+	if common.IsHexAddress(s) {
+		return common.BytesToHash(common.HexToAddress(s).Bytes()), nil
+	}
+	b, err := hexToBytes(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("want a 32-byte hex topic or an address, got %q", s)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// newestLogs sorts logs newest first and keeps the first limit, plus any
+// more from the last kept block so a block is never split across pages.
+func newestLogs(logs []types.Log, limit int) ([]types.Log, bool) {
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber > logs[j].BlockNumber
+		}
+		return logs[i].Index > logs[j].Index
+	})
+	if len(logs) <= limit {
+		return logs, false
+	}
+	end := limit
+	for end < len(logs) && logs[end].BlockNumber == logs[limit-1].BlockNumber {
+		end++
+	}
+	return logs[:end], end < len(logs)
+}
+
+// formatLog converts a log to JSON, decoding it with spec when set. Raw
+// topics and data are only included for logs that were not decoded.
+func formatLog(lg types.Log, spec *eventSpec) logJSON {
+	out := logJSON{
+		Address:     lg.Address.Hex(),
+		BlockNumber: lg.BlockNumber,
+		TxHash:      lg.TxHash.Hex(),
+		LogIndex:    lg.Index,
+	}
+	if spec != nil {
+		args, err := spec.decodeLog(lg)
+		if err == nil {
+			out.Event = spec.event.Name
+			out.Args = args
+			return out
+		}
+		out.DecodeError = err.Error()
+	}
+	out.Topics = make([]string, len(lg.Topics))
+	for i, t := range lg.Topics {
+		out.Topics[i] = t.Hex()
+	}
+	out.Data = "0x" + hex.EncodeToString(lg.Data)
+	return out
+}
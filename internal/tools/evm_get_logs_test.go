@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+func transferLog(from string, amount int64, block uint64, index uint) map[string]any {
+	return map[string]any{
+		"address": usdt,
+		"topics": []string{
+			transferTopic.Hex(),
+			ethcommon.BytesToHash(ethcommon.HexToAddress(from).Bytes()).Hex(),
+			ethcommon.BytesToHash(ethcommon.HexToAddress(testAddress).Bytes()).Hex(),
+		},
+		"data":            fmt.Sprintf("0x%064x", amount),
+		"blockNumber":     fmt.Sprintf("0x%x", block),
+		"transactionHash": fmt.Sprintf("0x%064x", block),
+		"logIndex":        fmt.Sprintf("0x%x", index),
+	}
+}
+
+func TestEVMGetLogs(t *testing.T) {
+	var filter struct {
+		FromBlock string   `json:"fromBlock"`
+		Topics    []any    `json:"topics"`
+		Address   []string `json:"address"`
+	}
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_blockNumber": "0x4e20",
+		"eth_getLogs": func(params json.RawMessage) any {
+			var args []json.RawMessage
+			_ = json.Unmarshal(params, &args)
+			_ = json.Unmarshal(args[0], &filter)
+			return []any{
+				transferLog(permit2, 1_000_000, 15_000, 3),
+				transferLog(sparkVault, 2_500_000, 19_000, 1),
+				transferLog(sparkVault, 7, 19_000, 4),
+			}
+		},
+	})
+	handler := handleEVMGetLogs(pool)
+
+	for _, event := range []string{
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		"Transfer(address,address,uint256)",
+		`{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256"}]}`,
+	} {
+		res, err := handler(context.Background(), callToolReq("evm_get_logs", map[string]any{
+			"address": usdt,
+			"event":   event,
+			"topics":  []any{nil, nil, testAddress},
+		}))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", event, err)
+		}
+		var result struct {
+			Logs     []logJSON `json:"logs"`
+			Complete bool      `json:"complete"`
+		}
+		if err := json.Unmarshal([]byte(resultText(t, res)), &result); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if len(result.Logs) != 3 || !result.Complete {
+			t.Fatalf("%s: logs %+v, complete %v", event, result.Logs, result.Complete)
+		}
+		newest := result.Logs[0]
+		if newest.BlockNumber != 19_000 || newest.LogIndex != 4 || newest.Event != "Transfer" || newest.Topics != nil {
+			t.Errorf("%s: newest log %+v", event, newest)
+		}
+		oldest := result.Logs[2].Args
+		fromKey, valueKey := "from", "value"
+		if !strings.Contains(event, "from") {
+			fromKey, valueKey = "arg0", "arg2"
+		}
+		if oldest[fromKey] != permit2 || oldest[valueKey] != "1000000" {
+			t.Errorf("%s: oldest args %v", event, oldest)
+		}
+	}
+
+	if filter.FromBlock != "0x2711" {
+		t.Errorf("fromBlock = %s, want the last 10000 blocks", filter.FromBlock)
+	}
+	topics, _ := json.Marshal(filter.Topics)
+	want := fmt.Sprintf(`[["%s"],null,["%s"]]`, transferTopic.Hex(), ethcommon.BytesToHash(ethcommon.HexToAddress(testAddress).Bytes()).Hex())
+	if string(topics) != want {
+		t.Errorf("topics = %s, want %s", topics, want)
+	}
+}
+
+func TestEVMGetLogs_Limit(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_getLogs": []any{
+			transferLog(permit2, 1, 15_000, 0),
+			transferLog(permit2, 2, 19_000, 0),
+			transferLog(permit2, 3, 19_000, 1),
+		},
+	})
+	res, err := handleEVMGetLogs(pool)(context.Background(), callToolReq("evm_get_logs", map[string]any{
+		"address":    usdt,
+		"from_block": "10000",
+		"to_block":   "20000",
+		"limit":      float64(1),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	// Both logs of block 19000 are kept.
+	if result["count"] != float64(2) || result["next_to_block"] != float64(18_999) || result["complete"] != false {
+		t.Errorf("result = %v", result)
+	}
+	first := result["logs"].([]any)[0].(map[string]any)
+	if first["data"] == nil || first["args"] != nil {
+		t.Errorf("undecoded log = %v", first)
+	}
+}
+
+func TestEVMGetLogs_InvalidParams(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{})
+	handler := handleEVMGetLogs(pool)
+
+	for name, args := range map[string]map[string]any{
+		"no filter":         {},
+		"bad address":       {"address": "0x123"},
+		"bad topic":         {"address": usdt, "topics": []any{"0x1234"}},
+		"too many topics":   {"address": usdt, "topics": []any{nil, nil, nil, nil, nil}},
+		"conflicting topic": {"event": "Approval(address,address,uint256)", "topics": []any{transferTopic.Hex()}},
+		"bad event":         {"event": "Transfer"},
+		"bad event type":    {"event": "Transfer(notatype)"},
+		"two events":        {"event": `[{"type":"event","name":"A","inputs":[]},{"type":"event","name":"B","inputs":[]}]`},
+		"bad block range":   {"address": usdt, "from_block": "20", "to_block": "10"},
+		"bad limit":         {"address": usdt, "limit": float64(maxLogsLimit + 1)},
+	} {
+		res, err := handler(context.Background(), callToolReq("evm_get_logs", args))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+}
+
+func TestEventSpec_DecodeIndexedDynamic(t *testing.T) {
+	spec, err := parseEvent("Named(string indexed name, bytes data)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameHash := crypto.Keccak256Hash([]byte("alice"))
+	data, _ := hexToBytes("0x" + fmt.Sprintf("%064x%064x", 32, 2) + "beef" + strings.Repeat("0", 60))
+	args, err := spec.decodeLog(types.Log{Topics: []ethcommon.Hash{spec.event.ID, nameHash}, Data: data})
+	if err != nil {
+		t.Fatalf("decodeLog: %v", err)
+	}
+	if args["name"] != nameHash.Hex() || args["data"] != "0xbeef" {
+		t.Errorf("args = %v", args)
+	}
+}
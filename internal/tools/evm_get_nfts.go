@@ -0,0 +1,281 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+const (
+	// nftScanMaxQueries caps the eth_getLogs requests per transfer event.
+	nftScanMaxQueries = 50
+	// maxNFTCandidates caps the tokens whose ownership is confirmed on chain.
+	maxNFTCandidates = 200
+	// maxNFTMetadata caps the metadata documents fetched per call.
+	maxNFTMetadata = 25
+)
+
+func newEVMGetNFTsTool() mcp.Tool {
+	return mcp.NewTool("evm_get_nfts",
+		mcp.WithDescription(
+			"List the ERC-721 and ERC-1155 NFTs an address holds on an EVM chain. "+
+				"Finds candidates from incoming Transfer, TransferSingle and TransferBatch logs, then confirms each one "+
+				"with ownerOf (ERC-721) or balanceOf (ERC-1155), so tokens sent away are dropped. "+
+				"Optionally resolves tokenURI metadata (name, description, image) over HTTP, IPFS or data: URIs. "+
+				"Address falls back to vault-derived if not provided. Use build_nft_transfer to send a result.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("address",
+			mcp.Description("Owner address (0x-prefixed). Optional if vault info is set."),
+		),
+		mcp.WithString("contract",
+			mcp.Description("Only list NFTs from this collection contract (0x-prefixed)."),
+		),
+		mcp.WithNumber("from_block",
+			mcp.Description("First block to scan (default 0, the full history). "+
+				"Large ranges may be cut short by RPC limits; the scan field reports the covered range."),
+		),
+		mcp.WithBoolean("resolve_metadata",
+			mcp.Description(fmt.Sprintf("Fetch token metadata for up to %d NFTs from their https, IPFS, Arweave or data: token URIs (default false).", maxNFTMetadata)),
+		),
+	)
+}
+
+type nftJSON struct {
+	Contract      string `json:"contract"`
+	Collection    string `json:"collection,omitempty"`
+	Standard      string `json:"standard"`
+	TokenID       string `json:"token_id"`
+	Balance       string `json:"balance"`
+	TokenURI      string `json:"token_uri,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Image         string `json:"image,omitempty"`
+	MetadataError string `json:"metadata_error,omitempty"`
+	LastReceived  uint64 `json:"last_received_block"`
+}
+
+type nftKey struct {
+	contract common.Address
+	id       string
+}
+
+type nftCandidate struct {
+	standard string
+	contract common.Address
+	id       *big.Int
+	block    uint64
+}
+
+func handleEVMGetNFTs(store *vault.Store, pool *evmclient.Pool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+		if _, ok := evmclient.ChainIDByName(chainName); !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported chain: %s", chainName)), nil
+		}
+
+		explicit := req.GetString("address", "")
+		if explicit != "" && !common.IsHexAddress(explicit) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid address: %s", explicit)), nil
+		}
+		addr, err := resolve.EVMAddress(explicit, resolve.ResolveVault(ctx, req, store))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		owner := common.HexToAddress(addr)
+
+		var contracts []common.Address
+		if c := req.GetString("contract", ""); c != "" {
+			if !common.IsHexAddress(c) {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid contract address: %s", c)), nil
+			}
+			contracts = []common.Address{common.HexToAddress(c)}
+		}
+
+		fromBlock := req.GetFloat("from_block", 0)
+		if fromBlock < 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid from_block: %v", fromBlock)), nil
+		}
+
+		client, _, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+		latest, err := client.BlockNumber(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("get block number: %v", err)), nil
+		}
+
+		ownerTopic := common.BytesToHash(owner.Bytes())
+		// Topics are [Transfer, from, to, tokenId] for ERC-721 and
+		// [TransferSingle|TransferBatch, operator, from, to] for ERC-1155.
+		erc721Scan, err := client.ScanLogs(ctx, ethereum.FilterQuery{
+			Addresses: contracts,
+			Topics:    [][]common.Hash{{evmclient.TransferTopic}, nil, {ownerTopic}},
+		}, uint64(fromBlock), latest, nftScanMaxQueries)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("scan ERC-721 transfers: %v", err)), nil
+		}
+		erc1155Scan, err := client.ScanLogs(ctx, ethereum.FilterQuery{
+			Addresses: contracts,
+			Topics:    [][]common.Hash{{evmclient.TransferSingleTopic, evmclient.TransferBatchTopic}, nil, nil, {ownerTopic}},
+		}, uint64(fromBlock), latest, nftScanMaxQueries)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("scan ERC-1155 transfers: %v", err)), nil
+		}
+
+		candidates := nftCandidates(erc721Scan.Logs, erc1155Scan.Logs)
+		truncated := len(candidates) > maxNFTCandidates
+		if truncated {
+			candidates = candidates[:maxNFTCandidates]
+		}
+
+		nfts := []nftJSON{}
+		collections := make(map[common.Address]string)
+		for _, c := range candidates {
+			balance := big.NewInt(1)
+			if c.standard == evmclient.StandardERC1155 {
+				balance, err = client.ERC1155BalanceOf(ctx, c.contract, owner, c.id)
+				if err != nil || balance.Sign() == 0 {
+					continue
+				}
+			} else {
+				current, err := client.OwnerOf(ctx, c.contract, c.id)
+				if err != nil || current != owner {
+					continue
+				}
+			}
+
+			name, ok := collections[c.contract]
+			if !ok {
+				name, _ = client.NFTCollectionName(ctx, c.contract)
+				collections[c.contract] = name
+			}
+			nfts = append(nfts, nftJSON{
+				Contract:     c.contract.Hex(),
+				Collection:   name,
+				Standard:     c.standard,
+				TokenID:      c.id.String(),
+				Balance:      balance.String(),
+				LastReceived: c.block,
+			})
+		}
+
+		if req.GetBool("resolve_metadata", false) {
+			for i := range nfts {
+				if i == maxNFTMetadata {
+					break
+				}
+				resolveNFTMetadata(ctx, client, &nfts[i])
+			}
+		}
+
+		scanFrom := max(erc721Scan.FromBlock, erc1155Scan.FromBlock)
+		data, err := json.Marshal(map[string]any{
+			"chain": chainName,
+			"owner": owner.Hex(),
+			"nfts":  nfts,
+			"count": len(nfts),
+			"scan": map[string]any{
+				"from_block": scanFrom,
+				"to_block":   latest,
+				"complete":   scanFrom <= uint64(fromBlock),
+			},
+			"truncated": truncated,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// nftCandidates returns the distinct tokens received in the given logs,
+// most recently received first.
+func nftCandidates(erc721Logs, erc1155Logs []ethtypes.Log) []nftCandidate {
+	seen := make(map[nftKey]*nftCandidate)
+	add := func(standard string, lg ethtypes.Log, id *big.Int) {
+		key := nftKey{contract: lg.Address, id: id.String()}
+		if prev, ok := seen[key]; ok {
+			prev.block = max(prev.block, lg.BlockNumber)
+			return
+		}
+		seen[key] = &nftCandidate{standard: standard, contract: lg.Address, id: id, block: lg.BlockNumber}
+	}
+
+	for _, lg := range erc721Logs {
+		if len(lg.Topics) != 4 {
+			continue // ERC-20 Transfer leaves the amount unindexed
+		}
+		add(evmclient.StandardERC721, lg, lg.Topics[3].Big())
+	}
+	for _, lg := range erc1155Logs {
+		if lg.Topics[0] == evmclient.TransferSingleTopic {
+			if len(lg.Data) < 64 {
+				continue
+			}
+			add(evmclient.StandardERC1155, lg, new(big.Int).SetBytes(lg.Data[:32]))
+			continue
+		}
+		ids, _, err := evmclient.DecodeTransferBatch(lg.Data)
+		if err != nil {
+			continue
+		}
+		for _, id := range ids {
+			add(evmclient.StandardERC1155, lg, id)
+		}
+	}
+
+	out := make([]nftCandidate, 0, len(seen))
+	for _, c := range seen {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].block != out[j].block {
+			return out[i].block > out[j].block
+		}
+		if out[i].contract != out[j].contract {
+			return out[i].contract.Hex() < out[j].contract.Hex()
+		}
+		return out[i].id.Cmp(out[j].id) < 0
+	})
+	return out
+}
+
+// resolveNFTMetadata fills in the token URI and metadata of n. Failures are
+// reported in MetadataError rather than dropping the NFT.
+func resolveNFTMetadata(ctx context.Context, client *evmclient.Client, n *nftJSON) {
+	id, _ := new(big.Int).SetString(n.TokenID, 10)
+	uri, err := client.NFTTokenURI(ctx, common.HexToAddress(n.Contract), n.Standard, id)
+	if err != nil {
+		n.MetadataError = err.Error()
+		return
+	}
+	n.TokenURI = uri
+	if uri == "" {
+		return
+	}
+	meta, err := evmclient.FetchNFTMetadata(ctx, uri)
+	if err != nil {
+		n.MetadataError = err.Error()
+		return
+	}
+	n.Name = meta.Name
+	n.Description = meta.Description
+	n.Image = meta.Image
+}
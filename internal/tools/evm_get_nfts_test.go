@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/keysign"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+const erc1155Contract = "0x76BE3b62873462d2142405439777e971754E8E77"
+
+func addressTopic(addr string) string {
+	return ethcommon.BytesToHash(ethcommon.HexToAddress(addr).Bytes()).Hex()
+}
+
+// abiString returns the ABI encoding of a single string return value.
+func abiString(s string) string {
+	enc := hex.EncodeToString([]byte(s))
+	if pad := len(enc) % 64; pad != 0 {
+		enc += strings.Repeat("0", 64-pad)
+	}
+	return fmt.Sprintf("0x%064x%064x%s", 32, len(s), enc)
+}
+
+// mockNFTLogs answers the ERC-721 query with transfers of tokens 7 and 8 plus
+// an ERC-20 transfer, and the ERC-1155 query with a TransferSingle of id 5.
+func mockNFTLogs(params json.RawMessage) any {
+	owner := addressTopic(testAddress)
+	if strings.Contains(string(params), evmclient.TransferTopic.Hex()) {
+		log := func(contract string, topics []string, block int) map[string]any {
+			return map[string]any{
+				"address":         contract,
+				"topics":          topics,
+				"data":            "0x",
+				"blockNumber":     fmt.Sprintf("0x%x", block),
+				"transactionHash": fmt.Sprintf("0x%064x", block),
+			}
+		}
+		return []any{
+			log(nftContract, []string{evmclient.TransferTopic.Hex(), addressTopic(sparkVault), owner, fmt.Sprintf("0x%064x", 7)}, 100),
+			log(nftContract, []string{evmclient.TransferTopic.Hex(), addressTopic(sparkVault), owner, fmt.Sprintf("0x%064x", 8)}, 200),
+			log(usdt, []string{evmclient.TransferTopic.Hex(), addressTopic(sparkVault), owner}, 300),
+		}
+	}
+	return []any{map[string]any{
+		"address":         erc1155Contract,
+		"topics":          []string{evmclient.TransferSingleTopic.Hex(), addressTopic(sparkVault), addressTopic(sparkVault), owner},
+		"data":            fmt.Sprintf("0x%064x%064x", 5, 3),
+		"blockNumber":     "0x190",
+		"transactionHash": fmt.Sprintf("0x%064x", 400),
+	}}
+}
+
+// mockNFTCalls answers ownerOf (token 7 held by the test address, others by
+// sparkVault), ERC-1155 balanceOf, tokenURI, uri, name and supportsInterface.
+func mockNFTCalls(erc1155Balance int64) func(json.RawMessage) any {
+	meta := "data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(`{"name":"Ape #7","image":"ipfs://QmImage"}`))
+	return func(params json.RawMessage) any {
+		var args []struct {
+			To    string `json:"to"`
+			Input string `json:"input"`
+		}
+		_ = json.Unmarshal(params, &args)
+		data := args[0].Input
+		word := func(v int64) string { return fmt.Sprintf("0x%064x", v) }
+		switch {
+		case strings.HasPrefix(data, "0x6352211e"): // ownerOf
+			if strings.HasSuffix(data, fmt.Sprintf("%064x", 7)) {
+				return addressTopic(testAddress)
+			}
+			return addressTopic(sparkVault)
+		case strings.HasPrefix(data, "0x00fdd58e"): // balanceOf(address,uint256)
+			return word(erc1155Balance)
+		case strings.HasPrefix(data, "0xc87b56dd"): // tokenURI
+			return abiString(meta)
+		case strings.HasPrefix(data, "0x0e89341c"): // uri
+			return abiString(`data:application/json,%7B%22name%22%3A%22{id}%22%7D`)
+		case strings.HasPrefix(data, "0x06fdde03"): // name
+			if strings.EqualFold(args[0].To, nftContract) {
+				return abiString("BoredApeYachtClub")
+			}
+			return abiString("")
+		case strings.HasPrefix(data, "0x01ffc9a7"): // supportsInterface
+			if strings.EqualFold(args[0].To, erc1155Contract) == strings.HasPrefix(data[10:], "d9b67a26") {
+				return word(1)
+			}
+			return word(0)
+		}
+		return "0x"
+	}
+}
+
+func nftStore() *vault.Store {
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+	return store
+}
+
+func TestEVMGetNFTs(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_blockNumber": "0x4e20",
+		"eth_getLogs":     mockNFTLogs,
+		"eth_call":        mockNFTCalls(2),
+	})
+
+	res, err := handleEVMGetNFTs(nftStore(), pool)(context.Background(), callToolReq("evm_get_nfts", map[string]any{
+		"resolve_metadata": true,
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	var result struct {
+		Owner string    `json:"owner"`
+		NFTs  []nftJSON `json:"nfts"`
+		Scan  struct {
+			Complete bool `json:"complete"`
+		} `json:"scan"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, res)), &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if result.Owner != testAddress || !result.Scan.Complete {
+		t.Errorf("owner = %s, scan complete = %v", result.Owner, result.Scan.Complete)
+	}
+	// Token 8 was sent away and the ERC-20 transfer is ignored.
+	if len(result.NFTs) != 2 {
+		t.Fatalf("nfts = %+v", result.NFTs)
+	}
+	multi, ape := result.NFTs[0], result.NFTs[1]
+	if multi.Standard != "erc1155" || multi.TokenID != "5" || multi.Balance != "2" || multi.Contract != erc1155Contract {
+		t.Errorf("erc1155 = %+v", multi)
+	}
+	if multi.Name != fmt.Sprintf("%064x", 5) {
+		t.Errorf("erc1155 name = %q, want the substituted {id} (%s)", multi.Name, multi.MetadataError)
+	}
+	if ape.Standard != "erc721" || ape.TokenID != "7" || ape.Collection != "BoredApeYachtClub" || ape.LastReceived != 100 {
+		t.Errorf("erc721 = %+v", ape)
+	}
+	if ape.Name != "Ape #7" || ape.Image != evmclient.IPFSGateway+"QmImage" {
+		t.Errorf("metadata = %q / %q (%s)", ape.Name, ape.Image, ape.MetadataError)
+	}
+}
+
+func nftTransferPool(t *testing.T, erc1155Balance int64) (*mockEVMRPC, func(map[string]any) *mcp.CallToolResult) {
+	t.Helper()
+	pool, rpc := mockEVMPool(t, map[string]any{
+		"eth_call":                 mockNFTCalls(erc1155Balance),
+		"eth_getTransactionCount":  "0x3",
+		"eth_maxPriorityFeePerGas": "0x5f5e100",
+		"eth_getBlockByNumber":     mockHeader("0x3b9aca00"),
+		"eth_estimateGas":          "0x186a0",
+		"eth_getBalance":           "0xde0b6b3a7640000",
+	})
+	handler := handleBuildNFTTransfer(nftStore(), pool)
+	return rpc, func(args map[string]any) *mcp.CallToolResult {
+		res, err := handler(context.Background(), callToolReq("build_nft_transfer", args))
+		if err != nil {
+			t.Fatalf("handler error: %v", err)
+		}
+		return res
+	}
+}
+
+func TestBuildNFTTransfer_ERC721(t *testing.T) {
+	_, call := nftTransferPool(t, 0)
+	result := decodeResult(t, call(map[string]any{"contract": nftContract, "token_id": "7", "to": sparkVault}))
+
+	want := "0x42842e0e" + fmt.Sprintf("%064s%064s%064x",
+		strings.ToLower(testAddress[2:]), strings.ToLower(sparkVault[2:]), 7)
+	if result["data"] != want || result["to"] != nftContract || result["value"] != "0" {
+		t.Errorf("tx = %v", result)
+	}
+	if result["standard"] != "erc721" || result["nonce"] != "3" || result["recipient"] != sparkVault {
+		t.Errorf("standard/nonce/recipient = %v/%v/%v", result["standard"], result["nonce"], result["recipient"])
+	}
+	if s, _ := result["summary"].(string); !strings.HasPrefix(s, "Send BoredApeYachtClub #7 from "+testAddress) {
+		t.Errorf("summary = %q", s)
+	}
+}
+
+func TestBuildNFTTransfer_ERC1155(t *testing.T) {
+	_, call := nftTransferPool(t, 4)
+	result := decodeResult(t, call(map[string]any{"contract": erc1155Contract, "token_id": "5", "to": sparkVault, "amount": "3"}))
+
+	data, _ := result["data"].(string)
+	if !strings.HasPrefix(data, "0xf242432a") || !strings.Contains(data, fmt.Sprintf("%064x%064x", 5, 3)) {
+		t.Errorf("data = %s", data)
+	}
+	if result["standard"] != "erc1155" || result["amount"] != "3" {
+		t.Errorf("standard/amount = %v/%v", result["standard"], result["amount"])
+	}
+}
+
+func TestBuildNFTTransfer_OwnershipChecks(t *testing.T) {
+	rpc, call := nftTransferPool(t, 2)
+	for name, args := range map[string]map[string]any{
+		"not owner":      {"contract": nftContract, "token_id": "8", "to": sparkVault},
+		"721 amount":     {"contract": nftContract, "token_id": "7", "to": sparkVault, "amount": "2"},
+		"1155 balance":   {"contract": erc1155Contract, "token_id": "5", "to": sparkVault, "amount": "3"},
+		"bad token id":   {"contract": nftContract, "token_id": "0x7", "to": sparkVault},
+		"to contract":    {"contract": nftContract, "token_id": "7", "to": nftContract},
+		"bad standard":   {"contract": nftContract, "token_id": "7", "to": sparkVault, "standard": "erc20"},
+		"zero recipient": {"contract": nftContract, "token_id": "7", "to": "0x0000000000000000000000000000000000000000"},
+	} {
+		if res := call(args); !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+	if rpc.callCount("eth_estimateGas") != 0 {
+		t.Error("gas estimated for a rejected transfer")
+	}
+}
+
+func TestBuildNFTTransfer_Keysign(t *testing.T) {
+	_, call := nftTransferPool(t, 4)
+	res := call(map[string]any{"contract": nftContract, "token_id": "7", "to": sparkVault, "output_format": "keysign"})
+
+	var out struct {
+		Action string `json:"action"`
+		Base64 string `json:"keysign_payload_base64"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, res)), &out); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	p, err := keysign.Decode(out.Base64)
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	want := "0x42842e0e" + fmt.Sprintf("%064s%064s%064x",
+		strings.ToLower(testAddress[2:]), strings.ToLower(sparkVault[2:]), 7)
+	if out.Action != "nft_transfer" || p.GetToAddress() != nftContract || p.GetToAmount() != "0" || p.GetMemo() != want {
+		t.Errorf("action %s, to %s, amount %s, memo %s", out.Action, p.GetToAddress(), p.GetToAmount(), p.GetMemo())
+	}
+	if eth := p.GetEthereumSpecific(); eth.GetNonce() != 3 || eth.GetGasLimit() != "100000" {
+		t.Errorf("ethereum_specific = %+v", eth)
+	}
+
+	// An explicit sender other than the vault cannot be signed for.
+	res = call(map[string]any{"contract": erc1155Contract, "token_id": "5", "to": sparkVault, "from": permit2, "output_format": "keysign"})
+	if text := res.Content[0].(mcp.TextContent).Text; !res.IsError || !strings.Contains(text, "does not match vault-derived address") {
+		t.Errorf("expected from mismatch error, got %s", text)
+	}
+	res = call(map[string]any{"contract": nftContract, "token_id": "7", "to": sparkVault, "tx_type": float64(0), "output_format": "keysign"})
+	if !res.IsError {
+		t.Error("expected an error for keysign output with tx_type 0")
+	}
+}
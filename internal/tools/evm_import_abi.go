@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/vultisig/mcp/internal/abiregistry"
+	evmclient "github.com/vultisig/mcp/internal/evm"
+)
+
+func newEVMImportABITool() mcp.Tool {
+	return mcp.NewTool("evm_import_abi",
+		mcp.WithDescription(
+			"Store a contract's JSON ABI in the local ABI registry, e.g. for an unverified contract or one the explorer does not index. "+
+				"evm_decode_calldata, abi_decode and evm_call then decode the contract's calldata and return data with its function and parameter names. "+
+				"An imported ABI replaces any ABI fetched from the explorer for the same address.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("address",
+			mcp.Description("Contract address (0x-prefixed). For a proxy, import the implementation's ABI under the implementation address or the proxy's."),
+			mcp.Required(),
+		),
+		mcp.WithString("abi",
+			mcp.Description("Contract JSON ABI (array)."),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Contract name to show alongside decoded calls (optional)."),
+		),
+	)
+}
+
+func handleEVMImportABI(registry *abiregistry.Registry) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+		chainID, ok := evmclient.ChainIDByName(chainName)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported chain: %s", chainName)), nil
+		}
+
+		addr, err := req.RequireString("address")
+		if err != nil {
+			return mcp.NewToolResultError("missing address parameter"), nil
+		}
+		if !common.IsHexAddress(addr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid address: %s", addr)), nil
+		}
+		abiJSON, err := req.RequireString("abi")
+		if err != nil {
+			return mcp.NewToolResultError("missing abi parameter"), nil
+		}
+
+		parsed, err := registry.Import(chainID.Uint64(), common.HexToAddress(addr), req.GetString("name", ""), []byte(abiJSON))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		functions := make([]string, 0, len(parsed.Methods))
+		for _, m := range parsed.Methods {
+			functions = append(functions, m.Sig)
+		}
+		sort.Strings(functions)
+
+		data, err := json.Marshal(map[string]any{
+			"chain":     chainName,
+			"address":   common.HexToAddress(addr).Hex(),
+			"functions": functions,
+			"events":    len(parsed.Events),
+			"errors":    len(parsed.Errors),
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal evm_import_abi result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
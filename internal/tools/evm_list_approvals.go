@@ -0,0 +1,263 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// approvalScanMaxQueries caps the eth_getLogs requests per chain, so a
+// range-limited RPC cannot turn a full-history scan into thousands of calls.
+const approvalScanMaxQueries = 50
+
+const (
+	approvalTypeERC20       = "erc20"
+	approvalTypeNFTOperator = "nft_operator"
+)
+
+func newEVMListApprovalsTool() mcp.Tool {
+	return mcp.NewTool("evm_list_approvals",
+		mcp.WithDescription(
+			"List the token approvals an address has granted on EVM chains. "+
+				"Scans ERC-20 Approval and ERC-721/1155 ApprovalForAll logs, then confirms each current allowance on chain, "+
+				"so revoked or spent approvals are dropped. Unlimited approvals are flagged and well-known spenders are labelled. "+
+				"Scans every configured EVM chain unless chain is set. Address falls back to vault-derived if not provided. "+
+				"Use build_evm_revoke to revoke the results.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name to scan. One of: "+chainEnumDesc()+". Scans all configured chains if omitted."),
+		),
+		mcp.WithString("address",
+			mcp.Description("Owner address (0x-prefixed). Optional if vault info is set."),
+		),
+		mcp.WithNumber("from_block",
+			mcp.Description("First block to scan on each chain (default 0, the full history). "+
+				"Large ranges may be cut short by RPC limits; the scans field reports the covered range."),
+		),
+	)
+}
+
+type approvalJSON struct {
+	Chain              string `json:"chain"`
+	Type               string `json:"type"`
+	Token              string `json:"token"`
+	Symbol             string `json:"symbol,omitempty"`
+	Spender            string `json:"spender"`
+	SpenderName        string `json:"spender_name,omitempty"`
+	Allowance          string `json:"allowance,omitempty"`
+	AllowanceFormatted string `json:"allowance_formatted,omitempty"`
+	Unlimited          bool   `json:"unlimited"`
+	LastApprovedBlock  uint64 `json:"last_approved_block"`
+	LastApprovedTx     string `json:"last_approved_tx"`
+}
+
+type approvalScanJSON struct {
+	Chain     string `json:"chain"`
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+	Complete  bool   `json:"complete"`
+	Error     string `json:"error,omitempty"`
+}
+
+func handleEVMListApprovals(store *vault.Store, pool *evmclient.Pool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chains := pool.Chains()
+		if chainName := req.GetString("chain", ""); chainName != "" {
+			if _, ok := evmclient.ChainIDByName(chainName); !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("unsupported chain: %s", chainName)), nil
+			}
+			chains = []string{chainName}
+		}
+
+		explicit := req.GetString("address", "")
+		if explicit != "" && !common.IsHexAddress(explicit) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid address: %s", explicit)), nil
+		}
+		addr, err := resolve.EVMAddress(explicit, resolve.ResolveVault(ctx, req, store))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		owner := common.HexToAddress(addr)
+
+		fromBlock := req.GetFloat("from_block", 0)
+		if fromBlock < 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid from_block: %v", fromBlock)), nil
+		}
+
+		approvals := make([][]approvalJSON, len(chains))
+		scans := make([]approvalScanJSON, len(chains))
+		var wg sync.WaitGroup
+		for i, chainName := range chains {
+			wg.Add(1)
+			go func(i int, chainName string) {
+				defer wg.Done()
+				scans[i] = approvalScanJSON{Chain: chainName}
+				found, scan, err := scanApprovals(ctx, pool, chainName, owner, uint64(fromBlock))
+				if err != nil {
+					scans[i].Error = err.Error()
+					return
+				}
+				approvals[i] = found
+				scans[i].FromBlock = scan.FromBlock
+				scans[i].ToBlock = scan.ToBlock
+				scans[i].Complete = scan.FromBlock <= uint64(fromBlock)
+			}(i, chainName)
+		}
+		wg.Wait()
+
+		all := []approvalJSON{}
+		unlimited := 0
+		for _, found := range approvals {
+			all = append(all, found...)
+		}
+		for _, a := range all {
+			if a.Unlimited {
+				unlimited++
+			}
+		}
+		sort.SliceStable(all, func(i, j int) bool {
+			return all[i].Unlimited && !all[j].Unlimited
+		})
+
+		data, err := json.Marshal(map[string]any{
+			"owner":           owner.Hex(),
+			"approvals":       all,
+			"count":           len(all),
+			"unlimited_count": unlimited,
+			"scans":           scans,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+type approvalKey struct {
+	kind           string
+	token, spender common.Address
+}
+
+// scanApprovals finds the approval events owner emitted on chainName and
+// returns the ones still in effect, newest first.
+func scanApprovals(ctx context.Context, pool *evmclient.Pool, chainName string, owner common.Address, fromBlock uint64) ([]approvalJSON, *evmclient.LogScan, error) {
+	client, _, err := pool.Get(ctx, chainName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("chain %s unavailable: %v", chainName, err)
+	}
+	latest, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get block number: %v", err)
+	}
+
+	scan, err := client.ScanLogs(ctx, ethereum.FilterQuery{
+		Topics: [][]common.Hash{
+			{evmclient.ApprovalTopic, evmclient.ApprovalForAllTopic},
+			{common.BytesToHash(owner.Bytes())},
+		},
+	}, fromBlock, latest, approvalScanMaxQueries)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Only the newest event per token and spender matters; the allowance is
+	// then confirmed on chain.
+	latestLog := make(map[approvalKey]approvalJSON)
+	for _, lg := range scan.Logs {
+		if len(lg.Topics) != 3 {
+			continue // ERC-721 single-token Approval indexes a token ID too
+		}
+		key := approvalKey{kind: approvalTypeERC20, token: lg.Address, spender: common.BytesToAddress(lg.Topics[2].Bytes())}
+		if lg.Topics[0] == evmclient.ApprovalForAllTopic {
+			key.kind = approvalTypeNFTOperator
+		}
+		if prev, ok := latestLog[key]; ok && prev.LastApprovedBlock > lg.BlockNumber {
+			continue
+		}
+		latestLog[key] = approvalJSON{
+			Chain:             chainName,
+			Type:              key.kind,
+			Token:             key.token.Hex(),
+			Spender:           key.spender.Hex(),
+			SpenderName:       evmclient.SpenderLabel(key.spender),
+			LastApprovedBlock: lg.BlockNumber,
+			LastApprovedTx:    lg.TxHash.Hex(),
+		}
+	}
+
+	found, err := confirmApprovals(ctx, client, owner, latestLog)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].LastApprovedBlock > found[j].LastApprovedBlock
+	})
+	return found, scan, nil
+}
+
+// confirmApprovals reads the current allowance or operator status of every
+// candidate approval, and the token metadata of ERC-20 ones, in one
+// Multicall batch. Revoked approvals and unreadable tokens are dropped.
+func confirmApprovals(ctx context.Context, client *evmclient.Client, owner common.Address, candidates map[approvalKey]approvalJSON) ([]approvalJSON, error) {
+	keys := make([]approvalKey, 0, len(candidates))
+	calls := make([]evmclient.Call, 0, len(candidates))
+	var tokens []common.Address
+	seenToken := make(map[common.Address]bool)
+	for key := range candidates {
+		keys = append(keys, key)
+		data := evmclient.PackERC20Allowance(owner, key.spender)
+		if key.kind == approvalTypeNFTOperator {
+			data = evmclient.PackIsApprovedForAll(owner, key.spender)
+		} else if !seenToken[key.token] {
+			seenToken[key.token] = true
+			tokens = append(tokens, key.token)
+		}
+		calls = append(calls, evmclient.Call{To: key.token, Data: data, AllowFailure: true})
+	}
+	results, err := client.Multicall(ctx, calls, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read allowances: %v", err)
+	}
+	infos, err := client.Tokens(ctx, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("read token metadata: %v", err)
+	}
+
+	var found []approvalJSON
+	for i, key := range keys {
+		r := results[i]
+		if !r.Success || len(r.ReturnData) < 32 {
+			continue
+		}
+		value := new(big.Int).SetBytes(r.ReturnData[:32])
+		if value.Sign() == 0 {
+			continue
+		}
+		a := candidates[key]
+		if key.kind == approvalTypeNFTOperator {
+			a.Unlimited = true
+			found = append(found, a)
+			continue
+		}
+		a.Allowance = value.String()
+		if info, ok := infos[key.token]; ok {
+			a.Symbol = info.Symbol
+			a.AllowanceFormatted = evmclient.FormatUnits(value, int(info.Decimals))
+		}
+		a.Unlimited = value.Cmp(evmclient.UnlimitedAllowance) >= 0
+		found = append(found, a)
+	}
+	return found, nil
+}
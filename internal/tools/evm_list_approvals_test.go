@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/keysign"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+const (
+	permit2      = "0x000000000022D473030F116dDEE9F6B43aC78BA3"
+	nftContract  = "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D"
+	unknownToken = "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+)
+
+func approvalLog(topic ethcommon.Hash, contract, spender string, block uint64) map[string]any {
+	return map[string]any{
+		"address": contract,
+		"topics": []string{
+			topic.Hex(),
+			ethcommon.BytesToHash(ethcommon.HexToAddress(testAddress).Bytes()).Hex(),
+			ethcommon.BytesToHash(ethcommon.HexToAddress(spender).Bytes()).Hex(),
+		},
+		"data":            "0x",
+		"blockNumber":     fmt.Sprintf("0x%x", block),
+		"transactionHash": fmt.Sprintf("0x%064x", block),
+	}
+}
+
+// mockApprovalCalls answers allowance() with allowances keyed by token and
+// isApprovedForAll() with operators, plus USDT token metadata.
+func mockApprovalCalls(allowances map[string]*big.Int, operators map[string]bool) func(json.RawMessage) any {
+	meta := mockERC20Call(6, "USDT", new(big.Int))
+	return func(params json.RawMessage) any {
+		var args []struct {
+			To    string `json:"to"`
+			Input string `json:"input"`
+		}
+		_ = json.Unmarshal(params, &args)
+		to := strings.ToLower(args[0].To)
+		switch {
+		case strings.HasPrefix(args[0].Input, "0xdd62ed3e"):
+			a := allowances[to]
+			if a == nil {
+				a = new(big.Int)
+			}
+			return fmt.Sprintf("0x%064x", a)
+		case strings.HasPrefix(args[0].Input, "0xe985e9c5"):
+			if operators[to] {
+				return fmt.Sprintf("0x%064x", 1)
+			}
+			return fmt.Sprintf("0x%064x", 0)
+		}
+		return meta(params)
+	}
+}
+
+func TestEVMListApprovals(t *testing.T) {
+	maxUint := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_blockNumber": "0x4e20",
+		"eth_getLogs": []any{
+			approvalLog(evmclient.ApprovalTopic, usdt, permit2, 100),
+			approvalLog(evmclient.ApprovalTopic, usdt, sparkVault, 200),
+			approvalLog(evmclient.ApprovalTopic, unknownToken, sparkVault, 300),
+			approvalLog(evmclient.ApprovalForAllTopic, nftContract, permit2, 400),
+		},
+		"eth_call": mockApprovalCalls(
+			map[string]*big.Int{strings.ToLower(usdt): maxUint},
+			map[string]bool{strings.ToLower(nftContract): true},
+		),
+	})
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+
+	res, err := handleEVMListApprovals(store, pool)(context.Background(), callToolReq("evm_list_approvals", map[string]any{}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	var result struct {
+		Owner          string             `json:"owner"`
+		Approvals      []approvalJSON     `json:"approvals"`
+		UnlimitedCount int                `json:"unlimited_count"`
+		Scans          []approvalScanJSON `json:"scans"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, res)), &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if result.Owner != testAddress {
+		t.Errorf("owner = %s", result.Owner)
+	}
+	if len(result.Scans) != 1 || !result.Scans[0].Complete || result.Scans[0].ToBlock != 20_000 {
+		t.Errorf("scans = %+v", result.Scans)
+	}
+	// The DAI approval has no remaining allowance and is dropped; the mock
+	// gives every USDT spender the same unlimited allowance.
+	if len(result.Approvals) != 3 || result.UnlimitedCount != 3 {
+		t.Fatalf("approvals = %+v", result.Approvals)
+	}
+	nft := result.Approvals[0]
+	if nft.Type != "nft_operator" || nft.Token != nftContract || nft.SpenderName != "Uniswap Permit2" || nft.LastApprovedBlock != 400 {
+		t.Errorf("nft approval = %+v", nft)
+	}
+	erc20 := result.Approvals[2]
+	if erc20.Type != "erc20" || erc20.Symbol != "USDT" || erc20.Allowance != maxUint.String() || erc20.SpenderName != "Uniswap Permit2" {
+		t.Errorf("erc20 approval = %+v", erc20)
+	}
+}
+
+func TestBuildEVMRevoke(t *testing.T) {
+	pool, rpc := mockEVMPool(t, map[string]any{
+		"eth_getTransactionCount":  "0x5",
+		"eth_maxPriorityFeePerGas": "0x1",
+		"eth_getBlockByNumber":     mockHeader("0x10"),
+		"eth_estimateGas":          "0xb5e0",
+		"eth_getBalance":           "0xde0b6b3a7640000",
+	})
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+
+	res, err := handleBuildEVMRevoke(store, pool)(context.Background(), callToolReq("build_evm_revoke", map[string]any{
+		"approvals": []any{
+			map[string]any{"token": usdt, "spender": permit2},
+			map[string]any{"token": nftContract, "spender": sparkVault, "type": "nft_operator"},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	var result struct {
+		From          string           `json:"from"`
+		Transactions  []map[string]any `json:"transactions"`
+		MaxNetworkFee string           `json:"max_network_fee"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, res)), &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.From != testAddress || len(result.Transactions) != 2 {
+		t.Fatalf("from = %s, %d transactions", result.From, len(result.Transactions))
+	}
+
+	approve, operator := result.Transactions[0], result.Transactions[1]
+	wantApprove := "0x095ea7b3" + fmt.Sprintf("%064s", strings.ToLower(permit2[2:])) + strings.Repeat("0", 64)
+	if approve["data"] != wantApprove || approve["to"] != usdt || approve["nonce"] != "5" {
+		t.Errorf("approve tx = %v", approve)
+	}
+	if approve["description"] != "Revoke Uniswap Permit2's ERC-20 allowance on "+usdt {
+		t.Errorf("description = %v", approve["description"])
+	}
+	wantOperator := "0xa22cb465" + fmt.Sprintf("%064s", strings.ToLower(sparkVault[2:])) + strings.Repeat("0", 64)
+	if operator["data"] != wantOperator || operator["nonce"] != "6" || operator["max_fee_per_gas"] != approve["max_fee_per_gas"] {
+		t.Errorf("operator tx = %v", operator)
+	}
+	// 46,560 gas at 33 wei each.
+	if approve["max_network_fee"] != "0.00000000000153648 ETH" || result.MaxNetworkFee != "0.00000000000307296 ETH" {
+		t.Errorf("max_network_fee = %v, total %s", approve["max_network_fee"], result.MaxNetworkFee)
+	}
+
+	// One revoke's fee is covered but not both.
+	rpc.results["eth_getBalance"] = "0x200000"
+	res, err = handleBuildEVMRevoke(store, pool)(context.Background(), callToolReq("build_evm_revoke", map[string]any{
+		"approvals": []any{
+			map[string]any{"token": usdt, "spender": permit2},
+			map[string]any{"token": nftContract, "spender": sparkVault, "type": "nft_operator"},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if text := res.Content[0].(mcp.TextContent).Text; !res.IsError || !strings.Contains(text, "insufficient ETH balance") {
+		t.Errorf("expected insufficient balance error, got %s", text)
+	}
+}
+
+func TestBuildEVMRevoke_Keysign(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_getTransactionCount":  "0x5",
+		"eth_maxPriorityFeePerGas": "0x1",
+		"eth_getBlockByNumber":     mockHeader("0x10"),
+		"eth_estimateGas":          "0xb5e0",
+		"eth_getBalance":           "0xde0b6b3a7640000",
+	})
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+	approvals := []any{
+		map[string]any{"token": usdt, "spender": permit2},
+		map[string]any{"token": nftContract, "spender": sparkVault, "type": "nft_operator"},
+	}
+
+	res, err := handleBuildEVMRevoke(store, pool)(context.Background(), callToolReq("build_evm_revoke", map[string]any{
+		"approvals":     approvals,
+		"output_format": "keysign",
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	var out struct {
+		Action   string `json:"action"`
+		Payloads []struct {
+			Sequence    int    `json:"sequence"`
+			Description string `json:"description"`
+			Base64      string `json:"keysign_payload_base64"`
+		} `json:"payloads"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, res)), &out); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if out.Action != "revoke" || len(out.Payloads) != 2 {
+		t.Fatalf("action = %s, %d payloads", out.Action, len(out.Payloads))
+	}
+	for i, want := range []struct {
+		to    string
+		data  string
+		nonce int64
+	}{
+		{usdt, "0x095ea7b3" + fmt.Sprintf("%064s", strings.ToLower(permit2[2:])) + strings.Repeat("0", 64), 5},
+		{nftContract, "0xa22cb465" + fmt.Sprintf("%064s", strings.ToLower(sparkVault[2:])) + strings.Repeat("0", 64), 6},
+	} {
+		entry := out.Payloads[i]
+		p, err := keysign.Decode(entry.Base64)
+		if err != nil {
+			t.Fatalf("decode payload %d: %v", i, err)
+		}
+		if entry.Sequence != i+1 || entry.Description == "" {
+			t.Errorf("payload %d: sequence %d, description %q", i, entry.Sequence, entry.Description)
+		}
+		if p.GetToAddress() != want.to || p.GetToAmount() != "0" || p.GetMemo() != want.data {
+			t.Errorf("payload %d: to %s amount %s memo %s", i, p.GetToAddress(), p.GetToAmount(), p.GetMemo())
+		}
+		if eth := p.GetEthereumSpecific(); eth.GetNonce() != want.nonce || eth.GetGasLimit() != "46560" {
+			t.Errorf("payload %d: ethereum_specific = %+v", i, eth)
+		}
+	}
+
+	// An explicit sender other than the vault cannot be signed for.
+	res, err = handleBuildEVMRevoke(store, pool)(context.Background(), callToolReq("build_evm_revoke", map[string]any{
+		"approvals":     approvals,
+		"from":          permit2,
+		"output_format": "keysign",
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if text := res.Content[0].(mcp.TextContent).Text; !res.IsError || !strings.Contains(text, "does not match vault-derived address") {
+		t.Errorf("expected from mismatch error, got %s", text)
+	}
+}
+
+func TestBuildEVMRevoke_InvalidApprovals(t *testing.T) {
+	handler := handleBuildEVMRevoke(vault.NewStore(), nil)
+	for name, approvals := range map[string]any{
+		"empty":       []any{},
+		"bad spender": []any{map[string]any{"token": usdt, "spender": "0x1"}},
+		"bad type":    []any{map[string]any{"token": usdt, "spender": permit2, "type": "erc777"}},
+		"not a list":  "usdt",
+	} {
+		res, err := handler(context.Background(), callToolReq("build_evm_revoke", map[string]any{"approvals": approvals}))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+)
+
+// maxMulticallCalls caps the calls in one evm_multicall request.
+const maxMulticallCalls = 500
+
+func newEVMMulticallTool() mcp.Tool {
+	return mcp.NewTool("evm_multicall",
+		mcp.WithDescription(
+			"Batch read-only contract calls on an EVM chain into a single Multicall3 aggregate3 eth_call. "+
+				"Each call reports success and its raw return data, decoded when output_types is set. "+
+				"Calls may revert without failing the batch unless allow_failure is false. "+
+				"Use this instead of repeated evm_call requests, e.g. to read many balances or allowances at once.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithArray("calls",
+			mcp.Description(fmt.Sprintf("Calls to make (max %d): objects with \"to\" (contract address), \"data\" (0x-prefixed calldata), "+
+				"optional \"output_types\" (comma-separated ABI types, e.g. \"uint256\") and "+
+				"optional \"allow_failure\" (default true; false fails the whole batch if the call reverts).", maxMulticallCalls)),
+			mcp.Required(),
+		),
+		mcp.WithString("block",
+			mcp.Description("Block number (decimal) or \"latest\" (default \"latest\")."),
+		),
+	)
+}
+
+type multicallItem struct {
+	To           string `json:"to"`
+	Data         string `json:"data"`
+	OutputTypes  string `json:"output_types"`
+	AllowFailure *bool  `json:"allow_failure"`
+}
+
+type multicallResultJSON struct {
+	To          string `json:"to"`
+	Success     bool   `json:"success"`
+	ReturnData  string `json:"return_data"`
+	Decoded     []any  `json:"decoded,omitempty"`
+	DecodeError string `json:"decode_error,omitempty"`
+}
+
+func handleEVMMulticall(pool *evmclient.Pool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		items, err := parseMulticallItems(req.GetArguments()["calls"])
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		calls := make([]evmclient.Call, len(items))
+		for i, item := range items {
+			data, err := hexToBytes(item.Data)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("calls[%d]: invalid data hex: %v", i, err)), nil
+			}
+			if item.OutputTypes != "" {
+				if _, err := parseABITypes(item.OutputTypes); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("calls[%d]: invalid output_types: %v", i, err)), nil
+				}
+			}
+			calls[i] = evmclient.Call{
+				To:           common.HexToAddress(item.To),
+				Data:         data,
+				AllowFailure: item.AllowFailure == nil || *item.AllowFailure,
+			}
+		}
+
+		var blockNum *big.Int
+		if blockStr := req.GetString("block", ""); blockStr != "" && blockStr != "latest" {
+			bn, ok := new(big.Int).SetString(blockStr, 10)
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid block number: %s", blockStr)), nil
+			}
+			blockNum = bn
+		}
+
+		client, _, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+		results, err := client.Multicall(ctx, calls, blockNum)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("multicall failed: %v", err)), nil
+		}
+
+		out := make([]multicallResultJSON, len(results))
+		failed := 0
+		for i, r := range results {
+			out[i] = multicallResultJSON{
+				To:         calls[i].To.Hex(),
+				Success:    r.Success,
+				ReturnData: "0x" + hex.EncodeToString(r.ReturnData),
+			}
+			if !r.Success {
+				failed++
+				continue
+			}
+			if items[i].OutputTypes == "" {
+				continue
+			}
+			args, _ := parseABITypes(items[i].OutputTypes)
+			values, err := args.UnpackValues(r.ReturnData)
+			if err != nil {
+				out[i].DecodeError = err.Error()
+				continue
+			}
+			out[i].Decoded = make([]any, len(values))
+			for j, v := range values {
+				out[i].Decoded[j] = formatABIValue(v)
+			}
+		}
+
+		data, err := json.Marshal(map[string]any{
+			"chain":        chainName,
+			"results":      out,
+			"count":        len(out),
+			"failed_count": failed,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal evm_multicall result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+func parseMulticallItems(raw any) ([]multicallItem, error) {
+	list, ok := raw.([]any)
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("calls must be a non-empty list")
+	}
+	if len(list) > maxMulticallCalls {
+		return nil, fmt.Errorf("at most %d calls can be batched at once, got %d", maxMulticallCalls, len(list))
+	}
+
+	encoded, err := json.Marshal(list)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calls: %v", err)
+	}
+	var items []multicallItem
+	if err := json.Unmarshal(encoded, &items); err != nil {
+		return nil, fmt.Errorf("calls must be objects with to and data: %v", err)
+	}
+	for i := range items {
+		if !common.IsHexAddress(items[i].To) {
+			return nil, fmt.Errorf("calls[%d]: invalid to address: %q", i, items[i].To)
+		}
+	}
+	return items, nil
+}
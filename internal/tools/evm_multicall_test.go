@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestEVMMulticall(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_call": mockERC20Call(6, "USDC", big.NewInt(2_500_000)),
+	})
+	handler := handleEVMMulticall(pool)
+
+	res, err := handler(context.Background(), callToolReq("evm_multicall", map[string]any{
+		"calls": []any{
+			map[string]any{"to": usdt, "data": "0x70a08231000000000000000000000000" + testAddress[2:], "output_types": "uint256"},
+			map[string]any{"to": usdt, "data": "0x95d89b41", "output_types": "string"},
+			map[string]any{"to": usdt, "data": "0x313ce567", "allow_failure": false},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	if result["count"] != float64(3) || result["failed_count"] != float64(0) {
+		t.Fatalf("count %v, failed %v", result["count"], result["failed_count"])
+	}
+	results := result["results"].([]any)
+	for i, want := range []any{"2500000", "USDC"} {
+		decoded := results[i].(map[string]any)["decoded"].([]any)
+		if decoded[0] != want {
+			t.Errorf("results[%d] decoded %v, want %v", i, decoded, want)
+		}
+	}
+	if results[2].(map[string]any)["decoded"] != nil {
+		t.Errorf("results[2] decoded without output_types")
+	}
+}
+
+func TestEVMMulticall_InvalidParams(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{})
+	handler := handleEVMMulticall(pool)
+
+	for name, calls := range map[string]any{
+		"missing calls":    nil,
+		"empty calls":      []any{},
+		"bad address":      []any{map[string]any{"to": "0x123", "data": "0x"}},
+		"bad data":         []any{map[string]any{"to": usdt, "data": "0xzz"}},
+		"bad output types": []any{map[string]any{"to": usdt, "data": "0x", "output_types": "notatype"}},
+		"not an object":    []any{"0x"},
+		"too many calls":   make([]any, maxMulticallCalls+1),
+	} {
+		res, err := handler(context.Background(), callToolReq("evm_multicall", map[string]any{"calls": calls}))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/vultisig/mcp/internal/abiregistry"
 	evmclient "github.com/vultisig/mcp/internal/evm"
 	"github.com/vultisig/mcp/internal/vault"
 )
@@ -15,7 +16,7 @@ import (
 // ---------------------------------------------------------------------------
 
 func TestBuildEVMTx_ChainParam(t *testing.T) {
-	handler := handleBuildEVMTx()
+	handler := handleBuildEVMTx(vault.NewStore(), nil)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -71,7 +72,7 @@ func TestBuildEVMTx_ChainParam(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestBuildEVMTx_ChainIDOverride(t *testing.T) {
-	handler := handleBuildEVMTx()
+	handler := handleBuildEVMTx(vault.NewStore(), nil)
 	ctx := context.Background()
 
 	req := callToolReq("build_evm_tx", map[string]any{
@@ -108,7 +109,7 @@ func TestBuildEVMTx_ChainIDOverride(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestBuildEVMTx_DefaultChainIsEthereum(t *testing.T) {
-	handler := handleBuildEVMTx()
+	handler := handleBuildEVMTx(vault.NewStore(), nil)
 	ctx := context.Background()
 
 	req := callToolReq("build_evm_tx", map[string]any{
@@ -205,7 +206,7 @@ func TestEVMCheckAllowance_UnknownChain(t *testing.T) {
 func TestEVMTxInfo_UnknownChain(t *testing.T) {
 	pool := evmclient.NewPool(map[string]string{})
 	store := vault.NewStore()
-	handler := handleEVMTxInfo(store, pool)
+	handler := handleEVMTxInfo(store, pool, nil)
 	ctx := context.Background()
 
 	req := callToolReq("evm_tx_info", map[string]any{
@@ -223,7 +224,7 @@ func TestEVMTxInfo_UnknownChain(t *testing.T) {
 
 func TestEVMCall_UnknownChain(t *testing.T) {
 	pool := evmclient.NewPool(map[string]string{})
-	handler := handleEVMCall(pool)
+	handler := handleEVMCall(pool, abiregistry.NewMemoryRegistry(nil), nil)
 	ctx := context.Background()
 
 	req := callToolReq("evm_call", map[string]any{
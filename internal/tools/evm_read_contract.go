@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+)
+
+func newEVMReadContractTool() mcp.Tool {
+	return mcp.NewTool("evm_read_contract",
+		mcp.WithDescription(
+			"Call a read-only contract function by signature on any EVM chain: encodes the arguments, runs eth_call and decodes the return values in one step. "+
+				"Pass either signature, e.g. \"balanceOf(address)(uint256)\" or \"getReserveData(address)((uint256 configuration, uint128 liquidityIndex))\", "+
+				"or abi plus method. Tuple and struct return values are rendered as JSON objects keyed by field name. "+
+				"A revert is returned as an error with a decoded revert field.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("EVM chain name. One of: "+chainEnumDesc()),
+			mcp.DefaultString("Ethereum"),
+		),
+		mcp.WithString("to",
+			mcp.Description("Contract address (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("signature",
+			mcp.Description("Function signature with return types: name(input types)(return types). Parameter names are optional; "+
+				"tuples are written as parenthesised component lists, e.g. \"(address token, uint256 amount)[]\"."),
+		),
+		mcp.WithString("abi",
+			mcp.Description("Contract JSON ABI (array or single function fragment), used with method instead of signature."),
+		),
+		mcp.WithString("method",
+			mcp.Description("Function name in abi, or its full signature such as \"safeTransferFrom(address,address,uint256)\" for overloaded functions."),
+		),
+		mcp.WithArray("args",
+			mcp.Description("Function arguments in order. Integers may be numbers or decimal strings; "+
+				"arrays are JSON arrays and tuples are objects keyed by component name or positional arrays."),
+		),
+		mcp.WithString("from",
+			mcp.Description("Sender address for call context (optional)."),
+		),
+		mcp.WithString("block",
+			mcp.Description("Block number (decimal) or \"latest\" (default \"latest\")."),
+		),
+	)
+}
+
+func handleEVMReadContract(pool *evmclient.Pool, reverts *revertDecoder) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName := req.GetString("chain", "Ethereum")
+
+		toStr, err := req.RequireString("to")
+		if err != nil {
+			return mcp.NewToolResultError("missing to parameter"), nil
+		}
+		if !common.IsHexAddress(toStr) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid to address: %s", toStr)), nil
+		}
+		to := common.HexToAddress(toStr)
+
+		var method abi.Method
+		signature := req.GetString("signature", "")
+		abiJSON := req.GetString("abi", "")
+		switch {
+		case signature != "" && abiJSON != "":
+			return mcp.NewToolResultError("pass either signature or abi, not both"), nil
+		case signature != "":
+			method, err = parseFunctionSignature(signature)
+		case abiJSON != "":
+			name := req.GetString("method", "")
+			if name == "" {
+				return mcp.NewToolResultError("method is required with abi"), nil
+			}
+			method, err = methodFromABI(abiJSON, name)
+		default:
+			return mcp.NewToolResultError("signature or abi is required"), nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		args, err := methodArgs(method, req.GetArguments()["args"])
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		packed, err := method.Inputs.Pack(args...)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("encode arguments: %v", err)), nil
+		}
+
+		msg := ethereum.CallMsg{
+			To:   &to,
+			Data: append(method.ID, packed...),
+		}
+		if fromStr := req.GetString("from", ""); fromStr != "" {
+			if !common.IsHexAddress(fromStr) {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid from address: %s", fromStr)), nil
+			}
+			msg.From = common.HexToAddress(fromStr)
+		}
+
+		var blockNum *big.Int
+		if blockStr := req.GetString("block", ""); blockStr != "" && blockStr != "latest" {
+			bn, ok := new(big.Int).SetString(blockStr, 10)
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid block number: %s", blockStr)), nil
+			}
+			blockNum = bn
+		}
+
+		client, chainID, err := pool.Get(ctx, chainName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("chain %s unavailable: %v", chainName, err)), nil
+		}
+
+		output, err := client.CallContract(ctx, msg, blockNum)
+		if err != nil {
+			if revert, ok := reverts.fromError(ctx, client, chainID.Uint64(), &to, err); ok {
+				return revertResult(method.Sig, revert), nil
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("eth_call failed: %v", err)), nil
+		}
+
+		resp := map[string]any{
+			"chain":     chainName,
+			"to":        to.Hex(),
+			"signature": method.Sig,
+			"raw":       "0x" + hex.EncodeToString(output),
+		}
+		if len(method.Outputs) > 0 {
+			if len(output) == 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("%s returned no data; check that %s is a contract on %s implementing %s", method.Sig, to.Hex(), chainName, method.Sig)), nil
+			}
+			values, err := method.Outputs.UnpackValues(output)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("decode output failed: %v", err)), nil
+			}
+			resp["result"] = renderOutputs(method.Outputs, values)
+		}
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal evm_read_contract result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// methodArgs converts the args parameter, a JSON array or a string holding
+// one, to the values method.Inputs packs.
+func methodArgs(method abi.Method, raw any) ([]any, error) {
+	if s, ok := raw.(string); ok {
+		if err := json.Unmarshal([]byte(s), &raw); err != nil {
+			return nil, fmt.Errorf("args must be a JSON array: %v", err)
+		}
+	}
+	var list []any
+	if raw != nil {
+		var ok bool
+		if list, ok = raw.([]any); !ok {
+			return nil, fmt.Errorf("args must be a JSON array")
+		}
+	}
+	if len(list) != len(method.Inputs) {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", method.Sig, len(method.Inputs), len(list))
+	}
+
+	args := make([]any, len(list))
+	for i, v := range list {
+		arg, err := abiValueFromJSON(v, method.Inputs[i].Type)
+		if err != nil {
+			return nil, fmt.Errorf("args[%d] (%s): %v", i, method.Inputs[i].Type, err)
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
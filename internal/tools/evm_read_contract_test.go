@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// mockMethodCall answers eth_call with method's outputs packed from values
+// and records the calldata it was called with.
+func mockMethodCall(t *testing.T, method abi.Method, calldata *string, values ...any) func(json.RawMessage) any {
+	t.Helper()
+	out, err := method.Outputs.Pack(values...)
+	if err != nil {
+		t.Fatalf("pack outputs: %v", err)
+	}
+	return func(params json.RawMessage) any {
+		var args []struct {
+			Input string `json:"input"`
+			Data  string `json:"data"`
+		}
+		_ = json.Unmarshal(params, &args)
+		*calldata = args[0].Input + args[0].Data
+		return "0x" + ethcommon.Bytes2Hex(out)
+	}
+}
+
+func TestEVMReadContract_Signature(t *testing.T) {
+	sig := "getPositions(address owner)((uint96 nonce, address operator)[] positions, bool active)"
+	method, err := parseFunctionSignature(sig)
+	if err != nil {
+		t.Fatalf("parseFunctionSignature: %v", err)
+	}
+	if method.Sig != "getPositions(address)" {
+		t.Fatalf("Sig = %s", method.Sig)
+	}
+
+	type position struct {
+		Nonce    *big.Int
+		Operator ethcommon.Address
+	}
+	var calldata string
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_call": mockMethodCall(t, method, &calldata,
+			[]position{{big.NewInt(7), ethcommon.HexToAddress(permit2)}}, true),
+	})
+
+	res, err := handleEVMReadContract(pool, nil)(context.Background(), callToolReq("evm_read_contract", map[string]any{
+		"to":        usdt,
+		"signature": sig,
+		"args":      []any{testAddress},
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	if !strings.HasPrefix(calldata, "0x"+ethcommon.Bytes2Hex(method.ID)) || !strings.HasSuffix(strings.ToLower(calldata), strings.ToLower(testAddress[2:])) {
+		t.Errorf("calldata = %s", calldata)
+	}
+
+	got, _ := json.Marshal(result["result"])
+	want := `{"active":true,"positions":[{"nonce":"7","operator":"` + permit2 + `"}]}`
+	if string(got) != want {
+		t.Errorf("result = %s, want %s", got, want)
+	}
+	if result["signature"] != "getPositions(address)" {
+		t.Errorf("signature = %v", result["signature"])
+	}
+}
+
+func TestEVMReadContract_ABI(t *testing.T) {
+	abiJSON := `[
+		{"type":"function","name":"quote","stateMutability":"view",
+		 "inputs":[{"name":"params","type":"tuple","components":[{"name":"tokenIn","type":"address"},{"name":"amountIn","type":"uint256"},{"name":"fee","type":"uint24"}]}],
+		 "outputs":[{"name":"amountOut","type":"uint256"}]},
+		{"type":"function","name":"quote","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
+	]`
+	method, err := methodFromABI(abiJSON, "quote((address,uint256,uint24))")
+	if err != nil {
+		t.Fatalf("methodFromABI: %v", err)
+	}
+	var calldata string
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_call": mockMethodCall(t, method, &calldata, big.NewInt(995)),
+	})
+	handler := handleEVMReadContract(pool, nil)
+
+	for _, params := range []any{
+		map[string]any{"tokenIn": usdt, "amountIn": "1000000", "fee": float64(500)},
+		[]any{usdt, float64(1_000_000), "500"},
+	} {
+		calldata = ""
+		res, err := handler(context.Background(), callToolReq("evm_read_contract", map[string]any{
+			"to":     usdt,
+			"abi":    abiJSON,
+			"method": "quote((address, uint256, uint24))",
+			"args":   []any{params},
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result := decodeResult(t, res)
+		if result["result"] != "995" {
+			t.Errorf("result = %v", result["result"])
+		}
+		packed, _ := method.Inputs.Pack(struct {
+			TokenIn  ethcommon.Address
+			AmountIn *big.Int
+			Fee      *big.Int
+		}{ethcommon.HexToAddress(usdt), big.NewInt(1_000_000), big.NewInt(500)})
+		if want := "0x" + ethcommon.Bytes2Hex(append(method.ID, packed...)); calldata != want {
+			t.Errorf("calldata = %s, want %s", calldata, want)
+		}
+	}
+}
+
+func TestEVMReadContract_InvalidParams(t *testing.T) {
+	pool, _ := mockEVMPool(t, map[string]any{"eth_call": "0x"})
+	handler := handleEVMReadContract(pool, nil)
+
+	for name, args := range map[string]map[string]any{
+		"no signature":    {"to": usdt},
+		"bad to":          {"to": "0x123", "signature": "totalSupply()(uint256)"},
+		"bad signature":   {"to": usdt, "signature": "totalSupply(uint256"},
+		"bad type":        {"to": usdt, "signature": "f(uint7)(uint256)"},
+		"both":            {"to": usdt, "signature": "totalSupply()(uint256)", "abi": "[]"},
+		"abi no method":   {"to": usdt, "abi": "[]"},
+		"unknown method":  {"to": usdt, "abi": "[]", "method": "foo"},
+		"arg count":       {"to": usdt, "signature": "balanceOf(address)(uint256)", "args": []any{}},
+		"bad arg":         {"to": usdt, "signature": "balanceOf(address)(uint256)", "args": []any{"0x12"}},
+		"fractional":      {"to": usdt, "signature": "f(uint256)(uint256)", "args": []any{1.5}},
+		"missing field":   {"to": usdt, "signature": "f((uint256 a, uint256 b))(uint256)", "args": []any{map[string]any{"a": "1"}}},
+		"no return data":  {"to": usdt, "signature": "totalSupply()(uint256)"},
+		"overloaded name": {"to": usdt, "abi": `[{"type":"function","name":"f","inputs":[]},{"type":"function","name":"f","inputs":[{"name":"a","type":"uint256"}]}]`, "method": "f"},
+	} {
+		res, err := handler(context.Background(), callToolReq("evm_read_contract", args))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+}
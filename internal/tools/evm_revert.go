@@ -0,0 +1,318 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/vultisig/mcp/internal/abiregistry"
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/fourbyte"
+)
+
+// Revert kinds.
+const (
+	revertError    = "error"
+	revertPanic    = "panic"
+	revertCustom   = "custom"
+	revertEmpty    = "empty"
+	revertUnknown  = "unknown"
+	revertOutOfGas = "out_of_gas"
+)
+
+// revertInfo is a decoded revert, surfaced as the revert field of EVM tool
+// results.
+type revertInfo struct {
+	Kind string `json:"kind"`
+	// Reason is the revert message, panic description or custom error
+	// name.
+	Reason string `json:"reason,omitempty"`
+	// Meaning explains reasons that are codes, such as Aave's numeric
+	// errors, or well-known custom errors.
+	Meaning   string         `json:"meaning,omitempty"`
+	PanicCode string         `json:"panic_code,omitempty"`
+	Signature string         `json:"signature,omitempty"`
+	Args      map[string]any `json:"args,omitempty"`
+	// Source says where a custom error's signature came from: the
+	// contract's ABI, the built-in table of common errors, or 4byte.
+	Source   string `json:"source,omitempty"`
+	Selector string `json:"selector,omitempty"`
+	Data     string `json:"data,omitempty"`
+}
+
+// message renders the revert for error strings.
+func (r *revertInfo) message() string {
+	switch {
+	case r.Meaning != "" && r.Reason != "":
+		return fmt.Sprintf("%s (%s)", r.Reason, r.Meaning)
+	case r.Reason != "":
+		return r.Reason
+	case r.Selector != "":
+		return "unknown error " + r.Selector
+	}
+	return "no reason given"
+}
+
+var (
+	errorStringSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector       = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// panicReasons describes the Solidity Panic(uint256) codes.
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "corrupted storage byte array",
+	0x31: "pop() on an empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory or too large an allocation",
+	0x51: "call to an uninitialised internal function",
+}
+
+// aaveErrorCodes are the numeric Error(string) reasons of Aave V3 pools
+// before v3.4 switched to custom errors.
+var aaveErrorCodes = map[string]string{
+	"26": "INVALID_AMOUNT",
+	"27": "RESERVE_INACTIVE",
+	"28": "RESERVE_FROZEN",
+	"29": "RESERVE_PAUSED",
+	"30": "BORROWING_NOT_ENABLED",
+	"32": "NOT_ENOUGH_AVAILABLE_USER_BALANCE",
+	"33": "INVALID_INTEREST_RATE_MODE_SELECTED",
+	"34": "COLLATERAL_BALANCE_IS_ZERO",
+	"35": "HEALTH_FACTOR_LOWER_THAN_LIQUIDATION_THRESHOLD",
+	"36": "COLLATERAL_CANNOT_COVER_NEW_BORROW",
+	"37": "COLLATERAL_SAME_AS_BORROWING_CURRENCY",
+	"39": "NO_DEBT_OF_SELECTED_TYPE",
+	"43": "UNDERLYING_BALANCE_ZERO",
+	"45": "HEALTH_FACTOR_NOT_BELOW_THRESHOLD",
+	"46": "COLLATERAL_CANNOT_BE_LIQUIDATED",
+	"50": "BORROW_CAP_EXCEEDED",
+	"51": "SUPPLY_CAP_EXCEEDED",
+}
+
+// knownErrors are custom errors common enough to decode without an ABI or
+// a 4byte lookup, with what they mean for the user where the name alone
+// is not clear.
+var knownErrors = buildKnownErrors(map[string]string{
+	// OpenZeppelin 5 token errors (ERC-6093).
+	"ERC20InsufficientAllowance(address spender, uint256 allowance, uint256 needed)":               "insufficient allowance: approve the spender for at least the needed amount",
+	"ERC20InsufficientBalance(address sender, uint256 balance, uint256 needed)":                    "insufficient token balance",
+	"ERC20InvalidSender(address sender)":                                                           "",
+	"ERC20InvalidReceiver(address receiver)":                                                       "invalid recipient (often the zero address)",
+	"ERC20InvalidApprover(address approver)":                                                       "",
+	"ERC20InvalidSpender(address spender)":                                                         "",
+	"ERC721NonexistentToken(uint256 tokenId)":                                                      "the NFT does not exist",
+	"ERC721IncorrectOwner(address sender, uint256 tokenId, address owner)":                         "the sender does not own the NFT",
+	"ERC721InsufficientApproval(address operator, uint256 tokenId)":                                "the operator is not approved for the NFT",
+	"ERC1155InsufficientBalance(address sender, uint256 balance, uint256 needed, uint256 tokenId)": "insufficient ERC-1155 balance",
+	"ERC1155MissingApprovalForAll(address operator, address owner)":                                "the operator is not approved for the owner's tokens",
+	"ERC2612ExpiredSignature(uint256 deadline)":                                                    "the permit's deadline has passed",
+	"ERC2612InvalidSigner(address signer, address owner)":                                          "the permit was not signed by the owner",
+	"InvalidAccountNonce(address account, uint256 currentNonce)":                                   "",
+	// OpenZeppelin 5 access control, security and utilities.
+	"OwnableUnauthorizedAccount(address account)":                           "only the owner can call this function",
+	"AccessControlUnauthorizedAccount(address account, bytes32 neededRole)": "the caller lacks the required role",
+	"EnforcedPause()":                             "the contract is paused",
+	"ReentrancyGuardReentrantCall()":              "",
+	"SafeERC20FailedOperation(address token)":     "a token transfer or approval failed",
+	"AddressInsufficientBalance(address account)": "insufficient native balance",
+	"FailedInnerCall()":                           "",
+	// Uniswap Permit2.
+	"AllowanceExpired(uint256 deadline)":          "the Permit2 allowance has expired",
+	"InsufficientAllowance(uint256 amount)":       "insufficient Permit2 allowance",
+	"InvalidNonce()":                              "the permit nonce was already used",
+	"SignatureExpired(uint256 signatureDeadline)": "the permit signature has expired",
+	"InvalidSignature()":                          "",
+	"InvalidSigner()":                             "",
+	"InvalidAmount(uint256 maxAmount)":            "the requested amount exceeds the permitted amount",
+	// Uniswap Universal Router.
+	"V3TooLittleReceived()":                                "slippage: the swap would return less than the minimum output",
+	"V3TooMuchRequested()":                                 "slippage: the swap would cost more than the maximum input",
+	"V2TooLittleReceived()":                                "slippage: the swap would return less than the minimum output",
+	"V2TooMuchRequested()":                                 "slippage: the swap would cost more than the maximum input",
+	"TransactionDeadlinePassed()":                          "the swap's deadline has passed",
+	"ExecutionFailed(uint256 commandIndex, bytes message)": "a router command failed; message holds its revert data",
+	"InsufficientETH()":                                    "not enough native coin sent with the swap",
+	// Aave V3.4 pool errors.
+	"HealthFactorLowerThanLiquidationThreshold()": "the health factor would fall below 1: repay debt or add collateral first",
+	"CollateralCannotCoverNewBorrow()":            "not enough collateral for the borrow",
+	"NotEnoughAvailableUserBalance()":             "not enough supplied balance to withdraw",
+	"HealthFactorNotBelowThreshold()":             "the position is healthy and cannot be liquidated",
+	"CollateralBalanceIsZero()":                   "no collateral supplied",
+	"SupplyCapExceeded()":                         "the reserve's supply cap is reached",
+	"BorrowCapExceeded()":                         "the reserve's borrow cap is reached",
+	"ReserveFrozen()":                             "the reserve is frozen",
+	"ReservePaused()":                             "the reserve is paused",
+	"ReserveInactive()":                           "the reserve is inactive",
+})
+
+type knownError struct {
+	err     abi.Error
+	meaning string
+}
+
+func buildKnownErrors(sigs map[string]string) map[[4]byte]knownError {
+	table := make(map[[4]byte]knownError, len(sigs))
+	for sig, meaning := range sigs {
+		method, err := parseFunctionSignature(sig)
+		if err != nil {
+			panic(fmt.Sprintf("known error %s: %v", sig, err))
+		}
+		e := abi.NewError(method.RawName, method.Inputs)
+		table[[4]byte(e.ID[:4])] = knownError{err: e, meaning: meaning}
+	}
+	return table
+}
+
+// revertDecoder decodes revert data with the reverting contract's ABI,
+// the built-in table of common errors and 4byte.directory, in that order.
+// A nil decoder uses the built-in table only.
+type revertDecoder struct {
+	registry *abiregistry.Registry
+	fourbyte *fourbyte.Client
+}
+
+func newRevertDecoder(registry *abiregistry.Registry, fbClient *fourbyte.Client) *revertDecoder {
+	return &revertDecoder{registry: registry, fourbyte: fbClient}
+}
+
+// fromError decodes the revert behind a failed eth_call or
+// eth_estimateGas to contract. ok is false for errors that are not
+// reverts.
+func (d *revertDecoder) fromError(ctx context.Context, client *evmclient.Client, chainID uint64, contract *common.Address, err error) (*revertInfo, bool) {
+	data, ok := evmclient.RevertData(err)
+	if !ok {
+		return nil, false
+	}
+	return d.decode(ctx, client, chainID, contract, data), true
+}
+
+// decode decodes revert data returned by contract, which may be nil.
+func (d *revertDecoder) decode(ctx context.Context, client *evmclient.Client, chainID uint64, contract *common.Address, data []byte) *revertInfo {
+	info := &revertInfo{Data: "0x" + hex.EncodeToString(data)}
+	if len(data) == 0 {
+		info.Kind = revertEmpty
+		info.Data = ""
+		return info
+	}
+	if len(data) < 4 {
+		info.Kind = revertUnknown
+		return info
+	}
+	selector := data[:4]
+
+	switch {
+	case bytes.Equal(selector, errorStringSelector):
+		values, err := abi.Arguments{{Type: mustABIType("string")}}.UnpackValues(data[4:])
+		if err == nil {
+			info.Kind = revertError
+			info.Reason = values[0].(string)
+			if code, ok := aaveErrorCodes[info.Reason]; ok {
+				info.Meaning = "Aave V3: " + code
+			}
+			info.Data = ""
+			return info
+		}
+	case bytes.Equal(selector, panicSelector):
+		values, err := abi.Arguments{{Type: mustABIType("uint256")}}.UnpackValues(data[4:])
+		if err == nil {
+			code := values[0].(*big.Int)
+			info.Kind = revertPanic
+			info.PanicCode = fmt.Sprintf("0x%02x", code)
+			info.Reason = "panic " + info.PanicCode
+			if code.IsUint64() {
+				if reason, ok := panicReasons[code.Uint64()]; ok {
+					info.Reason = reason
+				}
+			}
+			info.Data = ""
+			return info
+		}
+	}
+
+	info.Selector = "0x" + hex.EncodeToString(selector)
+	if d.decodeCustom(ctx, client, chainID, contract, data, info) {
+		info.Kind = revertCustom
+		info.Data = ""
+		return info
+	}
+	info.Kind = revertUnknown
+	return info
+}
+
+// decodeCustom fills in a custom error, reporting whether one matched.
+func (d *revertDecoder) decodeCustom(ctx context.Context, client *evmclient.Client, chainID uint64, contract *common.Address, data []byte, info *revertInfo) bool {
+	match := func(e abi.Error, source, meaning string) bool {
+		values, err := e.Inputs.UnpackValues(data[4:])
+		if err != nil {
+			return false
+		}
+		info.Reason = e.Name
+		info.Signature = e.Sig
+		info.Source = source
+		info.Meaning = meaning
+		if len(e.Inputs) > 0 {
+			info.Args = renderArguments(e.Inputs, values, "arg")
+		}
+		return true
+	}
+
+	if d != nil && d.registry != nil && contract != nil && client != nil {
+		if c, err := d.registry.Contract(ctx, chainID, *contract, client); err == nil {
+			if e, err := c.ABI.ErrorByID([4]byte(data[:4])); err == nil {
+				return match(*e, "abi", knownErrors[[4]byte(data[:4])].meaning)
+			}
+		}
+	}
+	if known, ok := knownErrors[[4]byte(data[:4])]; ok && match(known.err, "table", known.meaning) {
+		return true
+	}
+	if d == nil || d.fourbyte == nil {
+		return false
+	}
+	sigs, err := d.fourbyte.ResolveSelector(ctx, info.Selector)
+	if err != nil {
+		return false
+	}
+	// Error selectors share the function signature namespace, so the
+	// function matcher applies as is.
+	matches := decodeWithSignatures(sigs, data)
+	if len(matches) == 0 {
+		return false
+	}
+	m := matches[0].method
+	return match(abi.NewError(m.RawName, m.Inputs), "4byte", "")
+}
+
+func mustABIType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// revertResult is the tool error for a reverted call: JSON holding the
+// error message and the structured revert.
+func revertResult(prefix string, revert *revertInfo) *mcp.CallToolResult {
+	data, err := json.Marshal(map[string]any{
+		"error":  prefix + ": execution reverted: " + revert.message(),
+		"revert": revert,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(prefix + ": execution reverted: " + revert.message())
+	}
+	return mcp.NewToolResultError(string(data))
+}
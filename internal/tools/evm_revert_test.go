@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/vultisig/mcp/internal/abiregistry"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// encodeRevert ABI-encodes a revert with the error signature sig.
+func encodeRevert(t *testing.T, sig string, args ...any) []byte {
+	t.Helper()
+	method, err := parseFunctionSignature(sig)
+	if err != nil {
+		t.Fatalf("parse %s: %v", sig, err)
+	}
+	packed, err := method.Inputs.Pack(args...)
+	if err != nil {
+		t.Fatalf("pack %s: %v", sig, err)
+	}
+	return append(method.ID, packed...)
+}
+
+// mockRevert is an eth_call result reverting with data.
+func mockRevert(data []byte) mockRPCError {
+	return mockRPCError{Code: 3, Message: "execution reverted", Data: "0x" + ethcommon.Bytes2Hex(data)}
+}
+
+func TestRevertDecoder_Decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		kind    string
+		reason  string
+		meaning string
+	}{
+		{
+			name:   "error string",
+			data:   encodeRevert(t, "Error(string)", "ERC20: transfer amount exceeds balance"),
+			kind:   revertError,
+			reason: "ERC20: transfer amount exceeds balance",
+		},
+		{
+			name:    "aave error code",
+			data:    encodeRevert(t, "Error(string)", "35"),
+			kind:    revertError,
+			reason:  "35",
+			meaning: "Aave V3: HEALTH_FACTOR_LOWER_THAN_LIQUIDATION_THRESHOLD",
+		},
+		{
+			name:   "panic",
+			data:   encodeRevert(t, "Panic(uint256)", big.NewInt(0x11)),
+			kind:   revertPanic,
+			reason: "arithmetic overflow or underflow",
+		},
+		{
+			name:    "known custom error",
+			data:    encodeRevert(t, "HealthFactorLowerThanLiquidationThreshold()"),
+			kind:    revertCustom,
+			reason:  "HealthFactorLowerThanLiquidationThreshold",
+			meaning: "the health factor would fall below 1: repay debt or add collateral first",
+		},
+		{
+			name: "empty",
+			kind: revertEmpty,
+		},
+		{
+			name: "unknown custom error",
+			data: encodeRevert(t, "SomethingWentWrong(uint256)", big.NewInt(1)),
+			kind: revertUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d *revertDecoder
+			got := d.decode(context.Background(), nil, 1, nil, tt.data)
+			if got.Kind != tt.kind || got.Reason != tt.reason || got.Meaning != tt.meaning {
+				t.Errorf("decode = %+v", got)
+			}
+		})
+	}
+}
+
+func TestEVMCall_Revert(t *testing.T) {
+	data := encodeRevert(t, "ERC20InsufficientAllowance(address,uint256,uint256)",
+		ethcommon.HexToAddress(permit2), big.NewInt(0), big.NewInt(1000))
+	pool, _ := mockEVMPool(t, map[string]any{"eth_call": mockRevert(data)})
+	reverts := newRevertDecoder(abiregistry.NewMemoryRegistry(nil), nil)
+
+	res, err := handleEVMCall(pool, abiregistry.NewMemoryRegistry(nil), reverts)(context.Background(), callToolReq("evm_call", map[string]any{
+		"to":   usdt,
+		"data": transferCalldata(testAddress, 1000),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Fatalf("expected a tool error, got %v", res.Content)
+	}
+	var got struct {
+		Error  string     `json:"error"`
+		Revert revertInfo `json:"revert"`
+	}
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !strings.Contains(got.Error, "insufficient allowance") {
+		t.Errorf("error = %q", got.Error)
+	}
+	r := got.Revert
+	if r.Kind != revertCustom || r.Reason != "ERC20InsufficientAllowance" || r.Source != "table" ||
+		r.Args["needed"] != "1000" || !strings.EqualFold(r.Args["spender"].(string), permit2) {
+		t.Errorf("revert = %+v", r)
+	}
+}
+
+func TestEVMReadContract_RevertWithRegistryABI(t *testing.T) {
+	const vaultABI = `[
+		{"type":"function","name":"withdraw","stateMutability":"nonpayable","inputs":[{"name":"shares","type":"uint256"}],"outputs":[]},
+		{"type":"error","name":"ExceededMaxWithdraw","inputs":[{"name":"owner","type":"address"},{"name":"assets","type":"uint256"},{"name":"max","type":"uint256"}]}
+	]`
+	registry := abiregistry.NewMemoryRegistry(nil)
+	parsed, err := registry.Import(1, ethcommon.HexToAddress(usdt), "Vault", []byte(vaultABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := parsed.Errors["ExceededMaxWithdraw"]
+	packed, err := e.Inputs.Pack(ethcommon.HexToAddress(testAddress), big.NewInt(5), big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_getStorageAt": proxyStorage("", ""),
+		"eth_call":         mockRevert(append(e.ID[:4:4], packed...)),
+	})
+
+	res, err := handleEVMReadContract(pool, newRevertDecoder(registry, nil))(context.Background(), callToolReq("evm_read_contract", map[string]any{
+		"to":        usdt,
+		"signature": "withdraw(uint256)",
+		"args":      []any{"5"},
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got struct {
+		Revert revertInfo `json:"revert"`
+	}
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if r := got.Revert; r.Source != "abi" || r.Signature != "ExceededMaxWithdraw(address,uint256,uint256)" || r.Args["max"] != "2" {
+		t.Errorf("revert = %+v", r)
+	}
+}
+
+func TestGetTxStatus_FailedTxRevert(t *testing.T) {
+	tx := signedTestTx(t, usdt, 100_000, []byte{0xa9, 0x05, 0x9c, 0xbb})
+	receipt := testReceipt(tx, types.ReceiptStatusFailed, 30_000)
+
+	var callBlock string
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_getTransactionReceipt": receipt,
+		"eth_getTransactionByHash":  tx,
+		"eth_blockNumber":           "0x70",
+		"eth_call": func(params json.RawMessage) any {
+			var args []json.RawMessage
+			_ = json.Unmarshal(params, &args)
+			_ = json.Unmarshal(args[1], &callBlock)
+			return mockRevert(encodeRevert(t, "Error(string)", "ERC20: transfer amount exceeds balance"))
+		},
+	})
+
+	res, err := handleGetTxStatus(vault.NewStore(), pool, nil, nil, nil, nil, nil, nil)(context.Background(), callToolReq("get_tx_status", map[string]any{
+		"chain":   "Ethereum",
+		"tx_hash": tx.Hash().Hex(),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	revert, _ := result["revert"].(map[string]any)
+	if result["status"] != "failed" || revert["reason"] != "ERC20: transfer amount exceeds balance" {
+		t.Errorf("result = %v", result)
+	}
+	if callBlock != "0x63" {
+		t.Errorf("replayed at block %s, want the parent block 0x63", callBlock)
+	}
+}
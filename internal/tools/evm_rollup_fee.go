@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+)
+
+// rollupFeeJSON is a rollup's fee quote for a transaction.
+type rollupFeeJSON struct {
+	Model string `json:"model"`
+	// IncludedInGas reports whether the L1 or pubdata cost is already
+	// part of the gas estimate rather than charged on top.
+	IncludedInGas        bool   `json:"included_in_gas"`
+	L1Fee                string `json:"l1_fee,omitempty"`
+	L1FeeFormatted       string `json:"l1_fee_formatted,omitempty"`
+	L1Gas                uint64 `json:"l1_gas,omitempty"`
+	L1BaseFee            string `json:"l1_base_fee,omitempty"`
+	GasEstimate          uint64 `json:"gas_estimate,omitempty"`
+	MaxFeePerGas         string `json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas,omitempty"`
+	GasPerPubdataLimit   uint64 `json:"gas_per_pubdata_limit,omitempty"`
+}
+
+func newRollupFeeJSON(f *evmclient.RollupFee, ticker string) *rollupFeeJSON {
+	r := &rollupFeeJSON{
+		Model:              f.Model,
+		IncludedInGas:      f.Model != evmclient.FeeModelOPStack,
+		L1Gas:              f.L1Gas,
+		GasEstimate:        f.GasEstimate,
+		GasPerPubdataLimit: f.GasPerPubdataLimit,
+	}
+	if f.L1Fee != nil {
+		r.L1Fee = f.L1Fee.String()
+		r.L1FeeFormatted = evmclient.FormatUnits(f.L1Fee, 18) + " " + ticker
+	}
+	if f.L1BaseFee != nil {
+		r.L1BaseFee = f.L1BaseFee.String()
+	}
+	if f.MaxFeePerGas != nil {
+		r.MaxFeePerGas = f.MaxFeePerGas.String()
+		r.MaxPriorityFeePerGas = f.MaxPriorityFeePerGas.String()
+	}
+	return r
+}
+
+// evmRollupFee quotes the rollup fees of p sent from from. It returns nil
+// on chains with plain gas pricing.
+func evmRollupFee(ctx context.Context, client *evmclient.Client, chainName string, from common.Address, p *evmTxParams) (*evmclient.RollupFee, error) {
+	if evmclient.FeeModel(chainName) == evmclient.FeeModelL1 {
+		return nil, nil
+	}
+	unsigned, err := evmSigningPayload(ethtypes.NewTx(p.txData()), p.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("encode transaction: %v", err)
+	}
+	to := p.to
+	fee, err := client.EstimateRollupFee(ctx, chainName, ethereum.CallMsg{
+		From:  from,
+		To:    &to,
+		Value: p.value,
+		Data:  p.data,
+	}, unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quote %s fees: %v", chainName, err)
+	}
+	return fee, nil
+}
+
+// checkEVMFunds fails when from's native balance cannot cover value plus
+// the max network fee, which nodes reject before the transaction is mined.
+func checkEVMFunds(ctx context.Context, client *evmclient.Client, chainName string, from common.Address, value, networkFee *big.Int) error {
+	balance, err := client.ETH().BalanceAt(ctx, from, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get native balance: %v", err)
+	}
+	need := new(big.Int).Add(value, networkFee)
+	if balance.Cmp(need) >= 0 {
+		return nil
+	}
+	ticker := evmclient.NativeTicker(chainName)
+	return fmt.Errorf("insufficient %s balance: %s has %s, needs %s %s (value %s + max network fee %s)",
+		ticker, from.Hex(), evmclient.FormatUnits(balance, 18), evmclient.FormatUnits(need, 18), ticker,
+		evmclient.FormatUnits(value, 18), evmclient.FormatUnits(networkFee, 18))
+}
+
+// evmNetworkFee returns the most p can cost from in fees, including the L1
+// fee on OP-stack chains, together with the rollup quote. It fails when
+// from's native balance cannot cover the fee plus p's value.
+func evmNetworkFee(ctx context.Context, client *evmclient.Client, chainName string, from common.Address, p *evmTxParams) (*big.Int, *evmclient.RollupFee, error) {
+	rollup, err := evmRollupFee(ctx, client, chainName, from, p)
+	if err != nil {
+		return nil, nil, err
+	}
+	fee := new(big.Int).Add(p.maxNetworkFee(), rollup.ExtraFee())
+	if err := checkEVMFunds(ctx, client, chainName, from, p.value, fee); err != nil {
+		return nil, nil, err
+	}
+	return fee, rollup, nil
+}
@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+func TestEVMTxInfo_OPStackL1Fee(t *testing.T) {
+	pool, rpc := mockEVMChainPool(t, "Optimism", map[string]any{
+		"eth_getTransactionCount":  "0x2",
+		"eth_maxPriorityFeePerGas": "0x5f5e100",
+		"eth_getBlockByNumber":     mockHeader("0x3b9aca00"),
+		"eth_estimateGas":          "0x5208",
+		"eth_call":                 fmt.Sprintf("0x%064x", 50_000_000_000_000), // 0.00005 ETH L1 fee
+		"eth_getBalance":           "0x51dac207a000",                           // 0.00009 ETH
+	})
+	res, err := handleEVMTxInfo(vault.NewStore(), pool, nil)(context.Background(), callToolReq("evm_tx_info", map[string]any{
+		"chain":   "Optimism",
+		"address": testAddress,
+		"to":      permit2,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+
+	// 21,000 gas at 1.1 gwei expected and 2.1 gwei max, plus the L1 fee.
+	if result["fee_model"] != "op_stack" || result["estimated_fee"] != "73100000000000" || result["max_network_fee"] != "94100000000000" {
+		t.Errorf("result = %v", result)
+	}
+	if result["sufficient_funds"] != false || result["shortfall"] != "4100000000000" {
+		t.Errorf("funds = %v, shortfall %v", result["sufficient_funds"], result["shortfall"])
+	}
+	rollup, _ := result["rollup_fee"].(map[string]any)
+	if rollup["l1_fee"] != "50000000000000" || rollup["included_in_gas"] != false {
+		t.Errorf("rollup_fee = %v", rollup)
+	}
+	if rpc.callCount("eth_call") != 1 {
+		t.Errorf("eth_call count = %d", rpc.callCount("eth_call"))
+	}
+}
+
+func TestEVMGasOracle_ArbitrumL1Gas(t *testing.T) {
+	pool, _ := mockEVMChainPool(t, "Arbitrum", map[string]any{
+		"eth_feeHistory": map[string]any{
+			"oldestBlock":   "0x10",
+			"baseFeePerGas": []string{"0x2540be400", "0x2540be400"},
+			"gasUsedRatio":  []float64{0.5},
+			"reward":        [][]string{{"0x3b9aca00", "0x77359400", "0xb2d05e00"}},
+		},
+		// gasEstimateComponents: 26,000 gas, 5,000 of it for L1 data, at a
+		// 0.01 gwei base fee.
+		"eth_call": fmt.Sprintf("0x%064x%064x%064x%064x", 26_000, 5_000, 10_000_000, 20_000_000_000),
+	})
+	res, err := handleEVMGasOracle(pool, mockCoinGeckoPrice(t, "ethereum", 2000))(context.Background(), callToolReq("evm_gas_oracle", map[string]any{
+		"chains": []any{"Arbitrum"},
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chain := decodeResult(t, res)["chains"].([]any)[0].(map[string]any)
+	if chain["fee_model"] != "arbitrum" || chain["rollup_fee_error"] != nil {
+		t.Fatalf("chain = %v", chain)
+	}
+	// The L1 gas is added to each transfer, priced at 12 gwei.
+	costs := chain["transfer_cost"].(map[string]any)
+	for name, want := range map[string]float64{"native": 0.624, "erc20": 1.68} {
+		cost := costs[name].(map[string]any)
+		if cost["fee_usd"] != want || cost["l1_fee"] != "50000000000" {
+			t.Errorf("%s transfer = %v, want fee_usd %v", name, cost, want)
+		}
+	}
+}
+
+func TestBuildERC20Transfer_InsufficientNativeBalance(t *testing.T) {
+	rpc, call := erc20TransferPool(t, big.NewInt(5_000_000))
+	rpc.results["eth_getBalance"] = "0x1"
+
+	res := call(map[string]any{"token": usdt, "to": permit2, "amount": "1"})
+	text := res.Content[0].(mcp.TextContent).Text
+	if !res.IsError || !strings.Contains(text, "insufficient ETH balance") {
+		t.Errorf("expected insufficient native balance error, got %s", text)
+	}
+}
+
+func TestBuildEVMTx_OPStackL1Fee(t *testing.T) {
+	pool, _ := mockEVMChainPool(t, "Optimism", map[string]any{
+		"eth_getTransactionCount":  "0x2",
+		"eth_maxPriorityFeePerGas": "0x5f5e100",
+		"eth_getBlockByNumber":     mockHeader("0x3b9aca00"),
+		"eth_estimateGas":          "0x5208",
+		"eth_call":                 fmt.Sprintf("0x%064x", 50_000_000_000_000), // 0.00005 ETH L1 fee
+		"eth_getBalance":           "0xde0b6b3a7640000",
+	})
+	res, err := handleBuildEVMTx(vault.NewStore(), pool)(context.Background(), callToolReq("build_evm_tx", map[string]any{
+		"chain": "Optimism",
+		"from":  testAddress,
+		"to":    permit2,
+		"value": "0",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+
+	// 21,000 gas at 2.1 gwei max, plus the L1 fee.
+	if result["max_network_fee"] != "0.0000941 ETH" {
+		t.Errorf("max_network_fee = %v", result["max_network_fee"])
+	}
+	rollup, _ := result["rollup_fee"].(map[string]any)
+	if rollup["model"] != "op_stack" || rollup["l1_fee"] != "50000000000000" {
+		t.Errorf("rollup_fee = %v", rollup)
+	}
+
+	// The same transaction from an address that cannot cover the fee.
+	pool, _ = mockEVMChainPool(t, "Optimism", map[string]any{
+		"eth_getTransactionCount":  "0x2",
+		"eth_maxPriorityFeePerGas": "0x5f5e100",
+		"eth_getBlockByNumber":     mockHeader("0x3b9aca00"),
+		"eth_estimateGas":          "0x5208",
+		"eth_call":                 fmt.Sprintf("0x%064x", 50_000_000_000_000),
+		"eth_getBalance":           "0x51dac207a000", // 0.00009 ETH
+	})
+	res, err = handleBuildEVMTx(vault.NewStore(), pool)(context.Background(), callToolReq("build_evm_tx", map[string]any{
+		"chain": "Optimism",
+		"from":  testAddress,
+		"to":    permit2,
+		"value": "0",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := res.Content[0].(mcp.TextContent).Text; !res.IsError || !strings.Contains(text, "insufficient ETH balance") {
+		t.Errorf("expected insufficient balance error, got %s", text)
+	}
+}
+
+func TestEVMTxInfo_RollupFeeError(t *testing.T) {
+	pool, _ := mockEVMChainPool(t, "Optimism", map[string]any{
+		"eth_getTransactionCount":  "0x2",
+		"eth_maxPriorityFeePerGas": "0x5f5e100",
+		"eth_getBlockByNumber":     mockHeader("0x3b9aca00"),
+		"eth_estimateGas":          "0x5208",
+		"eth_call":                 mockRPCError{Code: -32000, Message: "oracle unavailable"},
+		"eth_getBalance":           "0xde0b6b3a7640000",
+	})
+	res, err := handleEVMTxInfo(vault.NewStore(), pool, nil)(context.Background(), callToolReq("evm_tx_info", map[string]any{
+		"chain":   "Optimism",
+		"address": testAddress,
+		"to":      permit2,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+
+	// The L2 estimate is still returned, without the L1 fee.
+	if result["estimated_gas"] != float64(21000) || result["max_network_fee"] != "44100000000000" || result["rollup_fee"] != nil {
+		t.Errorf("result = %v", result)
+	}
+	if msg, _ := result["rollup_fee_error"].(string); !strings.Contains(msg, "oracle unavailable") {
+		t.Errorf("rollup_fee_error = %v", result["rollup_fee_error"])
+	}
+}
@@ -0,0 +1,540 @@
+package tools
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/tokenmeta"
+)
+
+// Token standards of decoded transfers and balance changes.
+const (
+	standardNative  = "native"
+	standardERC20   = "erc20"
+	standardERC721  = evmclient.StandardERC721
+	standardERC1155 = evmclient.StandardERC1155
+)
+
+// txEvent is a decoded receipt log.
+type txEvent struct {
+	LogIndex uint   `json:"log_index"`
+	Event    string `json:"event"`
+	// Standard is the token standard of transfers and approvals, or the
+	// protocol of other events: weth, aave_v3, uniswap_v2 or uniswap_v3.
+	Standard string   `json:"standard"`
+	Contract string   `json:"contract"`
+	Symbol   string   `json:"symbol,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       string   `json:"to,omitempty"`
+	Owner    string   `json:"owner,omitempty"`
+	Spender  string   `json:"spender,omitempty"`
+	Amount   string   `json:"amount,omitempty"`
+	Raw      string   `json:"raw_amount,omitempty"`
+	TokenIDs []string `json:"token_ids,omitempty"`
+	// Unlimited marks approvals of an unlimited allowance.
+	Unlimited bool `json:"unlimited,omitempty"`
+	// Approved is set for ApprovalForAll events.
+	Approved *bool `json:"approved,omitempty"`
+	// Args holds the parameters of protocol events by name.
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// balanceChange is the net change of one asset's balance over a
+// transaction. Amounts are signed.
+type balanceChange struct {
+	Asset    string `json:"asset"`
+	Standard string `json:"standard"`
+	Contract string `json:"contract,omitempty"`
+	TokenID  string `json:"token_id,omitempty"`
+	Amount   string `json:"amount"`
+	Raw      string `json:"raw_amount"`
+}
+
+// protocolEvent is a protocol event decoded by its parameters.
+type protocolEvent struct {
+	spec     *eventSpec
+	protocol string
+}
+
+// protocolEvents are the known protocol events by topic. Aave events index
+// the reserve token first, whose metadata formats their amount.
+var protocolEvents = func() map[common.Hash]protocolEvent {
+	sigs := map[string]string{
+		"Supply(address indexed reserve, address user, address indexed onBehalfOf, uint256 amount, uint16 indexed referralCode)":                                                                             "aave_v3",
+		"Borrow(address indexed reserve, address user, address indexed onBehalfOf, uint256 amount, uint8 interestRateMode, uint256 borrowRate, uint16 indexed referralCode)":                                 "aave_v3",
+		"Repay(address indexed reserve, address indexed user, address indexed repayer, uint256 amount, bool useATokens)":                                                                                     "aave_v3",
+		"Withdraw(address indexed reserve, address indexed user, address indexed to, uint256 amount)":                                                                                                        "aave_v3",
+		"LiquidationCall(address indexed collateralAsset, address indexed debtAsset, address indexed user, uint256 debtToCover, uint256 liquidatedCollateralAmount, address liquidator, bool receiveAToken)": "aave_v3",
+		"Swap(address indexed sender, uint256 amount0In, uint256 amount1In, uint256 amount0Out, uint256 amount1Out, address indexed to)":                                                                     "uniswap_v2",
+		"Swap(address indexed sender, address indexed recipient, int256 amount0, int256 amount1, uint160 sqrtPriceX96, uint128 liquidity, int24 tick)":                                                       "uniswap_v3",
+	}
+	events := make(map[common.Hash]protocolEvent, len(sigs))
+	for sig, protocol := range sigs {
+		spec := mustEvent(sig)
+		events[spec.event.ID] = protocolEvent{spec: spec, protocol: protocol}
+	}
+	return events
+}()
+
+var (
+	erc20Transfer  = mustEvent("Transfer(address indexed from, address indexed to, uint256 value)")
+	erc721Transfer = mustEvent("Transfer(address indexed from, address indexed to, uint256 indexed tokenId)")
+	erc20Approval  = mustEvent("Approval(address indexed owner, address indexed spender, uint256 value)")
+	erc721Approval = mustEvent("Approval(address indexed owner, address indexed spender, uint256 indexed tokenId)")
+	approvalForAll = mustEvent("ApprovalForAll(address indexed owner, address indexed operator, bool approved)")
+	erc1155Single  = mustEvent("TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value)")
+	erc1155Batch   = mustEvent("TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)")
+	wethDeposit    = mustEvent("Deposit(address indexed dst, uint256 wad)")
+	wethWithdrawal = mustEvent("Withdrawal(address indexed src, uint256 wad)")
+)
+
+func mustEvent(sig string) *eventSpec {
+	spec, err := parseEvent(sig)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+// txEffects decodes the logs of receipt and sums the balance changes of
+// owner. The native change starts from the transaction's value and fee,
+// adds the internal transfers of its call trace and, on RPCs without
+// tracing, falls back to owner's balance difference across the block when
+// no other transaction there touches owner (see nativeChange). It returns
+// where the native change came from and the number of logs that were not
+// decoded.
+func txEffects(ctx context.Context, client *evmclient.Client, chain string, tx *types.Transaction, receipt *types.Receipt, sender, owner common.Address) ([]txEvent, []balanceChange, string, int) {
+	tokens := tokenMetadata(ctx, client, receipt.Logs)
+
+	var events []txEvent
+	undecoded := 0
+	deltas := newBalanceDeltas()
+	for _, lg := range receipt.Logs {
+		ev, ok := decodeTxLog(*lg, tokens)
+		if !ok {
+			undecoded++
+			continue
+		}
+		events = append(events, ev)
+		deltas.add(*lg, owner)
+	}
+
+	changes := make([]balanceChange, 0, len(deltas.amounts)+1)
+	native, nativeSource := nativeChange(ctx, client, chain, tx, receipt, sender, owner)
+	if native.Sign() != 0 {
+		changes = append(changes, balanceChange{
+			Asset:    evmclient.NativeTicker(chain),
+			Standard: standardNative,
+			Amount:   formatSigned(native, 18),
+			Raw:      native.String(),
+		})
+	}
+	for _, key := range deltas.order {
+		amount := deltas.amounts[key]
+		if amount.Sign() == 0 {
+			continue
+		}
+		c := balanceChange{
+			Standard: key.standard,
+			Contract: key.contract.Hex(),
+			TokenID:  key.tokenID,
+			Raw:      amount.String(),
+			Amount:   amount.String(),
+		}
+		meta, known := tokens[key.contract]
+		c.Asset = meta.Symbol
+		if c.Asset == "" {
+			c.Asset = key.contract.Hex()
+		}
+		if key.standard == standardERC20 && known {
+			c.Amount = formatSigned(amount, int(meta.Decimals))
+		} else if amount.Sign() > 0 {
+			c.Amount = "+" + c.Amount
+		}
+		changes = append(changes, c)
+	}
+	return events, changes, nativeSource, undecoded
+}
+
+// tokenMetadata reads the metadata of every contract that emitted a log,
+// leaving out contracts that are not tokens.
+func tokenMetadata(ctx context.Context, client *evmclient.Client, logs []*types.Log) map[common.Address]tokenmeta.Metadata {
+	seen := make(map[common.Address]bool)
+	var contracts []common.Address
+	add := func(addr common.Address) {
+		if !seen[addr] {
+			seen[addr] = true
+			contracts = append(contracts, addr)
+		}
+	}
+	for _, lg := range logs {
+		if len(lg.Topics) == 0 {
+			continue
+		}
+		if p, ok := protocolEvents[lg.Topics[0]]; ok {
+			// Aave events index the reserve token first.
+			if p.protocol == "aave_v3" && len(lg.Topics) > 1 {
+				add(common.BytesToAddress(lg.Topics[1].Bytes()))
+			}
+			continue
+		}
+		add(lg.Address)
+	}
+	tokens, err := client.Tokens(ctx, contracts)
+	if err != nil {
+		return nil
+	}
+	return tokens
+}
+
+// decodeTxLog decodes a token or known protocol event.
+func decodeTxLog(lg types.Log, tokens map[common.Address]tokenmeta.Metadata) (txEvent, bool) {
+	if len(lg.Topics) == 0 {
+		return txEvent{}, false
+	}
+	ev := txEvent{LogIndex: lg.Index, Contract: lg.Address.Hex()}
+	meta, known := tokens[lg.Address]
+	ev.Symbol = meta.Symbol
+	setAmount := func(v any) {
+		amount, _ := v.(*big.Int)
+		if amount == nil {
+			return
+		}
+		ev.Raw = amount.String()
+		ev.Amount = amount.String()
+		if known {
+			ev.Amount = evmclient.FormatUnits(amount, int(meta.Decimals))
+		}
+	}
+
+	topic := lg.Topics[0]
+	switch {
+	case topic == erc20Transfer.event.ID && len(lg.Topics) == 3:
+		args, ok := decodeTyped(erc20Transfer, lg)
+		if !ok {
+			return ev, false
+		}
+		ev.Event, ev.Standard = "Transfer", standardERC20
+		ev.From, ev.To = hexAddr(args["from"]), hexAddr(args["to"])
+		setAmount(args["value"])
+	case topic == erc721Transfer.event.ID && len(lg.Topics) == 4:
+		args, ok := decodeTyped(erc721Transfer, lg)
+		if !ok {
+			return ev, false
+		}
+		ev.Event, ev.Standard = "Transfer", standardERC721
+		ev.From, ev.To = hexAddr(args["from"]), hexAddr(args["to"])
+		ev.TokenIDs = []string{args["tokenId"].(*big.Int).String()}
+	case topic == erc20Approval.event.ID && len(lg.Topics) == 3:
+		args, ok := decodeTyped(erc20Approval, lg)
+		if !ok {
+			return ev, false
+		}
+		ev.Event, ev.Standard = "Approval", standardERC20
+		ev.Owner, ev.Spender = hexAddr(args["owner"]), hexAddr(args["spender"])
+		setAmount(args["value"])
+		if v, _ := args["value"].(*big.Int); v != nil && v.Cmp(evmclient.UnlimitedAllowance) >= 0 {
+			ev.Unlimited = true
+			ev.Amount = "unlimited"
+		}
+	case topic == erc721Approval.event.ID && len(lg.Topics) == 4:
+		args, ok := decodeTyped(erc721Approval, lg)
+		if !ok {
+			return ev, false
+		}
+		ev.Event, ev.Standard = "Approval", standardERC721
+		ev.Owner, ev.Spender = hexAddr(args["owner"]), hexAddr(args["spender"])
+		ev.TokenIDs = []string{args["tokenId"].(*big.Int).String()}
+	case topic == approvalForAll.event.ID:
+		args, ok := decodeTyped(approvalForAll, lg)
+		if !ok {
+			return ev, false
+		}
+		approved, _ := args["approved"].(bool)
+		ev.Event, ev.Standard = "ApprovalForAll", "nft"
+		ev.Owner, ev.Spender = hexAddr(args["owner"]), hexAddr(args["operator"])
+		ev.Approved = &approved
+	case topic == erc1155Single.event.ID:
+		args, ok := decodeTyped(erc1155Single, lg)
+		if !ok {
+			return ev, false
+		}
+		ev.Event, ev.Standard = "TransferSingle", standardERC1155
+		ev.From, ev.To = hexAddr(args["from"]), hexAddr(args["to"])
+		ev.TokenIDs = []string{args["id"].(*big.Int).String()}
+		ev.Raw = args["value"].(*big.Int).String()
+		ev.Amount = ev.Raw
+	case topic == erc1155Batch.event.ID:
+		args, ok := decodeTyped(erc1155Batch, lg)
+		if !ok {
+			return ev, false
+		}
+		ev.Event, ev.Standard = "TransferBatch", standardERC1155
+		ev.From, ev.To = hexAddr(args["from"]), hexAddr(args["to"])
+		ev.Args = map[string]any{"values": formatABIValue(args["values"])}
+		for _, id := range args["ids"].([]*big.Int) {
+			ev.TokenIDs = append(ev.TokenIDs, id.String())
+		}
+	case topic == wethDeposit.event.ID && len(lg.Topics) == 2:
+		args, ok := decodeTyped(wethDeposit, lg)
+		if !ok {
+			return ev, false
+		}
+		ev.Event, ev.Standard = "Deposit", "weth"
+		ev.To = hexAddr(args["dst"])
+		setAmount(args["wad"])
+	case topic == wethWithdrawal.event.ID && len(lg.Topics) == 2:
+		args, ok := decodeTyped(wethWithdrawal, lg)
+		if !ok {
+			return ev, false
+		}
+		ev.Event, ev.Standard = "Withdrawal", "weth"
+		ev.From = hexAddr(args["src"])
+		setAmount(args["wad"])
+	default:
+		p, ok := protocolEvents[topic]
+		if !ok {
+			return ev, false
+		}
+		args, err := p.spec.decodeLog(lg)
+		if err != nil {
+			return ev, false
+		}
+		ev.Event, ev.Standard, ev.Args = p.spec.event.Name, p.protocol, args
+		ev.Symbol = ""
+		if p.protocol == "aave_v3" {
+			reserve := common.BytesToAddress(lg.Topics[1].Bytes())
+			if meta, ok := tokens[reserve]; ok {
+				ev.Symbol = meta.Symbol
+				if amount, ok := new(big.Int).SetString(stringArg(args["amount"]), 10); ok {
+					ev.Raw = amount.String()
+					ev.Amount = evmclient.FormatUnits(amount, int(meta.Decimals))
+				}
+			}
+		}
+	}
+	return ev, true
+}
+
+// decodeTyped decodes lg with spec, keeping the values as their Go types.
+func decodeTyped(spec *eventSpec, lg types.Log) (map[string]any, bool) {
+	var indexed abi.Arguments
+	for _, arg := range spec.event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(indexed) != len(lg.Topics)-1 {
+		return nil, false
+	}
+	out := make(map[string]any, len(spec.event.Inputs))
+	if err := abi.ParseTopicsIntoMap(out, indexed, lg.Topics[1:]); err != nil {
+		return nil, false
+	}
+	if err := spec.event.Inputs.UnpackIntoMap(out, lg.Data); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+func hexAddr(v any) string {
+	addr, _ := v.(common.Address)
+	return addr.Hex()
+}
+
+func stringArg(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+type balanceKey struct {
+	standard string
+	contract common.Address
+	tokenID  string
+}
+
+// balanceDeltas sums token balance changes of one address, keeping the
+// order assets were first seen in.
+type balanceDeltas struct {
+	amounts map[balanceKey]*big.Int
+	order   []balanceKey
+}
+
+func newBalanceDeltas() *balanceDeltas {
+	return &balanceDeltas{amounts: make(map[balanceKey]*big.Int)}
+}
+
+func (d *balanceDeltas) change(key balanceKey, amount *big.Int, sign int) {
+	sum, ok := d.amounts[key]
+	if !ok {
+		sum = new(big.Int)
+		d.amounts[key] = sum
+		d.order = append(d.order, key)
+	}
+	if sign < 0 {
+		sum.Sub(sum, amount)
+	} else {
+		sum.Add(sum, amount)
+	}
+}
+
+// transfer records amount moving from from to to when owner is either.
+func (d *balanceDeltas) transfer(key balanceKey, from, to, owner common.Address, amount *big.Int) {
+	if from == owner && to == owner {
+		return
+	}
+	if from == owner {
+		d.change(key, amount, -1)
+	}
+	if to == owner {
+		d.change(key, amount, 1)
+	}
+}
+
+// add records the balance change of owner in lg.
+func (d *balanceDeltas) add(lg types.Log, owner common.Address) {
+	topic := lg.Topics[0]
+	switch {
+	case topic == erc721Transfer.event.ID && len(lg.Topics) == 4:
+		args, ok := decodeTyped(erc721Transfer, lg)
+		if !ok {
+			return
+		}
+		key := balanceKey{standard: standardERC721, contract: lg.Address, tokenID: args["tokenId"].(*big.Int).String()}
+		d.transfer(key, args["from"].(common.Address), args["to"].(common.Address), owner, big.NewInt(1))
+	case topic == erc20Transfer.event.ID && len(lg.Topics) == 3:
+		args, ok := decodeTyped(erc20Transfer, lg)
+		if !ok {
+			return
+		}
+		key := balanceKey{standard: standardERC20, contract: lg.Address}
+		d.transfer(key, args["from"].(common.Address), args["to"].(common.Address), owner, args["value"].(*big.Int))
+	case topic == erc1155Single.event.ID:
+		args, ok := decodeTyped(erc1155Single, lg)
+		if !ok {
+			return
+		}
+		key := balanceKey{standard: standardERC1155, contract: lg.Address, tokenID: args["id"].(*big.Int).String()}
+		d.transfer(key, args["from"].(common.Address), args["to"].(common.Address), owner, args["value"].(*big.Int))
+	case topic == erc1155Batch.event.ID:
+		args, ok := decodeTyped(erc1155Batch, lg)
+		if !ok {
+			return
+		}
+		ids, values := args["ids"].([]*big.Int), args["values"].([]*big.Int)
+		for i := range min(len(ids), len(values)) {
+			key := balanceKey{standard: standardERC1155, contract: lg.Address, tokenID: ids[i].String()}
+			d.transfer(key, args["from"].(common.Address), args["to"].(common.Address), owner, values[i])
+		}
+	case topic == wethDeposit.event.ID && len(lg.Topics) == 2:
+		// WETH mints on deposit without a Transfer event.
+		args, ok := decodeTyped(wethDeposit, lg)
+		if ok && args["dst"].(common.Address) == owner {
+			d.change(balanceKey{standard: standardERC20, contract: lg.Address}, args["wad"].(*big.Int), 1)
+		}
+	case topic == wethWithdrawal.event.ID && len(lg.Topics) == 2:
+		args, ok := decodeTyped(wethWithdrawal, lg)
+		if ok && args["src"].(common.Address) == owner {
+			d.change(balanceKey{standard: standardERC20, contract: lg.Address}, args["wad"].(*big.Int), -1)
+		}
+	}
+}
+
+// Sources of the native balance change reported by nativeChange.
+const (
+	nativeSourceTransaction = "transaction"
+	nativeSourceTrace       = "trace"
+	// nativeSourceBlockDiff marks an approximation: the balance difference
+	// across the block also counts anything that credited owner without a
+	// transaction from or to it, such as another contract's payout.
+	nativeSourceBlockDiff = "block_diff"
+)
+
+// nativeChange returns owner's native balance change from the
+// transaction and where it came from: the value it sent or received and
+// the fee if owner paid it, plus the internal transfers in the
+// transaction's call trace. RPCs without tracing fall back to owner's
+// balance difference across the block, but only when no other transaction
+// in the block is from or to owner; otherwise internal transfers are
+// missed.
+func nativeChange(ctx context.Context, client *evmclient.Client, chain string, tx *types.Transaction, receipt *types.Receipt, sender, owner common.Address) (*big.Int, string) {
+	change := new(big.Int)
+	if receipt.Status == types.ReceiptStatusSuccessful && tx.Value() != nil {
+		if tx.To() != nil && *tx.To() == owner {
+			change.Add(change, tx.Value())
+		}
+		if sender == owner {
+			change.Sub(change, tx.Value())
+		}
+	}
+	if sender == owner && receipt.EffectiveGasPrice != nil {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		change.Sub(change, fee)
+		if evmclient.FeeModel(chain) == evmclient.FeeModelOPStack {
+			if l1Fee, err := client.ReceiptL1Fee(ctx, tx.Hash()); err == nil && l1Fee != nil {
+				change.Sub(change, l1Fee)
+			}
+		}
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return change, nativeSourceTransaction
+	}
+	transfers, err := client.InternalTransfers(ctx, tx.Hash())
+	if err == nil {
+		for _, t := range transfers {
+			if t.To == owner {
+				change.Add(change, t.Value)
+			}
+			if t.From == owner {
+				change.Sub(change, t.Value)
+			}
+		}
+		return change, nativeSourceTrace
+	}
+	if diff, ok := blockBalanceChange(ctx, client, tx, receipt, owner); ok {
+		return diff, nativeSourceBlockDiff
+	}
+	return change, nativeSourceTransaction
+}
+
+// blockBalanceChange returns owner's balance difference across the
+// transaction's block if tx is the block's only transaction from or to
+// owner.
+func blockBalanceChange(ctx context.Context, client *evmclient.Client, tx *types.Transaction, receipt *types.Receipt, owner common.Address) (*big.Int, bool) {
+	if receipt.BlockNumber == nil || receipt.BlockNumber.Sign() <= 0 {
+		return nil, false
+	}
+	txs, err := client.BlockTransactions(ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, false
+	}
+	for _, other := range txs {
+		if other.Hash != tx.Hash() && (other.From == owner || other.To != nil && *other.To == owner) {
+			return nil, false
+		}
+	}
+	after, err := client.ETH().BalanceAt(ctx, owner, receipt.BlockNumber)
+	if err != nil {
+		return nil, false
+	}
+	before, err := client.ETH().BalanceAt(ctx, owner, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+	if err != nil {
+		return nil, false
+	}
+	return after.Sub(after, before), true
+}
+
+// formatSigned formats amount in units of decimals with an explicit sign.
+func formatSigned(amount *big.Int, decimals int) string {
+	s := evmclient.FormatUnits(new(big.Int).Abs(amount), decimals)
+	if amount.Sign() < 0 {
+		return "-" + s
+	}
+	return "+" + s
+}
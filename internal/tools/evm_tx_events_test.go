@@ -0,0 +1,256 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// signedTestTx returns a transaction to to signed by a random key on
+// chain 1.
+func signedTestTx(t *testing.T, to string, gas uint64, data []byte) *types.Transaction {
+	t.Helper()
+	toAddr := ethcommon.HexToAddress(to)
+	tx, _ := signTestTx(t, &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Gas:       gas,
+		GasFeeCap: big.NewInt(1),
+		To:        &toAddr,
+		Data:      data,
+	})
+	return tx
+}
+
+// testReceipt returns a receipt of tx mined in block 100.
+func testReceipt(tx *types.Transaction, status, gasUsed uint64, logs ...*types.Log) *types.Receipt {
+	for i, lg := range logs {
+		lg.Index = uint(i)
+		lg.TxHash = tx.Hash()
+		lg.BlockNumber = 100
+	}
+	if logs == nil {
+		logs = []*types.Log{}
+	}
+	return &types.Receipt{
+		Type:              types.DynamicFeeTxType,
+		Status:            status,
+		CumulativeGasUsed: gasUsed,
+		GasUsed:           gasUsed,
+		TxHash:            tx.Hash(),
+		BlockNumber:       big.NewInt(100),
+		EffectiveGasPrice: big.NewInt(1),
+		Logs:              logs,
+	}
+}
+
+func TestGetTxStatus_EventsAndBalanceChanges(t *testing.T) {
+	const aavePool = "0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2"
+	maxUint := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	supplyData, _ := supplyEventData(testAddress, 1_000_000)
+
+	tx := signedTestTx(t, aavePool, 300_000, nil)
+	receipt := testReceipt(tx, types.ReceiptStatusSuccessful, 200_000,
+		&types.Log{
+			Address: ethcommon.HexToAddress(usdt),
+			Topics:  []ethcommon.Hash{erc20Transfer.event.ID, topicAddr(testAddress), topicAddr(aavePool)},
+			Data:    ethcommon.LeftPadBytes(big.NewInt(1_500_000).Bytes(), 32),
+		},
+		&types.Log{
+			Address: ethcommon.HexToAddress(usdt),
+			Topics:  []ethcommon.Hash{erc20Approval.event.ID, topicAddr(testAddress), topicAddr(permit2)},
+			Data:    ethcommon.LeftPadBytes(maxUint.Bytes(), 32),
+		},
+		&types.Log{
+			Address: ethcommon.HexToAddress(aavePool),
+			Topics:  []ethcommon.Hash{supplyTopic(), topicAddr(usdt), topicAddr(testAddress), {}},
+			Data:    supplyData,
+		},
+		&types.Log{
+			Address: ethcommon.HexToAddress(aavePool),
+			Topics:  []ethcommon.Hash{crypto.Keccak256Hash([]byte("Unknown()"))},
+		},
+	)
+
+	pool, _ := mockEVMPool(t, map[string]any{
+		"eth_getTransactionReceipt": receipt,
+		"eth_getTransactionByHash":  tx,
+		"eth_blockNumber":           "0x70",
+		"eth_call":                  mockERC20Call(6, "USDT", new(big.Int)),
+		// The pool refunds 0.3 ETH; the reverted refund and the
+		// delegatecall's value are not transfers.
+		"debug_traceTransaction": map[string]any{
+			"type": "CALL", "from": testAddress, "to": aavePool, "value": "0x0",
+			"calls": []any{
+				map[string]any{"type": "DELEGATECALL", "from": aavePool, "to": permit2, "value": "0x429d069189e0000"},
+				map[string]any{"type": "CALL", "from": aavePool, "to": testAddress, "value": "0x429d069189e0000"},
+				map[string]any{"type": "CALL", "from": aavePool, "to": testAddress, "value": "0x1", "error": "execution reverted"},
+			},
+		},
+	})
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+
+	res, err := handleGetTxStatus(store, pool, nil, nil, nil, nil, nil, nil)(context.Background(), callToolReq("get_tx_status", map[string]any{
+		"chain":   "Ethereum",
+		"tx_hash": tx.Hash().Hex(),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result txStatusResult
+	if err := json.Unmarshal([]byte(resultText(t, res)), &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if result.BalanceAddress != testAddress || result.UndecodedLogs != 1 || len(result.Events) != 3 {
+		t.Fatalf("result = %+v", result)
+	}
+	transfer, approval, supply := result.Events[0], result.Events[1], result.Events[2]
+	if transfer.Event != "Transfer" || transfer.Standard != standardERC20 || transfer.Symbol != "USDT" || transfer.Amount != "1.5" || transfer.To != aavePool {
+		t.Errorf("transfer = %+v", transfer)
+	}
+	if approval.Event != "Approval" || !approval.Unlimited || approval.Spender != permit2 {
+		t.Errorf("approval = %+v", approval)
+	}
+	if supply.Event != "Supply" || supply.Standard != "aave_v3" || supply.Symbol != "USDT" || supply.Amount != "1" {
+		t.Errorf("supply = %+v", supply)
+	}
+
+	want := []balanceChange{
+		{Asset: "ETH", Standard: standardNative, Amount: "+0.3", Raw: "300000000000000000"},
+		{Asset: "USDT", Standard: standardERC20, Contract: usdt, Amount: "-1.5", Raw: "-1500000"},
+	}
+	got, _ := json.Marshal(result.BalanceChanges)
+	wantJSON, _ := json.Marshal(want)
+	if string(got) != string(wantJSON) {
+		t.Errorf("balance changes = %s, want %s", got, wantJSON)
+	}
+}
+
+func TestTxEffects_NFTAndWETH(t *testing.T) {
+	const weth = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	tx := signedTestTx(t, weth, 100_000, nil)
+	receipt := testReceipt(tx, types.ReceiptStatusSuccessful, 50_000,
+		&types.Log{
+			Address: ethcommon.HexToAddress(weth),
+			Topics:  []ethcommon.Hash{wethDeposit.event.ID, topicAddr(testAddress)},
+			Data:    ethcommon.LeftPadBytes(big.NewInt(5).Bytes(), 32),
+		},
+		&types.Log{
+			Address: ethcommon.HexToAddress(nftContract),
+			Topics:  []ethcommon.Hash{erc721Transfer.event.ID, topicAddr(permit2), topicAddr(testAddress), ethcommon.BigToHash(big.NewInt(42))},
+		},
+	)
+	pool, _ := mockEVMPool(t, map[string]any{"eth_call": "0x"})
+	client, _, err := pool.Get(context.Background(), "Ethereum")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, changes, source, undecoded := txEffects(context.Background(), client, "Ethereum", tx, receipt, ethcommon.HexToAddress(permit2), ethcommon.HexToAddress(testAddress))
+	if undecoded != 0 || len(events) != 2 || events[1].Standard != standardERC721 || events[1].TokenIDs[0] != "42" {
+		t.Fatalf("events = %+v", events)
+	}
+	// Without a trace or the block the native change comes from the
+	// transaction, which testAddress neither sent nor received.
+	if len(changes) != 2 || changes[0].Amount != "+5" || changes[1].TokenID != "42" || changes[1].Amount != "+1" {
+		t.Errorf("changes = %+v", changes)
+	}
+	if source != nativeSourceTransaction {
+		t.Errorf("native source = %q, want %q", source, nativeSourceTransaction)
+	}
+}
+
+func TestNativeChange_Fallbacks(t *testing.T) {
+	owner := ethcommon.HexToAddress(testAddress)
+	tx := signedTestTx(t, permit2, 100_000, nil)
+	receipt := testReceipt(tx, types.ReceiptStatusSuccessful, 50_000)
+
+	// balanceAcrossBlock drops by 0.3 ETH over block 100.
+	balanceAcrossBlock := func(params json.RawMessage) any {
+		var args []string
+		_ = json.Unmarshal(params, &args)
+		if args[1] == "0x64" {
+			return "0x17979cfe362a0000" // 1.7 ETH
+		}
+		return "0x1bc16d674ec80000" // 2 ETH
+	}
+	blockWith := func(txs ...map[string]any) map[string]any {
+		return map[string]any{"transactions": txs}
+	}
+	self := map[string]any{"hash": tx.Hash().Hex(), "from": permit2, "to": permit2}
+
+	tests := map[string]struct {
+		chain   string
+		sender  string
+		results map[string]any
+		want    string
+		source  string
+	}{
+		"block difference when alone in the block": {
+			chain:  "Ethereum",
+			sender: permit2,
+			results: map[string]any{
+				"eth_getBlockByNumber": blockWith(self),
+				"eth_getBalance":       balanceAcrossBlock,
+			},
+			want:   "-300000000000000000",
+			source: nativeSourceBlockDiff,
+		},
+		"transaction only when another transaction touches owner": {
+			chain:  "Ethereum",
+			sender: permit2,
+			results: map[string]any{
+				"eth_getBlockByNumber": blockWith(self, map[string]any{"hash": ethcommon.Hash{1}.Hex(), "from": testAddress, "to": usdt}),
+				"eth_getBalance":       balanceAcrossBlock,
+			},
+			want:   "0",
+			source: nativeSourceTransaction,
+		},
+		"op stack fee includes the l1 fee": {
+			chain:  "Optimism",
+			sender: testAddress,
+			results: map[string]any{
+				"eth_getTransactionReceipt": map[string]any{"l1Fee": "0x3e8"},
+			},
+			// 50,000 gas at 1 wei plus the 1,000 wei L1 fee.
+			want:   "-51000",
+			source: nativeSourceTransaction,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pool, _ := mockEVMChainPool(t, tt.chain, tt.results)
+			client, _, err := pool.Get(context.Background(), tt.chain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, source := nativeChange(context.Background(), client, tt.chain, tx, receipt, ethcommon.HexToAddress(tt.sender), owner)
+			if got.String() != tt.want || source != tt.source {
+				t.Errorf("change = %s (%s), want %s (%s)", got, source, tt.want, tt.source)
+			}
+		})
+	}
+}
+
+func topicAddr(addr string) ethcommon.Hash {
+	return ethcommon.HexToHash(addressTopic(addr))
+}
+
+func supplyTopic() ethcommon.Hash {
+	return crypto.Keccak256Hash([]byte("Supply(address,address,address,uint256,uint16)"))
+}
+
+// supplyEventData encodes the non-indexed parameters of an Aave Supply
+// event: user and amount.
+func supplyEventData(user string, amount int64) ([]byte, error) {
+	spec := protocolEvents[supplyTopic()].spec
+	return spec.event.Inputs.NonIndexed().Pack(ethcommon.HexToAddress(user), big.NewInt(amount))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
@@ -20,7 +21,10 @@ func newEVMTxInfoTool() mcp.Tool {
 	return mcp.NewTool("evm_tx_info",
 		mcp.WithDescription(
 			"Get nonce, gas prices, and chain ID for building an EVM transaction on any EVM chain. "+
-				"If to/data/value are provided, also estimates gas. "+
+				"If to/data/value are provided, also estimates gas, the expected and max network fee and whether the address's native balance covers value plus fees; "+
+				"a reverting transaction is returned as an error with a decoded revert field. "+
+				"On rollups the fees include the L1 data fee: the GasPriceOracle on OP-stack chains (Optimism, Base, Blast, Mantle), "+
+				"NodeInterface.gasEstimateComponents on Arbitrum and zks_estimateFee on zkSync. "+
 				"Address falls back to vault-derived if not provided.",
 		),
 		mcp.WithString("chain",
@@ -42,7 +46,7 @@ func newEVMTxInfoTool() mcp.Tool {
 	)
 }
 
-func handleEVMTxInfo(store *vault.Store, pool *evmclient.Pool) server.ToolHandlerFunc {
+func handleEVMTxInfo(store *vault.Store, pool *evmclient.Pool, reverts *revertDecoder) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		chainName := req.GetString("chain", "Ethereum")
 
@@ -89,6 +93,9 @@ func handleEVMTxInfo(store *vault.Store, pool *evmclient.Pool) server.ToolHandle
 			"max_priority_fee_per_gas":  tipCap.String(),
 			"suggested_max_fee_per_gas": suggestedMaxFee.String(),
 		}
+		if model := evmclient.FeeModel(chainName); model != evmclient.FeeModelL1 {
+			resp["fee_model"] = model
+		}
 
 		if toStr := req.GetString("to", ""); toStr != "" {
 			if !common.IsHexAddress(toStr) {
@@ -97,8 +104,9 @@ func handleEVMTxInfo(store *vault.Store, pool *evmclient.Pool) server.ToolHandle
 			to := common.HexToAddress(toStr)
 
 			msg := ethereum.CallMsg{
-				From: address,
-				To:   &to,
+				From:  address,
+				To:    &to,
+				Value: new(big.Int),
 			}
 
 			if dataHex := req.GetString("data", ""); dataHex != "" {
@@ -119,9 +127,49 @@ func handleEVMTxInfo(store *vault.Store, pool *evmclient.Pool) server.ToolHandle
 
 			gasEstimate, err := client.EstimateGas(ctx, msg)
 			if err != nil {
+				if revert, ok := reverts.fromError(ctx, client, chainID.Uint64(), &to, err); ok {
+					return revertResult("gas estimation failed", revert), nil
+				}
 				return mcp.NewToolResultError(fmt.Sprintf("gas estimation failed: %v", err)), nil
 			}
 			resp["estimated_gas"] = gasEstimate
+
+			p := &evmTxParams{
+				txType:         ethtypes.DynamicFeeTxType,
+				chainID:        chainID,
+				to:             to,
+				value:          msg.Value,
+				data:           msg.Data,
+				nonce:          &nonce,
+				gasLimit:       &gasEstimate,
+				maxFee:         suggestedMaxFee,
+				maxPriorityFee: tipCap,
+			}
+			// Without a rollup quote the fees below cover L2 gas only.
+			rollup, err := evmRollupFee(ctx, client, chainName, address, p)
+			if err != nil {
+				resp["rollup_fee_error"] = err.Error()
+			}
+			gas := new(big.Int).SetUint64(gasEstimate)
+			expectedFee := new(big.Int).Mul(gas, new(big.Int).Add(baseFee, tipCap))
+			expectedFee.Add(expectedFee, rollup.ExtraFee())
+			maxNetworkFee := new(big.Int).Add(p.maxNetworkFee(), rollup.ExtraFee())
+			resp["estimated_fee"] = expectedFee.String()
+			resp["max_network_fee"] = maxNetworkFee.String()
+			if rollup != nil {
+				resp["rollup_fee"] = newRollupFeeJSON(rollup, evmclient.NativeTicker(chainName))
+			}
+
+			balance, err := client.ETH().BalanceAt(ctx, address, nil)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get native balance: %v", err)), nil
+			}
+			need := new(big.Int).Add(msg.Value, maxNetworkFee)
+			resp["balance"] = balance.String()
+			resp["sufficient_funds"] = balance.Cmp(need) >= 0
+			if balance.Cmp(need) < 0 {
+				resp["shortfall"] = new(big.Int).Sub(need, balance).String()
+			}
 		}
 
 		data, err := json.Marshal(resp)
@@ -3,10 +3,12 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/vultisig/mcp/internal/keysign"
 	"github.com/vultisig/mcp/internal/vault"
 )
 
@@ -199,7 +201,7 @@ func TestConvertAmount_USDT(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestBuildEVMTx_SparkTransactions(t *testing.T) {
-	handler := handleBuildEVMTx()
+	handler := handleBuildEVMTx(vault.NewStore(), nil)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -503,7 +505,7 @@ func TestSparkDepositWorkflow(t *testing.T) {
 	json.Unmarshal([]byte(resultText(t, depositRes)), &deposit)
 
 	// Step 5: Build all three transactions in sequence.
-	buildHandler := handleBuildEVMTx()
+	buildHandler := handleBuildEVMTx(vault.NewStore(), nil)
 	txCases := []struct {
 		name string
 		to   string
@@ -592,7 +594,7 @@ func TestSparkWithdrawWorkflow(t *testing.T) {
 	}
 
 	// Step 3: Build the withdraw transaction with on-chain parameters (nonce 13).
-	buildHandler := handleBuildEVMTx()
+	buildHandler := handleBuildEVMTx(vault.NewStore(), nil)
 	res, err := buildHandler(ctx, callToolReq("build_evm_tx", map[string]any{
 		"to":                       sparkVault,
 		"value":                    "0",
@@ -638,7 +640,7 @@ func TestSparkWithdrawWorkflow(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestBuildEVMTx_Deterministic(t *testing.T) {
-	handler := handleBuildEVMTx()
+	handler := handleBuildEVMTx(vault.NewStore(), nil)
 	ctx := context.Background()
 
 	args := map[string]any{
@@ -665,3 +667,102 @@ func TestBuildEVMTx_Deterministic(t *testing.T) {
 		t.Errorf("non-deterministic output:\n  run1: %s\n  run2: %s", outputs[0], outputs[1])
 	}
 }
+
+func TestBuildEVMTx_KeysignOutput(t *testing.T) {
+	store := vault.NewStore()
+	store.Set("default", vault.Info{
+		ECDSAPublicKey: testECDSAPubKey,
+		EdDSAPublicKey: testEdDSAPubKey,
+		ChainCode:      testChainCode,
+	})
+	handler := handleBuildEVMTx(store, nil)
+
+	data := "0x095ea7b3000000000000000000000000e2e7a17dff93280dec073c995595155283e3c37200000000000000000000000000000000000000000000000000000000000f4240"
+	res, err := handler(context.Background(), callToolReq("build_evm_tx", map[string]any{
+		"to":                       usdt,
+		"value":                    "0",
+		"data":                     data,
+		"nonce":                    "11",
+		"gas_limit":                "60000",
+		"max_fee_per_gas":          "82751424",
+		"max_priority_fee_per_gas": "15750",
+		"chain_id":                 "1",
+		"output_format":            "keysign",
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	var out struct {
+		Chain  string `json:"chain"`
+		Action string `json:"action"`
+		Base64 string `json:"keysign_payload_base64"`
+	}
+	err = json.Unmarshal([]byte(resultText(t, res)), &out)
+	if err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if out.Chain != "Ethereum" || out.Action != "contract_call" {
+		t.Errorf("chain/action = %s/%s, want Ethereum/contract_call", out.Chain, out.Action)
+	}
+
+	p, err := keysign.Decode(out.Base64)
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if !strings.EqualFold(p.GetCoin().GetAddress(), testAddress) {
+		t.Errorf("coin address = %s, want %s", p.GetCoin().GetAddress(), testAddress)
+	}
+	if p.GetToAddress() != usdt {
+		t.Errorf("to = %s, want %s", p.GetToAddress(), usdt)
+	}
+	if p.GetMemo() != data {
+		t.Errorf("memo = %s, want calldata", p.GetMemo())
+	}
+	eth := p.GetEthereumSpecific()
+	if eth.GetNonce() != 11 || eth.GetGasLimit() != "60000" || eth.GetMaxFeePerGasWei() != "82751424" || eth.GetPriorityFee() != "15750" {
+		t.Errorf("ethereum_specific = %+v", eth)
+	}
+}
+
+func TestBuildEVMTx_KeysignRequiresVault(t *testing.T) {
+	handler := handleBuildEVMTx(vault.NewStore(), nil)
+	res, err := handler(context.Background(), callToolReq("build_evm_tx", map[string]any{
+		"to":                       usdt,
+		"value":                    "0",
+		"nonce":                    "0",
+		"gas_limit":                "21000",
+		"max_fee_per_gas":          "1",
+		"max_priority_fee_per_gas": "1",
+		"chain_id":                 "1",
+		"output_format":            "keysign",
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if !res.IsError {
+		t.Fatal("expected tool error without vault info")
+	}
+}
+
+func TestBuildEVMTx_KeysignFromMismatch(t *testing.T) {
+	store := vault.NewStore()
+	store.Set("default", vault.Info{ECDSAPublicKey: testECDSAPubKey, EdDSAPublicKey: testEdDSAPubKey, ChainCode: testChainCode})
+	handler := handleBuildEVMTx(store, nil)
+	res, err := handler(context.Background(), callToolReq("build_evm_tx", map[string]any{
+		"from":                     permit2,
+		"to":                       usdt,
+		"value":                    "0",
+		"nonce":                    "0",
+		"gas_limit":                "21000",
+		"max_fee_per_gas":          "1",
+		"max_priority_fee_per_gas": "1",
+		"output_format":            "keysign",
+	}))
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(mcp.TextContent).Text, "does not match vault-derived address") {
+		t.Fatalf("expected from mismatch error, got %v", res.Content)
+	}
+}
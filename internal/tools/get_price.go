@@ -12,6 +12,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/vultisig/mcp/internal/coingecko"
+	evmclient "github.com/vultisig/mcp/internal/evm"
 )
 
 // nativeCoinGeckoID maps uppercase ticker symbols to CoinGecko coin IDs.
@@ -92,7 +93,7 @@ func handleGetPrice(cgClient *coingecko.Client) server.ToolHandlerFunc {
 			if chain == "" {
 				return mcp.NewToolResultError("chain is required when querying by contract address"), nil
 			}
-			platform, ok := chainToPlatform[chain]
+			platform, ok := chainPlatform(chain)
 			if !ok {
 				return mcp.NewToolResultError(fmt.Sprintf("unsupported chain %q for token price lookup", chain)), nil
 			}
@@ -172,11 +173,38 @@ func handleGetPrice(cgClient *coingecko.Client) server.ToolHandlerFunc {
 	}
 }
 
+// chainPlatform returns the CoinGecko asset-platform ID for a chain,
+// including EVM chains declared at runtime.
+func chainPlatform(chain string) (string, bool) {
+	if platform, ok := chainToPlatform[chain]; ok {
+		return platform, true
+	}
+	return evmclient.CoinGeckoPlatform(chain)
+}
+
+// nativePriceID returns the CoinGecko coin ID of an EVM chain's native
+// coin, preferring the ID declared for a runtime chain over its ticker.
+func nativePriceID(chain string) (string, bool) {
+	if id, ok := evmclient.NativeCoinGeckoID(chain); ok {
+		return id, true
+	}
+	id, ok := nativeCoinGeckoID[evmclient.NativeTicker(chain)]
+	return id, ok
+}
+
 func chainPlatformNames() []string {
 	names := make([]string, 0, len(chainToPlatform))
 	for name := range chainToPlatform {
 		names = append(names, name)
 	}
+	for _, name := range evmclient.EVMChains {
+		if _, ok := chainToPlatform[name]; ok {
+			continue
+		}
+		if _, ok := evmclient.CoinGeckoPlatform(name); ok {
+			names = append(names, name)
+		}
+	}
 	sort.Strings(names)
 	return names
 }
@@ -79,30 +79,13 @@ func handleGetTRC20TokenBalance(store *vault.Store, tronClient *tron.Client) ser
 			return mcp.NewToolResultError(fmt.Sprintf("decode balance: %v", err)), nil
 		}
 
-		decimalsResult, err := tronClient.TriggerConstantContract(ctx, addr, contractAddr, "decimals()", "")
+		meta, err := tronClient.TokenMetadata(ctx, addr, contractAddr)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get TRC-20 decimals: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("get TRC-20 metadata: %v", err)), nil
 		}
-
-		if len(decimalsResult.ConstantResult) == 0 {
-			return mcp.NewToolResultError("no result from decimals() call"), nil
-		}
-		decimals, err := tron.DecodeTRC20Decimals(decimalsResult.ConstantResult[0])
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("decode decimals: %v", err)), nil
-		}
-
-		symbolResult, err := tronClient.TriggerConstantContract(ctx, addr, contractAddr, "symbol()", "")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("get TRC-20 symbol: %v", err)), nil
-		}
-
-		var symbol string
-		if len(symbolResult.ConstantResult) > 0 {
-			symbol, err = tron.DecodeTRC20Symbol(symbolResult.ConstantResult[0])
-			if err != nil {
-				symbol = "UNKNOWN"
-			}
+		symbol, decimals := meta.Symbol, meta.Decimals
+		if symbol == "" {
+			symbol = "UNKNOWN"
 		}
 
 		result := map[string]any{
@@ -112,6 +95,9 @@ func handleGetTRC20TokenBalance(store *vault.Store, tronClient *tron.Client) ser
 			"balance":          tron.FormatTokenBalance(balance, decimals),
 			"decimals":         decimals,
 		}
+		if meta.NoDecimals {
+			result["decimals_warning"] = "decimals unavailable, balance is in base units"
+		}
 
 		data, err := json.Marshal(result)
 		if err != nil {
@@ -13,14 +13,18 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/vultisig/mcp/internal/blockchair"
 	evmclient "github.com/vultisig/mcp/internal/evm"
 	gaiaclient "github.com/vultisig/mcp/internal/gaia"
+	"github.com/vultisig/mcp/internal/resolve"
 	solanaclient "github.com/vultisig/mcp/internal/solana"
 	tronclient "github.com/vultisig/mcp/internal/tron"
+	"github.com/vultisig/mcp/internal/utxobackend"
+	"github.com/vultisig/mcp/internal/vault"
 	xrpclient "github.com/vultisig/mcp/internal/xrp"
 )
 
@@ -43,6 +47,9 @@ func newGetTxStatusTool() mcp.Tool {
 		mcp.WithDescription(
 			"Check the confirmation status of a transaction by its hash. "+
 				"Returns status (confirmed/pending/failed/not_found), block number, confirmations, and fee. "+
+				"Failed EVM transactions include a decoded revert field explaining why they reverted. "+
+				"EVM transactions also list their decoded events (token transfers, approvals, WETH, Aave and Uniswap events) "+
+				"and the net balance changes of address (default: the vault's EVM address, else the sender). "+
 				"Supported chains: "+strings.Join(allChains, ", "),
 		),
 		mcp.WithString("chain",
@@ -53,6 +60,9 @@ func newGetTxStatusTool() mcp.Tool {
 			mcp.Description("Transaction hash (0x-prefixed for EVM, base58 for Solana, hex for UTXO/XRP)."),
 			mcp.Required(),
 		),
+		mcp.WithString("address",
+			mcp.Description("EVM only: address whose balance changes to report. Falls back to vault-derived, then to the sender."),
+		),
 	)
 }
 
@@ -67,9 +77,22 @@ type txStatusResult struct {
 	GasUsed       string `json:"gas_used,omitempty"`
 	From          string `json:"from,omitempty"`
 	To            string `json:"to,omitempty"`
+	ExplorerURL   string `json:"explorer_url,omitempty"`
+	// Revert explains why a failed EVM transaction reverted.
+	Revert *revertInfo `json:"revert,omitempty"`
+	// Events are the decoded token transfers, approvals and protocol
+	// events of an EVM transaction.
+	Events        []txEvent `json:"events,omitempty"`
+	UndecodedLogs int       `json:"undecoded_logs,omitempty"`
+	// BalanceChanges are the net balance changes of BalanceAddress.
+	// NativeChangeSource says how the native change was computed;
+	// "block_diff" is approximate.
+	BalanceAddress     string          `json:"balance_address,omitempty"`
+	BalanceChanges     []balanceChange `json:"balance_changes,omitempty"`
+	NativeChangeSource string          `json:"native_change_source,omitempty"`
 }
 
-func handleGetTxStatus(pool *evmclient.Pool, bcClient *blockchair.Client, solClient *solanaclient.Client, xrpClient *xrpclient.Client, tronClient *tronclient.Client, gaiaClient *gaiaclient.Client) server.ToolHandlerFunc {
+func handleGetTxStatus(store *vault.Store, pool *evmclient.Pool, reverts *revertDecoder, utxoBackend utxobackend.Backend, solClient *solanaclient.Client, xrpClient *xrpclient.Client, tronClient *tronclient.Client, gaiaClient *gaiaclient.Client) server.ToolHandlerFunc {
 	// Build lookup sets from canonical sources at init time.
 	evmChainSet := make(map[string]bool, len(evmclient.EVMChains))
 	for _, c := range evmclient.EVMChains {
@@ -90,9 +113,9 @@ func handleGetTxStatus(pool *evmclient.Pool, bcClient *blockchair.Client, solCli
 
 		switch {
 		case evmChainSet[chain]:
-			result, err = getEVMTxStatus(ctx, pool, chain, txHash)
+			result, err = getEVMTxStatus(ctx, pool, reverts, chain, txHash, evmBalanceAddress(ctx, req, store))
 		case blockchair.SupportedChains[chain] != (blockchair.ChainInfo{}):
-			result, err = getUTXOTxStatus(ctx, bcClient, chain, txHash)
+			result, err = getUTXOTxStatus(ctx, utxoBackend, chain, txHash)
 		case chain == "Solana":
 			result, err = getSolanaTxStatus(ctx, solClient, txHash)
 		case chain == "Ripple" || chain == "XRP":
@@ -117,12 +140,12 @@ func handleGetTxStatus(pool *evmclient.Pool, bcClient *blockchair.Client, solCli
 	}
 }
 
-func getEVMTxStatus(ctx context.Context, pool *evmclient.Pool, chain, txHash string) (*txStatusResult, error) {
+func getEVMTxStatus(ctx context.Context, pool *evmclient.Pool, reverts *revertDecoder, chain, txHash, address string) (*txStatusResult, error) {
 	if !evmTxHashRE.MatchString(txHash) {
 		return nil, fmt.Errorf("invalid EVM transaction hash: %s (expected 0x + 64 hex chars)", txHash)
 	}
 
-	client, _, err := pool.Get(ctx, chain)
+	client, chainID, err := pool.Get(ctx, chain)
 	if err != nil {
 		return nil, fmt.Errorf("chain %s unavailable: %v", chain, err)
 	}
@@ -183,6 +206,7 @@ func getEVMTxStatus(ctx context.Context, pool *evmclient.Pool, chain, txHash str
 		Success:       success,
 		Confirmations: confirmations,
 		Fee:           feeStr,
+		ExplorerURL:   evmclient.ExplorerTxURL(chain, txHash),
 	}
 
 	if receipt.BlockNumber != nil {
@@ -195,13 +219,64 @@ func getEVMTxStatus(ctx context.Context, pool *evmclient.Pool, chain, txHash str
 		if tx.To() != nil {
 			result.To = tx.To().Hex()
 		}
-		// From requires a signer, use the receipt logs or skip for simplicity.
+		from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+		if err == nil {
+			result.From = from.Hex()
+			if !success {
+				result.Revert = replayRevert(ctx, client, reverts, chainID, tx, receipt, from)
+			}
+
+			// Report balance changes of the vault, or of the sender when
+			// no vault or address is given.
+			owner := from
+			if ethcommon.IsHexAddress(address) {
+				owner = ethcommon.HexToAddress(address)
+			}
+			result.BalanceAddress = owner.Hex()
+			result.Events, result.BalanceChanges, result.NativeChangeSource, result.UndecodedLogs = txEffects(ctx, client, chain, tx, receipt, from, owner)
+		}
 	}
 
 	return result, nil
 }
 
-func getUTXOTxStatus(ctx context.Context, bcClient *blockchair.Client, chain, txHash string) (*txStatusResult, error) {
+// evmBalanceAddress returns the address param or the vault's EVM address,
+// or "" when neither is available.
+func evmBalanceAddress(ctx context.Context, req mcp.CallToolRequest, store *vault.Store) string {
+	addr, err := resolve.EVMAddress(req.GetString("address", ""), resolve.ResolveVault(ctx, req, store))
+	if err != nil {
+		return ""
+	}
+	return addr
+}
+
+// replayRevert explains why tx failed: out of gas when it used its whole
+// gas limit, otherwise the revert from replaying it with eth_call on the
+// state before its block. Transactions earlier in the same block are not
+// replayed, so the result is nil if the replay succeeds.
+func replayRevert(ctx context.Context, client *evmclient.Client, reverts *revertDecoder, chainID *big.Int, tx *types.Transaction, receipt *types.Receipt, from ethcommon.Address) *revertInfo {
+	if receipt.GasUsed == tx.Gas() {
+		return &revertInfo{Kind: revertOutOfGas, Reason: "ran out of gas: the gas limit was too low"}
+	}
+	if receipt.BlockNumber == nil || receipt.BlockNumber.Sign() == 0 {
+		return nil
+	}
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	_, err := client.CallContract(ctx, msg, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+	if err == nil {
+		return nil
+	}
+	revert, _ := reverts.fromError(ctx, client, chainID.Uint64(), tx.To(), err)
+	return revert
+}
+
+func getUTXOTxStatus(ctx context.Context, utxoBackend utxobackend.Backend, chain, txHash string) (*txStatusResult, error) {
 	if !utxoTxHashRE.MatchString(txHash) {
 		return nil, fmt.Errorf("invalid UTXO transaction hash: %s (expected 64 hex chars)", txHash)
 	}
@@ -211,9 +286,9 @@ func getUTXOTxStatus(ctx context.Context, bcClient *blockchair.Client, chain, tx
 		return nil, fmt.Errorf("unsupported UTXO chain: %s", chain)
 	}
 
-	tx, err := bcClient.GetTxDashboard(ctx, chain, txHash)
+	tx, err := utxoBackend.GetTxDashboard(ctx, chain, txHash)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tx status from Blockchair: %v", err)
+		return nil, fmt.Errorf("failed to get tx status: %v", err)
 	}
 	if tx == nil {
 		return &txStatusResult{
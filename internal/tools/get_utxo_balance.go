@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/vultisig/mcp/internal/blockchair"
+	"github.com/vultisig/mcp/internal/coingecko"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/utxobackend"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+func newGetUTXOBalanceTool() mcp.Tool {
+	return mcp.NewTool("get_utxo_balance",
+		mcp.WithDescription(
+			"Query the native balance of a Bitcoin, Litecoin, Dogecoin, Bitcoin-Cash, Dash or Zcash address, "+
+				"split into confirmed and unconfirmed amounts, with its USD value when a price is available. "+
+				"If no address is provided, derives it from the vault's ECDSA public key. "+
+				"Accepts inline vault keys (ecdsa_public_key, eddsa_public_key, chain_code) or falls back to set_vault_info session state.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("UTXO chain"),
+			mcp.Required(),
+			mcp.Enum(blockchair.ChainNames...),
+		),
+		mcp.WithString("address",
+			mcp.Description("Address on the chain. Optional if vault info is set."),
+		),
+	)
+}
+
+type utxoBalanceResult struct {
+	Chain              string   `json:"chain"`
+	Ticker             string   `json:"ticker"`
+	Address            string   `json:"address"`
+	Balance            int64    `json:"balance"`
+	BalanceFormatted   string   `json:"balance_formatted"`
+	ConfirmedBalance   int64    `json:"confirmed_balance"`
+	UnconfirmedBalance int64    `json:"unconfirmed_balance"`
+	BalanceUSD         *float64 `json:"balance_usd,omitempty"`
+	UTXOCount          int      `json:"utxo_count"`
+}
+
+func handleGetUTXOBalance(store *vault.Store, utxoBackend utxobackend.Backend, cgClient *coingecko.Client) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chain, addr, errResult := resolveUTXOAddress(ctx, req, store)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		dashboard, err := utxoBackend.GetAddressDashboard(ctx, chain, addr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("get %s balance: %v", chain, err)), nil
+		}
+
+		var unconfirmed int64
+		for _, u := range dashboard.UTXOs {
+			if u.BlockID <= 0 {
+				unconfirmed += u.Value
+			}
+		}
+
+		info := blockchair.SupportedChains[chain]
+		result := utxoBalanceResult{
+			Chain:              chain,
+			Ticker:             info.Ticker,
+			Address:            addr,
+			Balance:            dashboard.Address.Balance,
+			BalanceFormatted:   blockchair.FormatSatoshis(dashboard.Address.Balance, info.Decimals),
+			ConfirmedBalance:   dashboard.Address.Balance - unconfirmed,
+			UnconfirmedBalance: unconfirmed,
+			UTXOCount:          dashboard.Address.UnspentOutputCount,
+		}
+		if price, ok := utxoCoinPrice(ctx, cgClient, dashboard, info); ok {
+			usd := roundUSD(float64(dashboard.Address.Balance) / math.Pow10(info.Decimals) * price)
+			result.BalanceUSD = &usd
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal utxo balance result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// resolveUTXOAddress reads the chain parameter and the address to query,
+// validating an explicit address against the chain or deriving it from the vault.
+func resolveUTXOAddress(ctx context.Context, req mcp.CallToolRequest, store *vault.Store) (string, string, *mcp.CallToolResult) {
+	chain, err := req.RequireString("chain")
+	if err != nil {
+		return "", "", mcp.NewToolResultError("missing chain")
+	}
+	params, ok := utxoChains[chain]
+	if !ok {
+		return "", "", mcp.NewToolResultError(fmt.Sprintf("unsupported UTXO chain: %q", chain))
+	}
+
+	explicit := req.GetString("address", "")
+	if explicit != "" {
+		_, err = params.addressToPkScript(explicit)
+		if err != nil {
+			return "", "", mcp.NewToolResultError(fmt.Sprintf("invalid %s address: %v", chain, err))
+		}
+	}
+
+	addr, err := resolve.ChainAddress(explicit, resolve.ResolveVault(ctx, req, store), chain)
+	if err != nil {
+		return "", "", mcp.NewToolResultError(err.Error())
+	}
+	return chain, addr, nil
+}
+
+// utxoCoinPrice returns the USD price of the chain's coin, derived from the
+// dashboard's balance value when the backend prices it and looked up on
+// CoinGecko otherwise (Electrum servers carry no prices). ok is false when
+// neither has a price.
+func utxoCoinPrice(ctx context.Context, cgClient *coingecko.Client, dashboard *utxobackend.AddressDashboard, info blockchair.ChainInfo) (float64, bool) {
+	if price := usdPerCoin(dashboard.Address.Balance, dashboard.Address.BalanceUSD, info.Decimals); price > 0 {
+		return price, true
+	}
+	id, ok := nativeCoinGeckoID[info.Ticker]
+	if !ok || cgClient == nil {
+		return 0, false
+	}
+	pd, err := cgClient.GetSimplePrice(ctx, id)
+	if err != nil || pd.USD <= 0 {
+		return 0, false
+	}
+	return pd.USD, true
+}
+
+// usdPerCoin derives the coin price from a balance and its USD value.
+func usdPerCoin(balance int64, balanceUSD float64, decimals int) float64 {
+	if balance <= 0 {
+		return 0
+	}
+	return balanceUSD / (float64(balance) / math.Pow10(decimals))
+}
+
+func roundUSD(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vultisig/mcp/internal/blockchair"
+	"github.com/vultisig/mcp/internal/electrum"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// mockBlockchairDashboard serves one address dashboard at chain tip 800100:
+// two confirmed UTXOs (0.5 and 0.2 BTC) and one unconfirmed (0.1 BTC),
+// with the balance valued at $40,000.
+func mockBlockchairDashboard(t *testing.T) *blockchair.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 4 || parts[1]+"/"+parts[2] != "dashboards/address" {
+			http.NotFound(w, r)
+			return
+		}
+		addr := parts[3]
+		body := map[string]any{
+			"data": map[string]any{addr: map[string]any{
+				"address": map[string]any{
+					"balance":              80_000_000,
+					"balance_usd":          40_000.0,
+					"unspent_output_count": 3,
+				},
+				"utxo": []map[string]any{
+					{"block_id": 800000, "transaction_hash": strings.Repeat("aa", 32), "index": 0, "value": 20_000_000},
+					{"block_id": -1, "transaction_hash": strings.Repeat("bb", 32), "index": 1, "value": 10_000_000},
+					{"block_id": 800091, "transaction_hash": strings.Repeat("cc", 32), "index": 2, "value": 50_000_000},
+				},
+			}},
+			"context": map[string]any{"code": 200, "state": 800100},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(body)
+		if err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return blockchair.NewClient(srv.URL)
+}
+
+// mockElectrumDashboard serves the same address as mockBlockchairDashboard
+// over the Electrum protocol, which carries no prices.
+func mockElectrumDashboard(t *testing.T) *electrum.Client {
+	t.Helper()
+	results := map[string]any{
+		"server.version":                    []string{"fake 1.0", "1.4"},
+		"blockchain.scripthash.get_balance": map[string]int64{"confirmed": 70_000_000, "unconfirmed": 10_000_000},
+		"blockchain.scripthash.get_history": []map[string]any{
+			{"tx_hash": strings.Repeat("aa", 32), "height": 800000},
+			{"tx_hash": strings.Repeat("cc", 32), "height": 800091},
+			{"tx_hash": strings.Repeat("bb", 32), "height": 0},
+		},
+		"blockchain.scripthash.listunspent": []map[string]any{
+			{"tx_hash": strings.Repeat("aa", 32), "tx_pos": 0, "height": 800000, "value": 20_000_000},
+			{"tx_hash": strings.Repeat("bb", 32), "tx_pos": 1, "height": 0, "value": 10_000_000},
+			{"tx_hash": strings.Repeat("cc", 32), "tx_pos": 2, "height": 800091, "value": 50_000_000},
+		},
+		"blockchain.headers.subscribe": map[string]any{"height": 800100, "hex": ""},
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				sc := bufio.NewScanner(conn)
+				for sc.Scan() {
+					var req struct {
+						ID     int    `json:"id"`
+						Method string `json:"method"`
+					}
+					if json.Unmarshal(sc.Bytes(), &req) != nil {
+						return
+					}
+					data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": results[req.Method]})
+					if _, err := conn.Write(append(data, '\n')); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	c, err := electrum.NewClient("tcp://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestGetUTXOBalance_VaultAddress(t *testing.T) {
+	store := setupBTCVault(t)
+	handler := handleGetUTXOBalance(store, mockBlockchairDashboard(t), nil)
+
+	res, err := handler(context.Background(), callToolReq("get_utxo_balance", map[string]any{
+		"chain": "Bitcoin",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result utxoBalanceResult
+	err = json.Unmarshal([]byte(resultText(t, res)), &result)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if result.Address != deriveBTCAddress(t, store) {
+		t.Errorf("address = %q, want the vault address", result.Address)
+	}
+	if result.Balance != 80_000_000 || result.BalanceFormatted != "0.80000000" {
+		t.Errorf("balance = %d (%s)", result.Balance, result.BalanceFormatted)
+	}
+	if result.ConfirmedBalance != 70_000_000 || result.UnconfirmedBalance != 10_000_000 {
+		t.Errorf("confirmed/unconfirmed = %d/%d", result.ConfirmedBalance, result.UnconfirmedBalance)
+	}
+	if result.BalanceUSD == nil || *result.BalanceUSD != 40_000 || result.Ticker != "BTC" || result.UTXOCount != 3 {
+		t.Errorf("usd/ticker/count = %v/%s/%d", result.BalanceUSD, result.Ticker, result.UTXOCount)
+	}
+}
+
+func TestGetUTXOBalance_InvalidAddress(t *testing.T) {
+	handler := handleGetUTXOBalance(vault.NewStore(), mockBlockchairDashboard(t), nil)
+
+	res, err := handler(context.Background(), callToolReq("get_utxo_balance", map[string]any{
+		"chain":   "Litecoin",
+		"address": "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Fatal("expected an error for a Bitcoin address on Litecoin")
+	}
+}
+
+func TestGetUTXOBalance_Electrum(t *testing.T) {
+	store := setupBTCVault(t)
+
+	t.Run("coingecko price", func(t *testing.T) {
+		handler := handleGetUTXOBalance(store, mockElectrumDashboard(t), mockCoinGeckoPrice(t, "bitcoin", 50_000))
+		res, err := handler(context.Background(), callToolReq("get_utxo_balance", map[string]any{
+			"chain": "Bitcoin",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result := decodeResult(t, res)
+		if result["balance"] != float64(80_000_000) || result["unconfirmed_balance"] != float64(10_000_000) {
+			t.Errorf("balance = %v, unconfirmed %v", result["balance"], result["unconfirmed_balance"])
+		}
+		if result["balance_usd"] != float64(40_000) {
+			t.Errorf("balance_usd = %v, want 40000", result["balance_usd"])
+		}
+	})
+
+	t.Run("no price", func(t *testing.T) {
+		handler := handleGetUTXOBalance(store, mockElectrumDashboard(t), nil)
+		res, err := handler(context.Background(), callToolReq("get_utxo_balance", map[string]any{
+			"chain": "Bitcoin",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result := decodeResult(t, res)
+		if _, ok := result["balance_usd"]; ok || result["balance"] != float64(80_000_000) {
+			t.Errorf("result = %v, want balance_usd omitted", result)
+		}
+	})
+}
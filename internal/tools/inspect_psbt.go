@@ -0,0 +1,352 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vultisig/vultisig-go/address"
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/mcp/internal/blockchair"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/utxo"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// highFeePercent is the share of the amount sent above which a fee is flagged.
+const highFeePercent = 10
+
+const (
+	// sigHashMask selects the base sighash type (ALL, NONE or SINGLE).
+	sigHashMask txscript.SigHashType = 0x1f
+	// sigHashForkID is the Bitcoin Cash replay-protection sighash flag.
+	sigHashForkID txscript.SigHashType = 0x40
+)
+
+func newInspectPSBTTool() mcp.Tool {
+	return mcp.NewTool("inspect_psbt",
+		mcp.WithDescription(
+			"Decode a PSBT received from another wallet or co-signer and explain what signing it would do. "+
+				"Lists inputs with their amounts and outputs with addresses and script types, "+
+				"computes the fee and fee rate, marks outputs paying the vault's own address as change, "+
+				"and warns about non-default sighash flags and unusually high fees. "+
+				"Change detection uses inline vault keys (ecdsa_public_key, eddsa_public_key, chain_code) or set_vault_info session state when available.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("UTXO chain the PSBT spends on. Zcash has no PSBT format."),
+			mcp.Required(),
+			mcp.Enum("Bitcoin", "Litecoin", "Dogecoin", "Bitcoin-Cash", "Dash"),
+		),
+		mcp.WithString("psbt",
+			mcp.Description("PSBT as base64 or hex"),
+			mcp.Required(),
+		),
+	)
+}
+
+type psbtInputJSON struct {
+	TxID     string `json:"txid"`
+	Vout     uint32 `json:"vout"`
+	Sequence uint32 `json:"sequence"`
+	// Value is nil when the PSBT carries no UTXO information for the input.
+	Value       *int64 `json:"value"`
+	Address     string `json:"address,omitempty"`
+	ScriptType  string `json:"script_type,omitempty"`
+	SighashType string `json:"sighash_type"`
+	Signed      bool   `json:"signed"`
+	IsVault     bool   `json:"is_vault"`
+}
+
+type psbtOutputJSON struct {
+	Value          int64  `json:"value"`
+	ValueFormatted string `json:"value_formatted"`
+	Address        string `json:"address,omitempty"`
+	ScriptType     string `json:"script_type"`
+	ScriptHex      string `json:"script_hex"`
+	Memo           string `json:"memo,omitempty"`
+	IsChange       bool   `json:"is_change"`
+}
+
+type inspectPSBTResult struct {
+	Chain         string           `json:"chain"`
+	Ticker        string           `json:"ticker"`
+	TxID          string           `json:"txid"`
+	Version       int32            `json:"version"`
+	LockTime      uint32           `json:"lock_time"`
+	RBF           bool             `json:"rbf"`
+	Inputs        []psbtInputJSON  `json:"inputs"`
+	Outputs       []psbtOutputJSON `json:"outputs"`
+	InputTotal    *int64           `json:"input_total"`
+	OutputTotal   int64            `json:"output_total"`
+	Sent          int64            `json:"sent"`
+	Fee           *int64           `json:"fee"`
+	FeeFormatted  string           `json:"fee_formatted,omitempty"`
+	VSize         int              `json:"vsize,omitempty"`
+	FeeRate       float64          `json:"fee_rate,omitempty"`
+	ChangeAddress string           `json:"change_address,omitempty"`
+	Warnings      []string         `json:"warnings"`
+}
+
+func handleInspectPSBT(store *vault.Store) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		chainName, err := req.RequireString("chain")
+		if err != nil {
+			return mcp.NewToolResultError("missing chain"), nil
+		}
+		if chainName == "Zcash" {
+			return mcp.NewToolResultError("Zcash has no PSBT format; Zcash transactions are built as raw v4 transactions"), nil
+		}
+		if _, ok := utxoChains[chainName]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported UTXO chain: %q", chainName)), nil
+		}
+		encoded, err := req.RequireString("psbt")
+		if err != nil {
+			return mcp.NewToolResultError("missing psbt"), nil
+		}
+
+		pkt, err := parsePSBT(encoded)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var changeAddr string
+		var changeScript []byte
+		if v := resolve.ResolveVault(ctx, req, store); v != nil {
+			chain, err := common.FromString(chainName)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			changeAddr, _, _, err = address.GetAddress(v.ECDSAPublicKey, v.ChainCode, chain)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("derive %s address: %v", chainName, err)), nil
+			}
+			changeScript, err = utxo.AddressScript(chainName, changeAddr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("vault address script: %v", err)), nil
+			}
+		}
+
+		result := inspectPSBT(chainName, pkt, changeScript)
+		result.ChangeAddress = changeAddr
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// parsePSBT decodes a PSBT given as hex or base64.
+func parsePSBT(s string) (*psbt.Packet, error) {
+	s = strings.TrimSpace(s)
+	raw, err := hex.DecodeString(s)
+	b64 := err != nil
+	if b64 {
+		raw = []byte(s)
+	}
+	pkt, err := psbt.NewFromRawBytes(bytes.NewReader(raw), b64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PSBT: %w", err)
+	}
+	return pkt, nil
+}
+
+// inspectPSBT describes pkt. changeScript, when set, is the vault's own
+// output script on the chain.
+func inspectPSBT(chain string, pkt *psbt.Packet, changeScript []byte) *inspectPSBTResult {
+	tx := pkt.UnsignedTx
+	info := blockchair.SupportedChains[chain]
+	res := &inspectPSBTResult{
+		Chain:    chain,
+		Ticker:   info.Ticker,
+		TxID:     tx.TxHash().String(),
+		Version:  tx.Version,
+		LockTime: tx.LockTime,
+		Inputs:   make([]psbtInputJSON, len(tx.TxIn)),
+		Outputs:  make([]psbtOutputJSON, len(tx.TxOut)),
+		Warnings: []string{},
+	}
+	warn := func(format string, args ...any) {
+		res.Warnings = append(res.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	var inputTotal int64
+	valuesKnown := true
+	prevScripts := make([][]byte, len(tx.TxIn))
+	for i, txIn := range tx.TxIn {
+		pIn := pkt.Inputs[i]
+		in := psbtInputJSON{
+			TxID:     txIn.PreviousOutPoint.Hash.String(),
+			Vout:     txIn.PreviousOutPoint.Index,
+			Sequence: txIn.Sequence,
+			Signed:   len(pIn.PartialSigs) > 0 || len(pIn.FinalScriptSig) > 0 || len(pIn.FinalScriptWitness) > 0,
+		}
+		if txIn.Sequence < wire.MaxTxInSequenceNum-1 {
+			res.RBF = true
+		}
+
+		prevOut, err := psbtPrevOut(txIn, pIn)
+		if err != nil {
+			warn("input %d: %v", i, err)
+			valuesKnown = false
+		} else {
+			prevScripts[i] = prevOut.PkScript
+			inputTotal += prevOut.Value
+			in.Value = &prevOut.Value
+			in.ScriptType = txscript.GetScriptClass(prevOut.PkScript).String()
+			in.Address, _ = utxo.ScriptAddress(chain, prevOut.PkScript)
+			in.IsVault = changeScript != nil && bytes.Equal(prevOut.PkScript, changeScript)
+		}
+
+		sighash := psbtSighash(pIn)
+		in.SighashType = sighashName(sighash)
+		if !sighashIsDefault(chain, sighash) {
+			warn("input %d signs with sighash %s instead of ALL: other parties can change parts of the transaction after signing", i, in.SighashType)
+		}
+		res.Inputs[i] = in
+	}
+
+	for i, txOut := range tx.TxOut {
+		out := psbtOutputJSON{
+			Value:          txOut.Value,
+			ValueFormatted: blockchair.FormatSatoshis(txOut.Value, info.Decimals),
+			ScriptType:     txscript.GetScriptClass(txOut.PkScript).String(),
+			ScriptHex:      hex.EncodeToString(txOut.PkScript),
+			IsChange:       changeScript != nil && bytes.Equal(txOut.PkScript, changeScript),
+		}
+		out.Address, _ = utxo.ScriptAddress(chain, txOut.PkScript)
+		if txscript.IsNullData(txOut.PkScript) {
+			out.Memo = nullDataText(txOut.PkScript)
+		}
+		res.OutputTotal += txOut.Value
+		if !out.IsChange {
+			res.Sent += txOut.Value
+		}
+		res.Outputs[i] = out
+	}
+
+	if !valuesKnown {
+		warn("fee cannot be computed without the value of every input")
+		return res
+	}
+	res.InputTotal = &inputTotal
+	fee := inputTotal - res.OutputTotal
+	res.Fee = &fee
+	if fee < 0 {
+		warn("outputs spend %d more than the inputs provide; the transaction is invalid", -fee)
+		return res
+	}
+	res.FeeFormatted = blockchair.FormatSatoshis(fee, info.Decimals)
+
+	vsize, err := utxo.SignedVSize(tx, prevScripts)
+	if err != nil {
+		warn("fee rate unknown: %v", err)
+	} else {
+		res.VSize = vsize
+		res.FeeRate = roundRate(float64(fee) / float64(vsize))
+	}
+
+	base := res.Sent
+	if base == 0 {
+		base = inputTotal
+	}
+	if fee*100 > base*highFeePercent {
+		warn("fee %s %s is %.1f%% of the %s %s being sent",
+			res.FeeFormatted, info.Ticker, float64(fee)*100/float64(max(base, 1)),
+			blockchair.FormatSatoshis(base, info.Decimals), info.Ticker)
+	}
+	return res
+}
+
+// psbtPrevOut returns the output spent by txIn from the PSBT's witness or
+// non-witness UTXO field.
+func psbtPrevOut(txIn *wire.TxIn, pIn psbt.PInput) (*wire.TxOut, error) {
+	op := txIn.PreviousOutPoint
+	if pIn.NonWitnessUtxo != nil {
+		if pIn.NonWitnessUtxo.TxHash() != op.Hash {
+			return nil, fmt.Errorf("non-witness UTXO is transaction %s, not the spent %s", pIn.NonWitnessUtxo.TxHash(), op.Hash)
+		}
+		if int(op.Index) >= len(pIn.NonWitnessUtxo.TxOut) {
+			return nil, fmt.Errorf("non-witness UTXO has no output %d", op.Index)
+		}
+		return pIn.NonWitnessUtxo.TxOut[op.Index], nil
+	}
+	if pIn.WitnessUtxo != nil {
+		return pIn.WitnessUtxo, nil
+	}
+	return nil, fmt.Errorf("no UTXO information for %s", op)
+}
+
+// psbtSighash returns the sighash the input requests or was signed with.
+// Zero means none was given and the signer's default applies.
+func psbtSighash(pIn psbt.PInput) txscript.SigHashType {
+	if pIn.SighashType != 0 {
+		return pIn.SighashType
+	}
+	for _, sig := range pIn.PartialSigs {
+		if len(sig.Signature) > 0 {
+			return txscript.SigHashType(sig.Signature[len(sig.Signature)-1])
+		}
+	}
+	return 0
+}
+
+// sighashIsDefault reports whether h commits to every input and output.
+func sighashIsDefault(chain string, h txscript.SigHashType) bool {
+	if h == 0 || h == txscript.SigHashAll {
+		return true
+	}
+	return chain == "Bitcoin-Cash" && h == txscript.SigHashAll|sigHashForkID
+}
+
+func sighashName(h txscript.SigHashType) string {
+	if h == 0 {
+		return "ALL"
+	}
+	var name string
+	switch h & sigHashMask {
+	case txscript.SigHashAll:
+		name = "ALL"
+	case txscript.SigHashNone:
+		name = "NONE"
+	case txscript.SigHashSingle:
+		name = "SINGLE"
+	default:
+		name = fmt.Sprintf("0x%02x", uint32(h&sigHashMask))
+	}
+	if h&sigHashForkID != 0 {
+		name += "|FORKID"
+	}
+	if h&txscript.SigHashAnyOneCanPay != 0 {
+		name += "|ANYONECANPAY"
+	}
+	return name
+}
+
+// nullDataText returns the data pushed by an OP_RETURN script as text if it
+// is printable, or as hex otherwise.
+func nullDataText(script []byte) string {
+	pushes, err := txscript.PushedData(script)
+	if err != nil {
+		return ""
+	}
+	data := bytes.Join(pushes, nil)
+	printable := len(data) > 0 && strings.IndexFunc(string(data), func(r rune) bool {
+		return r == unicode.ReplacementChar || !unicode.IsPrint(r)
+	}) == -1
+	if printable {
+		return string(data)
+	}
+	return hex.EncodeToString(data)
+}
@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+func TestInspectPSBT_VaultSend(t *testing.T) {
+	store := setupBTCVault(t)
+	senderAddr := deriveBTCAddress(t, store)
+	build := handleBuildBTCSend(store, mockBlockchair(t, 30_000, 100_000, 60_000))
+	res, err := build(context.Background(), callToolReq("build_btc_send", map[string]any{
+		"to_address": feeBumpRecipient,
+		"amount":     "120000",
+		"fee_rate":   float64(10),
+		"memo":       "hello",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	built := decodeResult(t, res)
+
+	res, err = handleInspectPSBT(store)(context.Background(), callToolReq("inspect_psbt", map[string]any{
+		"chain": "Bitcoin",
+		"psbt":  built["psbt"],
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+
+	if result["fee"] != built["fee"] || result["vsize"] != built["vsize"] || result["fee_rate"] != float64(10) {
+		t.Errorf("fee/vsize/rate = %v/%v/%v, built %v/%v at 10", result["fee"], result["vsize"], result["fee_rate"], built["fee"], built["vsize"])
+	}
+	if result["change_address"] != senderAddr || result["sent"] != float64(120_000) {
+		t.Errorf("change_address/sent = %v/%v", result["change_address"], result["sent"])
+	}
+	if w := result["warnings"].([]any); len(w) != 0 {
+		t.Errorf("unexpected warnings: %v", w)
+	}
+
+	outputs := result["outputs"].([]any)
+	if len(outputs) != 3 {
+		t.Fatalf("outputs = %d, want 3", len(outputs))
+	}
+	pay, change, memo := outputs[0].(map[string]any), outputs[1].(map[string]any), outputs[2].(map[string]any)
+	if pay["address"] != feeBumpRecipient || pay["is_change"] != false || pay["script_type"] != "witness_v0_keyhash" {
+		t.Errorf("payment = %v", pay)
+	}
+	if change["address"] != senderAddr || change["is_change"] != true {
+		t.Errorf("change = %v", change)
+	}
+	if memo["memo"] != "hello" || memo["script_type"] != "nulldata" {
+		t.Errorf("memo = %v", memo)
+	}
+	for _, in := range result["inputs"].([]any) {
+		in := in.(map[string]any)
+		if in["is_vault"] != true || in["sighash_type"] != "ALL" || in["signed"] != false {
+			t.Errorf("input = %v", in)
+		}
+	}
+}
+
+// foreignPSBT builds a one-input PSBT spending prevValue with the given fee.
+func foreignPSBT(t *testing.T, prevValue, fee int64) *psbt.Packet {
+	t.Helper()
+	prevScript, _ := utxoChains["Bitcoin"].addressToPkScript(feeBumpRecipient)
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{7}, Index: 0}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(prevValue-fee, prevScript))
+	pkt, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkt.Inputs[0].WitnessUtxo = wire.NewTxOut(prevValue, prevScript)
+	return pkt
+}
+
+func inspectPacket(t *testing.T, pkt *psbt.Packet, asHex bool) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := pkt.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	encoded, _ := pkt.B64Encode()
+	if asHex {
+		encoded = hex.EncodeToString(buf.Bytes())
+	}
+	res, err := handleInspectPSBT(vault.NewStore())(context.Background(), callToolReq("inspect_psbt", map[string]any{
+		"chain": "Bitcoin",
+		"psbt":  encoded,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return decodeResult(t, res)
+}
+
+func hasWarning(result map[string]any, substr string) bool {
+	for _, w := range result["warnings"].([]any) {
+		if strings.Contains(w.(string), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInspectPSBT_Warnings(t *testing.T) {
+	pkt := foreignPSBT(t, 100_000, 30_000)
+	pkt.Inputs[0].SighashType = txscript.SigHashNone | txscript.SigHashAnyOneCanPay
+
+	result := inspectPacket(t, pkt, true)
+	if result["fee"] != float64(30_000) || result["vsize"] != float64(110) {
+		t.Errorf("fee/vsize = %v/%v", result["fee"], result["vsize"])
+	}
+	if _, ok := result["change_address"]; ok {
+		t.Error("change_address set without a vault")
+	}
+	if in := result["inputs"].([]any)[0].(map[string]any); in["sighash_type"] != "NONE|ANYONECANPAY" {
+		t.Errorf("sighash_type = %v", in["sighash_type"])
+	}
+	if !hasWarning(result, "sighash NONE|ANYONECANPAY") {
+		t.Errorf("missing sighash warning: %v", result["warnings"])
+	}
+	if !hasWarning(result, "42.9%") {
+		t.Errorf("missing high fee warning: %v", result["warnings"])
+	}
+}
+
+func TestInspectPSBT_MissingUTXO(t *testing.T) {
+	pkt := foreignPSBT(t, 100_000, 1_000)
+	pkt.Inputs[0].WitnessUtxo = nil
+
+	result := inspectPacket(t, pkt, false)
+	if result["fee"] != nil || result["input_total"] != nil {
+		t.Errorf("fee/input_total = %v/%v, want null", result["fee"], result["input_total"])
+	}
+	if !hasWarning(result, "no UTXO information") {
+		t.Errorf("warnings = %v", result["warnings"])
+	}
+}
+
+func TestInspectPSBT_NonWitnessUTXOMismatch(t *testing.T) {
+	pkt := foreignPSBT(t, 100_000, 1_000)
+	pkt.Inputs[0].WitnessUtxo = nil
+	other := wire.NewMsgTx(2)
+	other.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{8}}, nil, nil))
+	other.AddTxOut(wire.NewTxOut(100_000, pkt.UnsignedTx.TxOut[0].PkScript))
+	pkt.Inputs[0].NonWitnessUtxo = other
+
+	result := inspectPacket(t, pkt, false)
+	if !hasWarning(result, "not the spent") {
+		t.Errorf("warnings = %v", result["warnings"])
+	}
+}
+
+func TestInspectPSBT_Errors(t *testing.T) {
+	handler := handleInspectPSBT(vault.NewStore())
+	for name, args := range map[string]map[string]any{
+		"zcash":   {"chain": "Zcash", "psbt": "cHNidP8="},
+		"garbage": {"chain": "Bitcoin", "psbt": "not a psbt"},
+		"missing": {"chain": "Bitcoin"},
+	} {
+		res, err := handler(context.Background(), callToolReq("inspect_psbt", args))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+}
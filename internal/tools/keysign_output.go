@@ -0,0 +1,240 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	v1 "github.com/vultisig/commondata/go/vultisig/keysign/v1"
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/mcp/internal/blockchair"
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/keysign"
+	solanaclient "github.com/vultisig/mcp/internal/solana"
+	"github.com/vultisig/mcp/internal/tron"
+	"github.com/vultisig/mcp/internal/utxo"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+const (
+	outputFormatArgs    = "args"
+	outputFormatKeysign = "keysign"
+)
+
+// withOutputFormat adds the output_format parameter shared by the build_* tools.
+func withOutputFormat() mcp.ToolOption {
+	return mcp.WithString("output_format",
+		mcp.Description(
+			"Output format. \"args\" (default) returns transaction arguments. "+
+				"\"keysign\" returns a Vultisig keysign payload (protobuf JSON and base64) that can go straight to co-signing.",
+		),
+		mcp.Enum(outputFormatArgs, outputFormatKeysign),
+		mcp.DefaultString(outputFormatArgs),
+	)
+}
+
+// keysignRequested reports whether the caller asked for keysign output.
+func keysignRequested(req mcp.CallToolRequest) (bool, error) {
+	switch f := req.GetString("output_format", outputFormatArgs); f {
+	case "", outputFormatArgs:
+		return false, nil
+	case outputFormatKeysign:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid output_format %q (expected %q or %q)", f, outputFormatArgs, outputFormatKeysign)
+	}
+}
+
+type keysignResult struct {
+	Chain  string `json:"chain"`
+	Action string `json:"action"`
+	*keysign.Encoded
+}
+
+// keysignToolResult encodes a keysign payload and wraps it in a tool result.
+func keysignToolResult(chain, action string, p *v1.KeysignPayload) (*mcp.CallToolResult, error) {
+	enc, err := keysign.Encode(p)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	data, err := json.Marshal(keysignResult{Chain: chain, Action: action, Encoded: enc})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("marshal keysign result: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// keysignStep is one payload of a batch the vault signs in order.
+type keysignStep struct {
+	Description string
+	Payload     *v1.KeysignPayload
+}
+
+type keysignBatchEntry struct {
+	Sequence    int    `json:"sequence"`
+	Description string `json:"description"`
+	*keysign.Encoded
+}
+
+type keysignBatchResult struct {
+	Chain    string              `json:"chain"`
+	Action   string              `json:"action"`
+	Payloads []keysignBatchEntry `json:"payloads"`
+}
+
+// keysignBatchToolResult encodes the payloads of several transactions that
+// must be signed and broadcast in order, and wraps them in a tool result.
+func keysignBatchToolResult(chain, action string, steps []keysignStep) (*mcp.CallToolResult, error) {
+	entries := make([]keysignBatchEntry, len(steps))
+	for i, step := range steps {
+		enc, err := keysign.Encode(step.Payload)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("payload %d: %v", i+1, err)), nil
+		}
+		entries[i] = keysignBatchEntry{Sequence: i + 1, Description: step.Description, Encoded: enc}
+	}
+
+	data, err := json.Marshal(keysignBatchResult{Chain: chain, Action: action, Payloads: entries})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("marshal keysign result: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// utxoKeysignPayload builds the keysign payload for a UTXO-chain send that
+// spends the given inputs.
+func utxoKeysignPayload(vi *vault.Info, chainName, toAddress string, amount int64, feeRate uint64, memo string, inputs []utxo.UTXO) (*v1.KeysignPayload, error) {
+	info, ok := blockchair.SupportedChains[chainName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported UTXO chain: %s", chainName)
+	}
+	chain, err := common.FromString(chainName)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported chain %q: %w", chainName, err)
+	}
+
+	coin, err := keysign.NewCoin(vi, chain, info.Ticker, int32(info.Decimals), "")
+	if err != nil {
+		return nil, err
+	}
+
+	p := keysign.NewPayload(vi, coin, toAddress, strconv.FormatInt(amount, 10))
+	// A zero fee rate leaves byte_fee empty so the signer applies its own
+	// fee rule (ZIP-317 for Zcash).
+	specific := &v1.UTXOSpecific{}
+	if feeRate > 0 {
+		specific.ByteFee = strconv.FormatUint(feeRate, 10)
+	}
+	p.BlockchainSpecific = &v1.KeysignPayload_UtxoSpecific{UtxoSpecific: specific}
+	for _, u := range inputs {
+		p.UtxoInfo = append(p.UtxoInfo, &v1.UtxoInfo{
+			Hash:   u.TxHash,
+			Amount: u.Value,
+			Index:  u.Index,
+		})
+	}
+	if memo != "" {
+		p.Memo = &memo
+	}
+	return p, nil
+}
+
+// evmKeysignPayload builds the keysign payload for an EIP-1559 transaction on
+// an EVM chain. Non-empty calldata is carried in the memo field, which is how
+// the Vultisig signers receive contract-call data.
+func evmKeysignPayload(vi *vault.Info, chainName, to, value, data string, nonce int64, gasLimit, maxFeePerGas, maxPriorityFeePerGas string) (*v1.KeysignPayload, error) {
+	chain, err := common.FromString(chainName)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported chain %q: %w", chainName, err)
+	}
+
+	coin, err := keysign.NewCoin(vi, chain, evmclient.NativeTicker(chainName), 18, "")
+	if err != nil {
+		return nil, err
+	}
+
+	p := keysign.NewPayload(vi, coin, to, value)
+	p.BlockchainSpecific = ethereumSpecific(nonce, gasLimit, maxFeePerGas, maxPriorityFeePerGas)
+	if data != "" && data != "0x" {
+		p.Memo = &data
+	}
+	return p, nil
+}
+
+// erc20KeysignPayload builds the keysign payload for an ERC-20 transfer. The
+// coin is the token, so the signer encodes transfer(to, amount) itself.
+func erc20KeysignPayload(vi *vault.Info, chainName, token, symbol string, decimals uint8, to, amount string, nonce int64, gasLimit, maxFeePerGas, maxPriorityFeePerGas string) (*v1.KeysignPayload, error) {
+	chain, err := common.FromString(chainName)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported chain %q: %w", chainName, err)
+	}
+
+	coin, err := keysign.NewCoin(vi, chain, symbol, int32(decimals), token)
+	if err != nil {
+		return nil, err
+	}
+
+	p := keysign.NewPayload(vi, coin, to, amount)
+	p.BlockchainSpecific = ethereumSpecific(nonce, gasLimit, maxFeePerGas, maxPriorityFeePerGas)
+	return p, nil
+}
+
+func ethereumSpecific(nonce int64, gasLimit, maxFeePerGas, maxPriorityFeePerGas string) *v1.KeysignPayload_EthereumSpecific {
+	return &v1.KeysignPayload_EthereumSpecific{
+		EthereumSpecific: &v1.EthereumSpecific{
+			MaxFeePerGasWei: maxFeePerGas,
+			PriorityFee:     maxPriorityFeePerGas,
+			Nonce:           nonce,
+			GasLimit:        gasLimit,
+		},
+	}
+}
+
+// tronTxExpiry is how long a TRON keysign payload stays valid after it is built.
+const tronTxExpiry = time.Hour
+
+// tronKeysignSpecific fetches the latest block and returns the TRON-specific
+// keysign fields that pin the transaction's reference block and expiry.
+func tronKeysignSpecific(ctx context.Context, tronClient *tron.Client, gasEstimation uint64) (*v1.KeysignPayload_TronSpecific, error) {
+	header, err := tronClient.GetNowBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	return &v1.KeysignPayload_TronSpecific{
+		TronSpecific: &v1.TronSpecific{
+			Timestamp:                 uint64(now),
+			Expiration:                uint64(now + tronTxExpiry.Milliseconds()),
+			BlockHeaderTimestamp:      uint64(header.Timestamp),
+			BlockHeaderNumber:         uint64(header.Number),
+			BlockHeaderVersion:        uint64(header.Version),
+			BlockHeaderTxTrieRoot:     header.TxTrieRoot,
+			BlockHeaderParentHash:     header.ParentHash,
+			BlockHeaderWitnessAddress: header.WitnessAddress,
+			GasEstimation:             gasEstimation,
+		},
+	}, nil
+}
+
+// defaultSolanaPriorityFee is the compute-unit price, in micro-lamports,
+// that the Vultisig apps attach to Solana transactions.
+const defaultSolanaPriorityFee = "1000000"
+
+// solanaKeysignSpecific fetches a recent blockhash and returns the
+// Solana-specific keysign fields.
+func solanaKeysignSpecific(ctx context.Context, solClient *solanaclient.Client) (*v1.SolanaSpecific, error) {
+	blockhash, err := solClient.GetRecentBlockhash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.SolanaSpecific{
+		RecentBlockHash: blockhash,
+		PriorityFee:     defaultSolanaPriorityFee,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/vultisig/mcp/internal/blockchair"
+	"github.com/vultisig/mcp/internal/coingecko"
+	"github.com/vultisig/mcp/internal/utxobackend"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+func newListUTXOsTool() mcp.Tool {
+	return mcp.NewTool("list_utxos",
+		mcp.WithDescription(
+			"List the spendable UTXOs of a Bitcoin, Litecoin, Dogecoin, Bitcoin-Cash, Dash or Zcash address, "+
+				"largest first, with confirmations and USD value when a price is available. "+
+				"If no address is provided, derives it from the vault's ECDSA public key. "+
+				"Accepts inline vault keys (ecdsa_public_key, eddsa_public_key, chain_code) or falls back to set_vault_info session state.",
+		),
+		mcp.WithString("chain",
+			mcp.Description("UTXO chain"),
+			mcp.Required(),
+			mcp.Enum(blockchair.ChainNames...),
+		),
+		mcp.WithString("address",
+			mcp.Description("Address on the chain. Optional if vault info is set."),
+		),
+		mcp.WithNumber("min_confirmations",
+			mcp.Description("Only list UTXOs with at least this many confirmations (default 0, which includes unconfirmed UTXOs)"),
+		),
+	)
+}
+
+type utxoEntry struct {
+	TxID           string   `json:"txid"`
+	Vout           int      `json:"vout"`
+	Value          int64    `json:"value"`
+	ValueFormatted string   `json:"value_formatted"`
+	ValueUSD       *float64 `json:"value_usd,omitempty"`
+	Confirmations  int64    `json:"confirmations"`
+	BlockHeight    int64    `json:"block_height,omitempty"`
+}
+
+type listUTXOsResult struct {
+	Chain   string      `json:"chain"`
+	Ticker  string      `json:"ticker"`
+	Address string      `json:"address"`
+	UTXOs   []utxoEntry `json:"utxos"`
+	Total   int64       `json:"total"`
+	// Truncated is set when the backend returned fewer UTXOs than the address holds.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+func handleListUTXOs(store *vault.Store, utxoBackend utxobackend.Backend, cgClient *coingecko.Client) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		minConfFloat := req.GetFloat("min_confirmations", 0)
+		if math.IsNaN(minConfFloat) || minConfFloat < 0 {
+			return mcp.NewToolResultError("min_confirmations must be zero or positive"), nil
+		}
+		minConf := int64(minConfFloat)
+
+		chain, addr, errResult := resolveUTXOAddress(ctx, req, store)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		dashboard, err := utxoBackend.GetAddressDashboard(ctx, chain, addr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("list %s UTXOs: %v", chain, err)), nil
+		}
+
+		info := blockchair.SupportedChains[chain]
+		price, priced := utxoCoinPrice(ctx, cgClient, dashboard, info)
+
+		result := listUTXOsResult{
+			Chain:     chain,
+			Ticker:    info.Ticker,
+			Address:   addr,
+			UTXOs:     []utxoEntry{},
+			Truncated: len(dashboard.UTXOs) < dashboard.Address.UnspentOutputCount,
+		}
+		for _, u := range dashboard.UTXOs {
+			confs := utxoConfirmations(dashboard.Height, u.BlockID)
+			if confs < minConf {
+				continue
+			}
+			e := utxoEntry{
+				TxID:           u.TransactionHash,
+				Vout:           u.Index,
+				Value:          u.Value,
+				ValueFormatted: blockchair.FormatSatoshis(u.Value, info.Decimals),
+				Confirmations:  confs,
+			}
+			if priced {
+				usd := roundUSD(float64(u.Value) / math.Pow10(info.Decimals) * price)
+				e.ValueUSD = &usd
+			}
+			if u.BlockID > 0 {
+				e.BlockHeight = u.BlockID
+			}
+			result.UTXOs = append(result.UTXOs, e)
+			result.Total += u.Value
+		}
+		slices.SortStableFunc(result.UTXOs, func(a, b utxoEntry) int {
+			return cmp.Compare(b.Value, a.Value)
+		})
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal utxo list result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// utxoConfirmations counts the confirmations of an output mined in blockID
+// with the chain tip at height. Unconfirmed outputs have zero; a mined output
+// has at least one even when the tip height is unknown.
+func utxoConfirmations(height, blockID int64) int64 {
+	if blockID <= 0 {
+		return 0
+	}
+	return max(height-blockID+1, 1)
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestListUTXOs(t *testing.T) {
+	store := setupBTCVault(t)
+	handler := handleListUTXOs(store, mockBlockchairDashboard(t), nil)
+
+	res, err := handler(context.Background(), callToolReq("list_utxos", map[string]any{
+		"chain": "Bitcoin",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result listUTXOsResult
+	err = json.Unmarshal([]byte(resultText(t, res)), &result)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(result.UTXOs) != 3 || result.Total != 80_000_000 || result.Truncated {
+		t.Fatalf("utxos = %d, total = %d, truncated = %v", len(result.UTXOs), result.Total, result.Truncated)
+	}
+	want := []struct {
+		value int64
+		confs int64
+		usd   float64
+	}{
+		{50_000_000, 10, 25_000},
+		{20_000_000, 101, 10_000},
+		{10_000_000, 0, 5_000},
+	}
+	for i, w := range want {
+		u := result.UTXOs[i]
+		if u.Value != w.value || u.Confirmations != w.confs || u.ValueUSD == nil || *u.ValueUSD != w.usd {
+			t.Errorf("utxo %d = %+v, want value %d, %d confirmations, $%v", i, u, w.value, w.confs, w.usd)
+		}
+	}
+}
+
+func TestListUTXOs_MinConfirmations(t *testing.T) {
+	store := setupBTCVault(t)
+	handler := handleListUTXOs(store, mockBlockchairDashboard(t), nil)
+
+	res, err := handler(context.Background(), callToolReq("list_utxos", map[string]any{
+		"chain":             "Bitcoin",
+		"min_confirmations": float64(6),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result listUTXOsResult
+	err = json.Unmarshal([]byte(resultText(t, res)), &result)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(result.UTXOs) != 2 || result.Total != 70_000_000 {
+		t.Errorf("utxos = %d, total = %d, want the two confirmed coins", len(result.UTXOs), result.Total)
+	}
+}
+
+func TestListUTXOs_Electrum(t *testing.T) {
+	store := setupBTCVault(t)
+
+	t.Run("coingecko price", func(t *testing.T) {
+		handler := handleListUTXOs(store, mockElectrumDashboard(t), mockCoinGeckoPrice(t, "bitcoin", 50_000))
+		res, err := handler(context.Background(), callToolReq("list_utxos", map[string]any{
+			"chain": "Bitcoin",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var result listUTXOsResult
+		err = json.Unmarshal([]byte(resultText(t, res)), &result)
+		if err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if len(result.UTXOs) != 3 || result.UTXOs[0].Confirmations != 10 || result.UTXOs[2].Confirmations != 0 {
+			t.Fatalf("utxos = %+v", result.UTXOs)
+		}
+		for i, usd := range []float64{25_000, 10_000, 5_000} {
+			if u := result.UTXOs[i]; u.ValueUSD == nil || *u.ValueUSD != usd {
+				t.Errorf("utxo %d value_usd = %v, want %v", i, u.ValueUSD, usd)
+			}
+		}
+	})
+
+	t.Run("no price", func(t *testing.T) {
+		handler := handleListUTXOs(store, mockElectrumDashboard(t), nil)
+		res, err := handler(context.Background(), callToolReq("list_utxos", map[string]any{
+			"chain": "Bitcoin",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		utxos := decodeResult(t, res)["utxos"].([]any)
+		for i, u := range utxos {
+			if _, ok := u.(map[string]any)["value_usd"]; ok {
+				t.Errorf("utxo %d = %v, want value_usd omitted", i, u)
+			}
+		}
+		if len(utxos) != 3 {
+			t.Errorf("utxos = %d, want 3", len(utxos))
+		}
+	})
+}
@@ -1,41 +1,19 @@
 package tools
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
-
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
-	"github.com/vultisig/mcp/internal/thorchain"
+	"github.com/vultisig/mcp/internal/coingecko"
+	"github.com/vultisig/mcp/internal/feerate"
+	"github.com/vultisig/mcp/internal/vault"
 )
 
 func newLTCFeeRateTool() mcp.Tool {
-	return mcp.NewTool("ltc_fee_rate",
-		mcp.WithDescription("Get the recommended Litecoin fee rate in sat/vB from THORChain inbound addresses."),
-	)
+	return newUTXOFeeRateTool("ltc_fee_rate", "Litecoin",
+		"Get recommended Litecoin fee rates in sat/vB from THORChain inbound addresses, or from a configured litecoinspace.org-style API with THORChain as fallback.")
 }
 
-func handleLTCFeeRate(tcClient *thorchain.Client) server.ToolHandlerFunc {
-	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		rate, err := tcClient.SatsPerByte(ctx, "LTC")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get LTC fee rate: %v", err)), nil
-		}
-
-		result := feeRateResult{
-			Chain:       "Litecoin",
-			Ticker:      "LTC",
-			FeeRate:     rate,
-			FeeRateUnit: "sat/vB",
-		}
-
-		data, err := json.Marshal(result)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("marshal fee rate result: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(data)), nil
-	}
+func handleLTCFeeRate(store *vault.Store, fees feerate.Source, cgClient *coingecko.Client) server.ToolHandlerFunc {
+	return handleUTXOFeeRate("Litecoin", store, fees, cgClient)
 }
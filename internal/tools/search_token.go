@@ -10,6 +10,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/vultisig/mcp/internal/coingecko"
+	evmclient "github.com/vultisig/mcp/internal/evm"
 	"github.com/vultisig/mcp/internal/types"
 )
 
@@ -137,6 +138,9 @@ func handleSearchToken(cgClient *coingecko.Client) server.ToolHandlerFunc {
 					continue
 				}
 				chain := platformToChain[platform]
+				if chain == "" {
+					chain, _ = evmclient.ChainByCoinGeckoPlatform(platform)
+				}
 				if chain == "" {
 					chain = platform // use raw platform ID if no mapping
 				}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/vultisig/recipes/sdk/swap"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+)
+
+// swapPermitGasOverhead covers the permit call the router makes before the
+// swap.
+const swapPermitGasOverhead = 80_000
+
+// Universal Router command bytes: the low bits select the command, the top
+// bit allows it to revert.
+const (
+	urPermit2PermitCommand = 0x0a
+	urCommandTypeMask      = 0x3f
+)
+
+const swapPermitABIJSON = `[
+{"type":"function","name":"permitAndCall","stateMutability":"payable","inputs":[{"name":"permit","type":"bytes"},{"name":"action","type":"bytes"}],"outputs":[]},
+{"type":"function","name":"execute","stateMutability":"payable","inputs":[{"name":"commands","type":"bytes"},{"name":"inputs","type":"bytes[]"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+{"type":"function","name":"execute","stateMutability":"payable","inputs":[{"name":"commands","type":"bytes"},{"name":"inputs","type":"bytes[]"}],"outputs":[]}
+]`
+
+var swapPermitABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(swapPermitABIJSON))
+	if err != nil {
+		panic(fmt.Sprintf("tools: parse swap permit ABI: %v", err))
+	}
+	return parsed
+}()
+
+// applySwapPermit rewrites an ERC-20 swap to consume a signed permit instead
+// of a separate approve transaction. 1inch swaps are wrapped in the router's
+// permitAndCall with an EIP-2612 permit; Uniswap Universal Router swaps get
+// a leading PERMIT2_PERMIT command with a Permit2 permit. On success the
+// bundle no longer needs approval.
+func applySwapPermit(bundle *swap.SwapTxBundle, params swap.SwapParams, permit *evmclient.Permit, sig []byte) error {
+	if !bundle.NeedsApproval {
+		return fmt.Errorf("%s swap from %s needs no approval; drop the permit", bundle.Provider, params.FromSymbol)
+	}
+	chainID, ok := evmclient.ChainIDByName(params.FromChain)
+	if !ok {
+		return fmt.Errorf("permits are only supported on EVM chains, not %s", params.FromChain)
+	}
+	router := common.HexToAddress(bundle.SwapTx.To)
+	switch {
+	case permit.ChainID.Cmp(chainID) != 0:
+		return fmt.Errorf("permit is for chain %s, not %s", permit.ChainID, params.FromChain)
+	case !common.IsHexAddress(params.FromAddress) || permit.Token != common.HexToAddress(params.FromAddress):
+		return fmt.Errorf("permit is for token %s, not %s", permit.Token.Hex(), params.FromAddress)
+	case !common.IsHexAddress(params.Sender) || permit.Owner != common.HexToAddress(params.Sender):
+		return fmt.Errorf("permit owner %s is not the sender %s", permit.Owner.Hex(), params.Sender)
+	case permit.Spender != router:
+		return fmt.Errorf("permit spender %s is not the %s router %s; rebuild the permit with spender %s",
+			permit.Spender.Hex(), bundle.Provider, router.Hex(), router.Hex())
+	case permit.Amount.Cmp(params.Amount) < 0:
+		return fmt.Errorf("permit amount %s is below the swap amount %s", permit.Amount, params.Amount)
+	}
+
+	var data []byte
+	var err error
+	switch bundle.Provider {
+	case "1inch":
+		data, err = oneInchPermitAndCall(permit, sig, bundle.SwapTx.Data)
+	case "Uniswap":
+		data, err = universalRouterWithPermit(permit, sig, bundle.SwapTx.Data)
+	default:
+		return fmt.Errorf("%s swaps do not accept permits; use the approval transaction", bundle.Provider)
+	}
+	if err != nil {
+		return err
+	}
+
+	bundle.SwapTx.Data = data
+	if bundle.SwapTx.GasLimit > 0 {
+		bundle.SwapTx.GasLimit += swapPermitGasOverhead
+	}
+	bundle.NeedsApproval = false
+	bundle.ApprovalTx = nil
+	return nil
+}
+
+// oneInchPermitAndCall wraps 1inch router calldata in permitAndCall, whose
+// permit argument is the token address followed by the abi-encoded
+// EIP-2612 permit arguments.
+func oneInchPermitAndCall(permit *evmclient.Permit, sig, action []byte) ([]byte, error) {
+	if permit.Kind != evmclient.PermitKindEIP2612 {
+		return nil, fmt.Errorf("1inch swaps accept only EIP-2612 permits, got %s", permit.Kind)
+	}
+	args, err := permit.PackEIP2612Call(sig)
+	if err != nil {
+		return nil, err
+	}
+	packed := append(permit.Token.Bytes(), args...)
+	return swapPermitABI.Pack("permitAndCall", packed, action)
+}
+
+// universalRouterWithPermit prepends a PERMIT2_PERMIT command to Universal
+// Router execute calldata.
+func universalRouterWithPermit(permit *evmclient.Permit, sig, calldata []byte) ([]byte, error) {
+	if permit.Kind != evmclient.PermitKindPermit2 {
+		return nil, fmt.Errorf("Uniswap swaps accept only Permit2 permits, got %s", permit.Kind)
+	}
+	if len(calldata) < 4 {
+		return nil, fmt.Errorf("swap calldata too short")
+	}
+	method, err := swapPermitABI.MethodById(calldata[:4])
+	if err != nil || method.RawName != "execute" {
+		return nil, fmt.Errorf("swap calldata is not a Universal Router execute call")
+	}
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, fmt.Errorf("decode execute: %w", err)
+	}
+	commands := args[0].([]byte)
+	inputs := args[1].([][]byte)
+	for _, c := range commands {
+		if c&urCommandTypeMask == urPermit2PermitCommand {
+			return nil, fmt.Errorf("swap already includes a Permit2 permit")
+		}
+	}
+
+	permitInput, err := permit.PackPermit2Single(sig)
+	if err != nil {
+		return nil, err
+	}
+	args[0] = append([]byte{urPermit2PermitCommand}, commands...)
+	args[1] = append([][]byte{permitInput}, inputs...)
+
+	packed, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("encode execute: %w", err)
+	}
+	return append(append([]byte{}, method.ID...), packed...), nil
+}
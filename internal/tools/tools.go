@@ -7,10 +7,11 @@ import (
 
 	"github.com/vultisig/recipes/sdk/swap"
 
-	"github.com/vultisig/mcp/internal/blockchair"
+	"github.com/vultisig/mcp/internal/abiregistry"
 	"github.com/vultisig/mcp/internal/coingecko"
 	"github.com/vultisig/mcp/internal/defillama"
 	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/feerate"
 	"github.com/vultisig/mcp/internal/fourbyte"
 	gaiaclient "github.com/vultisig/mcp/internal/gaia"
 	"github.com/vultisig/mcp/internal/jupiter"
@@ -22,58 +23,87 @@ import (
 	"github.com/vultisig/mcp/internal/toolmeta"
 	pmtools "github.com/vultisig/mcp/internal/tools/polymarket"
 	tronclient "github.com/vultisig/mcp/internal/tron"
+	"github.com/vultisig/mcp/internal/utxobackend"
 	"github.com/vultisig/mcp/internal/vault"
 	"github.com/vultisig/mcp/internal/verifier"
 	xrpclient "github.com/vultisig/mcp/internal/xrp"
 )
 
-func RegisterAll(s *server.MCPServer, store *vault.Store, pool *evmclient.Pool, cgClient *coingecko.Client, bcClient *blockchair.Client, swapSvc *swap.Service, tcClient *thorchain.Client, mcClient *mayachain.Client, solClient *solanaclient.Client, jupClient *jupiter.Client, xrpClient *xrpclient.Client, tronClient *tronclient.Client, gaiaClient *gaiaclient.Client, pfClient *pumpfunclient.Client, fbClient *fourbyte.Client, vcClient *verifier.Client, dlClient *defillama.Client) error {
+func RegisterAll(s *server.MCPServer, store *vault.Store, pool *evmclient.Pool, cgClient *coingecko.Client, utxoBackend utxobackend.Backend, feeSource feerate.Source, swapSvc *swap.Service, tcClient *thorchain.Client, mcClient *mayachain.Client, solClient *solanaclient.Client, jupClient *jupiter.Client, xrpClient *xrpclient.Client, tronClient *tronclient.Client, gaiaClient *gaiaclient.Client, pfClient *pumpfunclient.Client, fbClient *fourbyte.Client, abiRegistry *abiregistry.Registry, vcClient *verifier.Client, dlClient *defillama.Client) error {
+	reverts := newRevertDecoder(abiRegistry, fbClient)
+
 	// Utility tools (always-on)
 	toolmeta.Register(s, newSetVaultInfoTool(), handleSetVaultInfo(store), "utility")
 	toolmeta.Register(s, newGetAddressTool(), handleGetAddress(store), "utility")
 	toolmeta.Register(s, newSearchTokenTool(), handleSearchToken(cgClient), "utility")
 	toolmeta.Register(s, newGetPriceTool(), handleGetPrice(cgClient), "utility")
-	toolmeta.Register(s, newGetTxStatusTool(), handleGetTxStatus(pool, bcClient, solClient, xrpClient, tronClient, gaiaClient), "utility")
+	toolmeta.Register(s, newGetTxStatusTool(), handleGetTxStatus(store, pool, reverts, utxoBackend, solClient, xrpClient, tronClient, gaiaClient), "utility")
 	toolmeta.Register(s, newConvertAmountTool(), handleConvertAmount(), "utility")
 
 	// Swap
-	toolmeta.Register(s, newBuildSwapTxTool(), handleBuildSwapTx(swapSvc), "swap")
+	toolmeta.Register(s, newBuildSwapTxTool(), handleBuildSwapTx(store, swapSvc), "swap")
 
 	// EVM tools
 	toolmeta.Register(s, newEVMGetBalanceTool(), handleEVMGetBalance(store, pool), "balance", "evm")
 	toolmeta.Register(s, newEVMGetTokenBalanceTool(), handleEVMGetTokenBalance(store, pool), "balance", "evm")
+	toolmeta.Register(s, newEVMGetNFTsTool(), handleEVMGetNFTs(store, pool), "balance", "evm")
 	toolmeta.Register(s, newEVMCheckAllowanceTool(), handleEVMCheckAllowance(store, pool), "contract", "evm")
-	toolmeta.Register(s, newEVMCallTool(), handleEVMCall(pool), "contract", "evm")
-	toolmeta.Register(s, newEVMTxInfoTool(), handleEVMTxInfo(store, pool), "contract", "evm", "fee")
-	toolmeta.Register(s, newBuildEVMTxTool(), handleBuildEVMTx(), "send", "evm")
+	toolmeta.Register(s, newEVMListApprovalsTool(), handleEVMListApprovals(store, pool), "contract", "evm")
+	toolmeta.Register(s, newEVMCallTool(), handleEVMCall(pool, abiRegistry, reverts), "contract", "evm")
+	toolmeta.Register(s, newEVMReadContractTool(), handleEVMReadContract(pool, reverts), "contract", "evm")
+	toolmeta.Register(s, newEVMMulticallTool(), handleEVMMulticall(pool), "contract", "evm")
+	toolmeta.Register(s, newEVMGetLogsTool(), handleEVMGetLogs(pool), "contract", "evm")
+	toolmeta.Register(s, newEVMTxInfoTool(), handleEVMTxInfo(store, pool, reverts), "contract", "evm", "fee")
+	toolmeta.Register(s, newEVMGasOracleTool(), handleEVMGasOracle(pool, cgClient), "fee", "evm")
+	toolmeta.Register(s, newBuildEVMTxTool(), handleBuildEVMTx(store, pool), "send", "evm")
+	toolmeta.Register(s, newBuildERC20TransferTool(), handleBuildERC20Transfer(store, pool), "send", "evm")
+	toolmeta.Register(s, newBuildEVMRevokeTool(), handleBuildEVMRevoke(store, pool), "send", "evm")
+	toolmeta.Register(s, newBuildEVMSpeedupTool(), handleBuildEVMReplacement(store, pool, replaceSpeedup), "send", "evm", "fee")
+	toolmeta.Register(s, newBuildEVMCancelTool(), handleBuildEVMReplacement(store, pool, replaceCancel), "send", "evm", "fee")
+	toolmeta.Register(s, newBuildNFTTransferTool(), handleBuildNFTTransfer(store, pool), "send", "evm")
+	toolmeta.Register(s, newBuildTypedDataTool(), handleBuildTypedData(store), "contract", "evm")
+	toolmeta.Register(s, newVerifyTypedDataSignatureTool(), handleVerifyTypedDataSignature(store), "contract", "evm")
+	toolmeta.Register(s, newEVMCheckPermitSupportTool(), handleEVMCheckPermitSupport(store, pool), "contract", "evm")
+	toolmeta.Register(s, newBuildPermitTool(), handleBuildPermit(store, pool), "contract", "evm", "swap", "aave")
 
 	// ABI tools
 	toolmeta.Register(s, newABIEncodeTool(), handleABIEncode(), "contract")
-	toolmeta.Register(s, newABIDecodeTool(), handleABIDecode(), "contract")
+	toolmeta.Register(s, newABIDecodeTool(), handleABIDecode(pool, abiRegistry), "contract")
 	toolmeta.Register(s, newResolveSelectorTool(), handleResolveSelector(fbClient), "contract")
+	toolmeta.Register(s, newEVMDecodeCalldataTool(), handleEVMDecodeCalldata(pool, abiRegistry, fbClient), "contract", "evm")
+	toolmeta.Register(s, newEVMImportABITool(), handleEVMImportABI(abiRegistry), "contract", "evm")
 
 	// Bitcoin
-	toolmeta.Register(s, newBTCFeeRateTool(), handleBTCFeeRate(tcClient), "fee", "bitcoin")
-	toolmeta.Register(s, newBuildBTCSendTool(), handleBuildBTCSend(store, bcClient), "send", "bitcoin")
+	toolmeta.Register(s, newBTCFeeRateTool(), handleBTCFeeRate(store, feeSource, cgClient), "fee", "bitcoin")
+	toolmeta.Register(s, newBuildBTCSendTool(), handleBuildBTCSend(store, utxoBackend), "send", "bitcoin")
 
 	// Litecoin
-	toolmeta.Register(s, newLTCFeeRateTool(), handleLTCFeeRate(tcClient), "fee", "litecoin")
-	toolmeta.Register(s, newBuildLTCSendTool(), handleBuildLTCSend(store, bcClient), "send", "litecoin")
+	toolmeta.Register(s, newLTCFeeRateTool(), handleLTCFeeRate(store, feeSource, cgClient), "fee", "litecoin")
+	toolmeta.Register(s, newBuildLTCSendTool(), handleBuildLTCSend(store, utxoBackend), "send", "litecoin")
 
 	// Dogecoin
-	toolmeta.Register(s, newDOGEFeeRateTool(), handleDOGEFeeRate(tcClient), "fee", "dogecoin")
-	toolmeta.Register(s, newBuildDOGESendTool(), handleBuildDOGESend(store, bcClient), "send", "dogecoin")
+	toolmeta.Register(s, newDOGEFeeRateTool(), handleDOGEFeeRate(store, feeSource, cgClient), "fee", "dogecoin")
+	toolmeta.Register(s, newBuildDOGESendTool(), handleBuildDOGESend(store, utxoBackend), "send", "dogecoin")
 
 	// Bitcoin Cash
-	toolmeta.Register(s, newBCHFeeRateTool(), handleBCHFeeRate(tcClient), "fee", "bitcoincash")
-	toolmeta.Register(s, newBuildBCHSendTool(), handleBuildBCHSend(store, bcClient), "send", "bitcoincash")
+	toolmeta.Register(s, newBCHFeeRateTool(), handleBCHFeeRate(store, feeSource, cgClient), "fee", "bitcoincash")
+	toolmeta.Register(s, newBuildBCHSendTool(), handleBuildBCHSend(store, utxoBackend), "send", "bitcoincash")
 
 	// Dash
 	toolmeta.Register(s, newDASHFeeRateTool(), handleDASHFeeRate(mcClient), "fee", "dash")
-	toolmeta.Register(s, newBuildDASHSendTool(), handleBuildDASHSend(store, bcClient), "send", "dash")
+	toolmeta.Register(s, newBuildDASHSendTool(), handleBuildDASHSend(store, utxoBackend), "send", "dash")
 
 	// Zcash
-	toolmeta.Register(s, newBuildZECSendTool(), handleBuildZECSend(store, bcClient), "send", "zcash")
+	toolmeta.Register(s, newBuildZECSendTool(), handleBuildZECSend(store, utxoBackend), "send", "zcash")
+
+	// UTXO chains
+	toolmeta.Register(s, newGetUTXOBalanceTool(), handleGetUTXOBalance(store, utxoBackend, cgClient), "balance", "bitcoin", "litecoin", "dogecoin", "bitcoincash", "dash", "zcash")
+	toolmeta.Register(s, newListUTXOsTool(), handleListUTXOs(store, utxoBackend, cgClient), "balance", "bitcoin", "litecoin", "dogecoin", "bitcoincash", "dash", "zcash")
+	toolmeta.Register(s, newInspectPSBTTool(), handleInspectPSBT(store), "utility", "bitcoin", "litecoin", "dogecoin", "bitcoincash", "dash")
+
+	// UTXO maintenance
+	toolmeta.Register(s, newBuildUTXOFeeBumpTool(), handleBuildUTXOFeeBump(store, utxoBackend), "send", "bitcoin", "litecoin", "dogecoin", "bitcoincash", "dash")
+	toolmeta.Register(s, newBuildUTXOConsolidateTool(), handleBuildUTXOConsolidate(store, utxoBackend, feeSource), "send", "bitcoin", "litecoin", "dogecoin", "bitcoincash", "dash", "zcash")
 
 	// MayaChain
 	toolmeta.Register(s, newMayaFeeRateTool(), handleMayaFeeRate(mcClient), "fee", "mayachain")
@@ -97,7 +127,7 @@ func RegisterAll(s *server.MCPServer, store *vault.Store, pool *evmclient.Pool,
 	toolmeta.Register(s, newGetTRXBalanceTool(), handleGetTRXBalance(store, tronClient), "balance", "tron")
 	toolmeta.Register(s, newGetTRC20TokenBalanceTool(), handleGetTRC20TokenBalance(store, tronClient), "balance", "tron")
 	toolmeta.Register(s, newGetTronAccountResourcesTool(), handleGetTronAccountResources(store, tronClient), "tron")
-	toolmeta.Register(s, newBuildTRXSendTool(), handleBuildTRXSend(store), "send", "tron")
+	toolmeta.Register(s, newBuildTRXSendTool(), handleBuildTRXSend(store, tronClient), "send", "tron")
 	toolmeta.Register(s, newBuildTRC20TransferTool(), handleBuildTRC20Transfer(store, tronClient), "send", "tron")
 
 	// Gaia (Cosmos Hub)
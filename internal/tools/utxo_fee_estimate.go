@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/vultisig/vultisig-go/address"
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/mcp/internal/blockchair"
+	"github.com/vultisig/mcp/internal/coingecko"
+	"github.com/vultisig/mcp/internal/feerate"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/utxo"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+// Default transaction shape quoted by the fee rate tools: one input paying a
+// recipient plus change.
+const (
+	defaultFeeEstimateInputs  = 1
+	defaultFeeEstimateOutputs = 2
+	maxFeeEstimateCount       = 1000
+)
+
+// defaultInputTypes is the script type of vault addresses on each chain,
+// used when neither an address nor vault info is available.
+var defaultInputTypes = map[string]utxo.InputType{
+	"Bitcoin":      utxo.InputP2WPKH,
+	"Litecoin":     utxo.InputP2WPKH,
+	"Dogecoin":     utxo.InputP2PKH,
+	"Bitcoin-Cash": utxo.InputP2PKH,
+}
+
+// newUTXOFeeRateTool creates a priority-tiered fee rate tool for chain.
+func newUTXOFeeRateTool(name, chain, description string) mcp.Tool {
+	return mcp.NewTool(name,
+		mcp.WithDescription(description+
+			" Returns slow, normal and fast tiers, each with the total fee in native units and USD for a transaction of the given shape; "+
+			"tiers scaled from a single quoted rate rather than quoted themselves are marked derived. "+
+			"The input script type comes from the address, the vault (inline keys or set_vault_info), or the chain's vault default."),
+		mcp.WithNumber("num_inputs",
+			mcp.Description(fmt.Sprintf("Number of inputs to quote for (default %d)", defaultFeeEstimateInputs)),
+		),
+		mcp.WithNumber("num_outputs",
+			mcp.Description(fmt.Sprintf("Number of outputs to quote for, including change (default %d)", defaultFeeEstimateOutputs)),
+		),
+		mcp.WithString("address",
+			mcp.Description(fmt.Sprintf("Sending %s address, used for its script type. Falls back to the vault-derived address if omitted.", chain)),
+		),
+	)
+}
+
+type feeTierJSON struct {
+	Priority     string  `json:"priority"`
+	FeeRate      uint64  `json:"fee_rate"`
+	Fee          int64   `json:"fee"`
+	FeeFormatted string  `json:"fee_formatted"`
+	FeeUSD       float64 `json:"fee_usd,omitempty"`
+	// Derived marks a rate scaled from the normal tier rather than quoted.
+	Derived bool `json:"derived,omitempty"`
+}
+
+type utxoFeeRateResult struct {
+	feeRateResult
+	Source     string        `json:"source"`
+	Address    string        `json:"address,omitempty"`
+	ScriptType string        `json:"script_type"`
+	NumInputs  int           `json:"num_inputs"`
+	NumOutputs int           `json:"num_outputs"`
+	VSize      int           `json:"vsize"`
+	PriceUSD   float64       `json:"price_usd,omitempty"`
+	Tiers      []feeTierJSON `json:"tiers"`
+}
+
+// handleUTXOFeeRate quotes fee tiers for chain. fee_rate carries the normal
+// tier. USD values are omitted when the price is unavailable.
+func handleUTXOFeeRate(chain string, store *vault.Store, fees feerate.Source, cgClient *coingecko.Client) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		info := blockchair.SupportedChains[chain]
+
+		numInputs := int(req.GetFloat("num_inputs", defaultFeeEstimateInputs))
+		numOutputs := int(req.GetFloat("num_outputs", defaultFeeEstimateOutputs))
+		if numInputs < 1 || numInputs > maxFeeEstimateCount || numOutputs < 1 || numOutputs > maxFeeEstimateCount {
+			return mcp.NewToolResultError(fmt.Sprintf("num_inputs and num_outputs must be between 1 and %d", maxFeeEstimateCount)), nil
+		}
+
+		addr, script, err := feeEstimateScript(ctx, req, store, chain)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		inputType := defaultInputTypes[chain]
+		if script != nil {
+			inputType, err = utxo.InputTypeOf(script)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("address %s: %v", addr, err)), nil
+			}
+		} else {
+			script = defaultScript(inputType)
+		}
+
+		tiers, err := fees.Tiers(ctx, chain)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get %s fee rate: %v", info.Ticker, err)), nil
+		}
+
+		outputs := make([]utxo.Output, numOutputs)
+		for i := range outputs {
+			outputs[i] = utxo.Output{PkScript: script}
+		}
+		shape := utxo.ShapeOf(inputType, numInputs, outputs, 0)
+
+		var price float64
+		if cgClient != nil {
+			if pd, err := cgClient.GetSimplePrice(ctx, nativeCoinGeckoID[info.Ticker]); err == nil {
+				price = pd.USD
+			}
+		}
+
+		result := utxoFeeRateResult{
+			feeRateResult: feeRateResult{
+				Chain:       chain,
+				Ticker:      info.Ticker,
+				FeeRate:     tiers.Normal,
+				FeeRateUnit: "sat/vB",
+			},
+			Source:     tiers.Source,
+			Address:    addr,
+			ScriptType: inputType.String(),
+			NumInputs:  numInputs,
+			NumOutputs: numOutputs,
+			VSize:      shape.VSize,
+			PriceUSD:   price,
+		}
+		for _, tier := range []struct {
+			priority string
+			rate     uint64
+		}{
+			{"slow", tiers.Slow},
+			{"normal", tiers.Normal},
+			{"fast", tiers.Fast},
+		} {
+			fee := utxo.RateFee(tier.rate)(shape)
+			result.Tiers = append(result.Tiers, feeTierJSON{
+				Priority:     tier.priority,
+				FeeRate:      tier.rate,
+				Fee:          fee,
+				FeeFormatted: blockchair.FormatSatoshis(fee, info.Decimals),
+				FeeUSD:       feeUSD(fee, info.Decimals, price),
+				Derived:      tiers.Derived && tier.priority != "normal",
+			})
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal fee rate result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// feeEstimateScript returns the sending address and its script from the
+// address parameter or the vault, or empty values if neither is available.
+func feeEstimateScript(ctx context.Context, req mcp.CallToolRequest, store *vault.Store, chain string) (string, []byte, error) {
+	addr := req.GetString("address", "")
+	if addr == "" {
+		v := resolve.ResolveVault(ctx, req, store)
+		if v == nil {
+			return "", nil, nil
+		}
+		c, err := common.FromString(chain)
+		if err != nil {
+			return "", nil, err
+		}
+		addr, _, _, err = address.GetAddress(v.ECDSAPublicKey, v.ChainCode, c)
+		if err != nil {
+			return "", nil, fmt.Errorf("derive %s address: %w", chain, err)
+		}
+	}
+	script, err := utxo.AddressScript(chain, addr)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid %s address: %w", chain, err)
+	}
+	return addr, script, nil
+}
+
+// defaultScript returns a placeholder output script of the given type, for
+// sizing outputs when no address is known.
+func defaultScript(t utxo.InputType) []byte {
+	if t == utxo.InputP2WPKH {
+		return make([]byte, 22)
+	}
+	return make([]byte, 25)
+}
+
+// feeUSD converts a fee in base units to USD at price per coin. It keeps
+// four decimals since fees on cheap chains are fractions of a cent.
+func feeUSD(fee int64, decimals int, price float64) float64 {
+	usd := float64(fee) / math.Pow10(decimals) * price
+	return math.Round(usd*10_000) / 10_000
+}
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/vultisig/mcp/internal/coingecko"
+	"github.com/vultisig/mcp/internal/feerate"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+type stubFeeSource struct {
+	tiers feerate.Tiers
+}
+
+func (s stubFeeSource) Tiers(context.Context, string) (*feerate.Tiers, error) {
+	tiers := s.tiers
+	return &tiers, nil
+}
+
+func mockCoinGeckoPrice(t *testing.T, id string, usd float64) *coingecko.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ids") != id {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"` + id + `":{"usd":` + strconv.FormatFloat(usd, 'f', -1, 64) + `}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return coingecko.NewClientWithBaseURL(srv.URL)
+}
+
+func TestUTXOFeeRate_Tiers(t *testing.T) {
+	fees := stubFeeSource{feerate.Tiers{Slow: 5, Normal: 10, Fast: 20, Source: "mempool"}}
+	handler := handleBTCFeeRate(setupBTCVault(t), fees, mockCoinGeckoPrice(t, "bitcoin", 100_000))
+
+	res, err := handler(context.Background(), callToolReq("btc_fee_rate", map[string]any{
+		"num_inputs":  float64(2),
+		"num_outputs": float64(2),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+
+	// Two P2WPKH inputs and two P2WPKH outputs.
+	if result["vsize"] != float64(209) || result["script_type"] != "p2wpkh" {
+		t.Errorf("vsize/script_type = %v/%v, want 209/p2wpkh", result["vsize"], result["script_type"])
+	}
+	if result["fee_rate"] != float64(10) || result["source"] != "mempool" || result["price_usd"] != float64(100_000) {
+		t.Errorf("fee_rate/source/price = %v/%v/%v", result["fee_rate"], result["source"], result["price_usd"])
+	}
+	if result["address"] != deriveBTCAddress(t, setupBTCVault(t)) {
+		t.Errorf("address = %v, want the vault address", result["address"])
+	}
+
+	tiers := result["tiers"].([]any)
+	want := []struct {
+		priority string
+		fee      float64
+		usd      float64
+	}{
+		{"slow", 1045, 1.045},
+		{"normal", 2090, 2.09},
+		{"fast", 4180, 4.18},
+	}
+	if len(tiers) != len(want) {
+		t.Fatalf("tiers = %v", tiers)
+	}
+	for i, w := range want {
+		tier := tiers[i].(map[string]any)
+		if tier["priority"] != w.priority || tier["fee"] != w.fee || tier["fee_usd"] != w.usd {
+			t.Errorf("tier %d = %v, want %s fee %v ($%v)", i, tier, w.priority, w.fee, w.usd)
+		}
+	}
+}
+
+func TestUTXOFeeRate_DefaultScriptWithoutVault(t *testing.T) {
+	fees := stubFeeSource{feerate.Tiers{Slow: 1000, Normal: 2000, Fast: 3000, Source: "thorchain", Derived: true}}
+	handler := handleDOGEFeeRate(vault.NewStore(), fees, nil)
+
+	res, err := handler(context.Background(), callToolReq("doge_fee_rate", map[string]any{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+
+	// One P2PKH input and two P2PKH outputs.
+	if result["vsize"] != float64(226) || result["script_type"] != "p2pkh" {
+		t.Errorf("vsize/script_type = %v/%v, want 226/p2pkh", result["vsize"], result["script_type"])
+	}
+	if _, ok := result["price_usd"]; ok {
+		t.Error("price_usd set without a price source")
+	}
+	tiers := result["tiers"].([]any)
+	normal := tiers[1].(map[string]any)
+	if normal["fee"] != float64(452_000) || normal["fee_formatted"] != "0.00452000" || normal["derived"] != nil {
+		t.Errorf("normal tier = %v", normal)
+	}
+	// THORChain's slow and fast tiers are scaled from its single rate.
+	if tiers[0].(map[string]any)["derived"] != true || tiers[2].(map[string]any)["derived"] != true {
+		t.Errorf("tiers = %v, want slow and fast marked derived", tiers)
+	}
+}
+
+func TestUTXOFeeRate_InvalidParams(t *testing.T) {
+	fees := stubFeeSource{feerate.Tiers{Slow: 1, Normal: 1, Fast: 1}}
+	handler := handleLTCFeeRate(vault.NewStore(), fees, nil)
+
+	for name, args := range map[string]map[string]any{
+		"zero inputs":   {"num_inputs": float64(0)},
+		"wrong address": {"address": "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L"},
+	} {
+		res, err := handler(context.Background(), callToolReq("ltc_fee_rate", args))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !res.IsError {
+			t.Errorf("%s: expected tool error", name)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/mark3labs/mcp-go/mcp"
+	btcsdk "github.com/vultisig/recipes/sdk/btc"
+	zcashsdk "github.com/vultisig/recipes/sdk/zcash"
+
+	"github.com/vultisig/mcp/internal/types"
+	"github.com/vultisig/mcp/internal/utxo"
+	"github.com/vultisig/mcp/internal/utxobackend"
+)
+
+// withCoinSelection adds the coin_selection parameter shared by the UTXO send tools.
+func withCoinSelection() mcp.ToolOption {
+	return mcp.WithString("coin_selection",
+		mcp.Description(
+			"Coin selection strategy. \"bnb\" (default) looks for an input set that needs no change output and falls back to largest-first. "+
+				"\"largest_first\" spends the biggest UTXOs first. "+
+				"\"privacy\" avoids merging UTXOs when one suffices and orders inputs and outputs per BIP69.",
+		),
+		mcp.Enum(utxo.Strategies...),
+		mcp.DefaultString(string(utxo.BranchAndBound)),
+	)
+}
+
+// withRecipients adds the recipients parameter for batched sends.
+func withRecipients() mcp.ToolOption {
+	return mcp.WithArray("recipients",
+		mcp.Description(
+			"Pay several recipients in one transaction instead of to_address/amount. "+
+				"Each entry has an address and an amount in base units (decimal string).",
+		),
+		mcp.Items(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"address": map[string]any{"type": "string"},
+				"amount":  map[string]any{"type": "string"},
+			},
+			"required": []string{"address", "amount"},
+		}),
+	)
+}
+
+// utxoRecipient is one payment of a send.
+type utxoRecipient struct {
+	Address string `json:"address"`
+	Amount  int64  `json:"amount"`
+}
+
+type utxoRecipients []utxoRecipient
+
+// parseUTXORecipients reads either to_address/amount or the recipients list.
+func parseUTXORecipients(req mcp.CallToolRequest) (utxoRecipients, error) {
+	args := req.GetArguments()
+	list, hasList := args["recipients"]
+	toAddress := req.GetString("to_address", "")
+	amountStr := req.GetString("amount", "")
+
+	if !hasList {
+		if toAddress == "" {
+			return nil, fmt.Errorf("missing to_address")
+		}
+		if amountStr == "" {
+			return nil, fmt.Errorf("missing amount")
+		}
+		amount, err := parseSendAmount(amountStr)
+		if err != nil {
+			return nil, err
+		}
+		return utxoRecipients{{Address: toAddress, Amount: amount}}, nil
+	}
+
+	if toAddress != "" || amountStr != "" {
+		return nil, fmt.Errorf("pass either to_address/amount or recipients, not both")
+	}
+	items, ok := list.([]any)
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("recipients must be a non-empty list")
+	}
+	recipients := make(utxoRecipients, 0, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("recipients[%d]: expected an object with address and amount", i)
+		}
+		addr, _ := m["address"].(string)
+		if addr == "" {
+			return nil, fmt.Errorf("recipients[%d]: missing address", i)
+		}
+		var amountStr string
+		switch a := m["amount"].(type) {
+		case string:
+			amountStr = a
+		case float64:
+			amountStr = strconv.FormatFloat(a, 'f', -1, 64)
+		}
+		amount, err := parseSendAmount(amountStr)
+		if err != nil {
+			return nil, fmt.Errorf("recipients[%d]: %w", i, err)
+		}
+		recipients = append(recipients, utxoRecipient{Address: addr, Amount: amount})
+	}
+	return recipients, nil
+}
+
+func parseSendAmount(s string) (int64, error) {
+	amount, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || amount <= 0 {
+		return 0, fmt.Errorf("invalid amount: %q", s)
+	}
+	return amount, nil
+}
+
+// outputs converts the recipients into payment outputs for the chain.
+func (rs utxoRecipients) outputs(params utxoChainParams) ([]utxo.Output, error) {
+	outs := make([]utxo.Output, len(rs))
+	for i, r := range rs {
+		script, err := params.addressToPkScript(r.Address)
+		if err != nil {
+			if len(rs) == 1 {
+				return nil, fmt.Errorf("invalid to_address: %w", err)
+			}
+			return nil, fmt.Errorf("invalid recipients[%d] address: %w", i, err)
+		}
+		outs[i] = utxo.Output{PkScript: script, Value: r.Amount}
+	}
+	return outs, nil
+}
+
+// addTo adds the recipient fields to a build tool's result map: to and
+// amount for a single recipient, or the list and its total for a batch.
+func (rs utxoRecipients) addTo(result map[string]any) {
+	if len(rs) == 1 {
+		result["to"] = rs[0].Address
+		result["amount"] = rs[0].Amount
+		return
+	}
+	var total int64
+	for _, r := range rs {
+		total += r.Amount
+	}
+	result["recipients"] = rs
+	result["amount"] = total
+}
+
+// utxoSendParams describes a send from a single vault address on a UTXO chain.
+type utxoSendParams struct {
+	chain     string
+	sender    string
+	pubKeyHex string // derived compressed public key of sender
+	payments  []utxo.Output
+	memo      string
+	fee       utxo.FeeFunc
+	strategy  utxo.Strategy
+}
+
+// builtUTXOTx is a funded, unsigned UTXO-chain transaction.
+type builtUTXOTx struct {
+	selection *utxo.Selection
+	encoding  string
+	// psbt is the base64 PSBT; empty for Zcash, which has no PSBT format.
+	psbt string
+	// unsignedTxHex is the raw unsigned transaction. For Zcash it carries the
+	// per-input sighashes and public key appended, as the signers expect.
+	unsignedTxHex string
+	// separateFee is the total fee of paying each output in its own
+	// transaction; zero when there is nothing to compare against.
+	separateFee int64
+}
+
+type utxoInputJSON struct {
+	TxID  string `json:"txid"`
+	Vout  uint32 `json:"vout"`
+	Value int64  `json:"value"`
+}
+
+type utxoOutputJSON struct {
+	Role      string `json:"role"`
+	ScriptHex string `json:"script_hex"`
+	Value     int64  `json:"value"`
+}
+
+// buildUTXOTx fetches the sender's UTXOs, selects inputs and
+// assembles the unsigned transaction in the chain's signing format.
+func buildUTXOTx(ctx context.Context, utxoBackend utxobackend.Backend, p utxoSendParams) (*builtUTXOTx, error) {
+	params, ok := utxoChains[p.chain]
+	if !ok {
+		return nil, fmt.Errorf("unsupported UTXO chain: %s", p.chain)
+	}
+
+	senderScript, err := params.addressToPkScript(p.sender)
+	if err != nil {
+		return nil, fmt.Errorf("sender script: %w", err)
+	}
+	inputType, err := utxo.InputTypeOf(senderScript)
+	if err != nil {
+		return nil, fmt.Errorf("sender address: %w", err)
+	}
+	pubKey, err := hex.DecodeString(p.pubKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("decode sender public key: %w", err)
+	}
+
+	coins, err := fetchUTXOs(ctx, utxoBackend, p.chain, p.sender)
+	if err != nil {
+		return nil, err
+	}
+
+	var opReturn []byte
+	if p.memo != "" {
+		opReturn, err = utxo.OpReturnScript([]byte(p.memo))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	selReq := utxo.Request{
+		UTXOs:        coins,
+		InputType:    inputType,
+		Payments:     p.payments,
+		OpReturn:     opReturn,
+		ChangeScript: senderScript,
+		DustLimit:    params.dustLimit,
+		Fee:          p.fee,
+		ExtraBytes:   params.extraBytes,
+		Strategy:     p.strategy,
+	}
+	sel, err := utxo.Select(selReq)
+	if err != nil {
+		return nil, err
+	}
+
+	btx, err := assembleUTXOTx(ctx, utxoBackend, p.chain, params, sel, senderScript, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.payments) > 1 {
+		btx.separateFee = separateSendsFee(selReq)
+	}
+	return btx, nil
+}
+
+// separateSendsFee estimates the total fee of paying each of req's payments
+// in its own transaction from the same coins. Payments that could not be
+// funded alone are skipped.
+func separateSendsFee(req utxo.Request) int64 {
+	var total int64
+	for _, pay := range req.Payments {
+		single := req
+		single.Payments = []utxo.Output{pay}
+		sel, err := utxo.Select(single)
+		if err != nil {
+			continue
+		}
+		total += sel.Fee
+	}
+	return total
+}
+
+// fetchUTXOs lists the spendable coins of addr.
+func fetchUTXOs(ctx context.Context, utxoBackend utxobackend.Backend, chain, addr string) ([]utxo.UTXO, error) {
+	utxos, err := utxoBackend.GetUTXOs(ctx, chain, addr)
+	if err != nil {
+		return nil, fmt.Errorf("fetch UTXOs: %w", err)
+	}
+	coins := make([]utxo.UTXO, 0, len(utxos))
+	for _, u := range utxos {
+		coins = append(coins, utxo.UTXO{
+			TxHash: u.TransactionHash,
+			Index:  uint32(u.Index),
+			Value:  u.Value,
+		})
+	}
+	return coins, nil
+}
+
+// assembleUTXOTx builds sel in the chain's signing format.
+func assembleUTXOTx(ctx context.Context, utxoBackend utxobackend.Backend, chain string, params utxoChainParams, sel *utxo.Selection, senderScript, pubKey []byte) (*builtUTXOTx, error) {
+	if chain == "Zcash" {
+		return buildZcashTx(sel, senderScript, pubKey)
+	}
+	return buildPSBT(ctx, utxoBackend, chain, params, sel, senderScript, pubKey)
+}
+
+// buildPSBT assembles a PSBT for sel. Witness inputs carry their previous
+// output directly; legacy inputs get the full previous transaction, fetched
+// from the backend, as legacy sighashes require.
+func buildPSBT(ctx context.Context, utxoBackend utxobackend.Backend, chain string, params utxoChainParams, sel *utxo.Selection, senderScript, pubKey []byte) (*builtUTXOTx, error) {
+	tx := wire.NewMsgTx(params.txVersion)
+	selected := make([]btcsdk.UTXO, 0, len(sel.Inputs))
+	for _, in := range sel.Inputs {
+		hash, err := chainhash.NewHashFromStr(in.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UTXO tx hash %q: %w", in.TxHash, err)
+		}
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Hash: *hash, Index: in.Index},
+			Sequence:         params.sequence,
+		})
+		selected = append(selected, btcsdk.UTXO{
+			TxHash:   in.TxHash,
+			Index:    in.Index,
+			Value:    uint64(in.Value),
+			PkScript: senderScript,
+		})
+	}
+	for _, out := range sel.Outputs {
+		tx.AddTxOut(wire.NewTxOut(out.Value, out.PkScript))
+	}
+
+	pkt, err := btcsdk.CreatePSBT(tx, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	err = btcsdk.PopulatePSBTMetadata(&btcsdk.BuildResult{
+		Packet:        pkt,
+		SelectedUTXOs: selected,
+	}, utxobackend.NewPrevTxFetcher(ctx, utxoBackend, chain))
+	if err != nil {
+		return nil, fmt.Errorf("populate PSBT inputs: %w", err)
+	}
+
+	b64, err := pkt.B64Encode()
+	if err != nil {
+		return nil, fmt.Errorf("encode PSBT: %w", err)
+	}
+	var raw bytes.Buffer
+	err = tx.SerializeNoWitness(&raw)
+	if err != nil {
+		return nil, fmt.Errorf("serialize transaction: %w", err)
+	}
+
+	return &builtUTXOTx{
+		selection:     sel,
+		encoding:      types.TxEncodingPSBT,
+		psbt:          b64,
+		unsignedTxHex: hex.EncodeToString(raw.Bytes()),
+	}, nil
+}
+
+// buildZcashTx serializes sel as a transparent Zcash v4 transaction with its
+// ZIP-243 sighashes attached.
+func buildZcashTx(sel *utxo.Selection, senderScript, pubKey []byte) (*builtUTXOTx, error) {
+	sdk := zcashsdk.NewSDK(nil)
+
+	inputs := make([]zcashsdk.TxInput, len(sel.Inputs))
+	for i, in := range sel.Inputs {
+		inputs[i] = zcashsdk.TxInput{
+			TxHash:   in.TxHash,
+			Index:    in.Index,
+			Value:    uint64(in.Value),
+			Script:   senderScript,
+			Sequence: wire.MaxTxInSequenceNum,
+		}
+	}
+	outputs := make([]*zcashsdk.TxOutput, len(sel.Outputs))
+	for i, out := range sel.Outputs {
+		outputs[i] = &zcashsdk.TxOutput{Value: out.Value, Script: out.PkScript}
+	}
+
+	raw, err := sdk.SerializeUnsignedTx(inputs, outputs)
+	if err != nil {
+		return nil, err
+	}
+	sigHashes := make([][]byte, len(inputs))
+	for i := range inputs {
+		sigHashes[i], err = sdk.CalculateSigHash(inputs, outputs, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &builtUTXOTx{
+		selection:     sel,
+		encoding:      types.TxEncodingZcashV4,
+		unsignedTxHex: hex.EncodeToString(zcashsdk.SerializeWithMetadata(raw, sigHashes, pubKey)),
+	}, nil
+}
+
+// addTo adds the transaction fields to a build tool's result map.
+func (t *builtUTXOTx) addTo(result map[string]any) {
+	sel := t.selection
+
+	inputs := make([]utxoInputJSON, len(sel.Inputs))
+	for i, in := range sel.Inputs {
+		inputs[i] = utxoInputJSON{TxID: in.TxHash, Vout: in.Index, Value: in.Value}
+	}
+	outputs := make([]utxoOutputJSON, len(sel.Outputs))
+	for i, out := range sel.Outputs {
+		role := "payment"
+		switch {
+		case i == sel.ChangeIndex:
+			role = "change"
+		case txscript.IsNullData(out.PkScript):
+			role = "memo"
+		}
+		outputs[i] = utxoOutputJSON{Role: role, ScriptHex: hex.EncodeToString(out.PkScript), Value: out.Value}
+	}
+
+	result["tx_encoding"] = t.encoding
+	if t.psbt != "" {
+		result["psbt"] = t.psbt
+	}
+	result["unsigned_tx_hex"] = t.unsignedTxHex
+	result["inputs"] = inputs
+	result["outputs"] = outputs
+	result["fee"] = sel.Fee
+	result["vsize"] = sel.Shape.VSize
+	result["change"] = sel.Change
+	if sel.Strategy != "" {
+		result["coin_selection"] = string(sel.Strategy)
+	}
+	if t.separateFee > 0 {
+		result["fee_if_sent_separately"] = t.separateFee
+		result["fee_saved"] = t.separateFee - sel.Fee
+	}
+}
@@ -0,0 +1,359 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/mcp/internal/blockchair"
+	"github.com/vultisig/mcp/internal/keysign"
+)
+
+// mockBlockchair serves address dashboards and raw transactions. Every
+// address is funded by a single previous transaction with one output per
+// entry in values, paying to that address's script.
+func mockBlockchair(t *testing.T, values ...int64) *blockchair.Client {
+	t.Helper()
+
+	slugToChain := make(map[string]string)
+	for name, info := range blockchair.SupportedChains {
+		slugToChain[info.Slug] = name
+	}
+
+	prevTxs := make(map[string]string) // txid -> raw hex
+	fund := func(chain, addr string) (string, error) {
+		script, err := utxoChains[chain].addressToPkScript(addr)
+		if err != nil {
+			return "", err
+		}
+		tx := wire.NewMsgTx(1)
+		tx.AddTxIn(&wire.TxIn{Sequence: wire.MaxTxInSequenceNum})
+		for _, v := range values {
+			tx.AddTxOut(wire.NewTxOut(v, script))
+		}
+		var buf bytes.Buffer
+		err = tx.Serialize(&buf)
+		if err != nil {
+			return "", err
+		}
+		txid := tx.TxHash().String()
+		prevTxs[txid] = hex.EncodeToString(buf.Bytes())
+		return txid, nil
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 4 {
+			http.NotFound(w, r)
+			return
+		}
+		chain, key := slugToChain[parts[0]], parts[3]
+
+		var body any
+		switch parts[1] + "/" + parts[2] {
+		case "dashboards/address":
+			txid, err := fund(chain, key)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var utxos []map[string]any
+			for i, v := range values {
+				utxos = append(utxos, map[string]any{
+					"block_id": 800000, "transaction_hash": txid, "index": i, "value": v,
+				})
+			}
+			body = map[string]any{"data": map[string]any{key: map[string]any{"utxo": utxos}}}
+		case "raw/transaction":
+			raw, ok := prevTxs[key]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			body = map[string]any{"data": map[string]any{key: map[string]any{"raw_transaction": raw}}}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(body)
+		if err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return blockchair.NewClient(srv.URL)
+}
+
+func decodeResult(t *testing.T, res *mcp.CallToolResult) map[string]any {
+	t.Helper()
+	if res.IsError {
+		t.Fatalf("unexpected tool error: %v", res.Content)
+	}
+	var result map[string]any
+	err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &result)
+	if err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	return result
+}
+
+func decodePSBT(t *testing.T, result map[string]any) *psbt.Packet {
+	t.Helper()
+	b64, _ := result["psbt"].(string)
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		t.Fatalf("decode psbt base64: %v", err)
+	}
+	pkt, err := psbt.NewFromRawBytes(bytes.NewReader(raw), false)
+	if err != nil {
+		t.Fatalf("parse psbt: %v", err)
+	}
+	return pkt
+}
+
+func TestBuildBTCSend_PSBT(t *testing.T) {
+	store := setupBTCVault(t)
+	senderAddr := deriveBTCAddress(t, store)
+	handler := handleBuildBTCSend(store, mockBlockchair(t, 30_000, 100_000, 60_000))
+
+	res, err := handler(context.Background(), callToolReq("build_btc_send", map[string]any{
+		"to_address":     "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"amount":         "120000",
+		"fee_rate":       float64(10),
+		"memo":           "SWAP:ETH.ETH:0x1234",
+		"coin_selection": "largest_first",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	pkt := decodePSBT(t, result)
+
+	tx := pkt.UnsignedTx
+	if len(tx.TxIn) != 2 {
+		t.Fatalf("inputs = %d, want 2", len(tx.TxIn))
+	}
+	for i, in := range tx.TxIn {
+		if in.Sequence != rbfSequence {
+			t.Errorf("input %d sequence = %#x, want RBF signal", i, in.Sequence)
+		}
+		if pkt.Inputs[i].WitnessUtxo == nil {
+			t.Errorf("input %d missing witness utxo", i)
+		}
+		if len(pkt.Inputs[i].Bip32Derivation) != 1 {
+			t.Errorf("input %d missing public key", i)
+		}
+	}
+
+	// payment, change, memo
+	if len(tx.TxOut) != 3 {
+		t.Fatalf("outputs = %d, want 3", len(tx.TxOut))
+	}
+	if tx.TxOut[0].Value != 120_000 {
+		t.Errorf("payment = %d, want 120000", tx.TxOut[0].Value)
+	}
+	senderScript, _ := utxoChains["Bitcoin"].addressToPkScript(senderAddr)
+	if !bytes.Equal(tx.TxOut[1].PkScript, senderScript) {
+		t.Error("change does not return to sender")
+	}
+	if tx.TxOut[2].PkScript[0] != 0x6a || !bytes.Contains(tx.TxOut[2].PkScript, []byte("SWAP:ETH.ETH:0x1234")) {
+		t.Errorf("memo output = %x", tx.TxOut[2].PkScript)
+	}
+
+	fee := int64(result["fee"].(float64))
+	vsize := int(result["vsize"].(float64))
+	if fee != int64(vsize)*10 {
+		t.Errorf("fee %d != vsize %d * 10", fee, vsize)
+	}
+	if in, out := int64(160_000), tx.TxOut[0].Value+tx.TxOut[1].Value; in-out != fee {
+		t.Errorf("inputs - outputs = %d, want fee %d", in-out, fee)
+	}
+	if result["tx_encoding"] != "psbt" || result["coin_selection"] != "largest_first" {
+		t.Errorf("tx_encoding/coin_selection = %v/%v", result["tx_encoding"], result["coin_selection"])
+	}
+	if result["unsigned_tx_hex"] == "" {
+		t.Error("expected unsigned_tx_hex")
+	}
+}
+
+func TestBuildBTCSend_InsufficientFunds(t *testing.T) {
+	store := setupBTCVault(t)
+	handler := handleBuildBTCSend(store, mockBlockchair(t, 10_000))
+
+	res, err := handler(context.Background(), callToolReq("build_btc_send", map[string]any{
+		"to_address": "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"amount":     "50000",
+		"fee_rate":   float64(10),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Fatal("expected insufficient funds error")
+	}
+}
+
+func TestBuildDOGESend_LegacyInputs(t *testing.T) {
+	store := setupVaultForChain(t)
+	handler := handleBuildDOGESend(store, mockBlockchair(t, 500_000_000, 300_000_000))
+
+	res, err := handler(context.Background(), callToolReq("build_doge_send", map[string]any{
+		"to_address":     "DDogepartyxxxxxxxxxxxxxxxxxxw1dfzr",
+		"amount":         "400000000",
+		"fee_rate":       float64(1000),
+		"coin_selection": "privacy",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	pkt := decodePSBT(t, result)
+
+	if len(pkt.Inputs) != 1 {
+		t.Fatalf("inputs = %d, want the single 5 DOGE coin", len(pkt.Inputs))
+	}
+	if pkt.Inputs[0].NonWitnessUtxo == nil {
+		t.Error("legacy input missing previous transaction")
+	}
+	if pkt.UnsignedTx.Version != 1 || pkt.UnsignedTx.TxIn[0].Sequence != wire.MaxTxInSequenceNum {
+		t.Errorf("version/sequence = %d/%#x", pkt.UnsignedTx.Version, pkt.UnsignedTx.TxIn[0].Sequence)
+	}
+	// 1-in 2-out P2PKH is 226 bytes.
+	if result["vsize"].(float64) != 226 || result["fee"].(float64) != 226_000 {
+		t.Errorf("vsize/fee = %v/%v, want 226/226000", result["vsize"], result["fee"])
+	}
+}
+
+func TestBuildZECSend_Transaction(t *testing.T) {
+	store := setupVaultForChain(t)
+	recipient := deriveChainAddr(t, common.Zcash)
+	handler := handleBuildZECSend(store, mockBlockchair(t, 50_000_000))
+
+	res, err := handler(context.Background(), callToolReq("build_zec_send", map[string]any{
+		"to_address": recipient,
+		"amount":     "10000000",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+
+	if result["fee"].(float64) != 10_000 {
+		t.Errorf("fee = %v, want ZIP-317 minimum 10000", result["fee"])
+	}
+	if _, ok := result["psbt"]; ok {
+		t.Error("expected no psbt for Zcash")
+	}
+	raw, err := hex.DecodeString(result["unsigned_tx_hex"].(string))
+	if err != nil {
+		t.Fatalf("decode unsigned_tx_hex: %v", err)
+	}
+	// v4 overwintered version, little-endian
+	if !bytes.HasPrefix(raw, []byte{0x04, 0x00, 0x00, 0x80}) {
+		t.Errorf("unexpected tx header %x", raw[:4])
+	}
+	if !bytes.Contains(raw, []byte("ZSH")) {
+		t.Error("expected sighash metadata appended")
+	}
+}
+
+func TestBuildBTCSend_KeysignUsesSelectedInputs(t *testing.T) {
+	store := setupBTCVault(t)
+	handler := handleBuildBTCSend(store, mockBlockchair(t, 30_000, 100_000, 60_000))
+
+	res, err := handler(context.Background(), callToolReq("build_btc_send", map[string]any{
+		"to_address":     "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"amount":         "50000",
+		"fee_rate":       float64(5),
+		"coin_selection": "privacy",
+		"output_format":  "keysign",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	p, err := keysign.Decode(result["keysign_payload_base64"].(string))
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if len(p.UtxoInfo) != 1 || p.UtxoInfo[0].Amount != 60_000 {
+		t.Errorf("utxo_info = %+v, want the single 60000 coin", p.UtxoInfo)
+	}
+	if p.GetUtxoSpecific().GetByteFee() != "5" {
+		t.Errorf("byte_fee = %q, want 5", p.GetUtxoSpecific().GetByteFee())
+	}
+}
+
+func TestBuildBTCSend_Recipients(t *testing.T) {
+	store := setupBTCVault(t)
+	handler := handleBuildBTCSend(store, mockBlockchair(t, 30_000, 100_000, 60_000))
+
+	res, err := handler(context.Background(), callToolReq("build_btc_send", map[string]any{
+		"recipients": []any{
+			map[string]any{"address": "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "amount": "40000"},
+			map[string]any{"address": "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "amount": "25000"},
+		},
+		"fee_rate": float64(10),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := decodeResult(t, res)
+	tx := decodePSBT(t, result).UnsignedTx
+
+	if tx.TxOut[0].Value != 40_000 || tx.TxOut[1].Value != 25_000 {
+		t.Errorf("payments = %d, %d", tx.TxOut[0].Value, tx.TxOut[1].Value)
+	}
+	if result["amount"].(float64) != 65_000 || len(result["recipients"].([]any)) != 2 {
+		t.Errorf("amount/recipients = %v/%v", result["amount"], result["recipients"])
+	}
+	fee := int64(result["fee"].(float64))
+	separate := int64(result["fee_if_sent_separately"].(float64))
+	if saved := int64(result["fee_saved"].(float64)); saved <= 0 || saved != separate-fee {
+		t.Errorf("fee_saved = %d, separate = %d, fee = %d", saved, separate, fee)
+	}
+}
+
+func TestBuildBTCSend_RecipientsErrors(t *testing.T) {
+	store := setupBTCVault(t)
+	handler := handleBuildBTCSend(store, mockBlockchair(t, 1_000_000))
+	two := []any{
+		map[string]any{"address": "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "amount": "40000"},
+		map[string]any{"address": "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "amount": "25000"},
+	}
+
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{"both forms", map[string]any{"recipients": two, "to_address": "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "amount": "1000"}},
+		{"empty list", map[string]any{"recipients": []any{}}},
+		{"bad amount", map[string]any{"recipients": []any{map[string]any{"address": "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "amount": "-5"}}}},
+		{"keysign batch", map[string]any{"recipients": two, "output_format": "keysign"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["fee_rate"] = float64(10)
+			res, err := handler(context.Background(), callToolReq("build_btc_send", tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !res.IsError {
+				t.Fatalf("expected tool error, got %v", resultText(t, res))
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	evmclient "github.com/vultisig/mcp/internal/evm"
+	"github.com/vultisig/mcp/internal/resolve"
+	"github.com/vultisig/mcp/internal/vault"
+)
+
+func newVerifyTypedDataSignatureTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription(
+			"Recover the signer of an EIP-712 signature and check it against the expected address. " +
+				"Takes the same typed-data input as build_typed_data plus the 65-byte signature (r || s || v, v as 0/1 or 27/28). " +
+				"The expected signer falls back to the vault-derived EVM address if address is not provided. " +
+				"Only EOA signatures are checked; smart-contract wallets (EIP-1271) are not.",
+		),
+		mcp.WithString("signature",
+			mcp.Description("Hex-encoded 65-byte signature (0x-prefixed)."),
+			mcp.Required(),
+		),
+		mcp.WithString("address",
+			mcp.Description("Expected signer address (0x-prefixed). Optional if vault info is set."),
+		),
+	}
+	opts = append(opts, typedDataOptions()...)
+	return mcp.NewTool("verify_typed_data_signature", opts...)
+}
+
+func handleVerifyTypedDataSignature(store *vault.Store) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sigHex, err := req.RequireString("signature")
+		if err != nil {
+			return mcp.NewToolResultError("missing signature parameter"), nil
+		}
+		sig, err := hexutil.Decode(sigHex)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid signature hex: %v", err)), nil
+		}
+
+		explicit := req.GetString("address", "")
+		if explicit != "" && !common.IsHexAddress(explicit) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid address: %s", explicit)), nil
+		}
+		expected, err := resolve.EVMAddress(explicit, resolve.ResolveVault(ctx, req, store))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		td, err := typedDataParam(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		h, err := td.Hash()
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		signer, err := evmclient.RecoverTypedDataSigner(h.Digest, sig)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		data, err := json.Marshal(map[string]any{
+			"valid":           signer == common.HexToAddress(expected),
+			"signer":          signer.Hex(),
+			"expected_signer": common.HexToAddress(expected).Hex(),
+			"primary_type":    td.PrimaryType,
+			"digest":          hexutil.Encode(h.Digest),
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("marshal result: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcutil/base58"
+
+	"github.com/vultisig/mcp/internal/tokenmeta"
 )
 
 const ABIWordHexLen = 64
@@ -20,6 +22,7 @@ const ABIWordHexLen = 64
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	tokens     *tokenmeta.Chain
 }
 
 func NewClient(baseURL string) *Client {
@@ -28,6 +31,7 @@ func NewClient(baseURL string) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		tokens: tokenmeta.NewMemoryRegistry().Chain("Tron"),
 	}
 }
 
@@ -211,6 +215,43 @@ func (c *Client) TriggerConstantContract(ctx context.Context, ownerAddr, contrac
 	return &result, nil
 }
 
+// BlockHeader holds the raw header fields of a TRON block that a signer needs
+// to set the transaction's reference block.
+type BlockHeader struct {
+	Number         int64  `json:"number"`
+	TxTrieRoot     string `json:"txTrieRoot"`
+	WitnessAddress string `json:"witness_address"`
+	ParentHash     string `json:"parentHash"`
+	Version        int64  `json:"version"`
+	Timestamp      int64  `json:"timestamp"`
+}
+
+type nowBlockResponse struct {
+	BlockID     string `json:"blockID"`
+	BlockHeader struct {
+		RawData BlockHeader `json:"raw_data"`
+	} `json:"block_header"`
+}
+
+// GetNowBlock returns the header of the latest block.
+func (c *Client) GetNowBlock(ctx context.Context) (*BlockHeader, error) {
+	raw, err := c.do(ctx, "/wallet/getnowblock", nil)
+	if err != nil {
+		return nil, fmt.Errorf("get now block: %w", err)
+	}
+
+	var resp nowBlockResponse
+	err = json.Unmarshal(raw, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("tron: unmarshal now block: %w", err)
+	}
+	if resp.BlockID == "" {
+		return nil, fmt.Errorf("tron: empty now block response")
+	}
+
+	return &resp.BlockHeader.RawData, nil
+}
+
 func ValidateAddress(address string) error {
 	if len(address) == 0 {
 		return fmt.Errorf("empty address")
@@ -0,0 +1,64 @@
+package tron
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/vultisig/mcp/internal/tokenmeta"
+)
+
+// SetTokenRegistry makes the client cache TRC-20 metadata in r. Call it
+// before the client is used.
+func (c *Client) SetTokenRegistry(r *tokenmeta.Registry) {
+	c.tokens = r.Chain("Tron")
+}
+
+// TokenMetadata returns the symbol, name and decimals of the TRC-20 token at
+// contractAddr, calling the contract as ownerAddr on first use. A missing or
+// undecodable symbol or name is left empty and missing decimals set
+// NoDecimals; a contract that has neither a symbol nor decimals is an error
+// and is not cached.
+func (c *Client) TokenMetadata(ctx context.Context, ownerAddr, contractAddr string) (tokenmeta.Metadata, error) {
+	if meta, ok := c.tokens.Get(contractAddr); ok {
+		return meta, nil
+	}
+
+	var meta tokenmeta.Metadata
+	result, err := c.TriggerConstantContract(ctx, ownerAddr, contractAddr, "symbol()", "")
+	if err != nil {
+		return tokenmeta.Metadata{}, fmt.Errorf("get token symbol: %w", err)
+	}
+	if len(result.ConstantResult) > 0 {
+		meta.Symbol, err = DecodeTRC20Symbol(result.ConstantResult[0])
+		if err != nil {
+			log.Printf("[tron] decode symbol for %s: %v", contractAddr, err)
+		}
+	}
+
+	result, err = c.TriggerConstantContract(ctx, ownerAddr, contractAddr, "decimals()", "")
+	if err != nil {
+		return tokenmeta.Metadata{}, fmt.Errorf("get token decimals: %w", err)
+	}
+	meta.NoDecimals = true
+	if len(result.ConstantResult) > 0 && result.ConstantResult[0] != "" {
+		d, err := DecodeTRC20Decimals(result.ConstantResult[0])
+		if err != nil {
+			log.Printf("[tron] decode decimals for %s: %v", contractAddr, err)
+		} else {
+			meta.Decimals, meta.NoDecimals = d, false
+		}
+	}
+
+	if meta.Symbol == "" && meta.NoDecimals {
+		return tokenmeta.Metadata{}, fmt.Errorf("%s has no symbol() or decimals(); not a TRC-20 token", contractAddr)
+	}
+
+	result, err = c.TriggerConstantContract(ctx, ownerAddr, contractAddr, "name()", "")
+	if err == nil && len(result.ConstantResult) > 0 {
+		meta.Name, _ = DecodeTRC20Symbol(result.ConstantResult[0])
+	}
+
+	c.tokens.Put(contractAddr, meta)
+	return meta, nil
+}
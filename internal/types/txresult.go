@@ -32,6 +32,7 @@ const (
 const (
 	TxEncodingLegacyRLP  = "legacy_rlp"
 	TxEncodingEIP1559RLP = "eip1559_rlp"
+	TxEncodingEIP2930RLP = "eip2930_rlp"
 	TxEncodingPSBT       = "psbt"
 	TxEncodingZcashV4    = "zcash_v4"
 )
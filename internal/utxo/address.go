@@ -0,0 +1,248 @@
+package utxo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil/base58"
+	bchcfg "github.com/gcash/bchd/chaincfg"
+	"github.com/gcash/bchutil"
+)
+
+// AddressScript converts an address on chain to the output script it pays.
+// Chains are named as in blockchair.SupportedChains.
+func AddressScript(chain, addr string) ([]byte, error) {
+	decode, ok := addressDecoders[chain]
+	if !ok {
+		return nil, fmt.Errorf("unsupported UTXO chain: %s", chain)
+	}
+	return decode(addr)
+}
+
+var addressDecoders = map[string]func(string) ([]byte, error){
+	"Bitcoin":      BitcoinAddressScript,
+	"Litecoin":     LitecoinAddressScript,
+	"Dogecoin":     DogecoinAddressScript,
+	"Dash":         DashAddressScript,
+	"Bitcoin-Cash": BitcoinCashAddressScript,
+	"Zcash":        ZcashAddressScript,
+}
+
+var (
+	dogecoinParams = &chaincfg.Params{
+		PubKeyHashAddrID: 0x1e,
+		ScriptHashAddrID: 0x16,
+	}
+	dashParams = &chaincfg.Params{
+		PubKeyHashAddrID: 0x4c,
+		ScriptHashAddrID: 0x10,
+	}
+	litecoinParams = &chaincfg.Params{
+		PubKeyHashAddrID: 0x30,
+		ScriptHashAddrID: 0x32,
+		Bech32HRPSegwit:  "ltc",
+	}
+)
+
+var (
+	// BitcoinAddressScript decodes a mainnet Bitcoin address.
+	BitcoinAddressScript = btcAddrToPkScript(&chaincfg.MainNetParams)
+	// DogecoinAddressScript decodes a Dogecoin P2PKH or P2SH address.
+	DogecoinAddressScript = btcAddrToPkScript(dogecoinParams)
+	// DashAddressScript decodes a Dash P2PKH or P2SH address.
+	DashAddressScript = btcAddrToPkScript(dashParams)
+)
+
+// btcAddrToPkScript returns a function that decodes a btcutil-compatible address
+// and produces a pkScript using btcd's txscript.
+func btcAddrToPkScript(params *chaincfg.Params) func(string) ([]byte, error) {
+	return func(addr string) ([]byte, error) {
+		decoded, err := btcutil.DecodeAddress(addr, params)
+		if err != nil {
+			return nil, fmt.Errorf("decode address %q: %w", addr, err)
+		}
+		return txscript.PayToAddrScript(decoded)
+	}
+}
+
+// LitecoinAddressScript decodes a Litecoin address (bech32 segwit or legacy P2PKH/P2SH)
+// and produces a pkScript. btcutil.DecodeAddress requires network registration to
+// handle bech32 prefixes, so segwit addresses are decoded directly.
+func LitecoinAddressScript(addr string) ([]byte, error) {
+	if strings.HasPrefix(strings.ToLower(addr), "ltc1") {
+		_, data, err := bech32.Decode(strings.ToLower(addr))
+		if err != nil {
+			return nil, fmt.Errorf("decode LTC bech32 address %q: %w", addr, err)
+		}
+		if len(data) < 2 {
+			return nil, fmt.Errorf("LTC bech32 address too short: %q", addr)
+		}
+		witnessVersion := data[0]
+		if witnessVersion > 16 {
+			return nil, fmt.Errorf("invalid LTC witness version %d: %q", witnessVersion, addr)
+		}
+		witnessProgram, err := bech32.ConvertBits(data[1:], 5, 8, false)
+		if err != nil {
+			return nil, fmt.Errorf("decode LTC bech32 bits %q: %w", addr, err)
+		}
+		if witnessVersion == 0 {
+			if len(witnessProgram) != 20 && len(witnessProgram) != 32 {
+				return nil, fmt.Errorf("invalid LTC v0 witness program length %d: %q", len(witnessProgram), addr)
+			}
+		} else {
+			if len(witnessProgram) < 2 || len(witnessProgram) > 40 {
+				return nil, fmt.Errorf("invalid LTC witness program length %d: %q", len(witnessProgram), addr)
+			}
+		}
+		var witnessOp byte
+		if witnessVersion == 0 {
+			witnessOp = txscript.OP_0
+		} else {
+			witnessOp = txscript.OP_1 - 1 + witnessVersion
+		}
+		builder := txscript.NewScriptBuilder()
+		builder.AddOp(witnessOp)
+		builder.AddData(witnessProgram)
+		return builder.Script()
+	}
+	return btcAddrToPkScript(litecoinParams)(addr)
+}
+
+// BitcoinCashAddressScript decodes a Bitcoin Cash CashAddr and produces a pkScript.
+func BitcoinCashAddressScript(addr string) ([]byte, error) {
+	decoded, err := bchutil.DecodeAddress(addr, &bchcfg.MainNetParams)
+	if err != nil {
+		return nil, fmt.Errorf("decode BCH address %q: %w", addr, err)
+	}
+
+	switch a := decoded.(type) {
+	case *bchutil.AddressPubKeyHash:
+		hash := a.Hash160()
+		return payToPubKeyHash(hash[:]), nil
+	case *bchutil.AddressScriptHash:
+		hash := a.Hash160()
+		return payToScriptHash(hash[:]), nil
+	default:
+		return nil, fmt.Errorf("unsupported BCH address type: %T", decoded)
+	}
+}
+
+// ZcashAddressScript decodes a Zcash transparent address (t-addr) with its
+// 2-byte version prefix and produces a pkScript.
+func ZcashAddressScript(addr string) ([]byte, error) {
+	decoded, version, err := base58.CheckDecode(addr)
+	if err != nil {
+		return nil, fmt.Errorf("decode Zcash address %q: %w", addr, err)
+	}
+
+	// Zcash t-addresses use a 2-byte prefix encoded as: first byte = version (from CheckDecode),
+	// second byte = first byte of decoded payload. The actual hash is the remaining 20 bytes.
+	// P2PKH: prefix 0x1c, 0xb8  (version=0x1c from CheckDecode, decoded[0]=0xb8)
+	// P2SH:  prefix 0x1c, 0xbd  (version=0x1c from CheckDecode, decoded[0]=0xbd)
+	if len(decoded) != 21 {
+		return nil, fmt.Errorf("invalid Zcash address payload length: %d", len(decoded))
+	}
+
+	secondByte := decoded[0]
+	hash := decoded[1:21]
+
+	switch {
+	case version == 0x1c && secondByte == 0xb8: // t1... P2PKH
+		return payToPubKeyHash(hash), nil
+	case version == 0x1c && secondByte == 0xbd: // t3... P2SH
+		return payToScriptHash(hash), nil
+	default:
+		return nil, fmt.Errorf("unsupported Zcash address prefix: 0x%02x%02x", version, secondByte)
+	}
+}
+
+// ScriptAddress encodes the output script pkScript as an address on chain.
+// It fails for scripts with no address form, such as OP_RETURN outputs.
+func ScriptAddress(chain string, pkScript []byte) (string, error) {
+	encode, ok := addressEncoders[chain]
+	if !ok {
+		return "", fmt.Errorf("unsupported UTXO chain: %s", chain)
+	}
+	return encode(pkScript)
+}
+
+var addressEncoders = map[string]func([]byte) (string, error){
+	"Bitcoin":      btcScriptToAddr(&chaincfg.MainNetParams),
+	"Litecoin":     btcScriptToAddr(litecoinParams),
+	"Dogecoin":     btcScriptToAddr(dogecoinParams),
+	"Dash":         btcScriptToAddr(dashParams),
+	"Bitcoin-Cash": bchScriptToAddr,
+	"Zcash":        zcashScriptToAddr,
+}
+
+// btcScriptToAddr returns a function that encodes a standard output script
+// as an address of the given network.
+func btcScriptToAddr(params *chaincfg.Params) func([]byte) (string, error) {
+	return func(pkScript []byte) (string, error) {
+		class, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, params)
+		if err != nil {
+			return "", err
+		}
+		if len(addrs) != 1 || class == txscript.PubKeyTy {
+			return "", fmt.Errorf("no address for %s script", class)
+		}
+		return addrs[0].EncodeAddress(), nil
+	}
+}
+
+// bchScriptToAddr encodes a P2PKH or P2SH script as a CashAddr with its
+// bitcoincash: prefix.
+func bchScriptToAddr(pkScript []byte) (string, error) {
+	var addr bchutil.Address
+	var err error
+	switch class := txscript.GetScriptClass(pkScript); class {
+	case txscript.PubKeyHashTy:
+		addr, err = bchutil.NewAddressPubKeyHash(pkScript[3:23], &bchcfg.MainNetParams)
+	case txscript.ScriptHashTy:
+		addr, err = bchutil.NewAddressScriptHashFromHash(pkScript[2:22], &bchcfg.MainNetParams)
+	default:
+		return "", fmt.Errorf("no address for %s script", class)
+	}
+	if err != nil {
+		return "", err
+	}
+	return bchcfg.MainNetParams.CashAddressPrefix + ":" + addr.EncodeAddress(), nil
+}
+
+// zcashScriptToAddr encodes a P2PKH or P2SH script as a transparent t-addr.
+func zcashScriptToAddr(pkScript []byte) (string, error) {
+	switch class := txscript.GetScriptClass(pkScript); class {
+	case txscript.PubKeyHashTy:
+		return base58.CheckEncode(append([]byte{0xb8}, pkScript[3:23]...), 0x1c), nil
+	case txscript.ScriptHashTy:
+		return base58.CheckEncode(append([]byte{0xbd}, pkScript[2:22]...), 0x1c), nil
+	default:
+		return "", fmt.Errorf("no address for %s script", class)
+	}
+}
+
+// payToPubKeyHash builds OP_DUP OP_HASH160 <hash> OP_EQUALVERIFY OP_CHECKSIG.
+func payToPubKeyHash(hash []byte) []byte {
+	script := make([]byte, 25)
+	script[0] = txscript.OP_DUP
+	script[1] = txscript.OP_HASH160
+	script[2] = 0x14 // push 20 bytes
+	copy(script[3:23], hash)
+	script[23] = txscript.OP_EQUALVERIFY
+	script[24] = txscript.OP_CHECKSIG
+	return script
+}
+
+// payToScriptHash builds OP_HASH160 <hash> OP_EQUAL.
+func payToScriptHash(hash []byte) []byte {
+	script := make([]byte, 23)
+	script[0] = txscript.OP_HASH160
+	script[1] = 0x14 // push 20 bytes
+	copy(script[2:22], hash)
+	script[22] = txscript.OP_EQUAL
+	return script
+}
@@ -0,0 +1,48 @@
+package utxo
+
+import "testing"
+
+func TestScriptAddressRoundTrip(t *testing.T) {
+	tests := []struct {
+		chain string
+		addr  string
+	}{
+		{"Bitcoin", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+		{"Bitcoin", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"},
+		{"Bitcoin", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"},
+		{"Bitcoin", "bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297"},
+		{"Litecoin", "ltc1qtfd95kj6tfd95kj6tfd95kj6tfd95kj6v3vqyj"},
+		{"Litecoin", "LaMT348PWRnrqeeWArpwQPbuanpXDZGEUz"},
+		{"Dogecoin", "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L"},
+		{"Dash", "XpESxaUmonkq8RaLLp46Brx2K39ggQe226"},
+		{"Bitcoin-Cash", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"},
+		{"Zcash", "t1Hsc1LR8yKnbbe3twRp88p6vFfC5t7DLbs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.chain+"/"+tt.addr, func(t *testing.T) {
+			script, err := AddressScript(tt.chain, tt.addr)
+			if err != nil {
+				t.Fatalf("AddressScript: %v", err)
+			}
+			got, err := ScriptAddress(tt.chain, script)
+			if err != nil {
+				t.Fatalf("ScriptAddress: %v", err)
+			}
+			if got != tt.addr {
+				t.Errorf("ScriptAddress = %s, want %s", got, tt.addr)
+			}
+		})
+	}
+}
+
+func TestScriptAddressNullData(t *testing.T) {
+	script, err := OpReturnScript([]byte("memo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chain := range addressEncoders {
+		if _, err := ScriptAddress(chain, script); err == nil {
+			t.Errorf("%s: expected error for OP_RETURN script", chain)
+		}
+	}
+}
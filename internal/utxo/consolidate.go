@@ -0,0 +1,93 @@
+package utxo
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ConsolidateRequest describes merging many coins into a single output.
+type ConsolidateRequest struct {
+	UTXOs     []UTXO
+	InputType InputType
+	// MaxValue leaves out coins worth more than this; zero includes every coin.
+	MaxValue int64
+	// MaxInputs caps how many coins are merged, smallest first; zero means no cap.
+	MaxInputs int
+	// Script receives the consolidated output.
+	Script     []byte
+	DustLimit  int64
+	Fee        FeeFunc
+	ExtraBytes int
+}
+
+// Consolidation is a consolidation layout.
+type Consolidation struct {
+	*Selection
+	// Uneconomical are the candidate coins worth less than the fee to spend them.
+	Uneconomical []UTXO
+	// SeparateFee is the total fee of sweeping each input in its own transaction.
+	SeparateFee int64
+}
+
+// inputCostSample is the input count used to average the marginal cost of an
+// input, so fee functions with a flat minimum (ZIP-317) are not undercounted.
+const inputCostSample = 100
+
+// Consolidate merges the small coins of req into one output to req.Script.
+// Coins that would cost more in fees than they are worth are left out.
+func Consolidate(req ConsolidateRequest) (*Consolidation, error) {
+	if req.Fee == nil {
+		return nil, errors.New("no fee function")
+	}
+
+	out := []Output{{PkScript: req.Script}}
+	single := req.Fee(ShapeOf(req.InputType, 1, out, req.ExtraBytes))
+	inputCost := (req.Fee(ShapeOf(req.InputType, 1+inputCostSample, out, req.ExtraBytes)) - single) / inputCostSample
+
+	var candidates, uneconomical []UTXO
+	for _, u := range req.UTXOs {
+		switch {
+		case req.MaxValue > 0 && u.Value > req.MaxValue:
+		case u.Value <= inputCost:
+			uneconomical = append(uneconomical, u)
+		default:
+			candidates = append(candidates, u)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b UTXO) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+	if req.MaxInputs > 0 && len(candidates) > req.MaxInputs {
+		candidates = candidates[:req.MaxInputs]
+	}
+	if len(candidates) < 2 {
+		return nil, fmt.Errorf("nothing to consolidate: %d UTXOs worth spending at this fee (%d too small to cover their input fee)",
+			len(candidates), len(uneconomical))
+	}
+
+	var total int64
+	for _, u := range candidates {
+		total += u.Value
+	}
+	shape := ShapeOf(req.InputType, len(candidates), out, req.ExtraBytes)
+	fee := req.Fee(shape)
+	value := total - fee
+	if value < req.DustLimit {
+		return nil, fmt.Errorf("insufficient funds: consolidating %d leaves %d after the %d fee, below the dust limit %d",
+			total, value, fee, req.DustLimit)
+	}
+
+	return &Consolidation{
+		Selection: &Selection{
+			Inputs:      candidates,
+			Outputs:     []Output{{PkScript: req.Script, Value: value}},
+			ChangeIndex: -1,
+			Fee:         fee,
+			Shape:       shape,
+		},
+		Uneconomical: uneconomical,
+		SeparateFee:  int64(len(candidates)) * single,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package utxo
+
+import (
+	"strings"
+	"testing"
+)
+
+func newConsolidateRequest(fee FeeFunc, utxos ...int64) ConsolidateRequest {
+	req := ConsolidateRequest{
+		InputType: InputP2WPKH,
+		Script:    p2wpkhScript,
+		DustLimit: 546,
+		Fee:       fee,
+	}
+	for i, v := range utxos {
+		req.UTXOs = append(req.UTXOs, UTXO{TxHash: strings.Repeat("ab", 32), Index: uint32(i), Value: v})
+	}
+	return req
+}
+
+func TestConsolidate(t *testing.T) {
+	req := newConsolidateRequest(RateFee(10), 50, 20_000, 500, 30_000, 5_000_000)
+	req.MaxValue = 100_000
+
+	c, err := Consolidate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.Inputs) != 2 || c.Inputs[0].Value != 20_000 || c.Inputs[1].Value != 30_000 {
+		t.Fatalf("inputs = %+v, want the 20000 and 30000 coins", c.Inputs)
+	}
+	if len(c.Uneconomical) != 2 {
+		t.Errorf("uneconomical = %+v, want the 50 and 500 coins", c.Uneconomical)
+	}
+	if c.Fee != int64(c.Shape.VSize)*10 {
+		t.Errorf("fee %d != vsize %d * 10", c.Fee, c.Shape.VSize)
+	}
+	if len(c.Outputs) != 1 || c.Outputs[0].Value != 50_000-c.Fee {
+		t.Errorf("outputs = %+v", c.Outputs)
+	}
+	// Two 1-in 1-out sweeps of 110 vB each.
+	if c.SeparateFee != 2200 {
+		t.Errorf("separate fee = %d, want 2200", c.SeparateFee)
+	}
+}
+
+func TestConsolidateMaxInputs(t *testing.T) {
+	req := newConsolidateRequest(RateFee(1), 9_000, 3_000, 7_000, 1_000)
+	req.MaxInputs = 3
+
+	c, err := Consolidate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []int64
+	for _, in := range c.Inputs {
+		got = append(got, in.Value)
+	}
+	if len(got) != 3 || got[0] != 1_000 || got[1] != 3_000 || got[2] != 7_000 {
+		t.Errorf("inputs = %v, want the three smallest", got)
+	}
+}
+
+func TestConsolidateZIP317SkipsSmallCoins(t *testing.T) {
+	req := newConsolidateRequest(ZIP317Fee, 4_000, 100_000, 200_000)
+	req.InputType = InputP2PKH
+	req.Script = p2pkhScript
+
+	c, err := Consolidate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.Inputs) != 2 || len(c.Uneconomical) != 1 || c.Uneconomical[0].Value != 4_000 {
+		t.Errorf("inputs/uneconomical = %+v / %+v", c.Inputs, c.Uneconomical)
+	}
+	if c.Fee != 10_000 {
+		t.Errorf("fee = %d, want 10000", c.Fee)
+	}
+}
+
+func TestConsolidateNothingToDo(t *testing.T) {
+	_, err := Consolidate(newConsolidateRequest(RateFee(10), 100, 1_000_000))
+	if err == nil || !strings.Contains(err.Error(), "nothing to consolidate") {
+		t.Errorf("err = %v, want nothing to consolidate", err)
+	}
+}
@@ -0,0 +1,360 @@
+package utxo
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// UTXO is a spendable coin.
+type UTXO struct {
+	TxHash string // txid, hex in display (big-endian) order
+	Index  uint32
+	Value  int64
+}
+
+// Output is a transaction output.
+type Output struct {
+	PkScript []byte
+	Value    int64
+}
+
+// Strategy selects the coin-selection algorithm.
+type Strategy string
+
+const (
+	// BranchAndBound searches for an input set that pays the target without
+	// a change output, falling back to LargestFirst when none exists.
+	BranchAndBound Strategy = "bnb"
+	// LargestFirst spends the biggest coins first, minimising input count.
+	LargestFirst Strategy = "largest_first"
+	// Privacy avoids merging coins when a single one suffices and orders
+	// inputs and outputs per BIP69 so the change position leaks nothing.
+	Privacy Strategy = "privacy"
+)
+
+// Strategies lists the accepted strategy names, for tool enums.
+var Strategies = []string{string(BranchAndBound), string(LargestFirst), string(Privacy)}
+
+// ParseStrategy validates a strategy name. An empty name means BranchAndBound.
+func ParseStrategy(s string) (Strategy, error) {
+	switch Strategy(s) {
+	case "":
+		return BranchAndBound, nil
+	case BranchAndBound, LargestFirst, Privacy:
+		return Strategy(s), nil
+	default:
+		return "", fmt.Errorf("unknown coin selection strategy %q (expected one of %s)", s, strings.Join(Strategies, ", "))
+	}
+}
+
+// bnbMaxTries bounds the branch-and-bound search, as in Bitcoin Core.
+const bnbMaxTries = 100_000
+
+// maxOpReturnBytes is the standard relay limit for OP_RETURN data.
+const maxOpReturnBytes = 80
+
+// OpReturnScript builds an OP_RETURN output script carrying data.
+func OpReturnScript(data []byte) ([]byte, error) {
+	if len(data) > maxOpReturnBytes {
+		return nil, fmt.Errorf("OP_RETURN data too long: %d bytes (max %d)", len(data), maxOpReturnBytes)
+	}
+	return txscript.NullDataScript(data)
+}
+
+// Request describes a payment to fund from a set of coins that all share the
+// same locking script.
+type Request struct {
+	UTXOs     []UTXO
+	InputType InputType
+	// Payments are the recipient outputs, in order.
+	Payments []Output
+	// OpReturn is an optional OP_RETURN script, placed after any change.
+	OpReturn []byte
+	// ChangeScript receives the change, if it is above DustLimit.
+	ChangeScript []byte
+	DustLimit    int64
+	Fee          FeeFunc
+	// ExtraBytes is passed through to ShapeOf.
+	ExtraBytes int
+	Strategy   Strategy
+}
+
+// Selection is a funded transaction layout.
+type Selection struct {
+	Inputs  []UTXO
+	Outputs []Output
+	// ChangeIndex is the position of the change output, or -1 if there is none.
+	ChangeIndex int
+	Change      int64
+	// Fee is total inputs minus total outputs. When change would be dust it
+	// is dropped and the remainder goes to the fee.
+	Fee   int64
+	Shape Shape
+	// Strategy is the algorithm that produced the selection; BranchAndBound
+	// reports LargestFirst when it had to fall back.
+	Strategy Strategy
+}
+
+// Select picks inputs for req and lays out the outputs.
+func Select(req Request) (*Selection, error) {
+	if len(req.UTXOs) == 0 {
+		return nil, errors.New("no UTXOs available to spend")
+	}
+	if len(req.Payments) == 0 {
+		return nil, errors.New("no payment outputs")
+	}
+	if req.Fee == nil {
+		return nil, errors.New("no fee function")
+	}
+
+	var target int64
+	for i, p := range req.Payments {
+		if p.Value < req.DustLimit {
+			return nil, fmt.Errorf("output %d amount %d is below the dust limit %d", i, p.Value, req.DustLimit)
+		}
+		target += p.Value
+	}
+
+	switch req.Strategy {
+	case "", BranchAndBound:
+		if sel := req.branchAndBound(target); sel != nil {
+			return sel, nil
+		}
+		return req.largestFirst(target)
+	case LargestFirst:
+		return req.largestFirst(target)
+	case Privacy:
+		sel, err := req.privacy(target)
+		if err != nil {
+			return nil, err
+		}
+		sortBIP69(sel)
+		return sel, nil
+	default:
+		return nil, fmt.Errorf("unknown coin selection strategy %q", req.Strategy)
+	}
+}
+
+// outputs returns the full output list, with a change output of the given
+// value when withChange is set.
+func (r *Request) outputs(withChange bool, change int64) []Output {
+	outs := slices.Clone(r.Payments)
+	if withChange {
+		outs = append(outs, Output{PkScript: r.ChangeScript, Value: change})
+	}
+	if len(r.OpReturn) > 0 {
+		outs = append(outs, Output{PkScript: r.OpReturn})
+	}
+	return outs
+}
+
+func (r *Request) fee(numInputs int, withChange bool) int64 {
+	return r.Fee(ShapeOf(r.InputType, numInputs, r.outputs(withChange, 0), r.ExtraBytes))
+}
+
+// finalize lays out a transaction spending inputs, adding change when it
+// clears the dust limit. It returns nil if inputs cannot cover target plus fee.
+func (r *Request) finalize(inputs []UTXO, target int64, strategy Strategy) *Selection {
+	var total int64
+	for _, u := range inputs {
+		total += u.Value
+	}
+
+	change := total - target - r.fee(len(inputs), true)
+	if change >= r.DustLimit && change > 0 {
+		outs := r.outputs(true, change)
+		return &Selection{
+			Inputs:      slices.Clone(inputs),
+			Outputs:     outs,
+			ChangeIndex: len(r.Payments),
+			Change:      change,
+			Fee:         total - target - change,
+			Shape:       ShapeOf(r.InputType, len(inputs), outs, r.ExtraBytes),
+			Strategy:    strategy,
+		}
+	}
+	return r.finalizeNoChange(inputs, target, strategy)
+}
+
+// finalizeNoChange lays out a transaction without change; any excess goes to
+// the fee. It returns nil if inputs cannot cover target plus fee.
+func (r *Request) finalizeNoChange(inputs []UTXO, target int64, strategy Strategy) *Selection {
+	var total int64
+	for _, u := range inputs {
+		total += u.Value
+	}
+	if total-target < r.fee(len(inputs), false) {
+		return nil
+	}
+
+	outs := r.outputs(false, 0)
+	return &Selection{
+		Inputs:      slices.Clone(inputs),
+		Outputs:     outs,
+		ChangeIndex: -1,
+		Fee:         total - target,
+		Shape:       ShapeOf(r.InputType, len(inputs), outs, r.ExtraBytes),
+		Strategy:    strategy,
+	}
+}
+
+func (r *Request) largestFirst(target int64) (*Selection, error) {
+	sorted := slices.Clone(r.UTXOs)
+	slices.SortStableFunc(sorted, func(a, b UTXO) int {
+		return cmp.Compare(b.Value, a.Value)
+	})
+
+	var total int64
+	for i := range sorted {
+		total += sorted[i].Value
+		if sel := r.finalize(sorted[:i+1], target, LargestFirst); sel != nil {
+			return sel, nil
+		}
+	}
+
+	need := target + r.fee(len(sorted), false)
+	return nil, fmt.Errorf("insufficient funds: have %d, need %d (amount %d + fee %d)",
+		total, need, target, need-target)
+}
+
+// privacy spends the smallest single coin that covers the payment, so no
+// coins are merged, and falls back to largest-first otherwise.
+func (r *Request) privacy(target int64) (*Selection, error) {
+	sorted := slices.Clone(r.UTXOs)
+	slices.SortStableFunc(sorted, func(a, b UTXO) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+
+	for i := range sorted {
+		if sel := r.finalize(sorted[i:i+1], target, Privacy); sel != nil {
+			return sel, nil
+		}
+	}
+
+	sel, err := r.largestFirst(target)
+	if err != nil {
+		return nil, err
+	}
+	sel.Strategy = Privacy
+	return sel, nil
+}
+
+// branchAndBound searches for an input set whose effective value lands
+// between the target and the target plus the cost of a change output, so the
+// transaction needs no change. It returns nil if no such set is found.
+func (r *Request) branchAndBound(target int64) *Selection {
+	baseFee := r.fee(0, false)
+	inputCost := r.fee(1, false) - baseFee
+	changeCost := r.fee(0, true) - baseFee + inputCost
+
+	type candidate struct {
+		utxo      UTXO
+		effective int64
+	}
+	var pool []candidate
+	var remaining int64
+	for _, u := range r.UTXOs {
+		ev := u.Value - inputCost
+		if ev <= 0 {
+			continue
+		}
+		pool = append(pool, candidate{utxo: u, effective: ev})
+		remaining += ev
+	}
+	slices.SortStableFunc(pool, func(a, b candidate) int {
+		return cmp.Compare(b.effective, a.effective)
+	})
+
+	selTarget := target + baseFee
+	upper := selTarget + changeCost
+
+	var (
+		tries     int
+		current   []int
+		best      []int
+		bestWaste int64 = math.MaxInt64
+	)
+	var search func(i int, sum, remaining int64)
+	search = func(i int, sum, remaining int64) {
+		tries++
+		if tries > bnbMaxTries || sum > upper {
+			return
+		}
+		if sum >= selTarget {
+			if waste := sum - selTarget; waste < bestWaste {
+				bestWaste = waste
+				best = slices.Clone(current)
+			}
+			return
+		}
+		if i == len(pool) || sum+remaining < selTarget {
+			return
+		}
+
+		current = append(current, i)
+		search(i+1, sum+pool[i].effective, remaining-pool[i].effective)
+		current = current[:len(current)-1]
+		if bestWaste == 0 {
+			return
+		}
+		search(i+1, sum, remaining-pool[i].effective)
+	}
+	search(0, 0, remaining)
+
+	if best == nil {
+		return nil
+	}
+	inputs := make([]UTXO, len(best))
+	for i, idx := range best {
+		inputs[i] = pool[idx].utxo
+	}
+	// The per-input cost is linear for rate-based fees but not for ZIP-317,
+	// so the exact fee is checked before accepting the set.
+	return r.finalizeNoChange(inputs, target, BranchAndBound)
+}
+
+// sortBIP69 orders inputs and outputs lexicographically (BIP69). Outputs are
+// left alone when an OP_RETURN is present, since memo-based protocols such as
+// THORChain expect the payment first.
+func sortBIP69(sel *Selection) {
+	slices.SortStableFunc(sel.Inputs, func(a, b UTXO) int {
+		if c := strings.Compare(a.TxHash, b.TxHash); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Index, b.Index)
+	})
+
+	for _, out := range sel.Outputs {
+		if txscript.IsNullData(out.PkScript) {
+			return
+		}
+	}
+
+	type indexed struct {
+		out    Output
+		change bool
+	}
+	outs := make([]indexed, len(sel.Outputs))
+	for i, out := range sel.Outputs {
+		outs[i] = indexed{out: out, change: i == sel.ChangeIndex}
+	}
+	slices.SortStableFunc(outs, func(a, b indexed) int {
+		if c := cmp.Compare(a.out.Value, b.out.Value); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.out.PkScript, b.out.PkScript)
+	})
+	for i, o := range outs {
+		sel.Outputs[i] = o.out
+		if o.change {
+			sel.ChangeIndex = i
+		}
+	}
+}
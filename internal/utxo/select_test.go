@@ -0,0 +1,257 @@
+package utxo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+var (
+	p2wpkhScript = append([]byte{0x00, 0x14}, bytes.Repeat([]byte{0x11}, 20)...)
+	p2pkhScript  = append(append([]byte{0x76, 0xa9, 0x14}, bytes.Repeat([]byte{0x22}, 20)...), 0x88, 0xac)
+	payScript    = append([]byte{0x00, 0x14}, bytes.Repeat([]byte{0x33}, 20)...)
+)
+
+func TestShapeOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputType InputType
+		inputs    int
+		outputs   []Output
+		extra     int
+		wantVSize int
+	}{
+		{"p2wpkh 1-in 2-out", InputP2WPKH, 1, []Output{{PkScript: payScript}, {PkScript: p2wpkhScript}}, 0, 141},
+		{"p2wpkh 2-in 2-out", InputP2WPKH, 2, []Output{{PkScript: payScript}, {PkScript: p2wpkhScript}}, 0, 209},
+		{"p2pkh 1-in 2-out", InputP2PKH, 1, []Output{{PkScript: p2pkhScript}, {PkScript: p2pkhScript}}, 0, 226},
+		{"p2pkh 2-in 1-out", InputP2PKH, 2, []Output{{PkScript: p2pkhScript}}, 0, 340},
+		{"zcash v4 1-in 1-out", InputP2PKH, 1, []Output{{PkScript: p2pkhScript}}, 19, 211},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ShapeOf(tt.inputType, tt.inputs, tt.outputs, tt.extra)
+			if got.VSize != tt.wantVSize {
+				t.Errorf("vsize = %d, want %d", got.VSize, tt.wantVSize)
+			}
+		})
+	}
+}
+
+func TestSignedVSize(t *testing.T) {
+	unsigned := func(inputs int, outputs ...[]byte) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		for range inputs {
+			tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+		}
+		for _, script := range outputs {
+			tx.AddTxOut(wire.NewTxOut(1000, script))
+		}
+		return tx
+	}
+
+	// Matches ShapeOf for single-type inputs.
+	got, err := SignedVSize(unsigned(1, payScript, p2wpkhScript), [][]byte{p2wpkhScript})
+	if err != nil || got != 141 {
+		t.Errorf("p2wpkh 1-in 2-out = %d, %v; want 141", got, err)
+	}
+	got, err = SignedVSize(unsigned(2, p2pkhScript), [][]byte{p2pkhScript, p2pkhScript})
+	if err != nil || got != 340 {
+		t.Errorf("p2pkh 2-in 1-out = %d, %v; want 340", got, err)
+	}
+
+	// A legacy input in a witness transaction adds its empty witness.
+	got, err = SignedVSize(unsigned(2, payScript), [][]byte{p2wpkhScript, p2pkhScript})
+	if err != nil || got != 258 {
+		t.Errorf("mixed 2-in 1-out = %d, %v; want 258", got, err)
+	}
+
+	_, err = SignedVSize(unsigned(1, payScript), [][]byte{{txscript.OP_TRUE}})
+	if err == nil {
+		t.Error("expected error for a non-standard input")
+	}
+}
+
+func TestZIP317Fee(t *testing.T) {
+	small := ShapeOf(InputP2PKH, 1, []Output{{PkScript: p2pkhScript}, {PkScript: p2pkhScript}}, 19)
+	if got := ZIP317Fee(small); got != 10000 {
+		t.Errorf("1-in 2-out fee = %d, want 10000", got)
+	}
+	large := ShapeOf(InputP2PKH, 5, []Output{{PkScript: p2pkhScript}}, 19)
+	if got := ZIP317Fee(large); got != 25000 {
+		t.Errorf("5-in 1-out fee = %d, want 25000", got)
+	}
+}
+
+func TestInputTypeOf(t *testing.T) {
+	if typ, err := InputTypeOf(p2wpkhScript); err != nil || typ != InputP2WPKH {
+		t.Errorf("p2wpkh: got %v, %v", typ, err)
+	}
+	if typ, err := InputTypeOf(p2pkhScript); err != nil || typ != InputP2PKH {
+		t.Errorf("p2pkh: got %v, %v", typ, err)
+	}
+	if _, err := InputTypeOf([]byte{0x6a}); err == nil {
+		t.Error("expected error for OP_RETURN script")
+	}
+}
+
+func newRequest(strategy Strategy, amount int64, utxos ...int64) Request {
+	req := Request{
+		InputType:    InputP2WPKH,
+		Payments:     []Output{{PkScript: payScript, Value: amount}},
+		ChangeScript: p2wpkhScript,
+		DustLimit:    546,
+		Fee:          RateFee(10),
+		Strategy:     strategy,
+	}
+	for i, v := range utxos {
+		req.UTXOs = append(req.UTXOs, UTXO{
+			TxHash: strings.Repeat(string(rune('a'+i)), 64),
+			Index:  uint32(i),
+			Value:  v,
+		})
+	}
+	return req
+}
+
+func checkBalanced(t *testing.T, sel *Selection) {
+	t.Helper()
+	var in, out int64
+	for _, u := range sel.Inputs {
+		in += u.Value
+	}
+	for _, o := range sel.Outputs {
+		out += o.Value
+	}
+	if in-out != sel.Fee {
+		t.Errorf("inputs %d - outputs %d = %d, want fee %d", in, out, in-out, sel.Fee)
+	}
+	if min := RateFee(10)(sel.Shape); sel.Fee < min {
+		t.Errorf("fee %d below rate minimum %d", sel.Fee, min)
+	}
+}
+
+func TestSelectLargestFirst(t *testing.T) {
+	sel, err := Select(newRequest(LargestFirst, 150_000, 50_000, 100_000, 80_000))
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	checkBalanced(t, sel)
+	if len(sel.Inputs) != 2 || sel.Inputs[0].Value != 100_000 || sel.Inputs[1].Value != 80_000 {
+		t.Errorf("inputs = %+v, want 100000 then 80000", sel.Inputs)
+	}
+	if sel.ChangeIndex != 1 {
+		t.Fatalf("change index = %d, want 1", sel.ChangeIndex)
+	}
+	if sel.Outputs[1].Value != sel.Change || !bytes.Equal(sel.Outputs[1].PkScript, p2wpkhScript) {
+		t.Errorf("change output = %+v", sel.Outputs[1])
+	}
+	if sel.Fee != 2090 {
+		t.Errorf("fee = %d, want 2090 (209 vB at 10 sat/vB)", sel.Fee)
+	}
+}
+
+func TestSelectBranchAndBoundExactMatch(t *testing.T) {
+	// 1-in 1-out P2WPKH is 110 vB, so an 81_100 coin pays 80_000 at
+	// 10 sat/vB with no change.
+	sel, err := Select(newRequest(BranchAndBound, 80_000, 200_000, 81_100, 30_000))
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	checkBalanced(t, sel)
+	if sel.Strategy != BranchAndBound {
+		t.Errorf("strategy = %s, want bnb", sel.Strategy)
+	}
+	if sel.ChangeIndex != -1 || len(sel.Outputs) != 1 {
+		t.Errorf("expected no change, got outputs %+v", sel.Outputs)
+	}
+	if len(sel.Inputs) != 1 || sel.Inputs[0].Value != 81_100 {
+		t.Errorf("inputs = %+v, want the 81100 coin", sel.Inputs)
+	}
+}
+
+func TestSelectBranchAndBoundFallback(t *testing.T) {
+	sel, err := Select(newRequest(BranchAndBound, 50_000, 200_000, 300_000))
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	checkBalanced(t, sel)
+	if sel.Strategy != LargestFirst {
+		t.Errorf("strategy = %s, want largest_first fallback", sel.Strategy)
+	}
+	if sel.ChangeIndex < 0 {
+		t.Error("expected change output")
+	}
+}
+
+func TestSelectPrivacy(t *testing.T) {
+	sel, err := Select(newRequest(Privacy, 60_000, 40_000, 500_000, 70_000, 30_000))
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	checkBalanced(t, sel)
+	if len(sel.Inputs) != 1 || sel.Inputs[0].Value != 70_000 {
+		t.Errorf("inputs = %+v, want the single 70000 coin", sel.Inputs)
+	}
+	// BIP69 puts the smaller change before the 60_000 payment.
+	if sel.ChangeIndex != 0 || sel.Outputs[1].Value != 60_000 {
+		t.Errorf("outputs = %+v, change index %d", sel.Outputs, sel.ChangeIndex)
+	}
+}
+
+func TestSelectPrivacyKeepsMemoOrder(t *testing.T) {
+	req := newRequest(Privacy, 60_000, 70_000)
+	memo, err := OpReturnScript([]byte("SWAP:ETH.ETH:0x1234"))
+	if err != nil {
+		t.Fatalf("OpReturnScript: %v", err)
+	}
+	req.OpReturn = memo
+
+	sel, err := Select(req)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if len(sel.Outputs) != 3 || sel.Outputs[0].Value != 60_000 || !bytes.Equal(sel.Outputs[2].PkScript, memo) {
+		t.Errorf("outputs = %+v, want payment, change, memo", sel.Outputs)
+	}
+}
+
+func TestSelectDustChangeGoesToFee(t *testing.T) {
+	// 81_500 covers 80_000 + 1_410 fee with change, leaving 90 sats of dust.
+	sel, err := Select(newRequest(LargestFirst, 80_000, 81_500))
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	checkBalanced(t, sel)
+	if sel.ChangeIndex != -1 {
+		t.Errorf("expected dust change to be dropped, got %+v", sel.Outputs)
+	}
+	if sel.Fee != 1_500 {
+		t.Errorf("fee = %d, want 1500", sel.Fee)
+	}
+}
+
+func TestSelectInsufficientFunds(t *testing.T) {
+	_, err := Select(newRequest(LargestFirst, 100_000, 50_000, 40_000))
+	if err == nil || !strings.Contains(err.Error(), "insufficient funds") {
+		t.Fatalf("expected insufficient funds error, got %v", err)
+	}
+}
+
+func TestSelectDustPayment(t *testing.T) {
+	_, err := Select(newRequest(LargestFirst, 500, 50_000))
+	if err == nil || !strings.Contains(err.Error(), "dust") {
+		t.Fatalf("expected dust error, got %v", err)
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	if s, err := ParseStrategy(""); err != nil || s != BranchAndBound {
+		t.Errorf("empty: got %q, %v", s, err)
+	}
+	if _, err := ParseStrategy("random"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
@@ -0,0 +1,191 @@
+package utxo
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// InputType identifies the locking script of the coins being spent, which
+// determines how large each input is once signed.
+type InputType int
+
+const (
+	InputP2PKH InputType = iota
+	InputP2WPKH
+)
+
+func (t InputType) String() string {
+	switch t {
+	case InputP2PKH:
+		return "p2pkh"
+	case InputP2WPKH:
+		return "p2wpkh"
+	default:
+		return fmt.Sprintf("InputType(%d)", int(t))
+	}
+}
+
+// Signed input sizes. Signatures are counted at their worst-case length of
+// 72 bytes (71-byte low-S DER plus the sighash byte), so the estimated fee is
+// never below what the signed transaction needs.
+const (
+	outpointBytes = 36 // txid + vout
+	sequenceBytes = 4
+	sigBytes      = 72
+	pubKeyBytes   = 33
+
+	// p2pkhScriptSigBytes is <push sig> <sig> <push pubkey> <pubkey>.
+	p2pkhScriptSigBytes = 1 + sigBytes + 1 + pubKeyBytes
+	// p2wpkhWitnessBytes is the item count plus the two length-prefixed items.
+	p2wpkhWitnessBytes = 1 + 1 + sigBytes + 1 + pubKeyBytes
+	// segwitMarkerBytes is the marker and flag bytes of a witness transaction.
+	segwitMarkerBytes = 2
+
+	// txOverheadBytes is version plus lock time; the input and output counts
+	// are added separately since they are variable-length.
+	txOverheadBytes = 4 + 4
+)
+
+// InputTypeOf reports the input type for coins locked to pkScript.
+func InputTypeOf(pkScript []byte) (InputType, error) {
+	switch {
+	case txscript.IsPayToPubKeyHash(pkScript):
+		return InputP2PKH, nil
+	case txscript.IsPayToWitnessPubKeyHash(pkScript):
+		return InputP2WPKH, nil
+	default:
+		return 0, fmt.Errorf("unsupported input script type: %s", txscript.GetScriptClass(pkScript))
+	}
+}
+
+// inputBytes returns the non-witness and witness sizes of one signed input.
+func (t InputType) inputBytes() (base, witness int) {
+	switch t {
+	case InputP2WPKH:
+		return outpointBytes + 1 + sequenceBytes, p2wpkhWitnessBytes
+	default:
+		return outpointBytes + wire.VarIntSerializeSize(p2pkhScriptSigBytes) + p2pkhScriptSigBytes + sequenceBytes, 0
+	}
+}
+
+// OutputBytes returns the serialized size of one output.
+func OutputBytes(out Output) int {
+	return 8 + wire.VarIntSerializeSize(uint64(len(out.PkScript))) + len(out.PkScript)
+}
+
+// Shape summarises a transaction's size for fee calculation.
+type Shape struct {
+	NumInputs   int
+	NumOutputs  int
+	InputBytes  int // total serialized size of the signed inputs, witness included
+	OutputBytes int // total serialized size of the outputs
+	VSize       int // virtual size in vbytes (equal to the size for non-witness transactions)
+}
+
+// ShapeOf computes the signed size of a transaction spending numInputs coins
+// of the given type into outputs. extraBytes covers chain-specific fields
+// outside the Bitcoin layout (e.g. the Zcash v4 header).
+func ShapeOf(inputType InputType, numInputs int, outputs []Output, extraBytes int) Shape {
+	inBase, inWitness := inputType.inputBytes()
+
+	outBytes := 0
+	for _, out := range outputs {
+		outBytes += OutputBytes(out)
+	}
+
+	base := txOverheadBytes + extraBytes +
+		wire.VarIntSerializeSize(uint64(numInputs)) + numInputs*inBase +
+		wire.VarIntSerializeSize(uint64(len(outputs))) + outBytes
+
+	witness := 0
+	if inWitness > 0 && numInputs > 0 {
+		witness = segwitMarkerBytes + numInputs*inWitness
+	}
+
+	weight := base*4 + witness
+	return Shape{
+		NumInputs:   numInputs,
+		NumOutputs:  len(outputs),
+		InputBytes:  numInputs * (inBase + inWitness),
+		OutputBytes: outBytes,
+		VSize:       (weight + 3) / 4,
+	}
+}
+
+// Witness sizes of input kinds that only appear in foreign transactions.
+const (
+	// p2trKeyPathWitnessBytes is the item count plus a 64-byte Schnorr signature.
+	p2trKeyPathWitnessBytes = 1 + 1 + 64
+	// p2shP2WPKHScriptSigBytes pushes the 22-byte witness program.
+	p2shP2WPKHScriptSigBytes = 1 + 22
+)
+
+// SignedVSize estimates the virtual size of the unsigned tx once every input
+// is signed. prevScripts holds the locking script of each input's coin. P2SH
+// coins are assumed to wrap P2WPKH and P2TR coins to be spent by key path;
+// other script types cannot be estimated and return an error.
+func SignedVSize(tx *wire.MsgTx, prevScripts [][]byte) (int, error) {
+	if len(prevScripts) != len(tx.TxIn) {
+		return 0, fmt.Errorf("have %d previous scripts for %d inputs", len(prevScripts), len(tx.TxIn))
+	}
+
+	base := tx.SerializeSizeStripped()
+	witness, legacy := 0, 0
+	for i, script := range prevScripts {
+		// Replace the input's current script sig with the signed one.
+		sigLen := len(tx.TxIn[i].SignatureScript)
+		base -= wire.VarIntSerializeSize(uint64(sigLen)) + sigLen
+		scriptSig := 0
+		switch class := txscript.GetScriptClass(script); class {
+		case txscript.PubKeyHashTy:
+			scriptSig = p2pkhScriptSigBytes
+			legacy++
+		case txscript.WitnessV0PubKeyHashTy:
+			witness += p2wpkhWitnessBytes
+		case txscript.ScriptHashTy:
+			scriptSig = p2shP2WPKHScriptSigBytes
+			witness += p2wpkhWitnessBytes
+		case txscript.WitnessV1TaprootTy:
+			witness += p2trKeyPathWitnessBytes
+		default:
+			return 0, fmt.Errorf("input %d: cannot estimate the signed size of a %s input", i, class)
+		}
+		base += wire.VarIntSerializeSize(uint64(scriptSig)) + scriptSig
+	}
+	if witness > 0 {
+		// Legacy inputs of a witness transaction carry an empty witness.
+		witness += segwitMarkerBytes + legacy
+	}
+	return (base*4 + witness + 3) / 4, nil
+}
+
+// FeeFunc returns the fee, in base units, for a transaction of the given shape.
+type FeeFunc func(Shape) int64
+
+// RateFee charges satsPerVByte for every virtual byte.
+func RateFee(satsPerVByte uint64) FeeFunc {
+	return func(s Shape) int64 {
+		return int64(s.VSize) * int64(satsPerVByte)
+	}
+}
+
+// ZIP-317 parameters for transparent-only transactions.
+const (
+	zip317MarginalFee     = 5000
+	zip317GraceActions    = 2
+	zip317P2PKHStdInSize  = 150
+	zip317P2PKHStdOutSize = 34
+)
+
+// ZIP317Fee is the Zcash conventional fee: 5000 zatoshis per logical action,
+// with a minimum of two actions.
+func ZIP317Fee(s Shape) int64 {
+	actions := max(
+		(s.InputBytes+zip317P2PKHStdInSize-1)/zip317P2PKHStdInSize,
+		(s.OutputBytes+zip317P2PKHStdOutSize-1)/zip317P2PKHStdOutSize,
+		zip317GraceActions,
+	)
+	return int64(zip317MarginalFee * actions)
+}
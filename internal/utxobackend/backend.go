@@ -0,0 +1,127 @@
+// Package utxobackend defines the data source used by the UTXO-chain tools
+// and routes each chain to the implementation configured for it.
+package utxobackend
+
+import (
+	"context"
+	"fmt"
+)
+
+// AddressInfo holds the address-level stats of an address dashboard.
+type AddressInfo struct {
+	Type               string  `json:"type"`
+	Balance            int64   `json:"balance"`
+	BalanceUSD         float64 `json:"balance_usd"`
+	Received           int64   `json:"received"`
+	Spent              int64   `json:"spent"`
+	OutputCount        int     `json:"output_count"`
+	UnspentOutputCount int     `json:"unspent_output_count"`
+	TransactionCount   int     `json:"transaction_count"`
+}
+
+// UTXO represents a single unspent transaction output. BlockID is -1 while
+// the output is unconfirmed.
+type UTXO struct {
+	BlockID         int64  `json:"block_id"`
+	TransactionHash string `json:"transaction_hash"`
+	Index           int    `json:"index"`
+	Value           int64  `json:"value"`
+}
+
+// AddressDashboard contains the full dashboard data for one address.
+type AddressDashboard struct {
+	Address      AddressInfo `json:"address"`
+	Transactions []string    `json:"transactions"`
+	UTXOs        []UTXO      `json:"utxo"`
+	// Height is the chain tip height when the dashboard was fetched, used to
+	// count confirmations. Zero if the backend did not report it.
+	Height int64 `json:"-"`
+}
+
+// TxDashboard holds the status of one transaction. BlockID is -1 while the
+// transaction is in the mempool.
+type TxDashboard struct {
+	BlockID       int64 `json:"block_id"`
+	Fee           int64 `json:"fee"`
+	InputTotal    int64 `json:"input_total"`
+	OutputTotal   int64 `json:"output_total"`
+	Confirmations int   `json:"confirmations,omitempty"`
+}
+
+// Backend is a source of UTXO-chain data. Chains are named as in
+// blockchair.SupportedChains (e.g. "Bitcoin", "Bitcoin-Cash").
+type Backend interface {
+	// GetAddressDashboard returns the balance, history and UTXO set of address.
+	GetAddressDashboard(ctx context.Context, chain, address string) (*AddressDashboard, error)
+	// GetUTXOs returns the unspent outputs of address, including unconfirmed ones.
+	GetUTXOs(ctx context.Context, chain, address string) ([]UTXO, error)
+	// GetRawTransaction returns the serialized transaction txHash.
+	GetRawTransaction(ctx context.Context, chain, txHash string) ([]byte, error)
+	// GetTxDashboard returns the status of txHash, or nil, nil if it is unknown.
+	GetTxDashboard(ctx context.Context, chain, txHash string) (*TxDashboard, error)
+	// BroadcastTransaction submits a signed transaction and returns its hash.
+	BroadcastTransaction(ctx context.Context, chain string, rawTx []byte) (string, error)
+}
+
+// Router sends each chain's requests to the backend configured for it,
+// falling back to a default backend.
+type Router struct {
+	fallback Backend
+	chains   map[string]Backend
+}
+
+var _ Backend = (*Router)(nil)
+
+// NewRouter creates a Router. chains overrides fallback for the named chains.
+func NewRouter(fallback Backend, chains map[string]Backend) *Router {
+	return &Router{fallback: fallback, chains: chains}
+}
+
+// For returns the backend serving chain.
+func (r *Router) For(chain string) Backend {
+	if b, ok := r.chains[chain]; ok {
+		return b
+	}
+	return r.fallback
+}
+
+func (r *Router) GetAddressDashboard(ctx context.Context, chain, address string) (*AddressDashboard, error) {
+	return r.For(chain).GetAddressDashboard(ctx, chain, address)
+}
+
+func (r *Router) GetUTXOs(ctx context.Context, chain, address string) ([]UTXO, error) {
+	return r.For(chain).GetUTXOs(ctx, chain, address)
+}
+
+func (r *Router) GetRawTransaction(ctx context.Context, chain, txHash string) ([]byte, error) {
+	return r.For(chain).GetRawTransaction(ctx, chain, txHash)
+}
+
+func (r *Router) GetTxDashboard(ctx context.Context, chain, txHash string) (*TxDashboard, error) {
+	return r.For(chain).GetTxDashboard(ctx, chain, txHash)
+}
+
+func (r *Router) BroadcastTransaction(ctx context.Context, chain string, rawTx []byte) (string, error) {
+	return r.For(chain).BroadcastTransaction(ctx, chain, rawTx)
+}
+
+// PrevTxFetcher adapts a Backend to the previous-transaction lookup used by
+// the recipes SDK when populating PSBT inputs.
+type PrevTxFetcher struct {
+	ctx     context.Context
+	backend Backend
+	chain   string
+}
+
+// NewPrevTxFetcher returns a fetcher for chain. ctx bounds every lookup.
+func NewPrevTxFetcher(ctx context.Context, backend Backend, chain string) *PrevTxFetcher {
+	return &PrevTxFetcher{ctx: ctx, backend: backend, chain: chain}
+}
+
+func (f *PrevTxFetcher) GetRawTransaction(txHash string) ([]byte, error) {
+	raw, err := f.backend.GetRawTransaction(f.ctx, f.chain, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("fetch previous transaction %s: %w", txHash, err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,55 @@
+package utxobackend
+
+import (
+	"context"
+	"testing"
+)
+
+// stubBackend reports its name as the hash of every raw transaction.
+type stubBackend struct {
+	name string
+}
+
+func (s stubBackend) GetAddressDashboard(context.Context, string, string) (*AddressDashboard, error) {
+	return &AddressDashboard{Transactions: []string{s.name}}, nil
+}
+
+func (s stubBackend) GetUTXOs(context.Context, string, string) ([]UTXO, error) {
+	return []UTXO{{TransactionHash: s.name}}, nil
+}
+
+func (s stubBackend) GetRawTransaction(context.Context, string, string) ([]byte, error) {
+	return []byte(s.name), nil
+}
+
+func (s stubBackend) GetTxDashboard(context.Context, string, string) (*TxDashboard, error) {
+	return nil, nil
+}
+
+func (s stubBackend) BroadcastTransaction(context.Context, string, []byte) (string, error) {
+	return s.name, nil
+}
+
+func TestRouter(t *testing.T) {
+	r := NewRouter(stubBackend{"blockchair"}, map[string]Backend{
+		"Bitcoin": stubBackend{"electrum"},
+	})
+	ctx := context.Background()
+
+	for chain, want := range map[string]string{
+		"Bitcoin":  "electrum",
+		"Litecoin": "blockchair",
+		"Zcash":    "blockchair",
+	} {
+		txid, _ := r.BroadcastTransaction(ctx, chain, nil)
+		utxos, _ := r.GetUTXOs(ctx, chain, "addr")
+		if txid != want || utxos[0].TransactionHash != want {
+			t.Errorf("%s routed to %q/%q, want %q", chain, txid, utxos[0].TransactionHash, want)
+		}
+	}
+
+	raw, err := NewPrevTxFetcher(ctx, r, "Bitcoin").GetRawTransaction("aa")
+	if err != nil || string(raw) != "electrum" {
+		t.Errorf("prev tx fetcher = %q, %v", raw, err)
+	}
+}